@@ -32,6 +32,11 @@ func WithAppAccess(appAccessID, appSecretKey string) Option {
 	}
 }
 
+// ContextWithAppAccess 返回携带 App 认证凭证的上下文，通过该上下文发起的调用覆盖客户端的默认凭证
+func ContextWithAppAccess(ctx context.Context, appAccessID, appSecretKey string) context.Context {
+	return service2.WithAppAccess(ctx, appAccessID, appSecretKey)
+}
+
 // WithProtoPublicClient 直接传入 proto client（用于测试）
 func WithProtoPublicClient(protoClient pbconnect.PublicServiceClient) Option {
 	return func(c *SsoClient) {
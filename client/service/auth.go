@@ -47,9 +47,7 @@ func (s *AuthService) RegisterByEmail(ctx context.Context, req *pb.RegisterByEma
 	protoReq := connect.NewRequest(req)
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 
 	// 调用 proto client
 	resp, err := s.client.RegisterByEmail(ctx, protoReq)
@@ -85,9 +83,7 @@ func (s *AuthService) PasswordLogin(ctx context.Context, req *pb.PasswordLoginRe
 	protoReq := connect.NewRequest(req)
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 
 	// 调用 proto client
 	resp, err := s.client.PasswordLogin(ctx, protoReq)
@@ -120,9 +116,7 @@ func (s *AuthService) ChangePassword(ctx context.Context, req *pb.ChangePassword
 	protoReq := connect.NewRequest(req)
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 
 	// 调用 proto client
 	resp, err := s.client.ChangePassword(ctx, protoReq)
@@ -159,9 +153,7 @@ func (s *AuthService) RevokeToken(ctx context.Context, accessToken string, req *
 	protoReq := connect.NewRequest(req)
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 	protoReq.Header().Set("authorization", authorization)
 
 	// 调用 proto client
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"net/http"
+)
+
+// appAccessContextKey App 认证凭证上下文键。
+type appAccessContextKey struct{}
+
+// appAccess 按请求覆盖的 App 认证凭证。
+type appAccess struct {
+	id     string
+	secret string
+}
+
+// WithAppAccess 返回携带 App 认证凭证的上下文
+//
+// 通过该上下文发起的调用使用给定的 `app-access-id` 与 `app-secret-key`，覆盖客户端创建时设置的默认凭证，
+// 用于同一客户端按请求切换租户。
+func WithAppAccess(ctx context.Context, appAccessID, appSecretKey string) context.Context {
+	return context.WithValue(ctx, appAccessContextKey{}, appAccess{id: appAccessID, secret: appSecretKey})
+}
+
+// setHeaders 将客户端默认请求头写入 proto 请求，上下文携带 App 认证凭证时覆盖默认凭证。
+func setHeaders(ctx context.Context, header http.Header, headers map[string]string) {
+	for k, v := range headers {
+		header.Set(k, v)
+	}
+	if access, ok := ctx.Value(appAccessContextKey{}).(appAccess); ok {
+		header.Set("app-access-id", access.id)
+		header.Set("app-secret-key", access.secret)
+	}
+}
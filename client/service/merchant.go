@@ -109,9 +109,7 @@ func (s *MerchantService) GetMerchantTags(ctx context.Context, req *GetMerchantT
 	})
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 
 	// 调用 proto client
 	resp, err := s.client.GetMerchantTags(ctx, protoReq)
@@ -157,9 +155,7 @@ func (s *MerchantService) GetUserTags(ctx context.Context, req *GetUserTagsReque
 	})
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 
 	// 调用 proto client
 	resp, err := s.client.GetUserTags(ctx, protoReq)
@@ -208,9 +204,7 @@ func (s *MerchantService) CheckUserHasTag(ctx context.Context, req *CheckUserHas
 	})
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 
 	// 调用 proto client
 	resp, err := s.client.CheckUserHasTag(ctx, protoReq)
@@ -251,9 +245,7 @@ func (s *MerchantService) GetRecentAnnouncements(ctx context.Context, req *GetRe
 	})
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 
 	// 调用 proto client
 	resp, err := s.client.GetRecentAnnouncements(ctx, protoReq)
@@ -303,9 +295,7 @@ func (s *MerchantService) GetAnnouncement(ctx context.Context, req *GetAnnouncem
 	})
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 
 	// 调用 proto client
 	resp, err := s.client.GetAnnouncement(ctx, protoReq)
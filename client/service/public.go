@@ -31,9 +31,7 @@ func (s *PublicService) SendRegisterEmailCode(ctx context.Context, req *pb.SendR
 	protoReq := connect.NewRequest(req)
 
 	// 添加 headers
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 
 	// 调用 proto client
 	resp, err := s.client.SendRegisterEmailCode(ctx, protoReq)
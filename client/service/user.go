@@ -38,9 +38,7 @@ func (s *UserService) GetCurrentUser(ctx context.Context, accessToken string) (*
 	protoReq := connect.NewRequest(&pb.GetCurrentUserRequest{})
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 	protoReq.Header().Set("authorization", authorization)
 
 	// 调用 proto client
@@ -82,9 +80,7 @@ func (s *UserService) GetUserByID(ctx context.Context, accessToken string, req *
 	protoReq := connect.NewRequest(req)
 
 	// 添加 headers (App 认证凭证)
-	for k, v := range s.headers {
-		protoReq.Header().Set(k, v)
-	}
+	setHeaders(ctx, protoReq.Header(), s.headers)
 	protoReq.Header().Set("authorization", authorization)
 
 	// 调用 proto client
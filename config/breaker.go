@@ -0,0 +1,174 @@
+package bSdkConfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultBreakerCooldown 未配置 `http.breaker_cooldown` 时熔断器的默认冷却时间。
+const defaultBreakerCooldown = 30 * time.Second
+
+// ErrBreakerOpen 熔断器处于打开状态时请求被直接拒绝
+var ErrBreakerOpen = errors.New("SSO 请求已熔断")
+
+// BreakerState 熔断器状态
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // 正常放行
+	BreakerOpen     BreakerState = "open"      // 拒绝请求，等待冷却
+	BreakerHalfOpen BreakerState = "half_open" // 冷却结束，放行单个试探请求
+)
+
+// BreakerStatus 熔断器状态快照
+type BreakerStatus struct {
+	Host      string       `json:"host"`                 // 请求的目标主机
+	State     BreakerState `json:"state"`                // 当前状态
+	Failures  int          `json:"failures"`             // 连续失败次数
+	OpenedAt  time.Time    `json:"opened_at,omitzero"`   // 最近一次打开的时间
+	LastError string       `json:"last_error,omitempty"` // 最近一次失败原因
+}
+
+// breaker 按目标主机区分的进程级熔断器。
+//
+// 连续失败（网络错误或 5xx）达到阈值后打开，冷却时间内拒绝请求；冷却结束后放行一个试探请求，
+// 成功则关闭，失败则重新打开。
+type breaker struct {
+	mu        sync.Mutex
+	host      string
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	cooldown  time.Duration // 最近一次放行判断使用的冷却时间，用于状态快照
+	probing   bool          // 半开状态下是否已有试探请求在途
+	lastError string
+}
+
+// breakers 按主机区分的熔断器（host -> *breaker）。
+var breakers sync.Map
+
+// breakerFor 返回指定主机的熔断器。
+func breakerFor(host string) *breaker {
+	value, _ := breakers.LoadOrStore(host, &breaker{host: host, state: BreakerClosed})
+	return value.(*breaker)
+}
+
+// Breakers 返回进程内各 SSO 主机的熔断器状态，按主机名排序
+//
+// 仅包含配置了 `http.breaker_threshold` 且已发出过请求的主机。
+func Breakers() []BreakerStatus {
+	var statuses []BreakerStatus
+	breakers.Range(func(_, value any) bool {
+		statuses = append(statuses, value.(*breaker).status())
+		return true
+	})
+	slices.SortFunc(statuses, func(a, b BreakerStatus) int { return strings.Compare(a.Host, b.Host) })
+	return statuses
+}
+
+// allow 判断是否放行请求。
+func (b *breaker) allow(cooldown time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.cooldown = cooldown
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < cooldown {
+			return fmt.Errorf("%w: %s", ErrBreakerOpen, b.host)
+		}
+		b.state, b.probing = BreakerHalfOpen, true
+	case BreakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w: %s", ErrBreakerOpen, b.host)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record 记录请求结果，failure 为空表示成功。
+func (b *breaker) record(failure string, threshold int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if failure == "" {
+		b.state, b.failures = BreakerClosed, 0
+		return
+	}
+	b.failures++
+	b.lastError = failure
+	if b.state == BreakerHalfOpen || b.failures >= threshold {
+		b.state, b.openedAt = BreakerOpen, time.Now()
+	}
+}
+
+// release 释放试探名额而不改变状态。
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// status 返回熔断器状态快照。
+//
+// 熔断器只在下一次请求时从打开转为半开，快照中冷却已结束的打开状态按半开报告。
+func (b *breaker) status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := b.state
+	if state == BreakerOpen && time.Since(b.openedAt) >= b.cooldown {
+		state = BreakerHalfOpen
+	}
+	return BreakerStatus{Host: b.host, State: state, Failures: b.failures, OpenedAt: b.openedAt, LastError: b.lastError}
+}
+
+// breakerTransport 在请求前后经过熔断器的 `http.RoundTripper`。
+type breakerTransport struct {
+	base      http.RoundTripper
+	threshold int
+	cooldown  time.Duration
+}
+
+// RoundTrip 熔断器打开时直接返回 `ErrBreakerOpen`，否则发出请求并记录结果。
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b := breakerFor(req.URL.Host)
+	if err := b.allow(t.cooldown); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil && errors.Is(err, context.Canceled):
+		b.release() // 调用方主动取消不计入结果
+	case err != nil:
+		b.record(err.Error(), t.threshold)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		b.record(fmt.Sprintf("状态码 %d", resp.StatusCode), t.threshold)
+	default:
+		b.record("", t.threshold)
+	}
+	return resp, err
+}
+
+// transport 为 base 套上熔断器，未启用熔断时原样返回 base。
+func (c HTTPConfig) transport(base http.RoundTripper) http.RoundTripper {
+	if c.BreakerThreshold <= 0 {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	cooldown := c.BreakerCooldown.Duration()
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	return &breakerTransport{base: base, threshold: c.BreakerThreshold, cooldown: cooldown}
+}
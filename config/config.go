@@ -0,0 +1,311 @@
+package bSdkConfig
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	"golang.org/x/oauth2"
+)
+
+// DefaultScopes 未配置授权范围时使用的默认值。
+var DefaultScopes = []string{"openid", "profile", "email", "phone"}
+
+// Config SDK 配置
+//
+// 配置可以来自环境变量（`LoadEnv`）、YAML/JSON 文件（`LoadFile`）或代码（`New` 与 `Option`），
+// 通过启动节点注册到上下文后由各逻辑组件显式读取，不再回写进程环境变量，
+// 因此同一进程内可以同时存在多个使用不同配置的 SDK 实例。
+type Config struct {
+	Client    ClientConfig   `json:"client" yaml:"client"`       // OAuth 客户端
+	Endpoints EndpointConfig `json:"endpoints" yaml:"endpoints"` // SSO 端点
+	Scopes    []string       `json:"scopes" yaml:"scopes"`       // 授权范围
+	Cache     CacheConfig    `json:"cache" yaml:"cache"`         // 缓存
+	Storage   StorageConfig  `json:"storage" yaml:"storage"`     // 状态存储
+	Business  BusinessConfig `json:"business" yaml:"business"`   // 业务层无效令牌拦截
+	Grpc      GrpcConfig     `json:"grpc" yaml:"grpc"`           // gRPC 客户端
+	HTTP      HTTPConfig     `json:"http" yaml:"http"`           // 请求 SSO 的 HTTP 客户端
+	Secrets   SecretsConfig  `json:"secrets" yaml:"secrets"`     // 客户端密钥读取
+	Session   SessionConfig  `json:"session" yaml:"session"`     // 本地会话 Cookie
+	Token     TokenConfig    `json:"token" yaml:"token"`         // 令牌存储与令牌密钥
+	Probe     ProbeConfig    `json:"probe" yaml:"probe"`         // 启动自检
+	Health    HealthConfig   `json:"health" yaml:"health"`       // 就绪检查
+	Reload    ReloadConfig   `json:"reload" yaml:"reload"`       // 配置重新加载
+
+	Providers map[string]*Config       `json:"providers,omitempty" yaml:"providers,omitempty"` // 额外的命名身份提供方，键为提供方名称
+	Tenants   map[string]*TenantConfig `json:"tenants,omitempty" yaml:"tenants,omitempty"`     // 租户，键为租户名称
+
+	TenantResolver TenantResolver    `json:"-" yaml:"-"` // 自定义租户解析函数，为 nil 时按租户的 hosts 与 path_prefix 匹配
+	SecretProvider SecretProvider    `json:"-" yaml:"-"` // 按 `client.secret_ref` 读取密钥的提供者，为 nil 时从文件读取
+	Metadata       *ProviderMetadata `json:"-" yaml:"-"` // 自动发现得到的身份提供方元数据（只读），未使用自动发现时为 nil
+
+	live *liveConfig // 热更新状态，仅由 `Reloader` 创建的配置句柄持有
+}
+
+// ClientConfig OAuth 客户端配置
+type ClientConfig struct {
+	ID                    string `json:"id" yaml:"id"`                                           // 客户端 ID
+	Secret                string `json:"secret" yaml:"secret"`                                   // 客户端 Secret
+	SecretRef             string `json:"secret_ref,omitempty" yaml:"secret_ref,omitempty"`       // 客户端 Secret 的引用，由 `SecretProvider` 读取并覆盖 Secret
+	RedirectURI           string `json:"redirect_uri" yaml:"redirect_uri"`                       // 授权回调地址
+	FrontchannelLogoutURI string `json:"frontchannel_logout_uri" yaml:"frontchannel_logout_uri"` // 在 SSO 登记的前端通道登出地址
+}
+
+// EndpointConfig SSO 端点配置
+//
+// 配置 `WellKnownURI` 时，未显式配置的端点与签发者会在 `Discover` 时从元数据中补全。
+type EndpointConfig struct {
+	WellKnownURI  string `json:"well_known_uri" yaml:"well_known_uri"` // OpenID Connect 元数据端点
+	Auth          string `json:"auth" yaml:"auth"`                     // 授权端点
+	Token         string `json:"token" yaml:"token"`                   // 令牌端点
+	Userinfo      string `json:"userinfo" yaml:"userinfo"`             // 用户信息端点
+	Introspection string `json:"introspection" yaml:"introspection"`   // 令牌自省端点
+	Revocation    string `json:"revocation" yaml:"revocation"`         // 令牌注销端点
+	JWKS          string `json:"jwks" yaml:"jwks"`                     // 签名公钥集端点
+	Issuer        string `json:"issuer" yaml:"issuer"`                 // 签发者标识（iss）
+
+	RefreshInterval Duration `json:"refresh_interval,omitempty" yaml:"refresh_interval,omitempty"` // 元数据后台刷新间隔，0 表示不刷新
+
+	FrontchannelLogoutSupported bool `json:"-" yaml:"-"` // 元数据是否声明支持前端通道登出，未使用自动发现时视为支持
+}
+
+// CacheConfig 缓存配置
+//
+// `KeyPrefix` 仅对命名身份提供方生效，用于隔离各提供方的授权 State 等缓存。
+// `Entries` 仅对根配置生效，在 SDK 组件创建时读取，不随热更新变化；名称与取值范围由 `bSdkCache.LoadCacheConfigs` 校验。
+// `Local` 仅对根配置生效，在创建状态存储时包装为该存储实例的一级缓存（参见 `bSdkCache.NewLocalStore`），不随热更新变化。
+type CacheConfig struct {
+	Business  bool                        `json:"business" yaml:"business"`                         // Userinfo 与 Introspection 缓存开关
+	FetchLock bool                        `json:"fetch_lock" yaml:"fetch_lock"`                     // 是否通过分布式锁合并跨实例的上游请求
+	KeyPrefix string                      `json:"key_prefix,omitempty" yaml:"key_prefix,omitempty"` // 命名身份提供方的缓存键前缀，默认为 `<名称>:`
+	Entries   map[string]CacheEntryConfig `json:"entries,omitempty" yaml:"entries,omitempty"`       // 单个缓存的覆盖配置，键为小写的缓存名称（如 `token`）
+	Local     LocalCacheConfig            `json:"local" yaml:"local"`                               // 进程内一级缓存
+}
+
+// CacheEntryConfig 单个缓存的覆盖配置，未设置的字段使用 SDK 默认值
+type CacheEntryConfig struct {
+	Enabled         *bool     `json:"enabled,omitempty" yaml:"enabled,omitempty"`                       // 是否启用，仅 Userinfo 与 Introspection 可关闭，默认跟随 `business`
+	TTL             Duration  `json:"ttl,omitempty" yaml:"ttl,omitempty"`                               // 默认有效期
+	MaxTTL          *Duration `json:"max_ttl,omitempty" yaml:"max_ttl,omitempty"`                       // 动态有效期上限，0 表示不限制
+	Prefix          string    `json:"prefix,omitempty" yaml:"prefix,omitempty"`                         // 键前缀，为空时使用 `xEnv.NoSqlPrefix`
+	StaleTTL        Duration  `json:"stale_ttl,omitempty" yaml:"stale_ttl,omitempty"`                   // 过期后仍可直接返回并后台刷新的时长，仅 Userinfo 支持
+	StaleIfErrorTTL Duration  `json:"stale_if_error_ttl,omitempty" yaml:"stale_if_error_ttl,omitempty"` // 过期后上游不可用时仍可返回的时长，仅 Userinfo 支持
+}
+
+// LocalCacheConfig 进程内一级缓存配置
+type LocalCacheConfig struct {
+	Enabled bool     `json:"enabled" yaml:"enabled"` // 是否在状态存储前启用进程内 LRU 缓存
+	Size    int      `json:"size" yaml:"size"`       // 每类缓存的最大条目数
+	TTL     Duration `json:"ttl" yaml:"ttl"`         // 条目有效期
+}
+
+// StorageConfig 状态存储配置，仅对根配置生效，在启动时读取，不随热更新变化
+type StorageConfig struct {
+	Driver        string   `json:"driver" yaml:"driver"`                 // 状态存储驱动（redis/memory/gorm）
+	PurgeInterval Duration `json:"purge_interval" yaml:"purge_interval"` // 数据库中过期状态与令牌记录的清理周期，0 表示不清理
+}
+
+// BusinessConfig 业务层无效令牌拦截配置，仅对根配置生效，支持热更新
+type BusinessConfig struct {
+	NegativeCacheTTL      Duration `json:"negative_cache_ttl" yaml:"negative_cache_ttl"`             // 无效令牌负缓存有效期，0 表示关闭
+	InvalidTokenLimit     int64    `json:"invalid_token_limit" yaml:"invalid_token_limit"`           // 限流窗口内允许的无效令牌次数（按 IP 与令牌前缀），0 表示关闭
+	InvalidTokenWindow    Duration `json:"invalid_token_window" yaml:"invalid_token_window"`         // 无效令牌限流窗口
+	InvalidTokenPrefixLen int      `json:"invalid_token_prefix_len" yaml:"invalid_token_prefix_len"` // 按令牌前缀限流时的前缀长度，0 表示仅按 IP 限流
+}
+
+// GrpcConfig gRPC 客户端配置
+type GrpcConfig struct {
+	Host string `json:"host" yaml:"host"` // 主机地址
+	Port string `json:"port" yaml:"port"` // 端口
+}
+
+// HTTPConfig 请求 SSO 的 HTTP 客户端配置
+type HTTPConfig struct {
+	Timeout    Duration `json:"timeout" yaml:"timeout"`         // 单次请求超时，0 表示不限制
+	RetryCount int      `json:"retry_count" yaml:"retry_count"` // 失败重试次数
+	UserAgent  string   `json:"user_agent" yaml:"user_agent"`   // 自定义 User-Agent
+
+	BreakerThreshold int      `json:"breaker_threshold,omitempty" yaml:"breaker_threshold,omitempty"` // 触发熔断的连续失败次数，0 表示不启用
+	BreakerCooldown  Duration `json:"breaker_cooldown,omitempty" yaml:"breaker_cooldown,omitempty"`   // 熔断后的冷却时间，默认 30 秒
+}
+
+// SecretsConfig 客户端密钥读取配置
+type SecretsConfig struct {
+	RefreshInterval Duration `json:"refresh_interval,omitempty" yaml:"refresh_interval,omitempty"` // 重新读取 `secret_ref` 的间隔，0 表示仅在启动与重载时读取
+}
+
+// SessionConfig 本地会话 Cookie 配置
+//
+// 开启 `Cookie` 后，登录回调以访问令牌写入会话 Cookie，`CheckAuth` 在请求头缺少令牌时读取该 Cookie，
+// 并在令牌临近过期时使用服务端保存的刷新令牌自动刷新。未开启时不读取会话 Cookie。
+// 以 Cookie 认证的非安全方法请求需通过来源校验：`Origin`（缺失时为 `Referer`）须为当前主机或 `TrustedOrigins` 之一。
+type SessionConfig struct {
+	Cookie         bool     `json:"cookie" yaml:"cookie"`                                       // 登录回调是否写入会话 Cookie（Cookie 会话模式）
+	CookieName     string   `json:"cookie_name" yaml:"cookie_name"`                             // Cookie 名称
+	CookieDomain   string   `json:"cookie_domain,omitempty" yaml:"cookie_domain,omitempty"`     // Cookie 作用域名，为空时仅对当前主机生效
+	SameSite       string   `json:"same_site" yaml:"same_site"`                                 // 会话 Cookie 的 SameSite 属性（lax/strict/none），默认 lax
+	TrustedOrigins []string `json:"trusted_origins,omitempty" yaml:"trusted_origins,omitempty"` // 除当前主机外允许以 Cookie 发起非安全方法请求的来源（如 `https://app.example.com`）
+	AutoRefresh    bool     `json:"auto_refresh" yaml:"auto_refresh"`                           // `CheckAuth` 是否自动刷新临近过期的 Cookie 会话
+	RefreshSkew    Duration `json:"refresh_skew" yaml:"refresh_skew"`                           // 自动刷新提前量，距过期不足该值时刷新
+}
+
+// TokenConfig 令牌存储与令牌密钥配置
+//
+// 在 SDK 组件创建时读取，不随热更新变化；加密密钥环例外，热更新后按新配置重新加载（参见 `bSdkUtil.ConfigureTokenKeys`）。
+// 令牌指纹（缓存键、会话索引）与缓存密文依赖这里的密钥，同一部署内所有实例需保持一致；
+// 未配置时由客户端密钥派生，轮换客户端密钥后已缓存与持久化的令牌将无法读取，生产环境建议显式配置。
+// 加密密钥按 `KeysFile`、`EncryptionKeys`、`EncryptionKey` 的顺序取第一个已配置的来源。
+type TokenConfig struct {
+	Persistence bool `json:"persistence" yaml:"persistence"` // 是否将令牌持久化到数据库，Redis 作为热缓存
+	LegacyRead  bool `json:"legacy_read" yaml:"legacy_read"` // 是否读取并迁移旧格式（明文键）的令牌缓存，仅在升级后旧缓存过期前开启
+
+	HashKey    string `json:"hash_key,omitempty" yaml:"hash_key,omitempty"`         // 令牌指纹的 HMAC 密钥，不支持热更新
+	HashKeyRef string `json:"hash_key_ref,omitempty" yaml:"hash_key_ref,omitempty"` // HMAC 密钥的引用，由 `SecretProvider` 读取并覆盖 HashKey
+
+	EncryptionKey    string           `json:"encryption_key,omitempty" yaml:"encryption_key,omitempty"`         // 单个加密密钥（Base64，16/24/32 字节），kid 为 `default`
+	EncryptionKeyRef string           `json:"encryption_key_ref,omitempty" yaml:"encryption_key_ref,omitempty"` // 单个加密密钥的引用，由 `SecretProvider` 读取并覆盖 EncryptionKey
+	EncryptionKeys   []TokenKeyConfig `json:"encryption_keys,omitempty" yaml:"encryption_keys,omitempty"`       // 加密密钥环，用于密钥轮换
+	ActiveKeyID      string           `json:"active_key_id,omitempty" yaml:"active_key_id,omitempty"`           // 活动密钥的 kid，默认取密钥环中的第一个
+	KeysFile         string           `json:"keys_file,omitempty" yaml:"keys_file,omitempty"`                   // JSON 密钥环文件路径，适用于挂载 Kubernetes Secret 等场景
+}
+
+// TokenKeyConfig 令牌加密密钥
+type TokenKeyConfig struct {
+	ID  string `json:"id" yaml:"id"`   // 密钥标识（kid），不可包含 "."
+	Key string `json:"key" yaml:"key"` // Base64 编码的 AES 密钥（16/24/32 字节）
+}
+
+// ProbeConfig 启动自检配置，仅对根配置生效，在启动时读取，不随热更新变化
+type ProbeConfig struct {
+	Mode    string   `json:"mode" yaml:"mode"`       // 启动自检模式（off/warn/fail），默认 off
+	Timeout Duration `json:"timeout" yaml:"timeout"` // 单项检查超时，默认 5 秒
+}
+
+// HealthConfig 就绪检查配置，仅对根配置生效，支持热更新
+type HealthConfig struct {
+	Timeout Duration `json:"timeout" yaml:"timeout"` // 就绪检查单项超时，默认 2 秒
+}
+
+// ReloadConfig 配置重新加载设置，仅对根配置生效，在启动时读取，不随热更新变化
+type ReloadConfig struct {
+	OnSighup bool `json:"on_sighup" yaml:"on_sighup"` // 收到 SIGHUP 时是否重新读取配置文件与环境变量
+}
+
+// Default 返回带默认值的配置
+func Default() *Config {
+	return &Config{
+		Scopes: slices.Clone(DefaultScopes),
+		HTTP:   HTTPConfig{Timeout: Duration(time.Duration(bSdkConst.DefaultHTTPTimeout) * time.Second)},
+		Cache: CacheConfig{
+			Local: LocalCacheConfig{
+				Size: bSdkConst.DefaultLocalCacheSize,
+				TTL:  Duration(time.Duration(bSdkConst.DefaultLocalCacheTTL) * time.Second),
+			},
+		},
+		Storage: StorageConfig{
+			Driver:        bSdkConst.DefaultStorage,
+			PurgeInterval: Duration(time.Duration(bSdkConst.DefaultPurgeInterval) * time.Second),
+		},
+		Business: BusinessConfig{
+			NegativeCacheTTL:   Duration(time.Duration(bSdkConst.DefaultNegativeCacheTTL) * time.Second),
+			InvalidTokenWindow: Duration(time.Duration(bSdkConst.DefaultInvalidWindow) * time.Second),
+		},
+		Session: SessionConfig{
+			CookieName:  bSdkConst.DefaultSessionCookieName,
+			SameSite:    bSdkConst.DefaultSessionSameSite,
+			AutoRefresh: true,
+			RefreshSkew: Duration(time.Duration(bSdkConst.DefaultAutoRefreshSkew) * time.Second),
+		},
+		Probe: ProbeConfig{
+			Mode:    bSdkConst.DefaultStartupProbe,
+			Timeout: Duration(time.Duration(bSdkConst.DefaultProbeTimeout) * time.Second),
+		},
+		Health: HealthConfig{
+			Timeout: Duration(time.Duration(bSdkConst.DefaultHealthTimeout) * time.Second),
+		},
+		Endpoints: EndpointConfig{
+			FrontchannelLogoutSupported: true,
+		},
+	}
+}
+
+// Clone 返回配置的深拷贝
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.live = nil
+	clone.Scopes = slices.Clone(c.Scopes)
+	clone.Session.TrustedOrigins = slices.Clone(c.Session.TrustedOrigins)
+	clone.Cache.Entries = maps.Clone(c.Cache.Entries)
+	clone.Token.EncryptionKeys = slices.Clone(c.Token.EncryptionKeys)
+	if c.Providers != nil {
+		clone.Providers = make(map[string]*Config, len(c.Providers))
+		for name, provider := range c.Providers {
+			if provider != nil {
+				clone.Providers[name] = provider.Clone()
+			}
+		}
+	}
+	if c.Tenants != nil {
+		clone.Tenants = make(map[string]*TenantConfig, len(c.Tenants))
+		for name, tenant := range c.Tenants {
+			if tenant != nil {
+				copied := *tenant
+				copied.Hosts = slices.Clone(tenant.Hosts)
+				clone.Tenants[name] = &copied
+			}
+		}
+	}
+	return &clone
+}
+
+// OAuth2 根据配置构建 `oauth2.Config`
+func (c *Config) OAuth2() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     c.Client.ID,
+		ClientSecret: c.Client.Secret,
+		RedirectURL:  c.Client.RedirectURI,
+		Scopes:       slices.Clone(c.Scopes),
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  c.Endpoints.Auth,
+			TokenURL: c.Endpoints.Token,
+		},
+	}
+}
+
+// NewRestyClient 按 HTTP 配置创建请求 SSO 的 resty 客户端，配置了 `BreakerThreshold` 时请求经过按主机区分的熔断器
+func (c *Config) NewRestyClient() *resty.Client {
+	client := resty.New().
+		SetTimeout(c.HTTP.Timeout.Duration()).
+		SetRetryCount(c.HTTP.RetryCount)
+	if c.HTTP.BreakerThreshold > 0 {
+		client.SetTransport(c.HTTP.transport(client.GetClient().Transport))
+	}
+	if c.HTTP.UserAgent != "" {
+		client.SetHeader("User-Agent", c.HTTP.UserAgent)
+	}
+	return client
+}
+
+// NewRestyClientWith 基于调用方提供的标准库客户端创建请求 SSO 的 resty 客户端
+//
+// client 为 nil 时等同于 `NewRestyClient`；否则沿用 client 的超时与传输层（不套用熔断器），仅应用重试次数与 User-Agent，
+// 且不会修改 client 本身。
+func (c *Config) NewRestyClientWith(client *http.Client) *resty.Client {
+	if client == nil {
+		return c.NewRestyClient()
+	}
+	hc := *client
+	restyClient := resty.NewWithClient(&hc).SetRetryCount(c.HTTP.RetryCount)
+	if c.HTTP.UserAgent != "" {
+		restyClient.SetHeader("User-Agent", c.HTTP.UserAgent)
+	}
+	return restyClient
+}
+
+// HTTPClient 按 HTTP 配置创建标准库客户端，用于 `oauth2` 的令牌交换与刷新
+func (c *Config) HTTPClient() *http.Client {
+	return &http.Client{Timeout: c.HTTP.Timeout.Duration(), Transport: c.HTTP.transport(nil)}
+}
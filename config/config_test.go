@@ -0,0 +1,861 @@
+package bSdkConfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+)
+
+func TestConfigValidate(t *testing.T) {
+	t.Run("合法配置", func(t *testing.T) {
+		cfg := New(
+			WithClient("cid", "csecret"),
+			WithRedirectURI("https://app.example.com/callback"),
+			WithEndpoints(EndpointConfig{
+				Auth:          "https://sso.example.com/authorize",
+				Token:         "https://sso.example.com/token",
+				Userinfo:      "https://sso.example.com/userinfo",
+				Introspection: "https://sso.example.com/introspect",
+				Revocation:    "https://sso.example.com/revoke",
+			}),
+			WithGrpc("sso.example.com", "9090"),
+		)
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("期望校验通过: %v", err)
+		}
+		if got := cfg.OAuth2(); got.ClientID != "cid" || len(got.Scopes) != len(DefaultScopes) {
+			t.Fatalf("oauth2 配置不正确: %+v", got)
+		}
+	})
+
+	t.Run("错误汇总", func(t *testing.T) {
+		cfg := New(
+			WithRedirectURI("/callback"),
+			WithScopes(),
+			WithGrpc("sso.example.com", ""),
+			WithHTTPRetry(-1),
+			WithBusiness(BusinessConfig{InvalidTokenLimit: -1}),
+		)
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatalf("期望校验失败")
+		}
+		for _, want := range []string{"client.id 未配置", "client.redirect_uri 必须为", "endpoints.token 未配置", "scopes 不能为空", "grpc.host 与 grpc.port", "http.retry_count", "business.invalid_token_limit"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("错误信息缺少 %q: %v", want, err)
+			}
+		}
+	})
+}
+
+func TestLoadFileAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "sso.yaml")
+	if err := os.WriteFile(yamlPath, []byte("client:\n  id: file-id\n  secret: file-secret\nscopes: [openid]\nhttp:\n  timeout: 3s\ncache:\n  business: true\n"), 0o600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	jsonPath := filepath.Join(dir, "sso.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"client":{"id":"json-id"},"http":{"timeout":5}}`), 0o600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	t.Run("YAML", func(t *testing.T) {
+		cfg, err := LoadFile(yamlPath)
+		if err != nil {
+			t.Fatalf("读取配置文件失败: %v", err)
+		}
+		if cfg.Client.ID != "file-id" || cfg.HTTP.Timeout.Duration() != 3*time.Second || !cfg.Cache.Business || len(cfg.Scopes) != 1 {
+			t.Fatalf("配置不正确: %+v", cfg)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		cfg, err := LoadFile(jsonPath)
+		if err != nil {
+			t.Fatalf("读取配置文件失败: %v", err)
+		}
+		if cfg.Client.ID != "json-id" || cfg.HTTP.Timeout.Duration() != 5*time.Second {
+			t.Fatalf("配置不正确: %+v", cfg)
+		}
+	})
+
+	t.Run("未知字段", func(t *testing.T) {
+		path := filepath.Join(dir, "typo.yaml")
+		_ = os.WriteFile(path, []byte("client:\n  client_id: x\n"), 0o600)
+		if _, err := LoadFile(path); err == nil {
+			t.Fatalf("未知字段应报错")
+		}
+	})
+
+	t.Run("环境变量覆盖配置文件", func(t *testing.T) {
+		t.Setenv("SSO_CONFIG_FILE", yamlPath)
+		t.Setenv("SSO_CLIENT_ID", "env-id")
+		t.Setenv("SSO_SCOPES", "openid profile,email")
+		t.Setenv("SSO_HTTP_RETRY", "abc")
+		t.Setenv("SSO_TOKEN_PERSISTENCE", "true")
+		t.Setenv("SSO_TOKEN_ENCRYPTION_KEYS", "k2:key-b, k1:key-a")
+
+		cfg, err := Load()
+		if err == nil || !strings.Contains(err.Error(), "SSO_HTTP_RETRY") {
+			t.Fatalf("期望非法环境变量报错，实际 %v", err)
+		}
+		if cfg.Client.ID != "env-id" || cfg.Client.Secret != "file-secret" || len(cfg.Scopes) != 3 {
+			t.Fatalf("配置不正确: %+v", cfg)
+		}
+		if !cfg.Token.Persistence || cfg.Token.LegacyRead {
+			t.Fatalf("令牌存储配置不正确: %+v", cfg.Token)
+		}
+		if keys := cfg.Token.EncryptionKeys; len(keys) != 2 || keys[0] != (TokenKeyConfig{ID: "k2", Key: "key-b"}) {
+			t.Fatalf("令牌加密密钥环不正确: %+v", keys)
+		}
+	})
+
+	t.Run("进程内缓存", func(t *testing.T) {
+		t.Setenv("SSO_LOCAL_CACHE", "true")
+		t.Setenv("SSO_LOCAL_CACHE_SIZE", "100")
+		t.Setenv("SSO_LOCAL_CACHE_TTL", "30")
+		cfg, err := LoadEnv()
+		if err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		if want := (LocalCacheConfig{Enabled: true, Size: 100, TTL: Duration(30 * time.Second)}); cfg.Cache.Local != want {
+			t.Fatalf("进程内缓存配置不正确: %+v", cfg.Cache.Local)
+		}
+
+		t.Setenv("SSO_LOCAL_CACHE_SIZE", "many")
+		if _, err = LoadEnv(); err == nil || !strings.Contains(err.Error(), "SSO_LOCAL_CACHE_SIZE") {
+			t.Fatalf("期望非法条目数报错，实际 %v", err)
+		}
+	})
+
+	t.Run("缓存、状态存储与无效令牌拦截", func(t *testing.T) {
+		path := filepath.Join(dir, "settings.yaml")
+		_ = os.WriteFile(path, []byte("cache:\n  entries:\n    token:\n      ttl: 168h\n      prefix: app\nstorage:\n  driver: gorm\nbusiness:\n  invalid_token_limit: 5\n  invalid_token_prefix_len: 8\n"), 0o600)
+		t.Setenv("SSO_CONFIG_FILE", path)
+		t.Setenv("SSO_CACHE_TOKEN_PREFIX", "env:")
+		t.Setenv("SSO_CACHE_USERINFO_STALE_IF_ERROR_TTL", "10m")
+		t.Setenv("SSO_STORAGE", "memory")
+		t.Setenv("SSO_STORAGE_PURGE_INTERVAL", "5m")
+		t.Setenv("SSO_NEGATIVE_CACHE_TTL", "30s")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("读取配置失败: %v", err)
+		}
+		if token := cfg.Cache.Entries["token"]; token.TTL.Duration() != 168*time.Hour || token.Prefix != "env:" {
+			t.Fatalf("令牌缓存配置不正确: %+v", token)
+		}
+		if userinfo := cfg.Cache.Entries["userinfo"]; userinfo.StaleIfErrorTTL.Duration() != 10*time.Minute {
+			t.Fatalf("用户信息缓存配置不正确: %+v", userinfo)
+		}
+		if cfg.Storage.Driver != "memory" || cfg.Storage.PurgeInterval.Duration() != 5*time.Minute {
+			t.Fatalf("状态存储配置应由环境变量覆盖，实际 %+v", cfg.Storage)
+		}
+		want := BusinessConfig{NegativeCacheTTL: Duration(30 * time.Second), InvalidTokenLimit: 5, InvalidTokenWindow: Duration(time.Minute), InvalidTokenPrefixLen: 8}
+		if cfg.Business != want {
+			t.Fatalf("无效令牌拦截配置不正确: %+v", cfg.Business)
+		}
+
+		t.Setenv("SSO_CACHE_TOKEN_TTL", "soon")
+		t.Setenv("SSO_INVALID_TOKEN_LIMIT", "many")
+		if _, err = Load(); err == nil || !strings.Contains(err.Error(), "SSO_CACHE_TOKEN_TTL") || !strings.Contains(err.Error(), "SSO_INVALID_TOKEN_LIMIT") {
+			t.Fatalf("期望非法环境变量报错，实际 %v", err)
+		}
+	})
+
+	t.Run("本地会话", func(t *testing.T) {
+		cfg, err := LoadEnv()
+		if err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		if session := cfg.Session; session.Cookie || session.CookieName != bSdkConst.DefaultSessionCookieName || !session.AutoRefresh ||
+			session.SameSite != bSdkConst.DefaultSessionSameSite || session.RefreshSkew.Duration() != time.Duration(bSdkConst.DefaultAutoRefreshSkew)*time.Second {
+			t.Fatalf("默认会话配置不正确: %+v", session)
+		}
+
+		t.Setenv("SSO_SESSION_COOKIE", "true")
+		t.Setenv("SSO_SESSION_COOKIE_NAME", "app_session")
+		t.Setenv("SSO_SESSION_COOKIE_DOMAIN", "example.com")
+		t.Setenv("SSO_SESSION_COOKIE_SAMESITE", "strict")
+		t.Setenv("SSO_SESSION_TRUSTED_ORIGINS", "https://app.example.com, https://admin.example.com")
+		t.Setenv("SSO_AUTO_REFRESH", "false")
+		t.Setenv("SSO_AUTO_REFRESH_SKEW", "120")
+		cfg, err = LoadEnv()
+		if err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		want := SessionConfig{
+			Cookie:         true,
+			CookieName:     "app_session",
+			CookieDomain:   "example.com",
+			SameSite:       "strict",
+			TrustedOrigins: []string{"https://app.example.com", "https://admin.example.com"},
+			RefreshSkew:    Duration(2 * time.Minute),
+		}
+		if !reflect.DeepEqual(cfg.Session, want) {
+			t.Fatalf("会话配置不正确: %+v", cfg.Session)
+		}
+
+		t.Setenv("SSO_AUTO_REFRESH_SKEW", "-5")
+		if cfg, err = LoadEnv(); err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		if err = cfg.Validate(); err == nil || !strings.Contains(err.Error(), "session.refresh_skew") {
+			t.Fatalf("期望负数提前量校验失败，实际 %v", err)
+		}
+
+		t.Setenv("SSO_SESSION_COOKIE_SAMESITE", "always")
+		t.Setenv("SSO_SESSION_TRUSTED_ORIGINS", "app.example.com")
+		if cfg, err = LoadEnv(); err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		err = cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), "session.same_site") || !strings.Contains(err.Error(), "session.trusted_origins") {
+			t.Fatalf("期望 SameSite 与可信来源校验失败，实际 %v", err)
+		}
+	})
+
+	t.Run("启动自检", func(t *testing.T) {
+		path := filepath.Join(dir, "probe.yaml")
+		_ = os.WriteFile(path, []byte("probe:\n  mode: warn\n  timeout: 3s\n"), 0o600)
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("读取配置文件失败: %v", err)
+		}
+		if want := (ProbeConfig{Mode: "warn", Timeout: Duration(3 * time.Second)}); cfg.Probe != want {
+			t.Fatalf("启动自检配置不正确: %+v", cfg.Probe)
+		}
+
+		t.Setenv("SSO_STARTUP_PROBE", "fail")
+		t.Setenv("SSO_STARTUP_PROBE_TIMEOUT", "10")
+		if cfg, err = LoadEnv(); err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		if want := (ProbeConfig{Mode: "fail", Timeout: Duration(10 * time.Second)}); cfg.Probe != want {
+			t.Fatalf("启动自检配置应由环境变量覆盖，实际 %+v", cfg.Probe)
+		}
+
+		t.Setenv("SSO_STARTUP_PROBE", "strict")
+		t.Setenv("SSO_STARTUP_PROBE_TIMEOUT", "0")
+		if cfg, err = LoadEnv(); err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		err = cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), "probe.mode") || !strings.Contains(err.Error(), "probe.timeout") {
+			t.Fatalf("期望启动自检配置校验失败，实际 %v", err)
+		}
+	})
+
+	t.Run("就绪检查", func(t *testing.T) {
+		cfg, err := LoadEnv()
+		if err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		if cfg.Health.Timeout.Duration() != time.Duration(bSdkConst.DefaultHealthTimeout)*time.Second {
+			t.Fatalf("默认就绪检查超时不正确: %v", cfg.Health.Timeout.Duration())
+		}
+
+		t.Setenv("SSO_HEALTH_TIMEOUT", "500ms")
+		if cfg, err = LoadEnv(); err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		if cfg.Health.Timeout.Duration() != 500*time.Millisecond {
+			t.Fatalf("就绪检查超时应由环境变量覆盖，实际 %v", cfg.Health.Timeout.Duration())
+		}
+
+		t.Setenv("SSO_HEALTH_TIMEOUT", "-1")
+		if cfg, err = LoadEnv(); err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		if err = cfg.Validate(); err == nil || !strings.Contains(err.Error(), "health.timeout") {
+			t.Fatalf("期望就绪检查超时校验失败，实际 %v", err)
+		}
+	})
+
+	t.Run("SIGHUP 重新加载", func(t *testing.T) {
+		path := filepath.Join(dir, "reload.yaml")
+		_ = os.WriteFile(path, []byte("reload:\n  on_sighup: true\n"), 0o600)
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("读取配置文件失败: %v", err)
+		}
+		if !cfg.Reload.OnSighup {
+			t.Fatalf("应从配置文件读取 reload.on_sighup")
+		}
+
+		t.Setenv("SSO_RELOAD_ON_SIGHUP", "yes")
+		if _, err = LoadEnv(); err == nil || !strings.Contains(err.Error(), "SSO_RELOAD_ON_SIGHUP") {
+			t.Fatalf("期望非法布尔值报错，实际 %v", err)
+		}
+		t.Setenv("SSO_RELOAD_ON_SIGHUP", "true")
+		if cfg, err = LoadEnv(); err != nil || !cfg.Reload.OnSighup {
+			t.Fatalf("应由环境变量开启 SIGHUP 重新加载: %v", err)
+		}
+	})
+}
+
+func TestProcessConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sso.yaml")
+	if err := os.WriteFile(path, []byte("client:\n  id: file-id\n"), 0o600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	t.Setenv("SSO_CONFIG_FILE", path)
+
+	first, err := Process()
+	if err != nil {
+		t.Fatalf("读取进程级配置失败: %v", err)
+	}
+	if first.Client.ID != "file-id" {
+		t.Fatalf("期望读取配置文件，实际 %q", first.Client.ID)
+	}
+
+	t.Run("环境变量未变化时复用快照", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte("client:\n  id: changed-id\n"), 0o600); err != nil {
+			t.Fatalf("写入配置文件失败: %v", err)
+		}
+		cfg, _ := Process()
+		if cfg != first {
+			t.Fatalf("环境变量未变化时应返回同一快照")
+		}
+	})
+
+	t.Run("环境变量变化时重新读取", func(t *testing.T) {
+		t.Setenv("SSO_CLIENT_SECRET", "env-secret")
+		cfg, _ := Process()
+		if cfg == first || cfg.Client.ID != "changed-id" || cfg.Client.Secret != "env-secret" {
+			t.Fatalf("环境变量变化后应重新读取，实际 %+v", cfg.Client)
+		}
+	})
+}
+
+func TestConfigDiscover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"authorization_endpoint":"https://sso.example.com/authorize","token_endpoint":"https://sso.example.com/token","issuer":"https://sso.example.com"}`))
+	}))
+	defer srv.Close()
+
+	cfg := New(
+		WithWellKnown(srv.URL),
+		WithEndpoints(EndpointConfig{Token: "https://override.example.com/token"}),
+	)
+	if err := cfg.Discover(context.Background()); err != nil {
+		t.Fatalf("自动发现失败: %v", err)
+	}
+	if cfg.Endpoints.Auth != "https://sso.example.com/authorize" || cfg.Endpoints.Issuer != "https://sso.example.com" {
+		t.Fatalf("未补全端点: %+v", cfg.Endpoints)
+	}
+	if cfg.Endpoints.Token != "https://override.example.com/token" {
+		t.Fatalf("显式配置的端点不应被覆盖: %s", cfg.Endpoints.Token)
+	}
+	if cfg.Endpoints.FrontchannelLogoutSupported {
+		t.Fatalf("元数据未声明时应视为不支持前端通道登出")
+	}
+}
+
+func TestCheckFrontChannelLogoutURI(t *testing.T) {
+	cases := []struct {
+		name    string
+		uri     string
+		wantErr bool
+	}{
+		{name: "合法地址", uri: "https://app.example.com/sso/oauth/frontchannel-logout", wantErr: false},
+		{name: "携带查询参数", uri: "https://app.example.com/api/sso/oauth/frontchannel-logout?tenant=a", wantErr: false},
+		{name: "相对地址", uri: "/sso/oauth/frontchannel-logout", wantErr: true},
+		{name: "包含片段", uri: "https://app.example.com/sso/oauth/frontchannel-logout#x", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := CheckFrontChannelLogoutURI(tc.uri)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("期望错误: %v，实际: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigProviders(t *testing.T) {
+	partnerEndpoints := EndpointConfig{
+		Auth:   "https://partner.example.com/authorize",
+		Token:  "https://partner.example.com/token",
+		Issuer: "https://partner.example.com",
+	}
+	base := []Option{
+		WithClient("cid", "csecret"),
+		WithRedirectURI("https://app.example.com/callback"),
+		WithEndpoints(EndpointConfig{
+			Auth:          "https://sso.example.com/authorize",
+			Token:         "https://sso.example.com/token",
+			Userinfo:      "https://sso.example.com/userinfo",
+			Introspection: "https://sso.example.com/introspect",
+			Revocation:    "https://sso.example.com/revoke",
+		}),
+		WithScopes("openid", "profile"),
+	}
+
+	t.Run("继承根配置", func(t *testing.T) {
+		cfg := New(append(base, WithProvider("partner",
+			WithClient("pid", "psecret"),
+			WithRedirectURI("https://app.example.com/sso/oauth/partner/callback"),
+			WithEndpoints(partnerEndpoints),
+			WithScopes(),
+		))...)
+		if err := cfg.Resolve(context.Background()); err != nil {
+			t.Fatalf("期望校验通过: %v", err)
+		}
+		provider, ok := cfg.Provider("partner")
+		if !ok {
+			t.Fatalf("提供方未注册")
+		}
+		if len(provider.Scopes) != 2 || provider.Scopes[1] != "profile" {
+			t.Fatalf("提供方未继承授权范围: %v", provider.Scopes)
+		}
+		if got := cfg.ProviderKeyPrefix("partner"); got != "partner:" {
+			t.Fatalf("默认键前缀不正确: %s", got)
+		}
+		if clone := cfg.Clone(); clone.Providers["partner"] == provider {
+			t.Fatalf("Clone 应深拷贝提供方配置")
+		}
+	})
+
+	t.Run("错误汇总", func(t *testing.T) {
+		cfg := New(append(base,
+			WithProvider("partner", WithClient("pid", "psecret")),
+			WithProvider("login", WithClient("pid", "psecret")),
+			WithProvider("Bad Name"),
+		)...)
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatalf("期望校验失败")
+		}
+		for _, want := range []string{
+			"providers.partner.client.redirect_uri 未配置",
+			"providers.partner.endpoints.issuer 未配置",
+			`providers 名称 "login" 与内置路由冲突`,
+			`providers 名称非法: "Bad Name"`,
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("错误信息缺少 %q: %v", want, err)
+			}
+		}
+		if strings.Contains(err.Error(), "providers.partner.endpoints.introspection") {
+			t.Fatalf("命名身份提供方不应要求自省端点: %v", err)
+		}
+	})
+}
+
+func TestConfigTenants(t *testing.T) {
+	base := []Option{
+		WithClient("cid", "csecret"),
+		WithRedirectURI("https://app.example.com/callback"),
+		WithEndpoints(EndpointConfig{
+			Auth:          "https://sso.example.com/authorize",
+			Token:         "https://sso.example.com/token",
+			Userinfo:      "https://sso.example.com/userinfo",
+			Introspection: "https://sso.example.com/introspect",
+			Revocation:    "https://sso.example.com/revoke",
+		}),
+	}
+	shopA := TenantConfig{
+		Client:     ClientConfig{ID: "aid", Secret: "asecret", RedirectURI: "https://a.example.com/callback"},
+		Hosts:      []string{"a.example.com"},
+		PathPrefix: "/m/shop",
+	}
+	shopB := TenantConfig{
+		Client:     ClientConfig{ID: "bid", Secret: "bsecret", RedirectURI: "https://b.example.com/callback"},
+		PathPrefix: "/m/shop/b",
+		KeyPrefix:  "b:",
+	}
+
+	t.Run("解析租户", func(t *testing.T) {
+		cfg := New(append(base, WithTenant("shop-a", shopA), WithTenant("shop-b", shopB))...)
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("期望校验通过: %v", err)
+		}
+		for target, want := range map[string]string{
+			"http://A.example.com:8080/any": "shop-a",
+			"http://x.example.com/m/shop/b": "shop-b",
+			"http://x.example.com/m/shop/c": "shop-a",
+			"http://x.example.com/m/shopx":  "",
+		} {
+			if got := cfg.ResolveTenant(httptest.NewRequest(http.MethodGet, target, nil)); got != want {
+				t.Fatalf("%s 解析结果不正确: %q", target, got)
+			}
+		}
+
+		tenant, ok := cfg.Tenant("shop-b")
+		if !ok || tenant.Client.ID != "bid" || tenant.Endpoints.Token != "https://sso.example.com/token" {
+			t.Fatalf("租户配置不正确: %+v", tenant)
+		}
+		if len(tenant.Tenants) != 0 {
+			t.Fatalf("租户配置不应包含租户")
+		}
+		if got := cfg.TenantKeyPrefix("shop-a"); got != "tenant:shop-a:" {
+			t.Fatalf("默认命名空间不正确: %s", got)
+		}
+		if got := cfg.TenantKeyPrefix("shop-b"); got != "b:" {
+			t.Fatalf("自定义命名空间不正确: %s", got)
+		}
+	})
+
+	t.Run("自定义解析", func(t *testing.T) {
+		cfg := New(append(base, WithTenant("shop-a", shopA), WithTenantResolver(func(r *http.Request) string {
+			return r.Header.Get("X-Tenant")
+		}))...)
+		req := httptest.NewRequest(http.MethodGet, "http://a.example.com/", nil)
+		if got := cfg.ResolveTenant(req); got != "" {
+			t.Fatalf("自定义解析应覆盖域名匹配: %q", got)
+		}
+		req.Header.Set("X-Tenant", "shop-a")
+		if got := cfg.ResolveTenant(req); got != "shop-a" {
+			t.Fatalf("自定义解析结果不正确: %q", got)
+		}
+	})
+
+	t.Run("错误汇总", func(t *testing.T) {
+		dup := shopB
+		dup.Hosts = []string{"A.EXAMPLE.COM"}
+		dup.PathPrefix = "m"
+		cfg := New(append(base, WithTenant("shop-a", shopA), WithTenant("shop-b", dup), WithTenant("Bad", TenantConfig{}))...)
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatalf("期望校验失败")
+		}
+		for _, want := range []string{
+			`tenants 名称非法: "Bad"`,
+			"tenants.Bad.client.id 未配置",
+			"tenants.shop-b.hosts 与 tenants.shop-a 重复",
+			"tenants.shop-b.path_prefix 必须以 / 开头",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("错误信息缺少 %q: %v", want, err)
+			}
+		}
+	})
+}
+
+func TestReloader(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		tokenURI    = "https://sso.example.com/token"
+		etag        = `"v1"`
+		notModified int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		_, _ = fmt.Fprintf(w, `{"authorization_endpoint":"https://sso.example.com/authorize","token_endpoint":%q,"userinfo_endpoint":"https://sso.example.com/userinfo","introspection_endpoint":"https://sso.example.com/introspect","revocation_endpoint":"https://sso.example.com/revoke","issuer":"https://sso.example.com"}`, tokenURI)
+	}))
+	defer srv.Close()
+
+	secret := "csecret"
+	var providers []Option
+	reloader, err := NewReloader(context.Background(), func() (*Config, error) {
+		return New(append([]Option{
+			WithClient("cid", secret),
+			WithRedirectURI("https://app.example.com/callback"),
+			WithEndpoints(EndpointConfig{WellKnownURI: srv.URL, RefreshInterval: Duration(time.Hour)}),
+		}, providers...)...), nil
+	})
+	if err != nil {
+		t.Fatalf("创建热更新器失败: %v", err)
+	}
+	handle := reloader.Config()
+	if handle.Reloader() != reloader || handle.Current().Endpoints.Token != tokenURI {
+		t.Fatalf("配置句柄不正确: %+v", handle.Current().Endpoints)
+	}
+	if got := reloader.nextRefresh(); got != time.Minute {
+		t.Fatalf("应按 Cache-Control 缩短刷新间隔: %s", got)
+	}
+
+	t.Run("元数据未变化", func(t *testing.T) {
+		before := handle.Current()
+		changed, err := reloader.Refresh(context.Background())
+		if err != nil || changed {
+			t.Fatalf("期望未替换: %v %v", changed, err)
+		}
+		if notModified != 1 || handle.Current() != before {
+			t.Fatalf("应使用条件请求并保留快照: %d", notModified)
+		}
+	})
+
+	t.Run("元数据变化", func(t *testing.T) {
+		mu.Lock()
+		tokenURI, etag = "https://sso.example.com/v2/token", `"v2"`
+		mu.Unlock()
+
+		before := handle.Current()
+		changed, err := reloader.Refresh(context.Background())
+		if err != nil || !changed {
+			t.Fatalf("期望替换快照: %v %v", changed, err)
+		}
+		if handle.Current().Endpoints.Token != tokenURI || handle.CurrentOAuth2().Endpoint.TokenURL != tokenURI {
+			t.Fatalf("快照未更新: %+v", handle.Current().Endpoints)
+		}
+		if before.Endpoints.Token != "https://sso.example.com/token" {
+			t.Fatalf("旧快照不应被修改")
+		}
+	})
+
+	t.Run("重载轮换密钥", func(t *testing.T) {
+		secret = "rotated"
+		if err := reloader.Reload(context.Background()); err != nil {
+			t.Fatalf("重载失败: %v", err)
+		}
+		if handle.Current().Client.Secret != "rotated" || handle.CurrentOAuth2().ClientSecret != "rotated" {
+			t.Fatalf("密钥未轮换")
+		}
+	})
+
+	t.Run("拒绝变更提供方集合", func(t *testing.T) {
+		providers = []Option{WithProvider("partner",
+			WithClient("pid", "psecret"),
+			WithRedirectURI("https://app.example.com/sso/oauth/partner/callback"),
+			WithEndpoints(EndpointConfig{
+				Auth:   "https://partner.example.com/authorize",
+				Token:  "https://partner.example.com/token",
+				Issuer: "https://partner.example.com",
+			}),
+		)}
+		err := reloader.Reload(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "providers 的名称集合不支持热更新") {
+			t.Fatalf("期望拒绝重载: %v", err)
+		}
+		if len(handle.Current().Providers) != 0 {
+			t.Fatalf("失败的重载不应替换快照")
+		}
+		if status := reloader.Status(); status.LastError == "" || status.RefreshedAt.After(status.FailedAt) {
+			t.Fatalf("热更新状态应记录失败: %+v", status)
+		}
+	})
+}
+
+// mapSecretProvider 测试用的密钥提供者。
+type mapSecretProvider map[string]string
+
+func (p mapSecretProvider) LoadSecret(_ context.Context, ref string) (string, error) {
+	if secret, ok := p[ref]; ok {
+		return secret, nil
+	}
+	return "", fmt.Errorf("密钥不存在: %s", ref)
+}
+
+func TestSecretRef(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "client-secret")
+	if err := os.WriteFile(path, []byte("v1\n"), 0o600); err != nil {
+		t.Fatalf("写入密钥文件失败: %v", err)
+	}
+	endpoints := EndpointConfig{
+		Auth:          "https://sso.example.com/authorize",
+		Token:         "https://sso.example.com/token",
+		Userinfo:      "https://sso.example.com/userinfo",
+		Introspection: "https://sso.example.com/introspect",
+		Revocation:    "https://sso.example.com/revoke",
+	}
+
+	t.Run("文件轮换", func(t *testing.T) {
+		reloader, err := NewReloader(context.Background(), func() (*Config, error) {
+			return New(
+				WithClient("cid", ""),
+				WithClientSecretRef(path),
+				WithSecretRefresh(time.Minute),
+				WithRedirectURI("https://app.example.com/callback"),
+				WithEndpoints(endpoints),
+			), nil
+		})
+		if err != nil {
+			t.Fatalf("创建热更新器失败: %v", err)
+		}
+		handle := reloader.Config()
+		if handle.Current().Client.Secret != "v1" || !handle.Current().refreshable() {
+			t.Fatalf("应从文件读取密钥: %q", handle.Current().Client.Secret)
+		}
+
+		if err = os.WriteFile(path, []byte("v2"), 0o600); err != nil {
+			t.Fatalf("写入密钥文件失败: %v", err)
+		}
+		changed, err := reloader.Refresh(context.Background())
+		if err != nil || !changed || handle.CurrentOAuth2().ClientSecret != "v2" {
+			t.Fatalf("刷新后应使用新密钥: %v %v", changed, err)
+		}
+	})
+
+	t.Run("自定义提供者", func(t *testing.T) {
+		cfg := New(
+			WithClient("cid", ""),
+			WithClientSecretRef("vault:sso"),
+			WithSecretProvider(mapSecretProvider{"vault:sso": "root", "vault:shop-a": "tenant", "vault:hash": "hash-key"}),
+			WithRedirectURI("https://app.example.com/callback"),
+			WithEndpoints(endpoints),
+			WithTenant("shop-a", TenantConfig{
+				Client: ClientConfig{ID: "shop-a", SecretRef: "vault:shop-a", RedirectURI: "https://a.example.com/callback"},
+				Hosts:  []string{"a.example.com"},
+			}),
+		)
+		cfg.Token.HashKeyRef = "vault:hash"
+		if err := cfg.Resolve(context.Background()); err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		if cfg.Client.Secret != "root" || cfg.Tenants["shop-a"].Client.Secret != "tenant" || cfg.Token.HashKey != "hash-key" {
+			t.Fatalf("密钥读取不正确: %q %q %q", cfg.Client.Secret, cfg.Tenants["shop-a"].Client.Secret, cfg.Token.HashKey)
+		}
+
+		cfg = New(WithClientSecretRef("vault:missing"), WithSecretProvider(mapSecretProvider{}))
+		if err := cfg.Resolve(context.Background()); err == nil || !strings.Contains(err.Error(), "client.secret_ref 读取失败") {
+			t.Fatalf("期望读取失败: %v", err)
+		}
+	})
+
+	t.Run("环境变量与日志脱敏", func(t *testing.T) {
+		t.Setenv(bSdkConst.EnvSsoClientSecret.String(), "plain")
+		t.Setenv(bSdkConst.EnvSsoClientSecretFile.String(), path)
+		if _, err := LoadEnv(); err == nil || !strings.Contains(err.Error(), "不能同时设置") {
+			t.Fatalf("期望拒绝同时设置: %v", err)
+		}
+		t.Setenv(bSdkConst.EnvSsoClientSecret.String(), "")
+		cfg, err := LoadEnv()
+		if err != nil || cfg.Client.SecretRef != path {
+			t.Fatalf("应读取密钥文件路径: %v", err)
+		}
+
+		var buf strings.Builder
+		cfg.Client.Secret = "plain"
+		cfg.Token.HashKey = "plain"
+		slog.New(slog.NewTextHandler(&buf, nil)).Info("client", slog.Any("client", cfg.Client), slog.Any("token", cfg.Token))
+		if strings.Contains(buf.String(), "plain") || !strings.Contains(buf.String(), Redacted) {
+			t.Fatalf("日志应隐藏密钥: %s", buf.String())
+		}
+	})
+}
+
+func TestBreaker(t *testing.T) {
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := New(WithHTTPBreaker(2, 50*time.Millisecond))
+	client := cfg.NewRestyClient()
+	host := strings.TrimPrefix(srv.URL, "http://")
+	state := func() BreakerState {
+		for _, status := range Breakers() {
+			if status.Host == host {
+				return status.State
+			}
+		}
+		return ""
+	}
+
+	fail.Store(true)
+	for range 2 {
+		if _, err := client.R().Get(srv.URL); err != nil {
+			t.Fatalf("熔断前不应拒绝请求: %v", err)
+		}
+	}
+	if state() != BreakerOpen {
+		t.Fatalf("连续失败后应熔断: %s", state())
+	}
+	if _, err := cfg.HTTPClient().Get(srv.URL); !errors.Is(err, ErrBreakerOpen) {
+		t.Fatalf("熔断期间应拒绝请求: %v", err)
+	}
+
+	fail.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	if state() != BreakerHalfOpen {
+		t.Fatalf("冷却结束后应报告半开: %s", state())
+	}
+	if _, err := client.R().Get(srv.URL); err != nil {
+		t.Fatalf("冷却结束后应放行试探请求: %v", err)
+	}
+	if state() != BreakerClosed {
+		t.Fatalf("试探成功后应关闭: %s", state())
+	}
+}
+
+func TestProviderMetadata(t *testing.T) {
+	var document string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(strings.ReplaceAll(document, "{{issuer}}", "http://"+r.Host)))
+	}))
+	defer srv.Close()
+
+	discover := func(doc string) (*Config, error) {
+		document = doc
+		cfg := New(WithWellKnown(srv.URL + "/.well-known/openid-configuration"))
+		return cfg, cfg.Discover(context.Background())
+	}
+
+	t.Run("能力报告", func(t *testing.T) {
+		cfg, err := discover(`{"issuer":"{{issuer}}","authorization_endpoint":"https://sso.example.com/authorize",` +
+			`"grant_types_supported":["authorization_code","refresh_token"],"code_challenge_methods_supported":["plain","S256"],` +
+			`"end_session_endpoint":"https://sso.example.com/logout","backchannel_logout_supported":true,` +
+			`"token_endpoint_auth_methods_supported":["client_secret_basic"]}`)
+		if err != nil {
+			t.Fatalf("自动发现失败: %v", err)
+		}
+		if cfg.Metadata == nil || cfg.Endpoints.Issuer != srv.URL {
+			t.Fatalf("元数据未保存: %+v", cfg.Endpoints)
+		}
+		capabilities := cfg.Capabilities()
+		if !capabilities.Discovered || !capabilities.PKCES256 || !capabilities.RefreshToken || capabilities.ClientCredentials ||
+			!capabilities.EndSession || !capabilities.BackchannelLogout || capabilities.PushedAuthorization {
+			t.Fatalf("能力报告不正确: %+v", capabilities)
+		}
+	})
+
+	for name, tc := range map[string]struct {
+		document string
+		want     string
+	}{
+		"字段类型非法":   {`{"issuer":"{{issuer}}","grant_types_supported":"authorization_code"}`, "元数据格式非法"},
+		"缺少签发者":    {`{"authorization_endpoint":"https://sso.example.com/authorize"}`, "缺少 issuer"},
+		"签发者不匹配":   {`{"issuer":"https://evil.example.com"}`, "issuer 与元数据端点不匹配"},
+		"不支持 S256": {`{"issuer":"{{issuer}}","code_challenge_methods_supported":["plain"]}`, "不包含 S256"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := discover(tc.document); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("错误信息缺少 %q: %v", tc.want, err)
+			}
+		})
+	}
+
+	for uri, want := range map[string]string{
+		"https://sso.example.com/.well-known/openid-configuration":              "https://sso.example.com",
+		"https://sso.example.com/realms/a/.well-known/openid-configuration":     "https://sso.example.com/realms/a",
+		"https://sso.example.com/.well-known/oauth-authorization-server/tenant": "https://sso.example.com/tenant",
+		"https://sso.example.com/metadata":                                      "",
+	} {
+		if got, _ := issuerFromWellKnown(uri); got != want {
+			t.Fatalf("%s 推导的签发者不正确: %q", uri, got)
+		}
+	}
+}
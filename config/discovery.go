@@ -0,0 +1,151 @@
+package bSdkConfig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metadataCache 元数据的条件请求缓存，按元数据端点保存最近一次的文档与校验器，
+// 供后台刷新时携带 `If-None-Match`/`If-Modified-Since` 请求。
+type metadataCache struct {
+	mu      sync.Mutex
+	entries map[string]*metadataEntry
+}
+
+// metadataEntry 单个元数据端点的缓存条目。
+type metadataEntry struct {
+	metadata     *ProviderMetadata
+	etag         string
+	lastModified string
+	maxAge       time.Duration // Cache-Control 声明的有效期，未声明时为 0
+}
+
+// newMetadataCache 创建元数据条件请求缓存。
+func newMetadataCache() *metadataCache {
+	return &metadataCache{entries: make(map[string]*metadataEntry)}
+}
+
+// Discover 从 OpenID Connect 元数据端点补全未显式配置的端点与签发者
+//
+// 未配置 `WellKnownURI` 时不做任何处理；显式配置的端点优先于元数据。解析后的元数据保存在 `Metadata` 中，
+// 元数据缺少 `issuer`、`issuer` 与元数据端点地址不匹配或声明的 PKCE 方法不包含 S256 时返回错误。
+//
+// 参数:
+//   - ctx: 用于元数据请求的上下文。
+//
+// 返回值:
+//   - error: 元数据请求失败或返回非成功状态码时返回错误。
+func (c *Config) Discover(ctx context.Context) error {
+	_, err := c.discover(ctx, nil)
+	return err
+}
+
+// discover 补全端点与签发者，cache 不为空时使用条件请求，返回元数据声明的有效期。
+func (c *Config) discover(ctx context.Context, cache *metadataCache) (time.Duration, error) {
+	if c.Endpoints.WellKnownURI == "" {
+		return 0, nil
+	}
+
+	entry, err := c.fetchMetadata(ctx, cache)
+	if err != nil {
+		return 0, err
+	}
+
+	metadata := entry.metadata
+	if c.Endpoints.Issuer != "" && !SameIssuer(c.Endpoints.Issuer, metadata.Issuer) {
+		return 0, fmt.Errorf("endpoints.issuer 与元数据不一致: 配置 %q，元数据 %q", c.Endpoints.Issuer, metadata.Issuer)
+	}
+
+	fill := func(target *string, value string) {
+		if *target == "" {
+			*target = value
+		}
+	}
+	fill(&c.Endpoints.Auth, metadata.AuthorizationEndpoint)
+	fill(&c.Endpoints.Token, metadata.TokenEndpoint)
+	fill(&c.Endpoints.Userinfo, metadata.UserinfoEndpoint)
+	fill(&c.Endpoints.Introspection, metadata.IntrospectionEndpoint)
+	fill(&c.Endpoints.Revocation, metadata.RevocationEndpoint)
+	fill(&c.Endpoints.JWKS, metadata.JWKSURI)
+	fill(&c.Endpoints.Issuer, metadata.Issuer)
+	c.Endpoints.FrontchannelLogoutSupported = metadata.FrontchannelLogoutSupported
+	c.Metadata = metadata
+	return entry.maxAge, nil
+}
+
+// fetchMetadata 请求元数据端点，cache 中存在校验器且服务端返回 304 时复用缓存的文档。
+func (c *Config) fetchMetadata(ctx context.Context, cache *metadataCache) (*metadataEntry, error) {
+	uri := c.Endpoints.WellKnownURI
+
+	var cached *metadataEntry
+	if cache != nil {
+		cache.mu.Lock()
+		cached = cache.entries[uri]
+		cache.mu.Unlock()
+	}
+
+	req := c.NewRestyClient().R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json")
+	if cached != nil {
+		if cached.etag != "" {
+			req.SetHeader("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.SetHeader("If-Modified-Since", cached.lastModified)
+		}
+	}
+	resp, err := req.Get(uri)
+	if err != nil {
+		return nil, fmt.Errorf("无法获取 endpoints.well_known_uri 的元数据: %v", err)
+	}
+
+	maxAge := parseMaxAge(resp.Header().Get("Cache-Control"))
+	switch {
+	case resp.StatusCode() == http.StatusNotModified && cached != nil:
+		entry := *cached
+		entry.maxAge = maxAge
+		return &entry, nil
+	case resp.StatusCode() != http.StatusOK:
+		return nil, fmt.Errorf("endpoints.well_known_uri 返回非成功状态码: %d", resp.StatusCode())
+	}
+
+	metadata, err := parseMetadata(resp.Body(), uri)
+	if err != nil {
+		return nil, err
+	}
+	entry := &metadataEntry{
+		metadata:     metadata,
+		etag:         resp.Header().Get("ETag"),
+		lastModified: resp.Header().Get("Last-Modified"),
+		maxAge:       maxAge,
+	}
+	if cache != nil {
+		cache.mu.Lock()
+		cache.entries[uri] = entry
+		cache.mu.Unlock()
+	}
+	return entry, nil
+}
+
+// parseMaxAge 解析 Cache-Control 的 max-age，声明 no-store 或 no-cache 时视为未声明。
+func parseMaxAge(header string) time.Duration {
+	var maxAge time.Duration
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return 0
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && seconds > 0 {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return maxAge
+}
@@ -0,0 +1,50 @@
+package bSdkConfig
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Duration 可从配置文件解析的时长
+//
+// 支持纯数字（按秒处理）或 Go 时长格式（如 `500ms`、`15m`）。
+type Duration time.Duration
+
+// Duration 返回标准库时长
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// MarshalText 以 Go 时长格式输出
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText 解析秒数或 Go 时长格式
+func (d *Duration) UnmarshalText(text []byte) error {
+	value, err := parseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(value)
+	return nil
+}
+
+// UnmarshalJSON 兼容 JSON 数字（秒）与字符串两种写法
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	return d.UnmarshalText(bytes.Trim(data, `"`))
+}
+
+// parseDuration 解析时长，纯数字按秒处理。
+func parseDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("时长格式非法: %q", value)
+	}
+	return duration, nil
+}
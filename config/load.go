@@ -0,0 +1,398 @@
+package bSdkConfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
+	"github.com/goccy/go-yaml"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+)
+
+// LoadEnv 在默认配置的基础上读取环境变量
+//
+// 返回值:
+//   - *Config: 读取后的配置，即使部分环境变量非法也会返回其余已读取的值。
+//   - error: 环境变量格式非法时返回汇总后的错误。
+func LoadEnv() (*Config, error) {
+	cfg := Default()
+	return cfg, cfg.ApplyEnv()
+}
+
+// LoadFile 在默认配置的基础上读取 YAML 或 JSON 配置文件
+//
+// 文件格式按扩展名判断（`.yaml`、`.yml` 或 `.json`），未知字段视为错误，避免拼写错误被静默忽略。
+//
+// 参数:
+//   - path: 配置文件路径。
+//
+// 返回值:
+//   - *Config: 未校验的配置。
+//   - error: 读取或解析失败时返回错误。
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	cfg := Default()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("不支持的配置文件格式: %s（可选 .yaml/.yml/.json）", path)
+	}
+	// JSON 是 YAML 的子集，两种格式使用同一解析器
+	if err = yaml.UnmarshalWithOptions(data, cfg, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Load 按 `SSO_CONFIG_FILE` 读取配置文件（未设置时使用默认配置），再以环境变量覆盖
+//
+// 返回值:
+//   - *Config: 未校验的配置。
+//   - error: 读取文件或环境变量失败时返回错误。
+func Load() (*Config, error) {
+	cfg := Default()
+	if path := xEnv.GetEnvString(bSdkConst.EnvSsoConfigFile, ""); path != "" {
+		loaded, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		cfg = loaded
+	}
+	return cfg, cfg.ApplyEnv()
+}
+
+// processConfig 进程级配置快照，参见 `Process`。
+var processConfig atomic.Pointer[processSnapshot]
+
+// processSnapshot 进程级配置快照及其读取时的环境变量。
+type processSnapshot struct {
+	env string // 读取时 `SSO_` 前缀的环境变量
+	cfg *Config
+	err error
+}
+
+// Process 返回未注册 SDK 配置时使用的进程级配置快照
+//
+// 首次调用时按 `Load` 读取 `SSO_CONFIG_FILE` 与环境变量，并通过密钥提供者读取密钥引用，此后返回同一快照；
+// 仅当 `SSO_` 前缀的环境变量发生变化时重新读取。所有未传入显式配置的 SDK 组件都应通过该函数读取配置，
+// 保证端点、客户端凭证、令牌密钥与缓存配置来自同一来源。返回的配置在进程内共享，调用方不得修改。
+//
+// 返回值:
+//   - *Config: 未校验的配置；读取配置文件失败时为默认配置叠加环境变量。
+//   - error: 读取配置文件、环境变量或密钥引用失败时返回错误。
+func Process() (*Config, error) {
+	env := ssoEnviron()
+	if snapshot := processConfig.Load(); snapshot != nil && snapshot.env == env {
+		return snapshot.cfg, snapshot.err
+	}
+
+	cfg, err := Load()
+	if cfg == nil {
+		cfg, _ = LoadEnv()
+	}
+	if err == nil {
+		err = cfg.ResolveSecrets(context.Background())
+	}
+	processConfig.Store(&processSnapshot{env: env, cfg: cfg, err: err})
+	return cfg, err
+}
+
+// ssoEnviron 返回 `SSO_` 前缀的环境变量，用于判断进程级配置快照是否需要重新读取。
+func ssoEnviron() string {
+	var builder strings.Builder
+	for _, entry := range os.Environ() {
+		if strings.HasPrefix(entry, "SSO_") {
+			builder.WriteString(entry)
+			builder.WriteByte(0)
+		}
+	}
+	return builder.String()
+}
+
+// ApplyEnv 以已设置的环境变量覆盖配置，未设置的环境变量不会改动对应字段
+//
+// 返回值:
+//   - error: 环境变量格式非法时返回汇总后的错误。
+func (c *Config) ApplyEnv() error {
+	setString := func(target *string, key xEnv.EnvKey) {
+		if value := xEnv.GetEnvString(key, ""); value != "" {
+			*target = value
+		}
+	}
+
+	setString(&c.Client.ID, bSdkConst.EnvSsoClientID)
+	setString(&c.Client.RedirectURI, bSdkConst.EnvSsoRedirectURI)
+	setString(&c.Client.FrontchannelLogoutURI, bSdkConst.EnvSsoFrontchannelLogoutURI)
+	setString(&c.Endpoints.WellKnownURI, bSdkConst.EnvSsoWellKnownURI)
+	setString(&c.Endpoints.Auth, bSdkConst.EnvSsoEndpointAuthURI)
+	setString(&c.Endpoints.Token, bSdkConst.EnvSsoEndpointTokenURI)
+	setString(&c.Endpoints.Userinfo, bSdkConst.EnvSsoEndpointUserinfoURI)
+	setString(&c.Endpoints.Introspection, bSdkConst.EnvSsoEndpointIntrospectionURI)
+	setString(&c.Endpoints.Revocation, bSdkConst.EnvSsoEndpointRevocationURI)
+	setString(&c.Endpoints.JWKS, bSdkConst.EnvSsoEndpointJwksURI)
+	setString(&c.Endpoints.Issuer, bSdkConst.EnvSsoIssuer)
+	setString(&c.Grpc.Host, bSdkConst.EnvSsoGrpcHost)
+	setString(&c.Grpc.Port, bSdkConst.EnvSsoGrpcPort)
+	setString(&c.Session.CookieName, bSdkConst.EnvSsoSessionCookieName)
+	setString(&c.Session.CookieDomain, bSdkConst.EnvSsoSessionCookieDomain)
+	setString(&c.Session.SameSite, bSdkConst.EnvSsoSessionCookieSameSite)
+	setString(&c.Storage.Driver, bSdkConst.EnvSsoStorage)
+	setString(&c.Probe.Mode, bSdkConst.EnvSsoStartupProbe)
+
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoScopes, ""); value != "" {
+		c.Scopes = strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoSessionTrustedOrigins, ""); value != "" {
+		c.Session.TrustedOrigins = strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+	}
+
+	var errs []error
+	setSecret := func(value *string, ref *string, key xEnv.EnvKey, fileKey xEnv.EnvKey) {
+		secret := xEnv.GetEnvString(key, "")
+		secretFile := xEnv.GetEnvString(fileKey, "")
+		switch {
+		case secret != "" && secretFile != "":
+			errs = append(errs, fmt.Errorf("%s 与 %s 不能同时设置", key, fileKey))
+		case secret != "":
+			*value, *ref = secret, ""
+		case secretFile != "":
+			*ref = secretFile
+		}
+	}
+	setSecret(&c.Client.Secret, &c.Client.SecretRef, bSdkConst.EnvSsoClientSecret, bSdkConst.EnvSsoClientSecretFile)
+	setSecret(&c.Token.HashKey, &c.Token.HashKeyRef, bSdkConst.EnvSsoTokenHashKey, bSdkConst.EnvSsoTokenHashKeyFile)
+	setSecret(&c.Token.EncryptionKey, &c.Token.EncryptionKeyRef, bSdkConst.EnvSsoTokenEncryptionKey, bSdkConst.EnvSsoTokenEncryptionKeyFile)
+	setString(&c.Token.ActiveKeyID, bSdkConst.EnvSsoTokenEncryptionKeyID)
+	setString(&c.Token.KeysFile, bSdkConst.EnvSsoTokenEncryptionKeysFile)
+
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoTokenEncryptionKeys, ""); value != "" {
+		keys, err := parseTokenKeys(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %w", bSdkConst.EnvSsoTokenEncryptionKeys, err))
+		} else {
+			c.Token.EncryptionKeys = keys
+		}
+	}
+
+	setBool := func(target *bool, key xEnv.EnvKey) {
+		value := xEnv.GetEnvString(key, "")
+		if value == "" {
+			return
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", key, value))
+			return
+		}
+		*target = parsed
+	}
+	setBool(&c.Cache.Business, bSdkConst.EnvSsoBusinessCache)
+	setBool(&c.Cache.FetchLock, bSdkConst.EnvSsoBusinessFetchLock)
+	setBool(&c.Cache.Local.Enabled, bSdkConst.EnvSsoLocalCache)
+	setBool(&c.Token.Persistence, bSdkConst.EnvSsoTokenPersistence)
+	setBool(&c.Token.LegacyRead, bSdkConst.EnvSsoTokenLegacyRead)
+	setBool(&c.Session.Cookie, bSdkConst.EnvSsoSessionCookie)
+	setBool(&c.Session.AutoRefresh, bSdkConst.EnvSsoAutoRefresh)
+	setBool(&c.Reload.OnSighup, bSdkConst.EnvSsoReloadOnSighup)
+
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoHTTPTimeout, ""); value != "" {
+		if err := c.HTTP.Timeout.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoHTTPTimeout, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoWellKnownRefresh, ""); value != "" {
+		if err := c.Endpoints.RefreshInterval.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoWellKnownRefresh, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoSecretRefresh, ""); value != "" {
+		if err := c.Secrets.RefreshInterval.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoSecretRefresh, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoAutoRefreshSkew, ""); value != "" {
+		if err := c.Session.RefreshSkew.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoAutoRefreshSkew, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoStoragePurgeInterval, ""); value != "" {
+		if err := c.Storage.PurgeInterval.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoStoragePurgeInterval, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoStartupProbeTimeout, ""); value != "" {
+		if err := c.Probe.Timeout.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoStartupProbeTimeout, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoHealthTimeout, ""); value != "" {
+		if err := c.Health.Timeout.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoHealthTimeout, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoLocalCacheTTL, ""); value != "" {
+		if err := c.Cache.Local.TTL.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoLocalCacheTTL, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoLocalCacheSize, ""); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoLocalCacheSize, value))
+		} else {
+			c.Cache.Local.Size = size
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoNegativeCacheTTL, ""); value != "" {
+		if err := c.Business.NegativeCacheTTL.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoNegativeCacheTTL, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoInvalidTokenWindow, ""); value != "" {
+		if err := c.Business.InvalidTokenWindow.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoInvalidTokenWindow, value))
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoInvalidTokenLimit, ""); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoInvalidTokenLimit, value))
+		} else {
+			c.Business.InvalidTokenLimit = limit
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoInvalidTokenPrefixLen, ""); value != "" {
+		prefixLen, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoInvalidTokenPrefixLen, value))
+		} else {
+			c.Business.InvalidTokenPrefixLen = prefixLen
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoHTTPRetry, ""); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoHTTPRetry, value))
+		} else {
+			c.HTTP.RetryCount = count
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoHTTPBreakerThreshold, ""); value != "" {
+		threshold, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoHTTPBreakerThreshold, value))
+		} else {
+			c.HTTP.BreakerThreshold = threshold
+		}
+	}
+	if value := xEnv.GetEnvString(bSdkConst.EnvSsoHTTPBreakerCooldown, ""); value != "" {
+		if err := c.HTTP.BreakerCooldown.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s 非法: %q", bSdkConst.EnvSsoHTTPBreakerCooldown, value))
+		}
+	}
+	errs = append(errs, c.applyCacheEnv()...)
+
+	return errors.Join(errs...)
+}
+
+// cacheEnvOptions `SSO_CACHE_<NAME>_<OPTION>` 支持的选项，较长的后缀在前，避免 `_STALE_TTL` 被 `_TTL` 误匹配。
+var cacheEnvOptions = []string{"_STALE_IF_ERROR_TTL", "_STALE_TTL", "_MAX_TTL", "_TTL", "_ENABLED", "_PREFIX"}
+
+// applyCacheEnv 以 `SSO_CACHE_<NAME>_*` 环境变量覆盖 `cache.entries` 中对应缓存的配置。
+//
+// 缓存名称与取值范围由 `bSdkCache.LoadCacheConfigs` 校验，这里仅解析格式。
+func (c *Config) applyCacheEnv() []error {
+	var errs []error
+	for _, item := range os.Environ() {
+		key, value, _ := strings.Cut(item, "=")
+		rest, ok := strings.CutPrefix(key, "SSO_CACHE_")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			continue
+		}
+		for _, option := range cacheEnvOptions {
+			name, found := strings.CutSuffix(rest, option)
+			if !found || name == "" {
+				continue
+			}
+			name = strings.ToLower(name)
+			if c.Cache.Entries == nil {
+				c.Cache.Entries = make(map[string]CacheEntryConfig)
+			}
+			entry := c.Cache.Entries[name]
+			if err := entry.set(option, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s 非法: %q", key, value))
+				break
+			}
+			c.Cache.Entries[name] = entry
+			break
+		}
+	}
+	// os.Environ 的顺序不固定，排序后错误信息稳定
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errs
+}
+
+// set 按环境变量选项设置单个缓存的配置字段。
+func (e *CacheEntryConfig) set(option string, value string) error {
+	var duration Duration
+	switch option {
+	case "_ENABLED":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		e.Enabled = &enabled
+		return nil
+	case "_PREFIX":
+		e.Prefix = value
+		return nil
+	}
+
+	if err := duration.UnmarshalText([]byte(value)); err != nil {
+		return err
+	}
+	switch option {
+	case "_TTL":
+		e.TTL = duration
+	case "_MAX_TTL":
+		e.MaxTTL = &duration
+	case "_STALE_TTL":
+		e.StaleTTL = duration
+	case "_STALE_IF_ERROR_TTL":
+		e.StaleIfErrorTTL = duration
+	}
+	return nil
+}
+
+// parseTokenKeys 解析形如 `kid1:base64,kid2:base64` 的加密密钥列表。
+func parseTokenKeys(value string) ([]TokenKeyConfig, error) {
+	var keys []TokenKeyConfig
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, key, found := strings.Cut(item, ":")
+		if !found {
+			return nil, fmt.Errorf("格式错误，应为 kid:base64")
+		}
+		keys = append(keys, TokenKeyConfig{ID: strings.TrimSpace(id), Key: strings.TrimSpace(key)})
+	}
+	return keys, nil
+}
@@ -0,0 +1,171 @@
+package bSdkConfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// 元数据端点的标准路径，用于从元数据端点地址推导签发者。
+const (
+	wellKnownOpenID = "/.well-known/openid-configuration"
+	wellKnownOAuth  = "/.well-known/oauth-authorization-server"
+)
+
+// ProviderMetadata 身份提供方元数据
+//
+// 对应 OpenID Connect Discovery 1.0 与 RFC 8414 定义的元数据文档，由 `Discover` 解析并经过校验后保存在
+// `Config.Metadata` 中，之后只读。字段类型与规范不符时解析失败，不会被静默忽略。
+type ProviderMetadata struct {
+	Issuer                             string   `json:"issuer"`
+	AuthorizationEndpoint              string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                      string   `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint                   string   `json:"userinfo_endpoint,omitempty"`
+	IntrospectionEndpoint              string   `json:"introspection_endpoint,omitempty"`
+	RevocationEndpoint                 string   `json:"revocation_endpoint,omitempty"`
+	JWKSURI                            string   `json:"jwks_uri,omitempty"`
+	EndSessionEndpoint                 string   `json:"end_session_endpoint,omitempty"`
+	DeviceAuthorizationEndpoint        string   `json:"device_authorization_endpoint,omitempty"`
+	PushedAuthorizationRequestEndpoint string   `json:"pushed_authorization_request_endpoint,omitempty"`
+	RequirePushedAuthorizationRequests bool     `json:"require_pushed_authorization_requests,omitempty"`
+	ScopesSupported                    []string `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported             []string `json:"response_types_supported,omitempty"`
+	GrantTypesSupported                []string `json:"grant_types_supported,omitempty"`
+	CodeChallengeMethodsSupported      []string `json:"code_challenge_methods_supported,omitempty"`
+	TokenEndpointAuthMethodsSupported  []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+	IDTokenSigningAlgValuesSupported   []string `json:"id_token_signing_alg_values_supported,omitempty"`
+	FrontchannelLogoutSupported        bool     `json:"frontchannel_logout_supported,omitempty"`
+	FrontchannelLogoutSessionSupported bool     `json:"frontchannel_logout_session_supported,omitempty"`
+	BackchannelLogoutSupported         bool     `json:"backchannel_logout_supported,omitempty"`
+	BackchannelLogoutSessionSupported  bool     `json:"backchannel_logout_session_supported,omitempty"`
+}
+
+// parseMetadata 解析并校验元数据文档
+//
+// 参数:
+//   - data: 元数据文档。
+//   - wellKnownURI: 元数据端点地址，符合标准路径时用于推导期望的签发者。
+func parseMetadata(data []byte, wellKnownURI string) (*ProviderMetadata, error) {
+	var metadata ProviderMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("endpoints.well_known_uri 的元数据格式非法: %v", err)
+	}
+
+	var errs []error
+	if metadata.Issuer == "" {
+		errs = append(errs, errors.New("缺少 issuer"))
+	} else if expected, ok := issuerFromWellKnown(wellKnownURI); ok && !SameIssuer(expected, metadata.Issuer) {
+		errs = append(errs, fmt.Errorf("issuer 与元数据端点不匹配: 期望 %q，实际 %q", expected, metadata.Issuer))
+	}
+	// 授权码流程固定使用 PKCE S256，声明了支持的方法却不包含 S256 时无法登录
+	if methods := metadata.CodeChallengeMethodsSupported; len(methods) > 0 && !slices.Contains(methods, "S256") {
+		errs = append(errs, fmt.Errorf("code_challenge_methods_supported 不包含 S256: %v", methods))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("endpoints.well_known_uri 的元数据非法: %w", err)
+	}
+	return &metadata, nil
+}
+
+// issuerFromWellKnown 按 OIDC Discovery 1.0 §4 与 RFC 8414 §3 从元数据端点地址推导签发者，
+// 地址不符合标准路径时返回 false。
+func issuerFromWellKnown(uri string) (string, bool) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return "", false
+	}
+
+	path := parsed.Path
+	switch {
+	case strings.HasPrefix(path, wellKnownOAuth):
+		path = strings.TrimPrefix(path, wellKnownOAuth)
+	case strings.HasPrefix(path, wellKnownOpenID+"/"):
+		path = strings.TrimPrefix(path, wellKnownOpenID)
+	case strings.HasSuffix(path, wellKnownOpenID):
+		path = strings.TrimSuffix(path, wellKnownOpenID)
+	default:
+		return "", false
+	}
+	return parsed.Scheme + "://" + parsed.Host + path, true
+}
+
+// SameIssuer 判断签发者是否一致，忽略末尾的 `/`
+func SameIssuer(a string, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+// SupportsGrantType 判断是否支持指定的授权类型，未声明时按 RFC 8414 视为仅支持 authorization_code 与 implicit
+func (m *ProviderMetadata) SupportsGrantType(grantType string) bool {
+	if len(m.GrantTypesSupported) == 0 {
+		return grantType == "authorization_code" || grantType == "implicit"
+	}
+	return slices.Contains(m.GrantTypesSupported, grantType)
+}
+
+// Capabilities 身份提供方能力报告
+//
+// 配置了元数据端点时根据元数据计算；否则仅根据显式配置的端点推断，登出能力视为支持。
+type Capabilities struct {
+	Discovered               bool     `json:"discovered"`                   // 是否来自元数据
+	PKCES256                 bool     `json:"pkce_s256"`                    // 声明支持 PKCE S256
+	RefreshToken             bool     `json:"refresh_token"`                // 支持 refresh_token 授权类型
+	ClientCredentials        bool     `json:"client_credentials"`           // 支持 client_credentials 授权类型
+	DeviceAuthorization      bool     `json:"device_authorization"`         // 提供设备授权端点
+	PushedAuthorization      bool     `json:"pushed_authorization"`         // 提供 PAR 端点
+	RequirePAR               bool     `json:"require_pushed_authorization"` // 要求使用 PAR
+	EndSession               bool     `json:"end_session"`                  // 提供 RP 发起登出端点
+	Introspection            bool     `json:"introspection"`                // 提供令牌自省端点
+	Revocation               bool     `json:"revocation"`                   // 提供令牌注销端点
+	FrontchannelLogout       bool     `json:"frontchannel_logout"`          // 支持前端通道登出
+	BackchannelLogout        bool     `json:"backchannel_logout"`           // 支持后端通道登出
+	TokenEndpointAuthMethods []string `json:"token_endpoint_auth_methods,omitempty"`
+}
+
+// Capabilities 返回当前身份提供方的能力报告
+func (c *Config) Capabilities() Capabilities {
+	capabilities := Capabilities{
+		Introspection:      c.Endpoints.Introspection != "",
+		Revocation:         c.Endpoints.Revocation != "",
+		FrontchannelLogout: c.Endpoints.FrontchannelLogoutSupported,
+		BackchannelLogout:  true,
+	}
+	metadata := c.Metadata
+	if metadata == nil {
+		return capabilities
+	}
+
+	capabilities.Discovered = true
+	capabilities.PKCES256 = slices.Contains(metadata.CodeChallengeMethodsSupported, "S256")
+	capabilities.RefreshToken = metadata.SupportsGrantType("refresh_token")
+	capabilities.ClientCredentials = metadata.SupportsGrantType("client_credentials")
+	capabilities.DeviceAuthorization = metadata.DeviceAuthorizationEndpoint != ""
+	capabilities.PushedAuthorization = metadata.PushedAuthorizationRequestEndpoint != ""
+	capabilities.RequirePAR = metadata.RequirePushedAuthorizationRequests
+	capabilities.EndSession = metadata.EndSessionEndpoint != ""
+	capabilities.BackchannelLogout = metadata.BackchannelLogoutSupported
+	capabilities.TokenEndpointAuthMethods = slices.Clone(metadata.TokenEndpointAuthMethodsSupported)
+	return capabilities
+}
+
+// LogValue 以日志属性组输出能力报告
+func (c Capabilities) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Bool("discovered", c.Discovered),
+		slog.Bool("pkce_s256", c.PKCES256),
+		slog.Bool("refresh_token", c.RefreshToken),
+		slog.Bool("client_credentials", c.ClientCredentials),
+		slog.Bool("device_authorization", c.DeviceAuthorization),
+		slog.Bool("pushed_authorization", c.PushedAuthorization),
+		slog.Bool("require_pushed_authorization", c.RequirePAR),
+		slog.Bool("end_session", c.EndSession),
+		slog.Bool("introspection", c.Introspection),
+		slog.Bool("revocation", c.Revocation),
+		slog.Bool("frontchannel_logout", c.FrontchannelLogout),
+		slog.Bool("backchannel_logout", c.BackchannelLogout),
+		slog.Any("token_endpoint_auth_methods", c.TokenEndpointAuthMethods),
+	)
+}
@@ -0,0 +1,224 @@
+package bSdkConfig
+
+import (
+	"slices"
+	"time"
+)
+
+// Option 以代码方式修改配置的函数选项
+type Option func(*Config)
+
+// New 在默认配置的基础上应用函数选项
+//
+// 参数:
+//   - opts: 函数选项，按顺序应用。
+//
+// 返回值:
+//   - *Config: 未校验的配置，使用前需调用 `Resolve` 或 `Validate`。
+func New(opts ...Option) *Config {
+	return Default().Apply(opts...)
+}
+
+// Apply 按顺序应用函数选项并返回配置本身
+func (c *Config) Apply(opts ...Option) *Config {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	return c
+}
+
+// WithClient 设置客户端 ID 与 Secret
+func WithClient(id string, secret string) Option {
+	return func(c *Config) {
+		c.Client.ID = id
+		c.Client.Secret = secret
+	}
+}
+
+// WithClientSecretRef 设置客户端 Secret 的引用，启动时由 `SecretProvider` 读取
+func WithClientSecretRef(ref string) Option {
+	return func(c *Config) {
+		c.Client.SecretRef = ref
+	}
+}
+
+// WithSecretProvider 设置读取 `client.secret_ref` 的密钥提供者，用于对接外部密钥管理服务
+func WithSecretProvider(provider SecretProvider) Option {
+	return func(c *Config) {
+		c.SecretProvider = provider
+	}
+}
+
+// WithSecretRefresh 设置重新读取 `client.secret_ref` 的间隔，用于密钥轮换
+func WithSecretRefresh(interval time.Duration) Option {
+	return func(c *Config) {
+		c.Secrets.RefreshInterval = Duration(interval)
+	}
+}
+
+// WithRedirectURI 设置授权回调地址
+func WithRedirectURI(uri string) Option {
+	return func(c *Config) {
+		c.Client.RedirectURI = uri
+	}
+}
+
+// WithFrontchannelLogoutURI 设置在 SSO 登记的前端通道登出地址
+func WithFrontchannelLogoutURI(uri string) Option {
+	return func(c *Config) {
+		c.Client.FrontchannelLogoutURI = uri
+	}
+}
+
+// WithWellKnown 设置 OpenID Connect 元数据端点，未配置的端点将自动发现
+func WithWellKnown(uri string) Option {
+	return func(c *Config) {
+		c.Endpoints.WellKnownURI = uri
+	}
+}
+
+// WithEndpoints 设置 SSO 端点，`WellKnownURI` 为空时保留原值
+func WithEndpoints(endpoints EndpointConfig) Option {
+	return func(c *Config) {
+		if endpoints.WellKnownURI == "" {
+			endpoints.WellKnownURI = c.Endpoints.WellKnownURI
+		}
+		endpoints.FrontchannelLogoutSupported = c.Endpoints.FrontchannelLogoutSupported
+		c.Endpoints = endpoints
+	}
+}
+
+// WithScopes 设置授权范围
+func WithScopes(scopes ...string) Option {
+	return func(c *Config) {
+		c.Scopes = slices.Clone(scopes)
+	}
+}
+
+// WithBusinessCache 设置 Userinfo 与 Introspection 缓存开关
+func WithBusinessCache(enabled bool) Option {
+	return func(c *Config) {
+		c.Cache.Business = enabled
+	}
+}
+
+// WithFetchLock 设置是否通过分布式锁合并跨实例的上游请求
+func WithFetchLock(enabled bool) Option {
+	return func(c *Config) {
+		c.Cache.FetchLock = enabled
+	}
+}
+
+// WithLocalCache 设置进程内一级缓存，size 与 ttl 小于等于 0 时使用默认值
+func WithLocalCache(enabled bool, size int, ttl time.Duration) Option {
+	return func(c *Config) {
+		c.Cache.Local = LocalCacheConfig{Enabled: enabled, Size: size, TTL: Duration(ttl)}
+	}
+}
+
+// WithGrpc 设置 gRPC 主机与端口
+func WithGrpc(host string, port string) Option {
+	return func(c *Config) {
+		c.Grpc.Host = host
+		c.Grpc.Port = port
+	}
+}
+
+// WithHTTPTimeout 设置请求 SSO 的超时时间
+func WithHTTPTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.HTTP.Timeout = Duration(timeout)
+	}
+}
+
+// WithHTTPRetry 设置请求 SSO 失败时的重试次数
+func WithHTTPRetry(count int) Option {
+	return func(c *Config) {
+		c.HTTP.RetryCount = count
+	}
+}
+
+// WithHTTPBreaker 启用请求 SSO 的熔断器，连续失败 threshold 次后在 cooldown 内拒绝请求
+func WithHTTPBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Config) {
+		c.HTTP.BreakerThreshold = threshold
+		c.HTTP.BreakerCooldown = Duration(cooldown)
+	}
+}
+
+// WithUserAgent 设置请求 SSO 时的 User-Agent
+func WithUserAgent(userAgent string) Option {
+	return func(c *Config) {
+		c.HTTP.UserAgent = userAgent
+	}
+}
+
+// WithSession 设置本地会话 Cookie，未设置名称与 SameSite 时保留原值
+func WithSession(session SessionConfig) Option {
+	return func(c *Config) {
+		if session.CookieName == "" {
+			session.CookieName = c.Session.CookieName
+		}
+		if session.SameSite == "" {
+			session.SameSite = c.Session.SameSite
+		}
+		c.Session = session
+	}
+}
+
+// WithStorage 设置状态存储驱动（redis/memory/gorm）
+func WithStorage(driver string) Option {
+	return func(c *Config) {
+		c.Storage.Driver = driver
+	}
+}
+
+// WithStoragePurge 设置数据库中过期状态与令牌记录的清理周期，0 表示不清理
+func WithStoragePurge(interval time.Duration) Option {
+	return func(c *Config) {
+		c.Storage.PurgeInterval = Duration(interval)
+	}
+}
+
+// WithProbe 设置启动自检模式（off/warn/fail）与单项检查超时，timeout 不大于 0 时保留原值
+func WithProbe(mode string, timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Probe.Mode = mode
+		if timeout > 0 {
+			c.Probe.Timeout = Duration(timeout)
+		}
+	}
+}
+
+// WithHealthTimeout 设置就绪检查的单项超时
+func WithHealthTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Health.Timeout = Duration(timeout)
+	}
+}
+
+// WithReloadOnSighup 设置收到 SIGHUP 时是否重新读取配置文件与环境变量
+func WithReloadOnSighup(enabled bool) Option {
+	return func(c *Config) {
+		c.Reload.OnSighup = enabled
+	}
+}
+
+// WithBusiness 设置业务层无效令牌的负缓存与限流
+func WithBusiness(business BusinessConfig) Option {
+	return func(c *Config) {
+		c.Business = business
+	}
+}
+
+// WithCacheEntry 覆盖单个缓存的配置，name 为小写的缓存名称（如 `token`）
+func WithCacheEntry(name string, entry CacheEntryConfig) Option {
+	return func(c *Config) {
+		if c.Cache.Entries == nil {
+			c.Cache.Entries = make(map[string]CacheEntryConfig)
+		}
+		c.Cache.Entries[name] = entry
+	}
+}
@@ -0,0 +1,121 @@
+package bSdkConfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+)
+
+// providerNamePattern 身份提供方名称格式，名称会出现在路由路径与缓存键中。
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// reservedProviderNames 与 `/sso/oauth` 下已有路由冲突的名称。
+var reservedProviderNames = []string{"login", "callback", "refresh", "logout", "backchannel-logout", "frontchannel-logout"}
+
+// WithProvider 注册命名身份提供方
+//
+// 提供方在默认配置的基础上应用 opts，未配置的授权范围与 HTTP 客户端配置在 `Resolve` 时继承自根配置。
+// 同名提供方会被覆盖。
+//
+// 参数:
+//   - name: 提供方名称，用于路由 `/sso/oauth/:provider/*` 与缓存键前缀。
+//   - opts: 作用于该提供方配置的函数选项。
+func WithProvider(name string, opts ...Option) Option {
+	return func(c *Config) {
+		if c.Providers == nil {
+			c.Providers = make(map[string]*Config)
+		}
+		c.Providers[name] = New(opts...)
+	}
+}
+
+// Provider 返回指定名称的身份提供方配置
+//
+// 返回值:
+//   - *Config: 提供方配置。
+//   - bool: 提供方是否已注册。
+func (c *Config) Provider(name string) (*Config, bool) {
+	provider, ok := c.Providers[name]
+	return provider, ok && provider != nil
+}
+
+// ProviderNames 按字典序返回已注册的身份提供方名称
+func (c *Config) ProviderNames() []string {
+	names := make([]string, 0, len(c.Providers))
+	for name, provider := range c.Providers {
+		if provider != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProviderKeyPrefix 返回身份提供方的缓存键前缀，未配置时为 `<名称>:`
+func (c *Config) ProviderKeyPrefix(name string) string {
+	if provider, ok := c.Provider(name); ok && provider.Cache.KeyPrefix != "" {
+		return provider.Cache.KeyPrefix
+	}
+	return name + ":"
+}
+
+// resolveProviders 为各身份提供方继承根配置并补全自动发现的端点，所有错误会一并返回，
+// 同时返回各提供方元数据声明的最短有效期。
+func (c *Config) resolveProviders(ctx context.Context, cache *metadataCache) (time.Duration, error) {
+	var (
+		errs   []error
+		minAge time.Duration
+	)
+	for _, name := range c.ProviderNames() {
+		provider := c.Providers[name]
+		if len(provider.Scopes) == 0 {
+			provider.Scopes = slices.Clone(c.Scopes)
+		}
+		if provider.HTTP == (HTTPConfig{}) {
+			provider.HTTP = c.HTTP
+		}
+		maxAge, err := provider.discover(ctx, cache)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("providers.%s: %w", name, err))
+		}
+		if maxAge > 0 && (minAge == 0 || maxAge < minAge) {
+			minAge = maxAge
+		}
+	}
+	return minAge, errors.Join(errs...)
+}
+
+// validateProviders 校验各身份提供方的名称与配置。
+//
+// 命名身份提供方仅用于登录，不要求自省与注销端点，但必须配置签发者，用于按令牌所属提供方校验签发者。
+func (c *Config) validateProviders() []error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(c.Providers)) {
+		provider := c.Providers[name]
+		if !providerNamePattern.MatchString(name) {
+			errs = append(errs, fmt.Errorf("providers 名称非法: %q（仅允许小写字母、数字、- 与 _，最长 32 位）", name))
+		}
+		if slices.Contains(reservedProviderNames, name) {
+			errs = append(errs, fmt.Errorf("providers 名称 %q 与内置路由冲突", name))
+		}
+		if provider == nil {
+			errs = append(errs, fmt.Errorf("providers.%s 未配置", name))
+			continue
+		}
+		if len(provider.Providers) > 0 {
+			errs = append(errs, fmt.Errorf("providers.%s.providers 不支持嵌套", name))
+		}
+		if len(provider.Tenants) > 0 {
+			errs = append(errs, fmt.Errorf("providers.%s.tenants 不支持，租户需配置在根配置中", name))
+		}
+		for _, err := range provider.validate(true) {
+			errs = append(errs, fmt.Errorf("providers.%s.%w", name, err))
+		}
+	}
+	return errs
+}
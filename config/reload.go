@@ -0,0 +1,352 @@
+package bSdkConfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// minRefreshInterval 元数据后台刷新的最小间隔，同时作为 Cache-Control 有效期的下限。
+const minRefreshInterval = 10 * time.Second
+
+// liveConfig 配置句柄的热更新状态。
+type liveConfig struct {
+	current  atomic.Pointer[snapshot]
+	reloader *Reloader
+}
+
+// snapshot 配置句柄的当前快照。
+type snapshot struct {
+	cfg   *Config
+	oauth *oauth2.Config
+}
+
+// Current 返回配置的当前快照
+//
+// 由 `Reloader` 创建的配置句柄返回最近一次热更新后的配置；其余配置返回自身。
+// 调用方应在单次请求内复用取得的快照，避免同一请求前后读取到不同版本的配置。
+func (c *Config) Current() *Config {
+	if c == nil || c.live == nil {
+		return c
+	}
+	return c.live.current.Load().cfg
+}
+
+// CurrentOAuth2 返回当前快照的 `oauth2.Config`，配置不是热更新句柄时返回 nil
+func (c *Config) CurrentOAuth2() *oauth2.Config {
+	if c == nil || c.live == nil {
+		return nil
+	}
+	return c.live.current.Load().oauth
+}
+
+// Reloader 返回配置句柄的热更新器，配置不是热更新句柄时返回 nil
+func (c *Config) Reloader() *Reloader {
+	if c == nil || c.live == nil {
+		return nil
+	}
+	return c.live.reloader
+}
+
+// Reloader 配置热更新器
+//
+// 持有注册到上下文的配置句柄（参见 `Config.Current`），在后台按 `endpoints.refresh_interval`
+// 重新请求元数据端点（携带 ETag/Last-Modified 条件请求，并遵循 Cache-Control 的 max-age），
+// 按 `secrets.refresh_interval` 重新读取 `secret_ref` 引用的密钥，或在调用 `Reload` 时重新读取配置源，用于轮换客户端密钥。
+//
+// 新配置补全并校验通过后以原子方式替换句柄的当前快照，正在处理的请求继续使用已取得的旧快照；
+// 元数据请求失败或新配置非法时保留当前快照。命名身份提供方与租户的名称集合不支持热更新。
+type Reloader struct {
+	handle *Config
+	source func() (*Config, error)
+	cache  *metadataCache
+
+	mu     sync.Mutex           // 串行化刷新与重载
+	base   *Config              // 最近一次读取的未补全配置，元数据刷新基于它重新补全
+	maxAge time.Duration        // 最近一次元数据声明的最短有效期
+	stops  []context.CancelFunc // 后台刷新与信号监听的停止函数
+
+	refreshing bool                         // 是否已启动后台刷新
+	status     atomic.Pointer[ReloadStatus] // 最近一次刷新或重载的结果，读取时不等待进行中的刷新
+}
+
+// ReloadStatus 配置热更新状态，用于判断元数据是否新鲜
+type ReloadStatus struct {
+	RefreshedAt time.Time     // 最近一次成功补全配置（含元数据未变化）的时间
+	Interval    time.Duration // 后台刷新间隔，未启动后台刷新时为 0
+	LastError   string        // 最近一次刷新或重载失败的原因，成功后清空
+	FailedAt    time.Time     // 最近一次失败的时间
+}
+
+// NewReloader 读取、补全并校验配置，返回绑定到新配置句柄的热更新器
+//
+// 参数:
+//   - ctx: 用于元数据请求的上下文。
+//   - source: 配置源，每次调用返回一份新的未补全配置，例如 `Load` 或复制预设配置的函数。
+//
+// 返回值:
+//   - *Reloader: 热更新器，通过 `Config` 获取配置句柄。
+//   - error: 读取、自动发现或校验失败时返回错误。
+func NewReloader(ctx context.Context, source func() (*Config, error)) (*Reloader, error) {
+	base, err := source()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reloader{source: source, cache: newMetadataCache()}
+	resolved, maxAge, err := r.resolve(ctx, base.Clone())
+	if err != nil {
+		return nil, err
+	}
+
+	handle := resolved.Clone()
+	handle.live = &liveConfig{reloader: r}
+	handle.live.current.Store(&snapshot{cfg: resolved, oauth: resolved.OAuth2()})
+	r.handle, r.base, r.maxAge = handle, base, maxAge
+	r.status.Store(&ReloadStatus{RefreshedAt: time.Now()})
+	return r, nil
+}
+
+// Config 返回配置句柄，应将其注册到上下文并通过 `Current` 读取最新配置
+func (r *Reloader) Config() *Config {
+	return r.handle
+}
+
+// Status 返回最近一次刷新或重载的结果
+func (r *Reloader) Status() ReloadStatus {
+	return *r.status.Load()
+}
+
+// Refresh 重新读取引用的密钥并请求元数据端点，在密钥或端点发生变化时替换当前快照
+//
+// 返回值:
+//   - bool: 当前快照是否被替换。
+//   - error: 读取密钥、元数据请求失败或补全后的配置非法时返回错误，当前快照保持不变。
+func (r *Reloader) Refresh(ctx context.Context) (changed bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defer func() { r.record(err) }()
+
+	resolved, maxAge, err := r.resolve(ctx, r.base.Clone())
+	if err != nil {
+		return false, err
+	}
+	r.maxAge = maxAge
+	return r.swap(resolved)
+}
+
+// Reload 重新读取配置源并替换当前快照，用于轮换客户端密钥等场景
+//
+// 返回值:
+//   - error: 读取、自动发现或校验失败时返回错误，当前快照保持不变。
+func (r *Reloader) Reload(ctx context.Context) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defer func() { r.record(err) }()
+
+	base, err := r.source()
+	if err != nil {
+		return err
+	}
+	resolved, maxAge, err := r.resolve(ctx, base.Clone())
+	if err != nil {
+		return err
+	}
+	if _, err = r.swap(resolved); err != nil {
+		return err
+	}
+	r.base, r.maxAge = base, maxAge
+	return nil
+}
+
+// Start 在后台周期性刷新元数据与 `secret_ref` 引用的密钥，直到调用 `Close`
+//
+// 未配置元数据端点或 `endpoints.refresh_interval` 为 0，且未配置 `secret_ref` 或 `secrets.refresh_interval` 为 0 时不启动。
+// 刷新间隔取两者与 Cache-Control 声明的有效期中最短者，且不小于 10 秒；每次刷新的结果通过 onResult 回调报告（可为 nil）。
+//
+// 返回值:
+//   - bool: 是否已启动后台刷新。
+func (r *Reloader) Start(ctx context.Context, onResult func(changed bool, err error)) bool {
+	if !r.handle.Current().refreshable() {
+		return false
+	}
+
+	ctx = r.detach(ctx)
+	r.mu.Lock()
+	r.refreshing = true
+	status := *r.status.Load()
+	status.Interval = r.interval()
+	r.status.Store(&status)
+	r.mu.Unlock()
+
+	go func() {
+		timer := time.NewTimer(r.nextRefresh())
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+			changed, err := r.Refresh(ctx)
+			if onResult != nil {
+				onResult(changed, err)
+			}
+			timer.Reset(r.nextRefresh())
+		}
+	}()
+	return true
+}
+
+// NotifyReload 在收到指定信号（如 SIGHUP）时调用 `Reload`，直到调用 `Close`
+//
+// 每次重载的结果通过 onResult 回调报告（可为 nil）。
+func (r *Reloader) NotifyReload(ctx context.Context, onResult func(err error), signals ...os.Signal) {
+	if len(signals) == 0 {
+		return
+	}
+	ctx = r.detach(ctx)
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+			}
+			err := r.Reload(ctx)
+			if onResult != nil {
+				onResult(err)
+			}
+		}
+	}()
+}
+
+// Close 停止后台刷新与信号监听，已替换的快照保持不变
+func (r *Reloader) Close() {
+	r.mu.Lock()
+	stops := r.stops
+	r.stops = nil
+	r.mu.Unlock()
+
+	for _, stop := range stops {
+		stop()
+	}
+}
+
+// detach 返回不随 ctx 取消、由 `Close` 停止的后台上下文。
+func (r *Reloader) detach(ctx context.Context) context.Context {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.mu.Lock()
+	r.stops = append(r.stops, cancel)
+	r.mu.Unlock()
+	return ctx
+}
+
+// nextRefresh 返回距下一次元数据刷新的间隔。
+func (r *Reloader) nextRefresh() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.interval()
+}
+
+// interval 返回元数据与密钥刷新间隔中较短者，调用方需持有 mu。
+func (r *Reloader) interval() time.Duration {
+	current := r.handle.Current()
+	var interval time.Duration
+	if current.Endpoints.WellKnownURI != "" && current.Endpoints.RefreshInterval > 0 {
+		interval = current.Endpoints.RefreshInterval.Duration()
+		if r.maxAge > 0 && r.maxAge < interval {
+			interval = r.maxAge
+		}
+	}
+	if secret := current.Secrets.RefreshInterval.Duration(); secret > 0 && current.hasSecretRefs() && (interval == 0 || secret < interval) {
+		interval = secret
+	}
+	return max(interval, minRefreshInterval)
+}
+
+// refreshable 判断配置是否需要后台刷新：配置了元数据端点与刷新间隔，或通过引用读取密钥且配置了密钥刷新间隔。
+func (c *Config) refreshable() bool {
+	if c.Endpoints.WellKnownURI != "" && c.Endpoints.RefreshInterval > 0 {
+		return true
+	}
+	return c.Secrets.RefreshInterval > 0 && c.hasSecretRefs()
+}
+
+// record 更新热更新状态，err 为空表示成功，调用方需持有 mu。
+func (r *Reloader) record(err error) {
+	status := *r.status.Load()
+	if r.refreshing {
+		status.Interval = r.interval()
+	}
+	if err != nil {
+		status.LastError, status.FailedAt = err.Error(), time.Now()
+	} else {
+		status.RefreshedAt, status.LastError = time.Now(), ""
+	}
+	r.status.Store(&status)
+}
+
+// resolve 在 cfg 上使用条件请求补全并校验配置。
+func (r *Reloader) resolve(ctx context.Context, cfg *Config) (*Config, time.Duration, error) {
+	maxAge, err := cfg.resolve(ctx, r.cache)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cfg, maxAge, nil
+}
+
+// swap 将 next 设为当前快照，与当前快照一致时不替换。
+func (r *Reloader) swap(next *Config) (bool, error) {
+	current := r.handle.Current()
+	if err := checkReloadable(current, next); err != nil {
+		return false, err
+	}
+	if next.equal(current) {
+		return false, nil
+	}
+	r.handle.live.current.Store(&snapshot{cfg: next, oauth: next.OAuth2()})
+	return true, nil
+}
+
+// checkReloadable 校验热更新前后命名身份提供方与租户的名称集合一致。
+func checkReloadable(current *Config, next *Config) error {
+	var errs []error
+	if !slices.Equal(current.ProviderNames(), next.ProviderNames()) {
+		errs = append(errs, fmt.Errorf("providers 的名称集合不支持热更新，请重启服务"))
+	}
+	if !slices.Equal(current.TenantNames(), next.TenantNames()) {
+		errs = append(errs, fmt.Errorf("tenants 的名称集合不支持热更新，请重启服务"))
+	}
+	return errors.Join(errs...)
+}
+
+// equal 判断两份已补全的配置是否一致，不比较自定义租户解析函数。
+func (c *Config) equal(other *Config) bool {
+	if c.Client != other.Client || c.Endpoints != other.Endpoints || !reflect.DeepEqual(c.Cache, other.Cache) ||
+		c.Storage != other.Storage || c.Business != other.Business || c.Grpc != other.Grpc || c.HTTP != other.HTTP ||
+		c.Secrets != other.Secrets || c.Probe != other.Probe || c.Health != other.Health || c.Reload != other.Reload || !reflect.DeepEqual(c.Session, other.Session) || !reflect.DeepEqual(c.Token, other.Token) || !slices.Equal(c.Scopes, other.Scopes) ||
+		!reflect.DeepEqual(c.Metadata, other.Metadata) {
+		return false
+	}
+	if !maps.EqualFunc(c.Providers, other.Providers, func(a, b *Config) bool { return a.equal(b) }) {
+		return false
+	}
+	return maps.EqualFunc(c.Tenants, other.Tenants, func(a, b *TenantConfig) bool {
+		return a.Client == b.Client && slices.Equal(a.Hosts, b.Hosts) && a.PathPrefix == b.PathPrefix && a.KeyPrefix == b.KeyPrefix
+	})
+}
@@ -0,0 +1,153 @@
+package bSdkConfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Redacted 日志与错误信息中替代密钥明文的占位符。
+const Redacted = "[REDACTED]"
+
+// SecretProvider 密钥提供者
+//
+// 负责按引用（`client.secret_ref`、`token.hash_key_ref`、`token.encryption_key_ref`）读取密钥，可对接 Vault 等外部密钥管理服务；
+// 启动、后台刷新（`secrets.refresh_interval`）与重载时执行，读取结果只保存在配置快照中。
+type SecretProvider interface {
+	// LoadSecret 读取引用对应的密钥明文。
+	LoadSecret(ctx context.Context, ref string) (string, error)
+}
+
+// DefaultSecretProvider 返回默认密钥提供者，即以引用为文件路径的 `FileSecretProvider`
+func DefaultSecretProvider() SecretProvider {
+	return FileSecretProvider{}
+}
+
+// FileSecretProvider 从本地文件读取密钥，适用于挂载 Docker/Kubernetes Secret 等场景
+//
+// 引用为文件路径，相对路径基于 `Dir` 解析；文件内容首尾的空白（含换行）会被去除。
+type FileSecretProvider struct {
+	Dir string // 相对路径的基准目录，为空时基于工作目录
+}
+
+// LoadSecret 实现 SecretProvider 接口。
+func (p FileSecretProvider) LoadSecret(_ context.Context, ref string) (string, error) {
+	path := ref
+	if p.Dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(p.Dir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取密钥文件失败: %w", err)
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("密钥文件为空: %s", path)
+	}
+	return secret, nil
+}
+
+// RedactSecret 返回用于日志输出的密钥占位符，未配置时返回空字符串以便区分缺失与已配置
+func RedactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// LogValue 实现 `slog.LogValuer`，输出客户端配置时隐藏密钥
+func (c ClientConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", c.ID),
+		slog.String("secret", RedactSecret(c.Secret)),
+		slog.String("secret_ref", c.SecretRef),
+		slog.String("redirect_uri", c.RedirectURI),
+		slog.String("frontchannel_logout_uri", c.FrontchannelLogoutURI),
+	)
+}
+
+// LogValue 实现 `slog.LogValuer`，输出令牌配置时隐藏密钥
+func (c TokenConfig) LogValue() slog.Value {
+	ids := make([]string, 0, len(c.EncryptionKeys))
+	for _, key := range c.EncryptionKeys {
+		ids = append(ids, key.ID)
+	}
+	return slog.GroupValue(
+		slog.Bool("persistence", c.Persistence),
+		slog.Bool("legacy_read", c.LegacyRead),
+		slog.String("hash_key", RedactSecret(c.HashKey)),
+		slog.String("encryption_key", RedactSecret(c.EncryptionKey)),
+		slog.Any("encryption_key_ids", ids),
+		slog.String("active_key_id", c.ActiveKeyID),
+		slog.String("keys_file", c.KeysFile),
+	)
+}
+
+// hasSecretRefs 判断根配置、命名身份提供方或租户是否通过引用读取密钥。
+func (c *Config) hasSecretRefs() bool {
+	if c.Client.SecretRef != "" || c.Token.HashKeyRef != "" || c.Token.EncryptionKeyRef != "" {
+		return true
+	}
+	for _, provider := range c.Providers {
+		if provider != nil && provider.Client.SecretRef != "" {
+			return true
+		}
+	}
+	for _, tenant := range c.Tenants {
+		if tenant != nil && tenant.Client.SecretRef != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveSecrets 通过密钥提供者读取根配置、命名身份提供方与租户的客户端密钥以及令牌密钥，所有错误会一并返回
+//
+// `Resolve` 与 `Reloader` 会自动调用；仅需读取密钥而不请求元数据、不校验配置时可单独使用。
+//
+// 参数说明:
+//   - ctx: 上下文对象，传递给密钥提供者。
+//
+// 返回值:
+//   - error: 任一引用读取失败时返回汇总后的错误。
+func (c *Config) ResolveSecrets(ctx context.Context) error {
+	provider := c.SecretProvider
+	if provider == nil {
+		provider = DefaultSecretProvider()
+	}
+
+	var errs []error
+	loadRef := func(name string, ref string, target *string) {
+		if ref == "" {
+			return
+		}
+		secret, err := provider.LoadSecret(ctx, ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 读取失败: %w", name, err))
+			return
+		}
+		*target = secret
+	}
+	load := func(name string, client *ClientConfig) {
+		loadRef(name, client.SecretRef, &client.Secret)
+	}
+
+	loadRef("token.hash_key_ref", c.Token.HashKeyRef, &c.Token.HashKey)
+	loadRef("token.encryption_key_ref", c.Token.EncryptionKeyRef, &c.Token.EncryptionKey)
+	load("client.secret_ref", &c.Client)
+	for _, name := range c.ProviderNames() {
+		load("providers."+name+".client.secret_ref", &c.Providers[name].Client)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Tenants)) {
+		if tenant := c.Tenants[name]; tenant != nil {
+			load("tenants."+name+".client.secret_ref", &tenant.Client)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,173 @@
+package bSdkConfig
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// TenantConfig 租户配置
+//
+// 同一部署服务多个商户时，每个租户使用独立的 OAuth 客户端（同时作为 gRPC 的 `app-access-id`/`app-secret-key`），
+// 并在独立的缓存命名空间中保存令牌等数据；SSO 端点、授权范围与 HTTP 客户端配置沿用根配置。
+type TenantConfig struct {
+	Client     ClientConfig `json:"client" yaml:"client"`                               // 租户的 OAuth 客户端
+	Hosts      []string     `json:"hosts,omitempty" yaml:"hosts,omitempty"`             // 按 Host 请求头匹配的域名（不含端口）
+	PathPrefix string       `json:"path_prefix,omitempty" yaml:"path_prefix,omitempty"` // 按请求路径前缀匹配，如 `/m/shop-a`
+	KeyPrefix  string       `json:"key_prefix,omitempty" yaml:"key_prefix,omitempty"`   // 缓存命名空间，默认为 `tenant:<名称>:`
+}
+
+// TenantResolver 自定义租户解析函数，返回租户名称；返回空字符串表示使用根配置
+type TenantResolver func(r *http.Request) string
+
+// WithTenant 注册租户
+//
+// 参数:
+//   - name: 租户名称。
+//   - tenant: 租户配置，同名租户会被覆盖。
+func WithTenant(name string, tenant TenantConfig) Option {
+	return func(c *Config) {
+		if c.Tenants == nil {
+			c.Tenants = make(map[string]*TenantConfig)
+		}
+		tenant.Hosts = slices.Clone(tenant.Hosts)
+		c.Tenants[name] = &tenant
+	}
+}
+
+// WithTenantResolver 设置自定义租户解析函数，设置后不再按 `hosts` 与 `path_prefix` 匹配
+func WithTenantResolver(resolver TenantResolver) Option {
+	return func(c *Config) {
+		c.TenantResolver = resolver
+	}
+}
+
+// Tenant 返回租户生效的配置
+//
+// 返回的配置以根配置为基础替换客户端，不包含命名身份提供方与租户；每次调用都会创建新的副本。
+//
+// 返回值:
+//   - *Config: 租户生效的配置。
+//   - bool: 租户是否已注册。
+func (c *Config) Tenant(name string) (*Config, bool) {
+	tenant, ok := c.Tenants[name]
+	if !ok || tenant == nil {
+		return nil, false
+	}
+	derived := c.Clone()
+	derived.Client = tenant.Client
+	derived.Providers = nil
+	derived.Tenants = nil
+	derived.TenantResolver = nil
+	return derived, true
+}
+
+// TenantNames 按字典序返回已注册的租户名称
+func (c *Config) TenantNames() []string {
+	names := make([]string, 0, len(c.Tenants))
+	for name, tenant := range c.Tenants {
+		if tenant != nil {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// TenantKeyPrefix 返回租户的缓存命名空间，未配置时为 `tenant:<名称>:`
+func (c *Config) TenantKeyPrefix(name string) string {
+	if tenant, ok := c.Tenants[name]; ok && tenant != nil && tenant.KeyPrefix != "" {
+		return tenant.KeyPrefix
+	}
+	return "tenant:" + name + ":"
+}
+
+// ResolveTenant 解析请求所属的租户
+//
+// 设置了 `TenantResolver` 时使用自定义函数；否则先按 Host 请求头（忽略端口与大小写）匹配 `hosts`，
+// 再按最长的 `path_prefix` 匹配请求路径。均未匹配时返回空字符串，表示使用根配置。
+func (c *Config) ResolveTenant(r *http.Request) string {
+	if c.TenantResolver != nil {
+		return c.TenantResolver(r)
+	}
+	if len(c.Tenants) == 0 || r == nil {
+		return ""
+	}
+
+	host := r.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	var (
+		matched    string
+		matchedLen int
+	)
+	for _, name := range c.TenantNames() {
+		tenant := c.Tenants[name]
+		for _, candidate := range tenant.Hosts {
+			if strings.EqualFold(candidate, host) {
+				return name
+			}
+		}
+		if prefix := tenant.PathPrefix; prefix != "" && r.URL != nil && len(prefix) > matchedLen && hasPathPrefix(r.URL.Path, prefix) {
+			matched, matchedLen = name, len(prefix)
+		}
+	}
+	return matched
+}
+
+// hasPathPrefix 判断路径是否以 prefix 为前缀，且前缀在路径段边界结束。
+func hasPathPrefix(path string, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// validateTenants 校验各租户的名称与配置。
+func (c *Config) validateTenants() []error {
+	var errs []error
+	hosts := make(map[string]string)
+	for _, name := range slices.Sorted(maps.Keys(c.Tenants)) {
+		tenant := c.Tenants[name]
+		if !providerNamePattern.MatchString(name) {
+			errs = append(errs, fmt.Errorf("tenants 名称非法: %q（仅允许小写字母、数字、- 与 _，最长 32 位）", name))
+		}
+		if tenant == nil {
+			errs = append(errs, fmt.Errorf("tenants.%s 未配置", name))
+			continue
+		}
+		if strings.TrimSpace(tenant.Client.ID) == "" {
+			errs = append(errs, fmt.Errorf("tenants.%s.client.id 未配置", name))
+		}
+		if strings.TrimSpace(tenant.Client.Secret) == "" && tenant.Client.SecretRef == "" {
+			errs = append(errs, fmt.Errorf("tenants.%s.client.secret 未配置", name))
+		}
+		if tenant.Client.RedirectURI == "" {
+			errs = append(errs, fmt.Errorf("tenants.%s.client.redirect_uri 未配置", name))
+		} else if err := checkAbsoluteURL(tenant.Client.RedirectURI); err != nil {
+			errs = append(errs, fmt.Errorf("tenants.%s.client.redirect_uri %w", name, err))
+		}
+		if uri := tenant.Client.FrontchannelLogoutURI; uri != "" {
+			if _, err := CheckFrontChannelLogoutURI(uri); err != nil {
+				errs = append(errs, fmt.Errorf("tenants.%s.%w", name, err))
+			}
+		}
+		for _, host := range tenant.Hosts {
+			key := strings.ToLower(host)
+			if owner, exist := hosts[key]; exist {
+				errs = append(errs, fmt.Errorf("tenants.%s.hosts 与 tenants.%s 重复: %s", name, owner, host))
+				continue
+			}
+			hosts[key] = name
+		}
+		if tenant.PathPrefix != "" && !strings.HasPrefix(tenant.PathPrefix, "/") {
+			errs = append(errs, fmt.Errorf("tenants.%s.path_prefix 必须以 / 开头: %q", name, tenant.PathPrefix))
+		}
+		if strings.ContainsAny(tenant.KeyPrefix, " \t\r\n") {
+			errs = append(errs, fmt.Errorf("tenants.%s.key_prefix 不能包含空白字符: %q", name, tenant.KeyPrefix))
+		}
+	}
+	return errs
+}
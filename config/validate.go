@@ -0,0 +1,257 @@
+package bSdkConfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resolve 读取 `secret_ref` 引用的客户端密钥，补全自动发现的端点（含各命名身份提供方）并校验配置
+//
+// 参数:
+//   - ctx: 用于元数据请求的上下文。
+//
+// 返回值:
+//   - error: 读取密钥、自动发现失败或配置非法时返回错误。
+func (c *Config) Resolve(ctx context.Context) error {
+	_, err := c.resolve(ctx, nil)
+	return err
+}
+
+// resolve 补全并校验配置，cache 不为空时使用条件请求获取元数据，返回各元数据声明的最短有效期。
+func (c *Config) resolve(ctx context.Context, cache *metadataCache) (time.Duration, error) {
+	if err := c.ResolveSecrets(ctx); err != nil {
+		return 0, err
+	}
+	maxAge, err := c.discover(ctx, cache)
+	if err != nil {
+		return 0, err
+	}
+	providerMaxAge, err := c.resolveProviders(ctx, cache)
+	if err != nil {
+		return 0, err
+	}
+	if providerMaxAge > 0 && (maxAge == 0 || providerMaxAge < maxAge) {
+		maxAge = providerMaxAge
+	}
+	return maxAge, c.Validate()
+}
+
+// Validate 校验配置，所有错误会一并返回，便于一次性修正
+//
+// 配置了 `WellKnownURI` 时应先调用 `Discover`（或直接使用 `Resolve`），否则缺失的端点会被视为错误。
+//
+// 返回值:
+//   - error: 配置非法时返回汇总后的错误，每条错误包含对应的配置项名称。
+func (c *Config) Validate() error {
+	errs := c.validate(false)
+	errs = append(errs, c.validateSession()...)
+	errs = append(errs, c.validateStorage()...)
+	errs = append(errs, c.validateBusiness()...)
+	errs = append(errs, c.validateProbe()...)
+	errs = append(errs, c.validateHealth()...)
+	errs = append(errs, c.validateProviders()...)
+	errs = append(errs, c.validateTenants()...)
+	return errors.Join(errs...)
+}
+
+// validateSession 校验本地会话 Cookie 配置，仅根配置生效。
+func (c *Config) validateSession() []error {
+	var errs []error
+	if strings.TrimSpace(c.Session.CookieName) == "" {
+		errs = append(errs, fmt.Errorf("session.cookie_name 未配置"))
+	}
+	if c.Session.RefreshSkew < 0 {
+		errs = append(errs, fmt.Errorf("session.refresh_skew 不能为负数"))
+	}
+	switch strings.ToLower(c.Session.SameSite) {
+	case "lax", "strict", "none":
+	default:
+		errs = append(errs, fmt.Errorf("session.same_site 仅支持 lax/strict/none: %q", c.Session.SameSite))
+	}
+	for _, origin := range c.Session.TrustedOrigins {
+		if err := checkAbsoluteURL(origin); err != nil {
+			errs = append(errs, fmt.Errorf("session.trusted_origins %w", err))
+		}
+	}
+	return errs
+}
+
+// validateStorage 校验状态存储配置，仅根配置生效。
+func (c *Config) validateStorage() []error {
+	var errs []error
+	if c.Storage.PurgeInterval < 0 {
+		errs = append(errs, fmt.Errorf("storage.purge_interval 不能为负数"))
+	}
+	return errs
+}
+
+// validateProbe 校验启动自检配置，仅根配置生效。
+func (c *Config) validateProbe() []error {
+	var errs []error
+	switch strings.ToLower(c.Probe.Mode) {
+	case "off", "warn", "fail":
+	default:
+		errs = append(errs, fmt.Errorf("probe.mode 仅支持 off/warn/fail: %q", c.Probe.Mode))
+	}
+	if c.Probe.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("probe.timeout 必须大于 0"))
+	}
+	return errs
+}
+
+// validateHealth 校验就绪检查配置，仅根配置生效。
+func (c *Config) validateHealth() []error {
+	var errs []error
+	if c.Health.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("health.timeout 必须大于 0"))
+	}
+	return errs
+}
+
+// validateBusiness 校验业务层无效令牌拦截配置，仅根配置生效。
+func (c *Config) validateBusiness() []error {
+	var errs []error
+	if c.Business.NegativeCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("business.negative_cache_ttl 不能为负数"))
+	}
+	if c.Business.InvalidTokenLimit < 0 {
+		errs = append(errs, fmt.Errorf("business.invalid_token_limit 不能为负数"))
+	}
+	if c.Business.InvalidTokenLimit > 0 && c.Business.InvalidTokenWindow <= 0 {
+		errs = append(errs, fmt.Errorf("business.invalid_token_window 必须大于 0"))
+	}
+	if c.Business.InvalidTokenPrefixLen < 0 {
+		errs = append(errs, fmt.Errorf("business.invalid_token_prefix_len 不能为负数"))
+	}
+	return errs
+}
+
+// validate 校验单个身份提供方的配置，provider 为 true 时按命名身份提供方的要求校验。
+func (c *Config) validate(provider bool) []error {
+	var errs []error
+	require := func(name string, value string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Errorf("%s 未配置", name))
+		}
+	}
+	checkURL := func(name string, value string) {
+		if value == "" {
+			return
+		}
+		if err := checkAbsoluteURL(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s %w", name, err))
+		}
+	}
+
+	require("client.id", c.Client.ID)
+	if c.Client.SecretRef == "" {
+		require("client.secret", c.Client.Secret)
+	}
+	require("client.redirect_uri", c.Client.RedirectURI)
+	require("endpoints.auth", c.Endpoints.Auth)
+	require("endpoints.token", c.Endpoints.Token)
+	if provider {
+		require("endpoints.issuer", c.Endpoints.Issuer)
+	} else {
+		require("endpoints.userinfo", c.Endpoints.Userinfo)
+		require("endpoints.introspection", c.Endpoints.Introspection)
+		require("endpoints.revocation", c.Endpoints.Revocation)
+	}
+
+	checkURL("client.redirect_uri", c.Client.RedirectURI)
+	checkURL("endpoints.well_known_uri", c.Endpoints.WellKnownURI)
+	checkURL("endpoints.auth", c.Endpoints.Auth)
+	checkURL("endpoints.token", c.Endpoints.Token)
+	checkURL("endpoints.userinfo", c.Endpoints.Userinfo)
+	checkURL("endpoints.introspection", c.Endpoints.Introspection)
+	checkURL("endpoints.revocation", c.Endpoints.Revocation)
+	checkURL("endpoints.jwks", c.Endpoints.JWKS)
+	if uri := c.Client.FrontchannelLogoutURI; uri != "" {
+		if _, err := CheckFrontChannelLogoutURI(uri); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(c.Scopes) == 0 {
+		errs = append(errs, fmt.Errorf("scopes 不能为空"))
+	}
+	for _, scope := range c.Scopes {
+		if scope == "" || strings.ContainsAny(scope, " \t\r\n") {
+			errs = append(errs, fmt.Errorf("scopes 包含非法的授权范围: %q", scope))
+		}
+	}
+
+	if strings.ContainsAny(c.Cache.KeyPrefix, " \t\r\n") {
+		errs = append(errs, fmt.Errorf("cache.key_prefix 不能包含空白字符: %q", c.Cache.KeyPrefix))
+	}
+	if c.Cache.Local.Size < 0 {
+		errs = append(errs, fmt.Errorf("cache.local.size 不能为负数"))
+	}
+	if c.Cache.Local.TTL < 0 {
+		errs = append(errs, fmt.Errorf("cache.local.ttl 不能为负数"))
+	}
+
+	if (c.Grpc.Host == "") != (c.Grpc.Port == "") {
+		errs = append(errs, fmt.Errorf("grpc.host 与 grpc.port 需同时配置"))
+	}
+	if c.Grpc.Port != "" {
+		if port, err := strconv.Atoi(c.Grpc.Port); err != nil || port <= 0 || port > 65535 {
+			errs = append(errs, fmt.Errorf("grpc.port 非法: %q", c.Grpc.Port))
+		}
+	}
+
+	if interval := c.Endpoints.RefreshInterval.Duration(); interval < 0 || (interval > 0 && interval < minRefreshInterval) {
+		errs = append(errs, fmt.Errorf("endpoints.refresh_interval 必须为 0 或不小于 %s: %s", minRefreshInterval, interval))
+	}
+	if interval := c.Secrets.RefreshInterval.Duration(); interval < 0 || (interval > 0 && interval < minRefreshInterval) {
+		errs = append(errs, fmt.Errorf("secrets.refresh_interval 必须为 0 或不小于 %s: %s", minRefreshInterval, interval))
+	}
+	if c.HTTP.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("http.timeout 不能为负数"))
+	}
+	if c.HTTP.RetryCount < 0 {
+		errs = append(errs, fmt.Errorf("http.retry_count 不能为负数"))
+	}
+	if c.HTTP.BreakerThreshold < 0 {
+		errs = append(errs, fmt.Errorf("http.breaker_threshold 不能为负数"))
+	}
+	if c.HTTP.BreakerCooldown < 0 {
+		errs = append(errs, fmt.Errorf("http.breaker_cooldown 不能为负数"))
+	}
+
+	return errs
+}
+
+// CheckFrontChannelLogoutURI 校验前端通道登出地址是否符合规范。
+//
+// 按 OpenID Connect Front-Channel Logout 1.0 §2，该地址必须为绝对 URL，且不得包含片段（fragment）。
+func CheckFrontChannelLogoutURI(uri string) (*url.URL, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("client.frontchannel_logout_uri 格式错误: %v", err)
+	}
+	if (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("client.frontchannel_logout_uri 必须为绝对地址: %s", uri)
+	}
+	if parsed.Fragment != "" {
+		return nil, fmt.Errorf("client.frontchannel_logout_uri 不得包含片段: %s", uri)
+	}
+	return parsed, nil
+}
+
+// checkAbsoluteURL 校验地址为 http/https 绝对地址。
+func checkAbsoluteURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("格式错误: %v", err)
+	}
+	if (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("必须为 http/https 绝对地址: %s", value)
+	}
+	return nil
+}
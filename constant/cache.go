@@ -10,15 +10,32 @@ type RedisKey string
 
 const (
 	RedisOAuthState            RedisKey = "oauth:state:%s"             // OAuth state 缓存键
-	RedisOAuthToken            RedisKey = "oauth:token:%s"             // OAuth token 缓存键
+	RedisOAuthToken            RedisKey = "oauth:tk:%s"                // OAuth token 缓存键（令牌指纹）
+	RedisOAuthTokenLegacy      RedisKey = "oauth:token:%s"             // OAuth token 旧格式缓存键（令牌明文，仅迁移期读取）
 	RedisBusinessUserinfo      RedisKey = "oauth:biz:userinfo:%s"      // 业务层 userinfo 缓存键
 	RedisBusinessIntrospection RedisKey = "oauth:biz:introspection:%s" // 业务层 introspection 缓存键
+	RedisBusinessFetchLock     RedisKey = "oauth:biz:lock:%s"          // 业务层上游请求分布式锁键（请求类型:令牌指纹）
+	RedisOAuthSessionSid       RedisKey = "oauth:session:sid:%s"       // OAuth 会话（sid）令牌索引键
+	RedisOAuthSessionSub       RedisKey = "oauth:session:sub:%s"       // OAuth 用户（sub）令牌索引键
+	RedisOAuthLogoutJti        RedisKey = "oauth:logout:jti:%s"        // 登出令牌 jti 防重放键
+	RedisRevocationChannel     RedisKey = "oauth:revocation"           // 令牌吊销广播频道
+	RedisOAuthTokenFamily      RedisKey = "oauth:family:%s"            // 刷新令牌家族键（家族 ID）
+	RedisOAuthTokenFamilyRT    RedisKey = "oauth:family:rt:%s"         // 刷新令牌到家族的索引键（刷新令牌指纹）
+	RedisOAuthRefreshLock      RedisKey = "oauth:refresh:lock:%s"      // 刷新令牌分布式锁键（家族 ID 或刷新令牌指纹）
+	RedisOAuthRefreshRecent    RedisKey = "oauth:refresh:recent:%s"    // 近期刷新结果缓存键（旧刷新令牌指纹）
 )
 
 // Get 返回一个格式化后的 `RedisKey`，根据输入参数对原始键进行格式化并生成新的键。
 func (k RedisKey) Get(args ...interface{}) RedisKey {
-	validKey := xEnv.GetEnvString(xEnv.NoSqlPrefix, "bss:") + string(k)
-	return RedisKey(fmt.Sprintf(validKey, args...))
+	return k.GetWithPrefix("", args...)
+}
+
+// GetWithPrefix 使用指定前缀格式化键，前缀为空时使用 `xEnv.NoSqlPrefix`（默认 "bss:"）。
+func (k RedisKey) GetWithPrefix(prefix string, args ...interface{}) RedisKey {
+	if prefix == "" {
+		prefix = xEnv.GetEnvString(xEnv.NoSqlPrefix, "bss:")
+	}
+	return RedisKey(fmt.Sprintf(prefix+string(k), args...))
 }
 
 // String 返回 `RedisKey` 的字符串表示形式，主要用于将自定义键类型转换为其底层字符串值。
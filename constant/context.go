@@ -3,7 +3,14 @@ package bSdkConst
 import xCtx "github.com/bamboo-services/bamboo-base-go/defined/context"
 
 const (
-	CtxOAuthConfig      xCtx.ContextKey = "oauth_config"       // OAuth 配置上下文键
-	CtxOAuthUserinfoURI xCtx.ContextKey = "oauth_userinfo_uri" // OAuth 用户信息 URI 上下文键
-	CtxSsoClient        xCtx.ContextKey = "sso_client"         // SsoClient 上下文键
+	CtxSdkConfig        xCtx.ContextKey = "sso_sdk_config"      // SDK 配置上下文键
+	CtxConfigReloader   xCtx.ContextKey = "sso_config_reloader" // SDK 配置热更新器上下文键
+	CtxOAuthConfig      xCtx.ContextKey = "oauth_config"        // OAuth 配置上下文键
+	CtxOAuthUserinfoURI xCtx.ContextKey = "oauth_userinfo_uri"  // OAuth 用户信息 URI 上下文键
+	CtxSsoClient        xCtx.ContextKey = "sso_client"          // SsoClient 上下文键
+	CtxRevocationBus    xCtx.ContextKey = "revocation_bus"      // 令牌吊销广播订阅器上下文键
+	CtxTokenPersistence xCtx.ContextKey = "token_persistence"   // 令牌持久化存储上下文键
+	CtxStore            xCtx.ContextKey = "sso_store"           // 状态存储上下文键
+	CtxCacheConfig      xCtx.ContextKey = "sso_cache_config"    // 缓存配置上下文键
+	CtxStartupProbe     xCtx.ContextKey = "sso_startup_probe"   // 启动自检报告上下文键
 )
@@ -5,6 +5,7 @@ import xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
 const (
 	EnvSsoClientID                 xEnv.EnvKey = "SSO_CLIENT_ID"                  // 单点登录客户端 ID
 	EnvSsoClientSecret             xEnv.EnvKey = "SSO_CLIENT_SECRET"              // 单点登录客户端 Secret
+	EnvSsoClientSecretFile         xEnv.EnvKey = "SSO_CLIENT_SECRET_FILE"         // 单点登录客户端 Secret 文件路径，与 SSO_CLIENT_SECRET 互斥
 	EnvSsoWellKnownURI             xEnv.EnvKey = "SSO_WELL_KNOWN_URI"             // 单点登录元数据端点
 	EnvSsoRedirectURI              xEnv.EnvKey = "SSO_REDIRECT_URI"               // 单点登录回调地址
 	EnvSsoEndpointAuthURI          xEnv.EnvKey = "SSO_ENDPOINT_AUTH_URI"          // 单点登录授权端点
@@ -12,8 +13,68 @@ const (
 	EnvSsoEndpointUserinfoURI      xEnv.EnvKey = "SSO_ENDPOINT_USERINFO_URI"      // 单点登录用户信息端点
 	EnvSsoEndpointIntrospectionURI xEnv.EnvKey = "SSO_ENDPOINT_INTROSPECTION_URI" // 单点登录令牌自省端点
 	EnvSsoEndpointRevocationURI    xEnv.EnvKey = "SSO_ENDPOINT_REVOCATION_URI"    // 单点登录令牌注销端点
+	EnvSsoEndpointJwksURI          xEnv.EnvKey = "SSO_ENDPOINT_JWKS_URI"          // 单点登录签名公钥集端点
+	EnvSsoIssuer                   xEnv.EnvKey = "SSO_ISSUER"                     // 单点登录签发者标识（iss）
 	EnvSsoBusinessCache            xEnv.EnvKey = "SSO_BUSINESS_CACHE"             // 业务函数缓存开关（true/false）
+	EnvSsoBusinessFetchLock        xEnv.EnvKey = "SSO_BUSINESS_FETCH_LOCK"        // 是否通过分布式锁合并跨实例的 Userinfo/Introspection 请求（true/false）
+	EnvSsoNegativeCacheTTL         xEnv.EnvKey = "SSO_NEGATIVE_CACHE_TTL"         // 无效令牌负缓存有效期（秒或 Go 时长格式），0 表示关闭
+	EnvSsoInvalidTokenLimit        xEnv.EnvKey = "SSO_INVALID_TOKEN_LIMIT"        // 限流窗口内允许的无效令牌次数（按 IP 与令牌前缀），0 表示关闭
+	EnvSsoInvalidTokenWindow       xEnv.EnvKey = "SSO_INVALID_TOKEN_WINDOW"       // 无效令牌限流窗口（秒或 Go 时长格式）
+	EnvSsoInvalidTokenPrefixLen    xEnv.EnvKey = "SSO_INVALID_TOKEN_PREFIX_LEN"   // 按令牌前缀限流时的前缀长度，0 表示仅按 IP 限流
+	EnvSsoFrontchannelLogoutURI    xEnv.EnvKey = "SSO_FRONTCHANNEL_LOGOUT_URI"    // 在 SSO 登记的前端通道登出地址（frontchannel_logout_uri）
+	EnvSsoSessionCookie            xEnv.EnvKey = "SSO_SESSION_COOKIE"             // 登录回调是否写入本地会话 Cookie（true/false）
+	EnvSsoSessionCookieName        xEnv.EnvKey = "SSO_SESSION_COOKIE_NAME"        // 本地会话 Cookie 名称
+	EnvSsoSessionCookieDomain      xEnv.EnvKey = "SSO_SESSION_COOKIE_DOMAIN"      // 本地会话 Cookie 作用域名
+	EnvSsoSessionCookieSameSite    xEnv.EnvKey = "SSO_SESSION_COOKIE_SAMESITE"    // 本地会话 Cookie 的 SameSite 属性（lax/strict/none）
+	EnvSsoSessionTrustedOrigins    xEnv.EnvKey = "SSO_SESSION_TRUSTED_ORIGINS"    // 允许以会话 Cookie 发起非安全方法请求的其他来源（逗号或空格分隔）
+	EnvSsoTokenHashKey             xEnv.EnvKey = "SSO_TOKEN_HASH_KEY"             // 令牌缓存键 HMAC 密钥
+	EnvSsoTokenHashKeyFile         xEnv.EnvKey = "SSO_TOKEN_HASH_KEY_FILE"        // 令牌缓存键 HMAC 密钥文件路径
+	EnvSsoTokenEncryptionKey       xEnv.EnvKey = "SSO_TOKEN_ENCRYPTION_KEY"       // 令牌缓存值 AEAD 加密密钥（Base64，16/24/32 字节）
+	EnvSsoTokenEncryptionKeyFile   xEnv.EnvKey = "SSO_TOKEN_ENCRYPTION_KEY_FILE"  // 令牌缓存值 AEAD 加密密钥文件路径
+	EnvSsoTokenEncryptionKeys      xEnv.EnvKey = "SSO_TOKEN_ENCRYPTION_KEYS"      // 令牌加密密钥环（kid1:base64,kid2:base64）
+	EnvSsoTokenEncryptionKeyID     xEnv.EnvKey = "SSO_TOKEN_ENCRYPTION_KEY_ID"    // 令牌加密活动密钥 kid（默认取密钥环第一个）
+	EnvSsoTokenEncryptionKeysFile  xEnv.EnvKey = "SSO_TOKEN_ENCRYPTION_KEYS_FILE" // 令牌加密密钥环 JSON 文件路径
+	EnvSsoTokenLegacyRead          xEnv.EnvKey = "SSO_TOKEN_LEGACY_READ"          // 是否读取旧格式（明文键）令牌缓存（true/false）
+	EnvSsoTokenPersistence         xEnv.EnvKey = "SSO_TOKEN_PERSISTENCE"          // 是否将令牌持久化到数据库（true/false），Redis 作为热缓存
+	EnvSsoAutoRefresh              xEnv.EnvKey = "SSO_AUTO_REFRESH"               // Cookie 会话模式下 CheckAuth 是否自动刷新令牌（true/false）
+	EnvSsoAutoRefreshSkew          xEnv.EnvKey = "SSO_AUTO_REFRESH_SKEW"          // 自动刷新提前量（秒），距过期不足该值时刷新
+	EnvSsoStorage                  xEnv.EnvKey = "SSO_STORAGE"                    // 状态存储驱动（redis/memory/gorm），默认 redis
+	EnvSsoStoragePurgeInterval     xEnv.EnvKey = "SSO_STORAGE_PURGE_INTERVAL"     // 数据库中过期状态与令牌记录的清理周期（秒或 Go 时长格式），0 表示不清理
+	EnvSsoLocalCache               xEnv.EnvKey = "SSO_LOCAL_CACHE"                // 是否启用进程内 LRU 二级缓存（true/false）
+	EnvSsoLocalCacheSize           xEnv.EnvKey = "SSO_LOCAL_CACHE_SIZE"           // 进程内缓存每类最大条目数
+	EnvSsoLocalCacheTTL            xEnv.EnvKey = "SSO_LOCAL_CACHE_TTL"            // 进程内缓存条目有效期（秒）
+
+	EnvSsoConfigFile           xEnv.EnvKey = "SSO_CONFIG_FILE"            // SDK 配置文件路径（.yaml/.yml/.json），环境变量优先于文件
+	EnvSsoScopes               xEnv.EnvKey = "SSO_SCOPES"                 // 授权范围（空格或逗号分隔）
+	EnvSsoHTTPTimeout          xEnv.EnvKey = "SSO_HTTP_TIMEOUT"           // 请求 SSO 的 HTTP 超时（秒或 Go 时长格式）
+	EnvSsoHTTPRetry            xEnv.EnvKey = "SSO_HTTP_RETRY"             // 请求 SSO 失败时的重试次数
+	EnvSsoHTTPBreakerThreshold xEnv.EnvKey = "SSO_HTTP_BREAKER_THRESHOLD" // 触发熔断的连续失败次数，0 表示不启用
+	EnvSsoHTTPBreakerCooldown  xEnv.EnvKey = "SSO_HTTP_BREAKER_COOLDOWN"  // 熔断冷却时间（秒或 Go 时长格式）
+
+	EnvSsoWellKnownRefresh xEnv.EnvKey = "SSO_WELL_KNOWN_REFRESH" // 元数据后台刷新间隔（秒或 Go 时长格式），0 表示不刷新
+	EnvSsoReloadOnSighup   xEnv.EnvKey = "SSO_RELOAD_ON_SIGHUP"   // 收到 SIGHUP 时是否重新加载 SDK 配置（true/false）
+	EnvSsoSecretRefresh    xEnv.EnvKey = "SSO_SECRET_REFRESH"     // 重新读取客户端 Secret 文件的间隔（秒或 Go 时长格式），0 表示不刷新
+
+	EnvSsoStartupProbe        xEnv.EnvKey = "SSO_STARTUP_PROBE"         // 启动自检模式（off/warn/fail），默认 off
+	EnvSsoStartupProbeTimeout xEnv.EnvKey = "SSO_STARTUP_PROBE_TIMEOUT" // 启动自检单项超时（秒或 Go 时长格式）
+	EnvSsoHealthTimeout       xEnv.EnvKey = "SSO_HEALTH_TIMEOUT"        // 就绪检查单项超时（秒或 Go 时长格式）
 
 	EnvSsoGrpcHost xEnv.EnvKey = "SSO_GRPC_HOST" // gRPC 主机地址
 	EnvSsoGrpcPort xEnv.EnvKey = "SSO_GRPC_PORT" // gRPC 端口
 )
+
+const (
+	DefaultSessionCookieName = "bss_session" // 本地会话 Cookie 默认名称
+	DefaultSessionSameSite   = "lax"         // 本地会话 Cookie 默认 SameSite 属性
+	DefaultAutoRefreshSkew   = 60            // 自动刷新默认提前量（秒）
+	DefaultStorage           = "redis"       // 状态存储默认驱动
+	DefaultPurgeInterval     = 600           // 数据库过期记录默认清理周期（秒）
+	DefaultLocalCacheSize    = 10000         // 进程内缓存每类默认最大条目数
+	DefaultLocalCacheTTL     = 5             // 进程内缓存条目默认有效期（秒）
+	DefaultNegativeCacheTTL  = 10            // 无效令牌负缓存默认有效期（秒）
+	DefaultInvalidWindow     = 60            // 无效令牌限流默认窗口（秒）
+	DefaultHTTPTimeout       = 10            // 请求 SSO 的默认 HTTP 超时（秒）
+	DefaultStartupProbe      = "off"         // 启动自检默认模式
+	DefaultProbeTimeout      = 5             // 启动自检单项默认超时（秒）
+	DefaultHealthTimeout     = 2             // 就绪检查单项默认超时（秒）
+)
@@ -0,0 +1,7 @@
+package bSdkConst
+
+import xError "github.com/bamboo-services/bamboo-base-go/common/error"
+
+var (
+	ErrRefreshTokenReused = &xError.ErrorCode{Code: 40180, Output: "REFRESH_TOKEN_REUSED", Message: "刷新令牌重复使用"} // 已轮换的刷新令牌被再次使用，整个令牌家族已被吊销
+)
@@ -8,6 +8,7 @@ import (
 	xResult "github.com/bamboo-services/bamboo-base-go/major/result"
 	"github.com/gin-gonic/gin"
 	pb "github.com/phalanx-labs/beacon-sso-sdk/client/api/beacon/sso/v1"
+	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
 	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
@@ -24,11 +25,22 @@ type AccountHandler handler
 // 返回值:
 //   - *AccountHandler: 配置完成的账户处理器实例指针。
 func NewAccountHandler(ctx context.Context) *AccountHandler {
-	newHandler := &AccountHandler{
-		log: xLog.WithName(xLog.NamedCONT, "AccountHandler"),
+	return &AccountHandler{
+		log:     xLog.WithName(xLog.NamedCONT, "AccountHandler"),
+		service: contextService(ctx),
 	}
-	(*handler)(newHandler).registerService(ctx)
-	return newHandler
+}
+
+// NewAccountHandlerWith 使用显式依赖创建 AccountHandler，`Deps.SsoClient` 为空时账户接口返回服务不可用；令牌密钥缺失或非法时返回错误
+func NewAccountHandlerWith(deps bSdkLogic.Deps) (*AccountHandler, error) {
+	svc, err := newService(deps)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountHandler{
+		log:     xLog.WithName(xLog.NamedCONT, "AccountHandler"),
+		service: svc,
+	}, nil
 }
 
 // RegisterByEmail 邮箱注册
@@ -76,7 +88,7 @@ func (h *AccountHandler) RegisterByEmail(ctx *gin.Context) {
 	// 调用业务逻辑
 	resp, err := h.service.authLogic.RegisterByEmail(ctx, &req)
 	if err != nil {
-		_ = ctx.Error(xError.NewError(ctx, xError.OperationFailed, xError.ErrMessage(err.Error()), false, err))
+		_ = ctx.Error(xError.NewError(ctx, errorCode(err, xError.OperationFailed), xError.ErrMessage(err.Error()), false, err))
 		return
 	}
 
@@ -126,7 +138,7 @@ func (h *AccountHandler) PasswordLogin(ctx *gin.Context) {
 	// 调用业务逻辑
 	resp, err := h.service.authLogic.PasswordLogin(ctx, &req)
 	if err != nil {
-		_ = ctx.Error(xError.NewError(ctx, xError.Unauthorized, xError.ErrMessage(err.Error()), false, err))
+		_ = ctx.Error(xError.NewError(ctx, errorCode(err, xError.Unauthorized), xError.ErrMessage(err.Error()), false, err))
 		return
 	}
 
@@ -174,7 +186,7 @@ func (h *AccountHandler) ChangePassword(ctx *gin.Context) {
 	// 调用业务逻辑
 	resp, err := h.service.authLogic.ChangePassword(ctx, &req)
 	if err != nil {
-		_ = ctx.Error(xError.NewError(ctx, xError.OperationFailed, xError.ErrMessage(err.Error()), false, err))
+		_ = ctx.Error(xError.NewError(ctx, errorCode(err, xError.OperationFailed), xError.ErrMessage(err.Error()), false, err))
 		return
 	}
 
@@ -220,7 +232,7 @@ func (h *AccountHandler) RevokeToken(ctx *gin.Context) {
 	// 调用业务逻辑
 	resp, err := h.service.authLogic.RevokeToken(ctx, accessToken, req)
 	if err != nil {
-		_ = ctx.Error(xError.NewError(ctx, xError.Unauthorized, xError.ErrMessage(err.Error()), false, err))
+		_ = ctx.Error(xError.NewError(ctx, errorCode(err, xError.Unauthorized), xError.ErrMessage(err.Error()), false, err))
 		return
 	}
 
@@ -267,7 +279,7 @@ func (h *AccountHandler) RefreshToken(ctx *gin.Context) {
 	// 调用业务逻辑
 	resp, err := h.service.authLogic.RefreshToken(ctx, req.RefreshToken)
 	if err != nil {
-		_ = ctx.Error(xError.NewError(ctx, xError.Unauthorized, xError.ErrMessage(err.Error()), false, err))
+		_ = ctx.Error(xError.NewError(ctx, errorCode(err, xError.Unauthorized), xError.ErrMessage(err.Error()), false, err))
 		return
 	}
 
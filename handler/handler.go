@@ -2,7 +2,9 @@ package bSdkHandler
 
 import (
 	"context"
+	"errors"
 
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
 	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
 )
@@ -10,11 +12,13 @@ import (
 // service 业务逻辑处理层的核心结构体
 //
 // 它负责封装应用程序的核心业务规则和逻辑，作为 HTTP 处理器（handler）与底层数据访问层之间的桥梁。
-// 通常在 `registerService` 方法中初始化并注入到处理器中。
+// 由 `newService`（显式依赖）或 `contextService`（上下文注入）创建并注入到处理器中。
 type service struct {
-	oauthLogic *bSdkLogic.OAuthLogic
-	authLogic  *bSdkLogic.AuthLogic
-	userLogic  *bSdkLogic.UserLogic
+	oauthLogic  *bSdkLogic.OAuthLogic
+	authLogic   *bSdkLogic.AuthLogic
+	userLogic   *bSdkLogic.UserLogic
+	logoutLogic *bSdkLogic.LogoutLogic
+	healthLogic *bSdkLogic.HealthLogic
 }
 
 // handler 是应用程序的 HTTP 处理器结构体。
@@ -31,13 +35,37 @@ type handler struct {
 	service *service
 }
 
-// registerService 注册 Service 的内容
-func (h *handler) registerService(ctx context.Context) {
-	h.service = &service{
-		oauthLogic: bSdkLogic.NewOAuth(ctx),
-		authLogic:  bSdkLogic.NewAuth(ctx),
-		userLogic:  bSdkLogic.NewUser(ctx),
+// newService 使用显式依赖创建 Service，令牌密钥由 `bSdkLogic.NewOAuthWith` 按 `deps.Config` 配置
+func newService(deps bSdkLogic.Deps) (*service, error) {
+	oauthLogic, err := bSdkLogic.NewOAuthWith(deps)
+	if err != nil {
+		return nil, err
 	}
+	return buildService(deps, oauthLogic), nil
+}
+
+// contextService 从 `xReg.Register` 注入的上下文创建 Service，令牌密钥由 `oAuthConfig` 启动节点配置
+func contextService(ctx context.Context) *service {
+	return buildService(bSdkLogic.DepsFromContext(ctx), bSdkLogic.NewOAuth(ctx))
+}
+
+// buildService 组装 Service 的内容
+func buildService(deps bSdkLogic.Deps, oauthLogic *bSdkLogic.OAuthLogic) *service {
+	return &service{
+		oauthLogic:  oauthLogic,
+		authLogic:   bSdkLogic.NewAuthWith(deps),
+		userLogic:   bSdkLogic.NewUserWith(deps),
+		logoutLogic: bSdkLogic.NewLogoutWith(deps),
+		healthLogic: bSdkLogic.NewHealthWith(deps),
+	}
+}
+
+// errorCode 返回业务错误对应的错误码，SsoClient 未初始化时为服务不可用，其余情况为 fallback。
+func errorCode(err error, fallback *xError.ErrorCode) *xError.ErrorCode {
+	if errors.Is(err, bSdkLogic.ErrSsoClientUnavailable) {
+		return xError.ServiceUnavailable
+	}
+	return fallback
 }
 
 // =============
@@ -49,9 +77,20 @@ type AuthHandler handler
 
 // NewAuthHandler 创建并初始化一个 AuthHandler 实例
 func NewAuthHandler(ctx context.Context) *AuthHandler {
-	newHandler := &AuthHandler{
-		log: xLog.WithName(xLog.NamedCONT, "AuthHandler"),
+	return &AuthHandler{
+		log:     xLog.WithName(xLog.NamedCONT, "AuthHandler"),
+		service: contextService(ctx),
+	}
+}
+
+// NewAuthHandlerWith 使用显式依赖创建 AuthHandler；令牌密钥缺失或非法时返回错误
+func NewAuthHandlerWith(deps bSdkLogic.Deps) (*AuthHandler, error) {
+	svc, err := newService(deps)
+	if err != nil {
+		return nil, err
 	}
-	(*handler)(newHandler).registerService(ctx)
-	return newHandler
+	return &AuthHandler{
+		log:     xLog.WithName(xLog.NamedCONT, "AuthHandler"),
+		service: svc,
+	}, nil
 }
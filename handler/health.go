@@ -0,0 +1,77 @@
+package bSdkHandler
+
+import (
+	"context"
+	"net/http"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	"github.com/gin-gonic/gin"
+	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
+)
+
+// HealthHandler 健康检查请求处理器
+type HealthHandler handler
+
+// NewHealthHandler 创建并初始化一个 HealthHandler 实例
+func NewHealthHandler(ctx context.Context) *HealthHandler {
+	return &HealthHandler{
+		log:     xLog.WithName(xLog.NamedCONT, "HealthHandler"),
+		service: contextService(ctx),
+	}
+}
+
+// NewHealthHandlerWith 使用显式依赖创建 HealthHandler；令牌密钥缺失或非法时返回错误
+func NewHealthHandlerWith(deps bSdkLogic.Deps) (*HealthHandler, error) {
+	svc, err := newService(deps)
+	if err != nil {
+		return nil, err
+	}
+	return &HealthHandler{
+		log:     xLog.WithName(xLog.NamedCONT, "HealthHandler"),
+		service: svc,
+	}, nil
+}
+
+// Liveness 存活探针
+//
+// 返回 SDK 进程内状态（元数据新鲜度、JWKS 缓存时长与熔断器状态），不请求外部依赖，恒返回 200。
+//
+// @Summary     [健康] 存活探针
+// @Description 返回 SDK 依赖的进程内状态，不请求外部依赖
+// @Tags        健康检查接口
+// @Produce     json
+// @Success     200  {object}  bSdkLogic.HealthReport  "存活"
+// @Router      /sso/health/live [GET]
+func (h *HealthHandler) Liveness(ctx *gin.Context) {
+	writeHealth(ctx, h.service.healthLogic.Liveness())
+}
+
+// Readiness 就绪探针
+//
+// 在存活报告的基础上检查 gRPC 连通性与 Redis 延迟，单项超时由 `health.timeout`（`SSO_HEALTH_TIMEOUT`）设置；
+// 任一检查失败时返回 503，熔断器状态仅随报告输出。
+//
+// @Summary     [健康] 就绪探针
+// @Description 检查元数据新鲜度、gRPC 连通性、Redis 延迟、JWKS 缓存时长与熔断器状态
+// @Tags        健康检查接口
+// @Produce     json
+// @Success     200  {object}  bSdkLogic.HealthReport  "就绪"
+// @Failure     503  {object}  bSdkLogic.HealthReport  "未就绪"
+// @Router      /sso/health/ready [GET]
+func (h *HealthHandler) Readiness(ctx *gin.Context) {
+	report := h.service.healthLogic.Readiness(ctx, 0)
+	if report.Status != bSdkLogic.HealthUp {
+		h.log.Warn(ctx, "Readiness - SDK 依赖未就绪")
+	}
+	writeHealth(ctx, report)
+}
+
+// writeHealth 按报告状态输出禁止缓存的 JSON 响应。
+func writeHealth(ctx *gin.Context, report *bSdkLogic.HealthReport) {
+	ctx.Header("Cache-Control", "no-store")
+	status := http.StatusOK
+	if report.Status != bSdkLogic.HealthUp {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, report)
+}
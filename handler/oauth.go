@@ -2,12 +2,15 @@ package bSdkHandler
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xHttp "github.com/bamboo-services/bamboo-base-go/defined/http"
 	xResult "github.com/bamboo-services/bamboo-base-go/major/result"
 	"github.com/gin-gonic/gin"
+	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 	"golang.org/x/oauth2"
 )
 
@@ -26,12 +29,41 @@ import (
 func (h *AuthHandler) Login(ctx *gin.Context) {
 	h.log.Info(ctx, "Login - 处理登录跳转请求")
 
-	oAuth, xErr := h.service.oauthLogic.Create(ctx)
+	h.login(ctx, h.service.oauthLogic)
+}
+
+// ProviderLogin 处理命名身份提供方的 OAuth2 登录跳转请求
+//
+// 与 `Login` 相同，但使用路径参数 provider 指定的身份提供方配置生成授权跳转链接。
+//
+// @Summary     [公开] 命名身份提供方登录跳转
+// @Description 生成指定身份提供方的 OAuth2 授权链接并重定向到其授权页面
+// @Tags        OAuth接口
+// @Accept      json
+// @Produce     json
+// @Param       provider  path  string  true  "身份提供方名称"
+// @Success     302  {string}  string  "重定向到身份提供方授权页面"
+// @Failure     404  {object}  xBase.BaseResponse  "身份提供方不存在"
+// @Router      /sso/oauth/{provider}/login [GET]
+func (h *AuthHandler) ProviderLogin(ctx *gin.Context) {
+	h.log.Info(ctx, "ProviderLogin - 处理命名身份提供方登录跳转请求")
+
+	oauthLogic, xErr := h.service.oauthLogic.Provider(ctx, ctx.Param("provider"))
 	if xErr != nil {
 		_ = ctx.Error(xErr)
 		return
 	}
-	authURL, xErr := h.service.oauthLogic.BuildURL(ctx, oAuth)
+	h.login(ctx, oauthLogic)
+}
+
+// login 创建 State 与 PKCE 验证器并重定向到身份提供方的授权页面。
+func (h *AuthHandler) login(ctx *gin.Context, oauthLogic *bSdkLogic.OAuthLogic) {
+	oAuth, xErr := oauthLogic.Create(ctx)
+	if xErr != nil {
+		_ = ctx.Error(xErr)
+		return
+	}
+	authURL, xErr := oauthLogic.BuildURL(ctx, oAuth)
 	if xErr != nil {
 		_ = ctx.Error(xErr)
 		return
@@ -44,6 +76,7 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 //
 // 接收来自外部 SSO 提供商的回调，通过授权码换取访问令牌，并返回登录结果。
 // 该处理器会自动从环境变量中读取 SSO 客户端凭证，并验证请求中携带的 code 和 state 参数。
+// 开启 `session.cookie` 时同时以访问令牌写入本地会话 Cookie。
 //
 // @Summary     [公开] OAuth2 登录回调
 // @Description 处理 SSO 提供商的回调，通过授权码换取访问令牌
@@ -59,6 +92,40 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 func (h *AuthHandler) Callback(ctx *gin.Context) {
 	h.log.Info(ctx, "Callback - 处理登录回调请求")
 
+	h.callback(ctx, h.service.oauthLogic)
+}
+
+// ProviderCallback 处理命名身份提供方的 OAuth2 登录回调请求
+//
+// 与 `Callback` 相同，但 State 校验与授权码换取均使用路径参数 provider 指定的身份提供方，
+// 换取的令牌会记录所属提供方，后续由 `CheckAuth` 按该提供方校验。
+//
+// @Summary     [公开] 命名身份提供方登录回调
+// @Description 处理指定身份提供方的回调，通过授权码换取访问令牌
+// @Tags        OAuth接口
+// @Accept      json
+// @Produce     json
+// @Param       provider  path   string  true  "身份提供方名称"
+// @Param       code      query  string  true  "授权码"
+// @Param       state     query  string  true  "状态参数（CSRF 防护）"
+// @Success     200  {object}  xBase.BaseResponse{data=oauth2.Token}  "登录成功"
+// @Failure     400  {object}  xBase.BaseResponse  "请求参数错误"
+// @Failure     401  {object}  xBase.BaseResponse  "用户拒绝授权或授权失败"
+// @Failure     404  {object}  xBase.BaseResponse  "身份提供方不存在"
+// @Router      /sso/oauth/{provider}/callback [GET]
+func (h *AuthHandler) ProviderCallback(ctx *gin.Context) {
+	h.log.Info(ctx, "ProviderCallback - 处理命名身份提供方登录回调请求")
+
+	oauthLogic, xErr := h.service.oauthLogic.Provider(ctx, ctx.Param("provider"))
+	if xErr != nil {
+		_ = ctx.Error(xErr)
+		return
+	}
+	h.callback(ctx, oauthLogic)
+}
+
+// callback 校验 State 并以授权码换取令牌；未携带 code/state 时使用请求头中的双令牌刷新。
+func (h *AuthHandler) callback(ctx *gin.Context, oauthLogic *bSdkLogic.OAuthLogic) {
 	// 检查是否产生错误返回
 	if getErrString, exist := ctx.GetQuery("error"); exist {
 		switch getErrString {
@@ -78,12 +145,12 @@ func (h *AuthHandler) Callback(ctx *gin.Context) {
 	getState, stateExist := ctx.GetQuery("state")
 
 	if codeExist && stateExist && getCode != "" && getState != "" {
-		oAuth, xErr := h.service.oauthLogic.Verify(ctx, getState)
+		oAuth, xErr := oauthLogic.Verify(ctx, getState)
 		if xErr != nil {
 			_ = ctx.Error(xErr)
 			return
 		}
-		token, xErr := h.service.oauthLogic.Exchange(ctx, getCode, oAuth.Verifier)
+		token, xErr := oauthLogic.Exchange(ctx, getCode, oAuth.Verifier)
 		if xErr != nil {
 			_ = ctx.Error(xErr)
 			return
@@ -97,12 +164,12 @@ func (h *AuthHandler) Callback(ctx *gin.Context) {
 			return
 		}
 
-		cacheToken, xErr := h.service.oauthLogic.GetToken(ctx, getAT)
+		cacheToken, xErr := oauthLogic.GetToken(ctx, getAT)
 		if xErr != nil {
 			_ = ctx.Error(xErr)
 			return
 		}
-		tokenSource, xErr := h.service.oauthLogic.TokenSource(ctx, cacheToken, getRT)
+		tokenSource, xErr := oauthLogic.TokenSource(ctx, cacheToken, getRT)
 		if xErr != nil {
 			_ = ctx.Error(xErr)
 			return
@@ -110,6 +177,10 @@ func (h *AuthHandler) Callback(ctx *gin.Context) {
 		getToken = tokenSource
 	}
 
+	// Cookie 会话模式：以访问令牌写入本地会话 Cookie，后续请求由 `CheckAuth` 读取并自动刷新
+	if session := h.service.oauthLogic.SessionConfig(); session.Cookie {
+		bSdkUtil.SetSessionCookie(ctx, session, getToken.AccessToken)
+	}
 	xResult.SuccessHasData(ctx, "登录成功", getToken)
 }
 
@@ -202,3 +273,72 @@ func (h *AuthHandler) Refresh(ctx *gin.Context) {
 
 	xResult.SuccessHasData(ctx, "刷新令牌成功", newToken)
 }
+
+// BackChannelLogout 处理 OIDC Back-Channel Logout 通知
+//
+// SSO 在用户于 SSO 侧登出时，以 `application/x-www-form-urlencoded` 形式 POST `logout_token`。
+// 校验通过后会清理该会话（sid）或用户（sub）在本地缓存的全部令牌，并触发业务方注册的登出钩子。
+//
+// @Summary     [SSO] OIDC 后端通道登出
+// @Description 接收 SSO 推送的 logout_token，校验后清理本地会话
+// @Tags        OAuth接口
+// @Accept      x-www-form-urlencoded
+// @Produce     json
+// @Param       logout_token  formData  string  true  "登出令牌（JWT）"
+// @Success     200  {object}  xBase.BaseResponse  "登出成功"
+// @Failure     400  {object}  xBase.BaseResponse  "登出令牌无效"
+// @Router      /sso/oauth/backchannel-logout [POST]
+func (h *AuthHandler) BackChannelLogout(ctx *gin.Context) {
+	h.log.Info(ctx, "BackChannelLogout - 处理后端通道登出通知")
+
+	ctx.Header("Cache-Control", "no-store")
+
+	logoutToken := ctx.PostForm("logout_token")
+	if logoutToken == "" {
+		_ = ctx.Error(xError.NewError(ctx, xError.ParameterEmpty, "需要 logout_token 参数", false, nil))
+		return
+	}
+
+	if _, xErr := h.service.logoutLogic.BackChannel(ctx, logoutToken); xErr != nil {
+		_ = ctx.Error(xErr)
+		return
+	}
+
+	xResult.Success(ctx, "登出成功")
+}
+
+// FrontChannelLogout 处理 OIDC Front-Channel Logout 通知
+//
+// SSO 在其登出页中以 iframe 加载该地址，并携带可选的 `iss` 与 `sid` 查询参数。
+// 无论校验结果如何都会清除本地会话 Cookie 并返回一个禁止缓存的空白页面；校验通过时同时清理 Cookie 所属的
+// 服务端会话缓存。`sid` 与 Cookie 所属会话不一致或 `iss` 无效时仅记录告警，不清理服务端会话，
+// 错误不会返回给 SSO 的 iframe。
+//
+// @Summary     [SSO] OIDC 前端通道登出
+// @Description 由 SSO 登出页通过 iframe 加载，清理本地会话 Cookie 与服务端会话
+// @Tags        OAuth接口
+// @Produce     html
+// @Param       iss  query  string  false  "签发者标识"
+// @Param       sid  query  string  false  "SSO 会话标识"
+// @Success     200  {string}  string  "空白页面"
+// @Router      /sso/oauth/frontchannel-logout [GET]
+func (h *AuthHandler) FrontChannelLogout(ctx *gin.Context) {
+	h.log.Info(ctx, "FrontChannelLogout - 处理前端通道登出通知")
+
+	ctx.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+	ctx.Header("Pragma", "no-cache")
+	ctx.Header("Expires", "0")
+
+	_, xErr := h.service.logoutLogic.FrontChannel(
+		ctx,
+		ctx.Query("iss"),
+		ctx.Query("sid"),
+		bSdkUtil.GetSessionCookie(ctx, h.service.oauthLogic.SessionConfig()),
+	)
+	if xErr != nil {
+		h.log.Warn(ctx, "FrontChannelLogout - 前端通道登出校验失败，仅清除本地会话 Cookie", slog.String("error", xErr.Error()))
+	}
+
+	bSdkUtil.ClearSessionCookie(ctx, h.service.oauthLogic.SessionConfig())
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte("<!DOCTYPE html><html><head><title>logout</title></head><body></body></html>"))
+}
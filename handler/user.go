@@ -8,6 +8,7 @@ import (
 	xResult "github.com/bamboo-services/bamboo-base-go/major/result"
 	"github.com/gin-gonic/gin"
 	pb "github.com/phalanx-labs/beacon-sso-sdk/client/api/beacon/sso/v1"
+	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
 	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
@@ -16,11 +17,22 @@ type UserHandler handler
 
 // NewUserHandler 创建并初始化一个 UserHandler 实例
 func NewUserHandler(ctx context.Context) *UserHandler {
-	newHandler := &UserHandler{
-		log: xLog.WithName(xLog.NamedCONT, "UserHandler"),
+	return &UserHandler{
+		log:     xLog.WithName(xLog.NamedCONT, "UserHandler"),
+		service: contextService(ctx),
 	}
-	(*handler)(newHandler).registerService(ctx)
-	return newHandler
+}
+
+// NewUserHandlerWith 使用显式依赖创建 UserHandler，`Deps.SsoClient` 为空时用户接口返回服务不可用；令牌密钥缺失或非法时返回错误
+func NewUserHandlerWith(deps bSdkLogic.Deps) (*UserHandler, error) {
+	svc, err := newService(deps)
+	if err != nil {
+		return nil, err
+	}
+	return &UserHandler{
+		log:     xLog.WithName(xLog.NamedCONT, "UserHandler"),
+		service: svc,
+	}, nil
 }
 
 // GetCurrentUser 获取当前用户信
@@ -46,7 +58,7 @@ func (h *UserHandler) GetCurrentUser(ctx *gin.Context) {
 
 	userinfo, err := h.service.userLogic.GetCurrentUser(ctx, accessToken)
 	if err != nil {
-		_ = ctx.Error(xError.NewError(ctx, xError.Unauthorized, xError.ErrMessage(err.Error()), false, err))
+		_ = ctx.Error(xError.NewError(ctx, errorCode(err, xError.Unauthorized), xError.ErrMessage(err.Error()), false, err))
 		return
 	}
 
@@ -94,7 +106,7 @@ func (h *UserHandler) GetUserByID(ctx *gin.Context) {
 	// 调用业务逻辑
 	userinfo, err := h.service.userLogic.GetUserByID(ctx, accessToken, req)
 	if err != nil {
-		_ = ctx.Error(xError.NewError(ctx, xError.Unauthorized, xError.ErrMessage(err.Error()), false, err))
+		_ = ctx.Error(xError.NewError(ctx, errorCode(err, xError.Unauthorized), xError.ErrMessage(err.Error()), false, err))
 		return
 	}
 
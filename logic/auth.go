@@ -4,19 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"time"
 
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
-	xCtxUtil "github.com/bamboo-services/bamboo-base-go/common/utility/context"
-	xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
-	"github.com/go-resty/resty/v2"
 	bSdkClient "github.com/phalanx-labs/beacon-sso-sdk/client"
 	pb "github.com/phalanx-labs/beacon-sso-sdk/client/api/beacon/sso/v1"
-	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
 	bSdkRepo "github.com/phalanx-labs/beacon-sso-sdk/repository"
-	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
 // AuthLogic 认证业务逻辑组件，封装了用户认证流程的核心处理能力。
@@ -24,9 +19,13 @@ import (
 // 该结构体作为业务层的聚合器，整合了 SsoClient 的认证服务接口，
 // 用于处理用户注册、登录和密码修改等复杂逻辑。
 type AuthLogic struct {
-	log       *xLog.LogNamedLogger     // 日志实例
-	ssoClient bSdkClient.IAuth         // SsoClient Auth 服务接口
-	tokenData *bSdkRepo.OAuthTokenRepo // OAuth Token 数据仓储实例
+	log        *xLog.LogNamedLogger     // 日志实例
+	ssoClient  bSdkClient.IAuth         // SsoClient Auth 服务接口
+	tokenData  *bSdkRepo.OAuthTokenRepo // OAuth Token 数据仓储实例
+	revocation *RevocationLogic         // 令牌吊销广播逻辑
+	family     *TokenFamilyLogic        // 刷新令牌家族逻辑
+	oauth      *OAuthLogic              // OAuth 业务逻辑，刷新令牌时复用其并发控制
+	cfg        *bSdkConfig.Config       // SDK 配置，未注册时按需读取环境变量
 }
 
 // NewAuth 创建并初始化一个新的 AuthLogic 业务逻辑实例。
@@ -36,20 +35,29 @@ type AuthLogic struct {
 // 从而为认证流程提供完整的日志追踪能力。
 //
 // 参数:
-//   - ctx: 请求上下文，用于获取 SsoClient 实例、数据库和 Redis 实例。
+//   - ctx: 请求上下文，用于获取 SsoClient 实例、数据库与状态存储实例；未注册 SsoClient 时依赖 gRPC 的方法返回 `ErrSsoClientUnavailable`。
 //
 // 返回值:
 //   - *AuthLogic: 配置完成的认证逻辑层实例指针。
 func NewAuth(ctx context.Context) *AuthLogic {
-	client := bSdkUtil.GetSsoClient(ctx)
-	db := xCtxUtil.MustGetDB(ctx)
-	rdb := xCtxUtil.MustGetRDB(ctx)
-
-	return &AuthLogic{
-		log:       xLog.WithName(xLog.NamedLOGC, "AuthLogic"),
-		ssoClient: client.Auth,
-		tokenData: bSdkRepo.NewOAuthTokenRepo(db, rdb),
+	return NewAuthWith(DepsFromContext(ctx))
+}
+
+// NewAuthWith 使用显式依赖创建 AuthLogic，`Deps.SsoClient` 为空时依赖 gRPC 的方法返回 `ErrSsoClientUnavailable`。
+func NewAuthWith(deps Deps) *AuthLogic {
+	cfg := sdkConfig(deps.Config)
+	logic := &AuthLogic{
+		log:        xLog.WithName(xLog.NamedLOGC, "AuthLogic"),
+		tokenData:  bSdkRepo.NewOAuthTokenRepoWith(deps.DB, deps.store(), cfg),
+		revocation: NewRevocationWith(deps),
+		family:     NewTokenFamilyWith(deps),
+		oauth:      newOAuthWith(deps),
+		cfg:        deps.Config,
 	}
+	if deps.SsoClient != nil {
+		logic.ssoClient = deps.SsoClient.Auth
+	}
+	return logic
 }
 
 // RegisterByEmail 通过邮箱注册
@@ -66,7 +74,10 @@ func NewAuth(ctx context.Context) *AuthLogic {
 //   - error: 如果注册失败（如验证码错误、邮箱已注册），则返回非 nil 的错误。
 func (l *AuthLogic) RegisterByEmail(ctx context.Context, req *pb.RegisterByEmailRequest) (*pb.RegisterByEmailResponse, error) {
 	l.log.Info(ctx, "RegisterByEmail - 处理邮箱注册请求")
-	return l.ssoClient.RegisterByEmail(ctx, req)
+	if l.ssoClient == nil {
+		return nil, ErrSsoClientUnavailable
+	}
+	return l.ssoClient.RegisterByEmail(grpcContext(ctx, l.cfg), req)
 }
 
 // PasswordLogin 密码登录（Resource Owner Password Credentials Grant）
@@ -84,9 +95,12 @@ func (l *AuthLogic) RegisterByEmail(ctx context.Context, req *pb.RegisterByEmail
 //   - error: 如果登录失败（如凭证无效），则返回非 nil 的错误。
 func (l *AuthLogic) PasswordLogin(ctx context.Context, req *pb.PasswordLoginRequest) (*pb.PasswordLoginResponse, error) {
 	l.log.Info(ctx, "PasswordLogin - 处理密码登录请求")
+	if l.ssoClient == nil {
+		return nil, ErrSsoClientUnavailable
+	}
 
 	// 调用 gRPC 服务
-	resp, err := l.ssoClient.PasswordLogin(ctx, req)
+	resp, err := l.ssoClient.PasswordLogin(grpcContext(ctx, l.cfg), req)
 	if err != nil {
 		return nil, err
 	}
@@ -100,6 +114,12 @@ func (l *AuthLogic) PasswordLogin(ctx context.Context, req *pb.PasswordLoginRequ
 			RefreshToken: resp.GetRefreshToken(),
 			Expiry:       expiry.Format(time.RFC3339),
 		}
+		bindTokenIdentity(cacheToken, resp.GetIdToken())
+		if familyErr := l.family.Start(ctx, cacheToken); familyErr != nil {
+			l.log.Warn(ctx, "PasswordLogin - 创建令牌家族失败",
+				slog.String("error", familyErr.Error()),
+			)
+		}
 		if storeErr := l.tokenData.Store(ctx, cacheToken); storeErr != nil {
 			l.log.Warn(ctx, "PasswordLogin - 缓存令牌失败",
 				slog.String("error", storeErr.Error()),
@@ -124,7 +144,10 @@ func (l *AuthLogic) PasswordLogin(ctx context.Context, req *pb.PasswordLoginRequ
 //   - error: 如果修改失败（如旧密码错误），则返回非 nil 的错误。
 func (l *AuthLogic) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
 	l.log.Info(ctx, "ChangePassword - 处理修改密码请求")
-	return l.ssoClient.ChangePassword(ctx, req)
+	if l.ssoClient == nil {
+		return nil, ErrSsoClientUnavailable
+	}
+	return l.ssoClient.ChangePassword(grpcContext(ctx, l.cfg), req)
 }
 
 // RevokeToken 注销用户 Token（登出）
@@ -143,9 +166,12 @@ func (l *AuthLogic) ChangePassword(ctx context.Context, req *pb.ChangePasswordRe
 //   - error: 注销失败时返回错误。
 func (l *AuthLogic) RevokeToken(ctx context.Context, accessToken string, req *pb.RevokeTokenRequest) (*pb.RevokeTokenResponse, error) {
 	l.log.Info(ctx, "RevokeToken - 处理注销令牌请求")
+	if l.ssoClient == nil {
+		return nil, ErrSsoClientUnavailable
+	}
 
 	// 调用 gRPC 服务
-	resp, err := l.ssoClient.RevokeToken(ctx, accessToken, req)
+	resp, err := l.ssoClient.RevokeToken(grpcContext(ctx, l.cfg), accessToken, req)
 	if err != nil {
 		return nil, err
 	}
@@ -156,6 +182,11 @@ func (l *AuthLogic) RevokeToken(ctx context.Context, accessToken string, req *pb
 			slog.String("error", delErr.Error()),
 		)
 	}
+	if pubErr := l.revocation.Publish(ctx, RevocationReasonRevoke, "access_token", accessToken); pubErr != nil {
+		l.log.Warn(ctx, "RevokeToken - 广播令牌吊销失败",
+			slog.String("error", pubErr.Error()),
+		)
+	}
 
 	return resp, nil
 }
@@ -174,12 +205,16 @@ type RefreshTokenResponse struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 	// Scope 授权范围
 	Scope string `json:"scope,omitempty"`
+	// IDToken OIDC 身份令牌（仅在授权范围包含 openid 时返回）
+	IDToken string `json:"id_token,omitempty"`
 }
 
 // RefreshToken 使用 Refresh Token 获取新的 Access Token
 //
-// 该方法通过 HTTP REST API 实现 OAuth 2.0 Refresh Token Grant。
-// 刷新成功后会更新本地缓存的 Token，支持 Token Rotation 机制。
+// 该方法实现 OAuth 2.0 Refresh Token Grant，刷新交由 `OAuthLogic.RefreshByToken` 完成，
+// 与 `/sso/oauth/refresh` 共享刷新锁、singleflight 与近期刷新结果，并发刷新同一令牌不会被判定为重放。
+// 刷新成功后会更新本地缓存的 Token，支持 Token Rotation 机制；
+// 已轮换的刷新令牌被再次使用时吊销整个令牌家族并返回 `ErrRefreshTokenReused` 错误。
 //
 // 参数说明:
 //   - ctx: 上下文，用于控制请求的生命周期和超时控制。
@@ -195,57 +230,20 @@ func (l *AuthLogic) RefreshToken(ctx context.Context, refreshToken string) (*Ref
 		return nil, fmt.Errorf("refresh_token 不能为空")
 	}
 
-	// 获取 Token 端点配置
-	tokenURI := xEnv.GetEnvString(bSdkConst.EnvSsoEndpointTokenURI, "")
-	if tokenURI == "" {
-		return nil, fmt.Errorf("令牌端点未配置")
+	token, xErr := l.oauth.RefreshByToken(ctx, refreshToken)
+	if xErr != nil {
+		return nil, xErr
 	}
 
-	// 获取客户端凭证
-	clientID := xEnv.GetEnvString(bSdkConst.EnvSsoClientID, "")
-	clientSecret := xEnv.GetEnvString(bSdkConst.EnvSsoClientSecret, "")
-	if clientID == "" || clientSecret == "" {
-		return nil, fmt.Errorf("客户端凭证配置缺失")
+	resp := &RefreshTokenResponse{
+		AccessToken:  token.AccessToken,
+		TokenType:    token.Type(),
+		RefreshToken: token.RefreshToken,
+		Scope:        tokenExtraString(token, "scope"),
+		IDToken:      tokenExtraString(token, "id_token"),
 	}
-
-	// 构建请求
-	client := resty.New()
-	var respBody RefreshTokenResponse
-	resp, reqErr := client.R().
-		SetContext(ctx).
-		SetHeader("Accept", "application/json").
-		SetHeader("Content-Type", "application/x-www-form-urlencoded").
-		SetBasicAuth(clientID, clientSecret).
-		SetFormData(map[string]string{
-			"grant_type":    "refresh_token",
-			"refresh_token": refreshToken,
-		}).
-		SetResult(&respBody).
-		Post(tokenURI)
-
-	if reqErr != nil {
-		return nil, fmt.Errorf("刷新令牌请求失败: %w", reqErr)
+	if !token.Expiry.IsZero() {
+		resp.ExpiresIn = int64(time.Until(token.Expiry).Round(time.Second) / time.Second)
 	}
-
-	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("刷新令牌失败，状态码: %d, 响应: %s", resp.StatusCode(), resp.String())
-	}
-
-	// 更新本地缓存的 Token
-	if respBody.AccessToken != "" {
-		expiry := time.Now().Add(time.Duration(respBody.ExpiresIn) * time.Second)
-		cacheToken := &bSdkModels.CacheOAuthToken{
-			AccessToken:  respBody.AccessToken,
-			TokenType:    respBody.TokenType,
-			RefreshToken: respBody.RefreshToken,
-			Expiry:       expiry.Format(time.RFC3339),
-		}
-		if storeErr := l.tokenData.Store(ctx, cacheToken); storeErr != nil {
-			l.log.Warn(ctx, "RefreshToken - 缓存令牌失败",
-				slog.String("error", storeErr.Error()),
-			)
-		}
-	}
-
-	return &respBody, nil
+	return resp, nil
 }
@@ -6,44 +6,93 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
 	xUtil "github.com/bamboo-services/bamboo-base-go/common/utility"
-	xCtxUtil "github.com/bamboo-services/bamboo-base-go/common/utility/context"
-	xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
-	"github.com/go-resty/resty/v2"
-	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
 	bSdkRepo "github.com/phalanx-labs/beacon-sso-sdk/repository"
-	"github.com/redis/go-redis/v9"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
 
+const (
+	businessWaitTimeout  = time.Second * 3       // 等待其他实例完成上游请求的最长时间
+	businessPollInterval = time.Millisecond * 50 // 等待期间轮询业务缓存的间隔
+	businessFetchTimeout = time.Second * 10      // 共享上游请求（等待锁 + 请求 SSO）的最长时间
+
+	userinfoRevalidateTimeout = time.Second * 10 // 后台刷新用户信息的超时时间
+)
+
+var (
+	// businessGroup 进程内 Userinfo / Introspection singleflight，合并同一实例上对同一令牌的并发请求。
+	businessGroup singleflight.Group
+
+	// userinfoRevalidating 正在后台刷新的用户信息（令牌指纹），避免重复启动刷新协程。
+	userinfoRevalidating sync.Map
+)
+
+// businessResult singleflight 共享的上游请求结果。
+type businessResult[T any] struct {
+	value       *T
+	unavailable bool // 失败是否由上游不可用导致
+	xErr        *xError.Error
+}
+
 // BusinessLogic 提供与业务相关的独立 OAuth 能力。
 type BusinessLogic struct {
+	cfg               *bSdkConfig.Config
 	db                *gorm.DB
-	rdb               *redis.Client
+	store             bSdkStore.Store
 	log               *xLog.LogNamedLogger
 	userinfoData      *bSdkRepo.UserinfoRepo
 	introspectionData *bSdkRepo.IntrospectionRepo
+	lockData          *bSdkRepo.BusinessLockRepo
+	httpClient        *http.Client
 }
 
 // NewBusiness 创建并初始化 BusinessLogic。
 func NewBusiness(ctx context.Context) *BusinessLogic {
-	db := xCtxUtil.MustGetDB(ctx)
-	rdb := xCtxUtil.MustGetRDB(ctx)
+	return newBusinessWith(DepsFromContext(ctx))
+}
+
+// NewBusinessWith 使用显式依赖创建 BusinessLogic，并按 `deps.Config` 配置进程级令牌密钥（参见 `bSdkUtil.ConfigureTokenKeys`）。
+//
+// 令牌密钥缺失、非法或与进程内已配置的密钥冲突时返回错误。
+func NewBusinessWith(deps Deps) (*BusinessLogic, error) {
+	if err := deps.configureTokenKeys(); err != nil {
+		return nil, err
+	}
+	return newBusinessWith(deps), nil
+}
+
+// newBusinessWith 使用显式依赖创建 BusinessLogic，不配置令牌密钥；上下文构造函数的令牌密钥由 `oAuthConfig` 启动节点配置。
+func newBusinessWith(deps Deps) *BusinessLogic {
+	cfg := sdkConfig(deps.Config)
+	store := deps.store()
 
 	return &BusinessLogic{
-		db:                db,
-		rdb:               rdb,
+		cfg:               deps.Config,
+		db:                deps.DB,
+		store:             store,
 		log:               xLog.WithName(xLog.NamedLOGC, "BusinessLogic"),
-		userinfoData:      bSdkRepo.NewUserinfoRepo(db, rdb),
-		introspectionData: bSdkRepo.NewIntrospectionRepo(db, rdb),
+		userinfoData:      bSdkRepo.NewUserinfoRepoWith(deps.DB, store, cfg),
+		introspectionData: bSdkRepo.NewIntrospectionRepoWith(deps.DB, store, cfg),
+		lockData:          bSdkRepo.NewBusinessLockRepoWith(deps.DB, store, cfg),
+		httpClient:        deps.HTTPClient,
 	}
 }
 
+// config 返回当前实例在上下文所属租户下生效的 SDK 配置。
+func (l *BusinessLogic) config(ctx context.Context) *bSdkConfig.Config {
+	return tenantConfig(ctx, l.cfg)
+}
+
 // Userinfo 通过 Access Token 获取并解析 SSO 用户信息
 //
 // 该方法接收一个有效的 Access Token，向配置的 SSO Userinfo 端点发起请求，
@@ -51,6 +100,12 @@ func NewBusiness(ctx context.Context) *BusinessLogic {
 // 响应的原始 JSON 数据映射到结构化对象及 Raw 字段中，以兼容标准字段及
 // 扩展字段。
 //
+// 缓存未命中时，同一实例上对同一令牌的并发请求通过 singleflight 合并为一次上游请求；
+// 启用 `SSO_BUSINESS_FETCH_LOCK` 后还会通过分布式锁在实例间合并，其余实例等待业务缓存写入后直接读取。
+//
+// 配置 `cache.entries.userinfo.stale_ttl`（`SSO_CACHE_USERINFO_STALE_TTL`）后，超过新鲜期但仍在宽限期内的缓存会立即返回并在后台刷新；
+// 配置 `cache.entries.userinfo.stale_if_error_ttl`（`SSO_CACHE_USERINFO_STALE_IF_ERROR_TTL`）后，SSO 不可用（网络错误或 5xx）时返回宽限期内的过期缓存。
+//
 // 参数说明:
 //   - ctx: 上下文对象，用于传递请求上下文及日志追踪。
 //   - accessToken: 访问令牌，用于 Bearer 认证。
@@ -65,32 +120,120 @@ func (l *BusinessLogic) Userinfo(ctx context.Context, accessToken string) (*bSdk
 		return nil, xError.NewError(ctx, xError.ParameterEmpty, "令牌为空", false, nil)
 	}
 
-	cacheValue, cacheExists, cacheErr := l.userinfoData.GetCache(ctx, accessToken)
+	cacheValue, freshness, cacheErr := l.userinfoData.LookupCache(ctx, accessToken)
 	if cacheErr != nil {
 		l.log.Warn(ctx, "BusinessLogic|Userinfo - 读取缓存失败",
 			slog.String("error", cacheErr.Error()),
 		)
-	} else if cacheExists {
+	}
+	switch freshness {
+	case bSdkRepo.UserinfoFresh:
 		return cacheValue, nil
+	case bSdkRepo.UserinfoStale:
+		l.revalidateUserinfo(ctx, accessToken)
+		return cacheValue, nil
+	}
+
+	invalid, xErr := l.checkInvalid(ctx, invalidKindUserinfo, accessToken)
+	if xErr != nil {
+		return nil, xErr
+	}
+	if invalid {
+		return nil, errInvalidUserinfo(ctx)
+	}
+
+	var stale *bSdkModels.OAuthUserinfo
+	if freshness == bSdkRepo.UserinfoStaleIfError {
+		stale = cacheValue
+	}
+	return l.loadUserinfo(ctx, accessToken, stale)
+}
+
+// loadUserinfo 合并并发请求后获取用户信息，上游不可用且存在 stale 时返回过期缓存。
+//
+// 过期缓存在合并请求之外按各调用方自身持有的 stale 判断，合并的请求只共享上游结果。
+func (l *BusinessLogic) loadUserinfo(ctx context.Context, accessToken string, stale *bSdkModels.OAuthUserinfo) (*bSdkModels.OAuthUserinfo, *xError.Error) {
+	fingerprint, err := bSdkUtil.TokenFingerprint(accessToken)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	userinfo, unavailable, xErr := coalesceFetch(ctx, l, "userinfo:"+fingerprint, l.userinfoData.CacheEnabled(),
+		func(ctx context.Context) (*bSdkModels.OAuthUserinfo, *xError.Error, bool) {
+			if invalid, err := l.introspectionData.IsInvalid(ctx, invalidKindUserinfo, accessToken); err == nil && invalid {
+				return nil, errInvalidUserinfo(ctx), true
+			}
+			value, exists, err := l.userinfoData.GetCache(ctx, accessToken)
+			return value, nil, err == nil && exists
+		},
+		func(ctx context.Context) (*bSdkModels.OAuthUserinfo, bool, *xError.Error) {
+			return l.fetchUserinfo(ctx, accessToken)
+		},
+	)
+	if xErr != nil && unavailable && stale != nil {
+		l.log.Warn(ctx, "BusinessLogic|loadUserinfo - 上游不可用，返回过期缓存",
+			slog.String("error", xErr.Error()),
+		)
+		return stale, nil
+	}
+	return userinfo, xErr
+}
+
+// revalidateUserinfo 在后台刷新处于 stale-while-revalidate 宽限期的用户信息
+//
+// 同一令牌同时只有一个后台刷新；刷新使用独立的上下文，不依赖已返回的请求，仅继承请求所属的租户。
+func (l *BusinessLogic) revalidateUserinfo(parent context.Context, accessToken string) {
+	fingerprint, err := bSdkUtil.TokenFingerprint(accessToken)
+	if err != nil {
+		return
+	}
+	key := bSdkUtil.TenantNamespace(parent) + fingerprint
+	if _, running := userinfoRevalidating.LoadOrStore(key, struct{}{}); running {
+		return
 	}
 
-	userinfoURI := xEnv.GetEnvString(bSdkConst.EnvSsoEndpointUserinfoURI, "")
+	go func() {
+		defer userinfoRevalidating.Delete(key)
+
+		ctx, cancel := context.WithTimeout(bSdkUtil.InheritTenant(context.Background(), parent), userinfoRevalidateTimeout)
+		defer cancel()
+		if _, xErr := l.loadUserinfo(ctx, accessToken, nil); xErr != nil {
+			l.log.Warn(ctx, "BusinessLogic|revalidateUserinfo - 后台刷新用户信息失败",
+				slog.String("error", xErr.Error()),
+			)
+		}
+	}()
+}
+
+// fetchUserinfo 请求 SSO Userinfo 端点并写入业务缓存。
+//
+// 返回的 bool 表示失败是否由上游不可用（网络错误或 5xx）导致，此时允许使用 stale-if-error 缓存。
+func (l *BusinessLogic) fetchUserinfo(ctx context.Context, accessToken string) (*bSdkModels.OAuthUserinfo, bool, *xError.Error) {
+	cfg := l.config(ctx)
+	userinfoURI := cfg.Endpoints.Userinfo
 	if userinfoURI == "" {
-		return nil, xError.NewError(ctx, xError.OperationFailed, "用户信息端点为空", false, nil)
+		return nil, false, xError.NewError(ctx, xError.OperationFailed, "用户信息端点为空", false, nil)
 	}
 
-	client := resty.New()
-	resp, err := client.R().
+	resp, err := cfg.NewRestyClientWith(l.httpClient).R().
 		SetContext(ctx).
 		SetHeader("Accept", "application/json").
 		SetAuthToken(accessToken).
 		Get(userinfoURI)
 	if err != nil {
-		return nil, xError.NewError(ctx, xError.OperationFailed, "请求用户信息失败", false, err)
+		return nil, true, xError.NewError(ctx, xError.OperationFailed, "请求用户信息失败", false, err)
 	}
 
+	if resp.StatusCode() == http.StatusUnauthorized {
+		l.recordInvalid(ctx, invalidKindUserinfo, accessToken)
+		// 令牌已失效，过期缓存不可再用于 stale 响应
+		if xErr := l.userinfoData.DeleteCache(ctx, accessToken); xErr != nil {
+			l.log.Warn(ctx, "BusinessLogic|fetchUserinfo - 删除缓存失败",
+				slog.String("error", xErr.Error()),
+			)
+		}
+	}
 	if resp.StatusCode() != http.StatusOK {
-		return nil, xError.NewError(
+		return nil, resp.StatusCode() >= http.StatusInternalServerError, xError.NewError(
 			ctx,
 			xError.Unauthorized,
 			xError.ErrMessage(fmt.Sprintf("获取用户信息失败，状态码: %d", resp.StatusCode())),
@@ -101,7 +244,7 @@ func (l *BusinessLogic) Userinfo(ctx context.Context, accessToken string) (*bSdk
 
 	raw := make(map[string]any)
 	if err = json.Unmarshal(resp.Body(), &raw); err != nil {
-		return nil, xError.NewError(ctx, xError.OperationFailed, "解析用户信息失败", false, err)
+		return nil, false, xError.NewError(ctx, xError.OperationFailed, "解析用户信息失败", false, err)
 	}
 
 	userinfo := &bSdkModels.OAuthUserinfo{Raw: raw}
@@ -121,18 +264,19 @@ func (l *BusinessLogic) Userinfo(ctx context.Context, accessToken string) (*bSdk
 		userinfo.Phone = value
 	}
 
-	if cacheErr = l.userinfoData.StoreCache(ctx, accessToken, userinfo); cacheErr != nil {
-		l.log.Warn(ctx, "BusinessLogic|Userinfo - 写入缓存失败",
+	if cacheErr := l.userinfoData.StoreCache(ctx, accessToken, userinfo); cacheErr != nil {
+		l.log.Warn(ctx, "BusinessLogic|fetchUserinfo - 写入缓存失败",
 			slog.String("error", cacheErr.Error()),
 		)
 	}
-	return userinfo, nil
+	return userinfo, false, nil
 }
 
 // Introspection 调用 OAuth2 Introspection Endpoint 查询令牌状态与有效期。
 //
 // 该方法接收令牌类型和令牌值，向配置的 SSO Introspection 端点发起请求，
 // 获取令牌的活跃状态、过期时间等信息，并缓存结果以提升后续查询性能。
+// 并发未命中的合并策略与 `Userinfo` 相同。
 //
 // 参数说明:
 //   - ctx: 上下文对象，用于传递请求上下文及日志追踪。
@@ -161,19 +305,50 @@ func (l *BusinessLogic) Introspection(ctx context.Context, tokenType string, tok
 		return cacheValue, nil
 	}
 
-	introspectionURI := xEnv.GetEnvString(bSdkConst.EnvSsoEndpointIntrospectionURI, "")
+	kind := invalidKindIntrospection + ":" + tokenType
+	invalid, xErr := l.checkInvalid(ctx, kind, token)
+	if xErr != nil {
+		return nil, xErr
+	}
+	if invalid {
+		return &bSdkModels.OAuthIntrospection{Active: false}, nil
+	}
+
+	fingerprint, err := bSdkUtil.TokenFingerprint(token)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	result, _, xErr := coalesceFetch(ctx, l, "introspection:"+tokenType+":"+fingerprint, l.introspectionData.CacheEnabled(),
+		func(ctx context.Context) (*bSdkModels.OAuthIntrospection, *xError.Error, bool) {
+			if invalid, err := l.introspectionData.IsInvalid(ctx, kind, token); err == nil && invalid {
+				return &bSdkModels.OAuthIntrospection{Active: false}, nil, true
+			}
+			value, exists, err := l.introspectionData.GetCache(ctx, tokenType, token)
+			return value, nil, err == nil && exists
+		},
+		func(ctx context.Context) (*bSdkModels.OAuthIntrospection, bool, *xError.Error) {
+			result, xErr := l.fetchIntrospection(ctx, tokenType, token)
+			return result, false, xErr
+		},
+	)
+	return result, xErr
+}
+
+// fetchIntrospection 请求 SSO Introspection 端点并写入业务缓存。
+func (l *BusinessLogic) fetchIntrospection(ctx context.Context, tokenType string, token string) (*bSdkModels.OAuthIntrospection, *xError.Error) {
+	cfg := l.config(ctx)
+	introspectionURI := cfg.Endpoints.Introspection
 	if introspectionURI == "" {
 		return nil, xError.NewError(ctx, xError.OperationFailed, "自省端点为空", false, nil)
 	}
 
-	clientID := xEnv.GetEnvString(bSdkConst.EnvSsoClientID, "")
-	clientSecret := xEnv.GetEnvString(bSdkConst.EnvSsoClientSecret, "")
+	clientID := cfg.Client.ID
+	clientSecret := cfg.Client.Secret
 	if clientID == "" || clientSecret == "" {
 		return nil, xError.NewError(ctx, xError.OperationFailed, "客户端配置缺失", false, nil)
 	}
 
-	client := resty.New()
-	resp, reqErr := client.R().
+	resp, reqErr := cfg.NewRestyClientWith(l.httpClient).R().
 		SetContext(ctx).
 		SetHeader("Accept", "application/json").
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
@@ -225,10 +400,114 @@ func (l *BusinessLogic) Introspection(ctx context.Context, tokenType string, tok
 		result.ExpiresIn = expiresIn
 	}
 
-	if cacheErr = l.introspectionData.StoreCache(ctx, tokenType, token, result); cacheErr != nil {
-		l.log.Warn(ctx, "BusinessLogic|Introspection - 写入缓存失败",
+	// 未激活的令牌写入独立的短期负缓存，不进入业务缓存
+	if !result.Active {
+		l.recordInvalid(ctx, invalidKindIntrospection+":"+tokenType, token)
+		return result, nil
+	}
+
+	if cacheErr := l.introspectionData.StoreCache(ctx, tokenType, token, result); cacheErr != nil {
+		l.log.Warn(ctx, "BusinessLogic|fetchIntrospection - 写入缓存失败",
 			slog.String("error", cacheErr.Error()),
 		)
 	}
 	return result, nil
 }
+
+// coalesceFetch 合并同一键上的并发上游请求
+//
+// 进程内通过 singleflight 共享结果；启用 `SSO_BUSINESS_FETCH_LOCK` 且对应业务缓存开启时，
+// 再通过分布式锁保证多实例间仅一个实例请求 SSO。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - l: 业务逻辑实例。
+//   - key: 合并键（请求类型:令牌指纹），同时用作分布式锁标识。
+//   - shared: 对应业务缓存是否启用，关闭时等待方无法取回结果，不使用分布式锁。
+//   - cached: 读取业务缓存或负缓存，命中时返回 true 及对应的结果或错误。
+//   - fetch: 请求 SSO 并写入业务缓存，返回的 bool 表示失败是否由上游不可用导致。
+//
+// 返回值:
+//   - *T: 上游或业务缓存的结果。
+//   - bool: 失败是否由上游不可用导致，调用方据此决定是否使用自身持有的过期缓存。
+//   - *xError.Error: 请求失败或等待超时时返回的错误。
+func coalesceFetch[T any](
+	ctx context.Context,
+	l *BusinessLogic,
+	key string,
+	shared bool,
+	cached func(ctx context.Context) (*T, *xError.Error, bool),
+	fetch func(ctx context.Context) (*T, bool, *xError.Error),
+) (*T, bool, *xError.Error) {
+	// singleflight 为进程级，键附加租户命名空间，避免不同租户的同一令牌共享上游结果
+	// 共享请求脱离首个调用方的取消信号，避免其断开连接导致其他等待方一同失败；各调用方仍可因自身取消提前返回
+	ch := businessGroup.DoChan(bSdkUtil.TenantNamespace(ctx)+key, func() (interface{}, error) {
+		sharedCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), businessFetchTimeout)
+		defer cancel()
+		result, unavailable, xErr := lockedFetch(sharedCtx, l, key, shared, cached, fetch)
+		return &businessResult[T]{value: result, unavailable: unavailable, xErr: xErr}, nil
+	})
+	select {
+	case res := <-ch:
+		result := res.Val.(*businessResult[T])
+		return result.value, result.unavailable, result.xErr
+	case <-ctx.Done():
+		return nil, false, xError.NewError(ctx, xError.Timeout, "等待上游请求超时", false, ctx.Err())
+	}
+}
+
+// lockedFetch 在分布式锁保护下执行上游请求
+//
+// 未获取到锁时轮询业务缓存，直至持锁实例写入结果；等待超时或锁不可用时退化为直接请求，
+// 上游请求是幂等的，退化只会多一次请求而不会影响正确性。
+func lockedFetch[T any](
+	ctx context.Context,
+	l *BusinessLogic,
+	key string,
+	shared bool,
+	cached func(ctx context.Context) (*T, *xError.Error, bool),
+	fetch func(ctx context.Context) (*T, bool, *xError.Error),
+) (*T, bool, *xError.Error) {
+	if !shared || !l.config(ctx).Cache.FetchLock {
+		return fetch(ctx)
+	}
+
+	owner := xUtil.Generate().RandomUpperString(32)
+	deadline := time.Now().Add(businessWaitTimeout)
+	for {
+		locked, xErr := l.lockData.Lock(ctx, key, owner)
+		if xErr != nil {
+			l.log.Warn(ctx, "BusinessLogic|lockedFetch - 获取上游请求锁失败，直接请求",
+				slog.String("error", xErr.Error()),
+			)
+			return fetch(ctx)
+		}
+		if locked {
+			break
+		}
+		if value, xErr, ok := cached(ctx); ok {
+			return value, false, xErr
+		}
+		if time.Now().After(deadline) {
+			return fetch(ctx)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false, xError.NewError(ctx, xError.Timeout, "等待上游请求超时", false, ctx.Err())
+		case <-time.After(businessPollInterval):
+		}
+	}
+	defer func() {
+		if xErr := l.lockData.Unlock(context.WithoutCancel(ctx), key, owner); xErr != nil {
+			l.log.Warn(ctx, "BusinessLogic|lockedFetch - 释放上游请求锁失败",
+				slog.String("error", xErr.Error()),
+			)
+		}
+	}()
+
+	// 获取锁后再次检查，等待期间可能已由其他实例写入缓存
+	if value, xErr, ok := cached(ctx); ok {
+		return value, false, xErr
+	}
+	return fetch(ctx)
+}
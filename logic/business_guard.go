@@ -0,0 +1,83 @@
+package bSdkLogic
+
+import (
+	"context"
+	"log/slog"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// 无效令牌负缓存的请求类型，Introspection 还会追加令牌类型。
+const (
+	invalidKindUserinfo      = "userinfo"
+	invalidKindIntrospection = "introspection"
+)
+
+// errInvalidUserinfo 令牌命中 Userinfo 负缓存时返回的错误，与上游 401 保持一致。
+func errInvalidUserinfo(ctx context.Context) *xError.Error {
+	return xError.NewError(ctx, xError.Unauthorized, "访问令牌无效", false, nil)
+}
+
+// checkInvalid 在请求 SSO 前本地拦截无效令牌
+//
+// 令牌命中负缓存时返回 true；配置了 `business.invalid_token_limit` 且请求方（客户端 IP 或令牌前缀）
+// 在当前窗口内提交的无效令牌已达上限时返回 `TooManyRequests` 错误。读取失败时放行，不影响正常请求。
+//
+// 参数说明:
+//   - ctx: 请求上下文，为 Gin 上下文时按客户端 IP 限流。
+//   - kind: 请求类型（如 "userinfo"、"introspection:access_token"）。
+//   - token: 待校验的令牌。
+//
+// 返回值:
+//   - bool: 令牌是否命中负缓存。
+//   - *xError.Error: 触发限流时返回错误。
+func (l *BusinessLogic) checkInvalid(ctx context.Context, kind string, token string) (bool, *xError.Error) {
+	invalid, err := l.introspectionData.IsInvalid(ctx, kind, token)
+	if err != nil {
+		l.log.Warn(ctx, "BusinessLogic|checkInvalid - 读取负缓存失败",
+			slog.String("error", err.Error()),
+		)
+	} else if invalid {
+		return true, nil
+	}
+
+	business := l.config(ctx).Business
+	if business.InvalidTokenLimit <= 0 {
+		return false, nil
+	}
+	for _, scope := range bSdkUtil.InvalidTokenScopes(ctx, token, business.InvalidTokenPrefixLen) {
+		count, err := l.introspectionData.InvalidCount(ctx, scope)
+		if err != nil {
+			l.log.Warn(ctx, "BusinessLogic|checkInvalid - 读取限流计数失败",
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if count >= business.InvalidTokenLimit {
+			return false, xError.NewError(ctx, xError.TooManyRequests, "无效令牌过多，请稍后重试", false, nil)
+		}
+	}
+	return false, nil
+}
+
+// recordInvalid 记录一次上游判定的无效令牌：写入负缓存并累加各限流维度的计数，失败仅记录日志。
+func (l *BusinessLogic) recordInvalid(ctx context.Context, kind string, token string) {
+	business := l.config(ctx).Business
+	if err := l.introspectionData.MarkInvalid(ctx, kind, token, business.NegativeCacheTTL.Duration()); err != nil {
+		l.log.Warn(ctx, "BusinessLogic|recordInvalid - 写入负缓存失败",
+			slog.String("error", err.Error()),
+		)
+	}
+
+	if business.InvalidTokenLimit <= 0 {
+		return
+	}
+	for _, scope := range bSdkUtil.InvalidTokenScopes(ctx, token, business.InvalidTokenPrefixLen) {
+		if _, err := l.introspectionData.IncrInvalid(ctx, scope, business.InvalidTokenWindow.Duration()); err != nil {
+			l.log.Warn(ctx, "BusinessLogic|recordInvalid - 累加限流计数失败",
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
@@ -1,15 +1,24 @@
 package bSdkLogic
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
 func TestBusinessLogicUserinfo(t *testing.T) {
+	configureTestKeys(t)
 	gin.SetMode(gin.TestMode)
 
 	tests := []struct {
@@ -104,6 +113,7 @@ func TestBusinessLogicUserinfo(t *testing.T) {
 			}
 
 			t.Setenv("SSO_ENDPOINT_USERINFO_URI", userinfoURI)
+			t.Setenv("SSO_NEGATIVE_CACHE_TTL", "0")
 
 			userinfo, xErr := logic.Userinfo(ctx, tt.accessToken)
 			if tt.expectErr != nil {
@@ -151,3 +161,313 @@ func newTestGinContext() *gin.Context {
 
 	return ctx
 }
+
+// configureTestKeys 配置进程级令牌密钥，令牌指纹与加解密依赖该密钥。
+func configureTestKeys(t *testing.T) {
+	t.Helper()
+	if _, err := bSdkUtil.ConfigureTokenKeys(context.Background(), bSdkConfig.New(bSdkConfig.WithClient("cid", "csecret"))); err != nil {
+		t.Fatalf("配置令牌密钥失败: %v", err)
+	}
+}
+
+func TestBusinessLogicUserinfoCoalesce(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"sub":"coalesce"}`))
+	}))
+	defer srv.Close()
+	t.Setenv("SSO_ENDPOINT_USERINFO_URI", srv.URL)
+
+	logic := NewBusiness(context.Background())
+
+	const concurrency = 8
+	var wg sync.WaitGroup
+	errs := make(chan string, concurrency)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userinfo, xErr := logic.Userinfo(newTestGinContext(), "coalesce-token")
+			if xErr != nil {
+				errs <- xErr.Error()
+				return
+			}
+			if userinfo.Sub != "coalesce" {
+				errs <- "sub 不匹配: " + userinfo.Sub
+			}
+		}()
+	}
+
+	// 等待首个上游请求到达，并留出时间让其余请求进入 singleflight
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for msg := range errs {
+		t.Fatalf("期望成功，实际错误: %s", msg)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("并发请求应合并为 1 次上游请求，实际 %d 次", got)
+	}
+}
+
+func TestBusinessLogicUserinfoCoalesceCancel(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"sub":"cancel"}`))
+	}))
+	defer srv.Close()
+	t.Setenv("SSO_ENDPOINT_USERINFO_URI", srv.URL)
+
+	logic := NewBusiness(context.Background())
+
+	// 首个调用方在上游请求进行中断开，共享请求不随之取消
+	canceledCtx, cancel := context.WithCancel(newTestGinContext())
+	canceled := make(chan *xError.Error, 1)
+	go func() {
+		_, xErr := logic.Userinfo(canceledCtx, "cancel-token")
+		canceled <- xErr
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiter := make(chan *xError.Error, 1)
+	go func() {
+		_, xErr := logic.Userinfo(newTestGinContext(), "cancel-token")
+		waiter <- xErr
+	}()
+
+	cancel()
+	if xErr := <-canceled; xErr == nil || xErr.GetErrorCode().Code != xError.Timeout.Code {
+		t.Fatalf("取消的调用方期望超时错误，实际 %v", xErr)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if xErr := <-waiter; xErr != nil {
+		t.Fatalf("其他等待方期望成功，实际错误: %v", xErr)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("并发请求应合并为 1 次上游请求，实际 %d 次", got)
+	}
+}
+
+func TestBusinessLogicUserinfoCoalesceTenant(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"sub":"tenant"}`))
+	}))
+	defer srv.Close()
+	t.Setenv("SSO_ENDPOINT_USERINFO_URI", srv.URL)
+
+	logic := NewBusiness(context.Background())
+
+	var wg sync.WaitGroup
+	for _, tenant := range []string{"shop-a", "shop-b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := bSdkUtil.WithTenant(newTestGinContext(), tenant, "tenant:"+tenant+":")
+			if _, xErr := logic.Userinfo(ctx, "tenant-token"); xErr != nil {
+				t.Errorf("期望成功，实际错误: %v", xErr)
+			}
+		}()
+	}
+
+	// 不同租户的同一令牌不应合并，等待两个上游请求都到达
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("不同租户的请求不应合并，期望 2 次上游请求，实际 %d 次", got)
+	}
+}
+
+func TestBusinessLogicUserinfoStaleCoalesce(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	t.Setenv("SSO_ENDPOINT_USERINFO_URI", srv.URL)
+
+	logic := NewBusiness(context.Background())
+	stale := &bSdkModels.OAuthUserinfo{Sub: "stale"}
+
+	// 不持有过期缓存的调用（如后台刷新）先发起上游请求，持有过期缓存的调用随后加入同一次请求
+	withoutStale := make(chan *xError.Error, 1)
+	go func() {
+		_, xErr := logic.loadUserinfo(newTestGinContext(), "stale-coalesce-token", nil)
+		withoutStale <- xErr
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	var userinfo *bSdkModels.OAuthUserinfo
+	var xErr *xError.Error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		userinfo, xErr = logic.loadUserinfo(newTestGinContext(), "stale-coalesce-token", stale)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-done
+
+	if xErr != nil || userinfo != stale {
+		t.Fatalf("持有过期缓存的调用方应返回自身的过期缓存，实际 %v %v", userinfo, xErr)
+	}
+	if xErr := <-withoutStale; xErr == nil {
+		t.Fatalf("不持有过期缓存的调用方应返回上游错误")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("并发请求应合并为 1 次上游请求，实际 %d 次", got)
+	}
+}
+
+func TestBusinessLogicUserinfoNegativeCache(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	t.Setenv("SSO_ENDPOINT_USERINFO_URI", srv.URL)
+	t.Setenv("SSO_NEGATIVE_CACHE_TTL", "10")
+	t.Setenv("SSO_INVALID_TOKEN_LIMIT", "2")
+
+	logic := NewBusiness(context.Background())
+
+	t.Run("无效令牌命中负缓存", func(t *testing.T) {
+		for range 3 {
+			_, xErr := logic.Userinfo(newTestGinContext(), "negative-token")
+			if xErr == nil || xErr.GetErrorCode().Code != xError.Unauthorized.Code {
+				t.Fatalf("期望错误码 %d，实际 %v", xError.Unauthorized.Code, xErr)
+			}
+		}
+		if got := calls.Load(); got != 1 {
+			t.Fatalf("重复的无效令牌应只请求上游 1 次，实际 %d 次", got)
+		}
+	})
+
+	t.Run("同一 IP 无效令牌过多时限流", func(t *testing.T) {
+		// 上一子测试已计数 1 次，再提交 1 个新的无效令牌即达到上限
+		if _, xErr := logic.Userinfo(newTestGinContext(), "negative-token-2"); xErr == nil {
+			t.Fatalf("期望无效令牌返回错误")
+		}
+		_, xErr := logic.Userinfo(newTestGinContext(), "negative-token-3")
+		if xErr == nil || xErr.GetErrorCode().Code != xError.TooManyRequests.Code {
+			t.Fatalf("期望错误码 %d，实际 %v", xError.TooManyRequests.Code, xErr)
+		}
+		if got := calls.Load(); got != 2 {
+			t.Fatalf("限流后不应再请求上游，实际 %d 次", got)
+		}
+	})
+}
+
+func TestBusinessLogicUserinfoStale(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+
+	var calls atomic.Int32
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"sub":"v%d"}`, n)))
+	}))
+	defer srv.Close()
+	t.Setenv("SSO_ENDPOINT_USERINFO_URI", srv.URL)
+	t.Setenv("SSO_BUSINESS_CACHE", "true")
+	t.Setenv("SSO_CACHE_USERINFO_TTL", "50ms")
+
+	t.Run("宽限期内返回过期缓存并后台刷新", func(t *testing.T) {
+		t.Setenv("SSO_CACHE_USERINFO_STALE_TTL", "1m")
+		calls.Store(0)
+		logic := NewBusiness(context.Background())
+
+		if userinfo, xErr := logic.Userinfo(newTestGinContext(), "swr-token"); xErr != nil || userinfo.Sub != "v1" {
+			t.Fatalf("首次请求应返回 v1，实际 %v %v", userinfo, xErr)
+		}
+		time.Sleep(60 * time.Millisecond)
+
+		userinfo, xErr := logic.Userinfo(newTestGinContext(), "swr-token")
+		if xErr != nil || userinfo.Sub != "v1" {
+			t.Fatalf("过期后应立即返回旧值 v1，实际 %v %v", userinfo, xErr)
+		}
+		deadline := time.Now().Add(time.Second)
+		for calls.Load() < 2 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if userinfo, xErr := logic.Userinfo(newTestGinContext(), "swr-token"); xErr != nil || userinfo.Sub != "v2" {
+			t.Fatalf("后台刷新后应返回 v2，实际 %v %v", userinfo, xErr)
+		}
+	})
+
+	t.Run("上游不可用时返回过期缓存", func(t *testing.T) {
+		t.Setenv("SSO_CACHE_USERINFO_STALE_IF_ERROR_TTL", "1m")
+		calls.Store(0)
+		failing.Store(false)
+		logic := NewBusiness(context.Background())
+
+		if _, xErr := logic.Userinfo(newTestGinContext(), "sie-token"); xErr != nil {
+			t.Fatalf("首次请求失败: %v", xErr)
+		}
+		time.Sleep(60 * time.Millisecond)
+		failing.Store(true)
+
+		userinfo, xErr := logic.Userinfo(newTestGinContext(), "sie-token")
+		if xErr != nil || userinfo.Sub != "v1" {
+			t.Fatalf("上游 5xx 时应返回过期缓存 v1，实际 %v %v", userinfo, xErr)
+		}
+		if got := calls.Load(); got != 2 {
+			t.Fatalf("过期后应请求上游，实际 %d 次", got)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package bSdkLogic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	bSdkClient "github.com/phalanx-labs/beacon-sso-sdk/client"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"golang.org/x/oauth2"
+)
+
+// ErrSsoClientUnavailable 上下文中未注册 SsoClient（如排除了 `ssoClient` 节点）时，依赖 gRPC 的方法返回该错误。
+var ErrSsoClientUnavailable = errors.New("SsoClient 未初始化，gRPC 相关功能不可用")
+
+// tenantConfigs 缓存按租户派生的配置，避免每个请求重复复制。
+var tenantConfigs sync.Map
+
+// tenantConfigKey 租户派生配置的缓存键。
+type tenantConfigKey struct {
+	cfg  *bSdkConfig.Config
+	name string
+}
+
+// tenantConfigEntry 租户派生的 SDK 配置与 OAuth 配置。
+type tenantConfigEntry struct {
+	source *bSdkConfig.Config // 派生所基于的配置快照，快照被热更新替换后重新派生
+	cfg    *bSdkConfig.Config
+	oauth  *oauth2.Config
+}
+
+// sdkConfig 返回显式注册的 SDK 配置的当前快照；未注册时使用进程级配置（`SSO_CONFIG_FILE` 与环境变量，参见 `bSdkConfig.Process`）。
+func sdkConfig(cfg *bSdkConfig.Config) *bSdkConfig.Config {
+	if cfg != nil {
+		return cfg.Current()
+	}
+	cfg, _ = bSdkConfig.Process()
+	return cfg
+}
+
+// tenantConfig 返回上下文所属租户生效的 SDK 配置，未绑定租户或租户未注册时返回根配置。
+func tenantConfig(ctx context.Context, cfg *bSdkConfig.Config) *bSdkConfig.Config {
+	if entry, ok := lookupTenantConfig(ctx, cfg); ok {
+		return entry.cfg
+	}
+	return sdkConfig(cfg)
+}
+
+// providerConfig 返回身份提供方生效的 SDK 配置，name 为空时为上下文所属租户的默认提供方配置。
+//
+// 命名身份提供方未在当前快照中注册时返回 false。
+func providerConfig(ctx context.Context, cfg *bSdkConfig.Config, name string) (*bSdkConfig.Config, bool) {
+	if name == "" {
+		return tenantConfig(ctx, cfg), true
+	}
+	return sdkConfig(cfg).Provider(name)
+}
+
+// tenantOAuth2 返回上下文所属租户的 OAuth 配置，未绑定租户或租户未注册时返回 nil。
+func tenantOAuth2(ctx context.Context, cfg *bSdkConfig.Config) *oauth2.Config {
+	if entry, ok := lookupTenantConfig(ctx, cfg); ok {
+		return entry.oauth
+	}
+	return nil
+}
+
+// grpcContext 按上下文所属租户与当前配置快照注入 gRPC 调用使用的 App 认证凭证，使租户切换与密钥轮换对 gRPC 生效。
+func grpcContext(ctx context.Context, cfg *bSdkConfig.Config) context.Context {
+	if cfg == nil {
+		return ctx
+	}
+	current := tenantConfig(ctx, cfg)
+	return bSdkClient.ContextWithAppAccess(ctx, current.Client.ID, current.Client.Secret)
+}
+
+// lookupTenantConfig 读取或创建上下文所属租户的派生配置。
+func lookupTenantConfig(ctx context.Context, cfg *bSdkConfig.Config) (*tenantConfigEntry, bool) {
+	if cfg == nil || len(cfg.Tenants) == 0 {
+		return nil, false
+	}
+	name := bSdkUtil.GetTenant(ctx)
+	if name == "" {
+		return nil, false
+	}
+	current := cfg.Current()
+	key := tenantConfigKey{cfg: cfg, name: name}
+	if entry, ok := tenantConfigs.Load(key); ok && entry.(*tenantConfigEntry).source == current {
+		return entry.(*tenantConfigEntry), true
+	}
+	derived, ok := current.Tenant(name)
+	if !ok {
+		return nil, false
+	}
+	entry := &tenantConfigEntry{source: current, cfg: derived, oauth: derived.OAuth2()}
+	tenantConfigs.Store(key, entry)
+	return entry, true
+}
+
+// oauth2Context 将 HTTP 客户端注入上下文，供 `oauth2` 的令牌交换与刷新使用，client 为 nil 时按配置创建。
+func oauth2Context(ctx context.Context, cfg *bSdkConfig.Config, client *http.Client) context.Context {
+	if client == nil {
+		client = cfg.HTTPClient()
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, client)
+}
@@ -0,0 +1,68 @@
+package bSdkLogic
+
+import (
+	"context"
+	"net/http"
+
+	xCtxUtil "github.com/bamboo-services/bamboo-base-go/common/utility/context"
+	bSdkClient "github.com/phalanx-labs/beacon-sso-sdk/client"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Deps SDK 逻辑组件的显式依赖
+//
+// 用于在不使用 `xReg.Register` 上下文注入的服务中构建 SDK 组件（参见 `NewOAuthLogic` 与各 `New*With` 构造函数），
+// 除 Config 外均为可选，缺失的依赖按字段说明降级。`NewOAuthWith` 与 `NewBusinessWith` 会按 Config 配置进程级令牌密钥
+// （参见 `bSdkUtil.ConfigureTokenKeys`），无需另行调用。
+type Deps struct {
+	Config     *bSdkConfig.Config    // SDK 配置，通常由 `bSdkConfig.NewReloader` 构建以支持热更新；为 nil 时按需读取环境变量
+	Store      bSdkStore.Store       // 状态存储，为 nil 时使用进程内存储（仅适用于单实例）；以 `bSdkCache.NewLocalStore` 包装后启用一级缓存
+	DB         *gorm.DB              // 数据库实例，启用 `token.persistence` 时用于令牌持久化
+	Redis      *redis.Client         // Redis 客户端，用于多副本吊销广播与依赖检查，为 nil 时仅在本实例生效
+	SsoClient  *bSdkClient.SsoClient // gRPC 客户端，为 nil 时依赖 gRPC 的方法返回 `ErrSsoClientUnavailable`
+	HTTPClient *http.Client          // 请求 SSO 使用的 HTTP 客户端，为 nil 时按 `http` 配置创建
+}
+
+// DepsFromContext 从 `xReg.Register` 注入的上下文中读取依赖，是上下文构造函数（如 `NewOAuth`）的适配层
+//
+// 参数说明:
+//   - ctx: 启动节点注册后的上下文，未注册的依赖保持为空；为 nil 时视为未注册任何依赖。
+//
+// 返回值:
+//   - Deps: 读取到的依赖。
+func DepsFromContext(ctx context.Context) Deps {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	db, _ := xCtxUtil.GetDB(ctx)
+	rdb, _ := xCtxUtil.GetRDB(ctx)
+	ssoClient, _ := bSdkUtil.TryGetSsoClient(ctx)
+	return Deps{
+		Config:    bSdkUtil.GetConfig(ctx),
+		Store:     bSdkUtil.GetStore(ctx),
+		DB:        db,
+		Redis:     rdb,
+		SsoClient: ssoClient,
+	}
+}
+
+// store 返回按请求所属租户附加命名空间的状态存储，未提供时退回进程内存储。
+func (d Deps) store() bSdkStore.Store {
+	if d.Store == nil {
+		return bSdkUtil.GetStore(context.Background())
+	}
+	return bSdkStore.NewNamespaceStore(d.Store, bSdkUtil.TenantNamespace)
+}
+
+// configureTokenKeys 按 Config 配置进程级令牌密钥，Config 为 nil 时由令牌操作按环境变量惰性加载。
+func (d Deps) configureTokenKeys() error {
+	if d.Config == nil {
+		return nil
+	}
+	_, err := bSdkUtil.ConfigureTokenKeys(context.Background(), d.Config)
+	return err
+}
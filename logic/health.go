@@ -0,0 +1,149 @@
+package bSdkLogic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+)
+
+// HealthStatus 健康状态
+type HealthStatus string
+
+const (
+	HealthUp   HealthStatus = "up"   // 可用
+	HealthDown HealthStatus = "down" // 不可用
+)
+
+// HealthReport SDK 依赖健康报告
+type HealthReport struct {
+	Status    HealthStatus               `json:"status"`             // 总体状态
+	CheckedAt time.Time                  `json:"checked_at"`         // 检查时间
+	Checks    []ProbeCheck               `json:"checks"`             // 各检查项结果，按固定顺序排列
+	Breakers  []bSdkConfig.BreakerStatus `json:"breakers,omitempty"` // 请求 SSO 的熔断器状态
+}
+
+// HealthLogic 健康检查逻辑组件，为存活与就绪探针提供 SDK 依赖的状态报告。
+type HealthLogic struct {
+	log   *xLog.LogNamedLogger
+	cfg   *bSdkConfig.Config // SDK 配置句柄，用于读取元数据刷新状态
+	probe *ProbeLogic        // 复用依赖自检的 gRPC 与 Redis 检查
+}
+
+// NewHealth 创建并初始化一个 HealthLogic 实例。
+//
+// 参数:
+//   - ctx: 上下文，用于获取 SDK 配置、SsoClient 与 Redis 客户端；未注册的依赖在检查时跳过。
+//
+// 返回值:
+//   - *HealthLogic: 健康检查逻辑实例指针。
+func NewHealth(ctx context.Context) *HealthLogic {
+	return NewHealthWith(DepsFromContext(ctx))
+}
+
+// NewHealthWith 使用显式依赖创建 HealthLogic，未提供的依赖在检查时跳过。
+func NewHealthWith(deps Deps) *HealthLogic {
+	return &HealthLogic{
+		log:   xLog.WithName(xLog.NamedLOGC, "HealthLogic"),
+		cfg:   deps.Config,
+		probe: NewProbeWith(deps),
+	}
+}
+
+// Liveness 返回存活报告
+//
+// 仅读取进程内状态（元数据新鲜度、JWKS 缓存时长与熔断器状态），不发起网络请求，且总体状态恒为 `up`，
+// 避免 SSO 等外部依赖故障时探针反复重启服务。
+func (l *HealthLogic) Liveness() *HealthReport {
+	return &HealthReport{
+		Status:    HealthUp,
+		CheckedAt: time.Now(),
+		Checks:    []ProbeCheck{l.checkDiscovery(), l.checkJWKS()},
+		Breakers:  bSdkConfig.Breakers(),
+	}
+}
+
+// Readiness 返回就绪报告
+//
+// 在存活报告的基础上并发检查 gRPC 连通性与 Redis 延迟，任一检查失败时总体状态为 `down`。
+// 熔断器状态仅供参考，不影响总体状态：熔断只说明 SSO 暂不可用，摘除流量无助于恢复。
+//
+// 参数说明:
+//   - ctx: 上下文。
+//   - timeout: 单项检查的超时时间，不大于 0 时使用 `health.timeout`（`SSO_HEALTH_TIMEOUT`，默认 2 秒）。
+//
+// 返回值:
+//   - *HealthReport: 就绪报告，检查项顺序固定为元数据、gRPC、Redis、JWKS。
+func (l *HealthLogic) Readiness(ctx context.Context, timeout time.Duration) *HealthReport {
+	if timeout <= 0 {
+		timeout = sdkConfig(l.cfg).Health.Timeout.Duration()
+	}
+
+	report := &HealthReport{Status: HealthUp, CheckedAt: time.Now()}
+	var grpcCheck, redisCheck ProbeCheck
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		grpcCheck = runProbe(ctx, ProbeGrpc, timeout, l.probe.CheckGrpc)
+	}()
+	go func() {
+		defer wg.Done()
+		redisCheck = runProbe(ctx, ProbeRedis, timeout, l.probe.CheckRedis)
+	}()
+	wg.Wait()
+
+	report.Checks = []ProbeCheck{l.checkDiscovery(), grpcCheck, redisCheck, l.checkJWKS()}
+	report.Breakers = bSdkConfig.Breakers()
+	for _, check := range report.Checks {
+		if check.Status == ProbeFailed {
+			report.Status = HealthDown
+		}
+	}
+	return report
+}
+
+// checkDiscovery 检查元数据是否按期刷新
+//
+// 未使用自动发现时跳过；启用后台刷新后，距上次成功刷新超过两个刷新周期视为失败。
+func (l *HealthLogic) checkDiscovery() ProbeCheck {
+	check := ProbeCheck{Name: ProbeDiscovery, Status: ProbeOK}
+	reloader := l.cfg.Reloader()
+	if reloader == nil || l.cfg.Current().Endpoints.WellKnownURI == "" {
+		check.Status, check.Error = ProbeSkipped, "未使用自动发现"
+		return check
+	}
+
+	status := reloader.Status()
+	age := time.Since(status.RefreshedAt)
+	check.AgeMS = age.Milliseconds()
+	if status.Interval > 0 && age > 2*status.Interval {
+		check.Status = ProbeFailed
+		check.Error = fmt.Sprintf("元数据已 %s 未刷新", age.Round(time.Second))
+		if status.LastError != "" {
+			check.Error += ": " + status.LastError
+		}
+	}
+	return check
+}
+
+// checkJWKS 报告签名公钥集的缓存时长，公钥集按需拉取，尚未加载时跳过。
+func (l *HealthLogic) checkJWKS() ProbeCheck {
+	check := ProbeCheck{Name: ProbeJWKS, Status: ProbeOK}
+	cfg := sdkConfig(l.cfg)
+	if cfg == nil || cfg.Endpoints.JWKS == "" {
+		check.Status, check.Error = ProbeSkipped, "公钥集端点未配置"
+		return check
+	}
+
+	fetchedAt := l.probe.jwks.FetchedAt()
+	if fetchedAt.IsZero() {
+		check.Status, check.Error = ProbeSkipped, "公钥集尚未加载"
+		return check
+	}
+	check.AgeMS = time.Since(fetchedAt).Milliseconds()
+	return check
+}
@@ -0,0 +1,37 @@
+package bSdkLogic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+)
+
+func TestHealthLogic(t *testing.T) {
+	cfg := bSdkConfig.New(bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{JWKS: "https://sso.example.com/health-jwks"}))
+	ctx := context.WithValue(context.Background(), bSdkConst.CtxSdkConfig, cfg)
+	health := NewHealth(ctx)
+
+	report := health.Readiness(ctx, time.Second)
+	if report.Status != HealthUp || len(report.Checks) != 4 {
+		t.Fatalf("未配置的依赖不应导致未就绪: %+v", report)
+	}
+	for _, check := range report.Checks {
+		if check.Status != ProbeSkipped {
+			t.Fatalf("%s 应被跳过: %s", check.Name, check.Status)
+		}
+	}
+
+	keySet := keySetFor(cfg.Endpoints.JWKS)
+	keySet.mu.Lock()
+	keySet.fetchedAt = time.Now().Add(-time.Minute)
+	keySet.mu.Unlock()
+
+	report = health.Liveness()
+	jwks := report.Checks[1]
+	if report.Status != HealthUp || jwks.Status != ProbeOK || jwks.AgeMS < time.Minute.Milliseconds() {
+		t.Fatalf("应报告公钥集缓存时长: %+v", jwks)
+	}
+}
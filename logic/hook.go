@@ -0,0 +1,121 @@
+package bSdkLogic
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+)
+
+// LogoutHook 登出钩子函数
+//
+// 当 SDK 处理完 SSO 发起的登出通知（并已清理自身缓存）后被调用，
+// 业务方可借此清理自身维护的会话、WebSocket 连接等状态。
+// 钩子同步执行，请避免在其中进行长时间阻塞操作。
+type LogoutHook func(ctx context.Context, event *bSdkModels.LogoutEvent)
+
+var (
+	hookMu      sync.RWMutex
+	logoutHooks []LogoutHook
+)
+
+// RegisterLogoutHook 注册一个登出钩子，可多次调用注册多个钩子，按注册顺序执行。
+func RegisterLogoutHook(hook LogoutHook) {
+	if hook == nil {
+		return
+	}
+
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	logoutHooks = append(logoutHooks, hook)
+}
+
+// fireLogoutHooks 依次执行已注册的登出钩子，单个钩子 panic 不会影响其他钩子与主流程。
+func fireLogoutHooks(ctx context.Context, event *bSdkModels.LogoutEvent) {
+	hookMu.RLock()
+	hooks := make([]LogoutHook, len(logoutHooks))
+	copy(hooks, logoutHooks)
+	hookMu.RUnlock()
+
+	for _, hook := range hooks {
+		runHook(ctx, "LogoutHook", func() { hook(ctx, event) })
+	}
+}
+
+// runHook 执行钩子并捕获 panic。
+func runHook(ctx context.Context, name string, fn func()) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			xLog.WithName(xLog.NamedLOGC, "Hook").Error(ctx, "runHook - 钩子执行异常",
+				slog.String("hook", name),
+				slog.String("panic", fmt.Sprint(recovered)),
+			)
+		}
+	}()
+	fn()
+}
+
+// RevocationHandler 令牌吊销处理函数
+//
+// 本实例发布吊销消息或收到其他实例广播的吊销消息时被调用，
+// 用于按令牌指纹清理进程内缓存，保证多副本间的登出即时生效。
+type RevocationHandler func(ctx context.Context, message *bSdkModels.RevocationMessage)
+
+var revocationHandlers []RevocationHandler
+
+// RegisterRevocationHandler 注册一个令牌吊销处理函数，按注册顺序执行。
+func RegisterRevocationHandler(handler RevocationHandler) {
+	if handler == nil {
+		return
+	}
+
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	revocationHandlers = append(revocationHandlers, handler)
+}
+
+// fireRevocationHandlers 依次执行已注册的吊销处理函数，单个处理函数 panic 不会影响其他处理函数。
+func fireRevocationHandlers(ctx context.Context, message *bSdkModels.RevocationMessage) {
+	hookMu.RLock()
+	handlers := make([]RevocationHandler, len(revocationHandlers))
+	copy(handlers, revocationHandlers)
+	hookMu.RUnlock()
+
+	for _, handler := range handlers {
+		runHook(ctx, "RevocationHandler", func() { handler(ctx, message) })
+	}
+}
+
+// SecurityHook 安全事件钩子函数
+//
+// SDK 检测到可疑行为并完成处置后被调用（如刷新令牌重放），业务方可借此记录审计日志或发出告警。
+// 钩子同步执行，请避免在其中进行长时间阻塞操作。
+type SecurityHook func(ctx context.Context, event *bSdkModels.SecurityEvent)
+
+var securityHooks []SecurityHook
+
+// RegisterSecurityHook 注册一个安全事件钩子，按注册顺序执行。
+func RegisterSecurityHook(hook SecurityHook) {
+	if hook == nil {
+		return
+	}
+
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	securityHooks = append(securityHooks, hook)
+}
+
+// fireSecurityHooks 依次执行已注册的安全事件钩子，单个钩子 panic 不会影响其他钩子与主流程。
+func fireSecurityHooks(ctx context.Context, event *bSdkModels.SecurityEvent) {
+	hookMu.RLock()
+	hooks := make([]SecurityHook, len(securityHooks))
+	copy(hooks, securityHooks)
+	hookMu.RUnlock()
+
+	for _, hook := range hooks {
+		runHook(ctx, "SecurityHook", func() { hook(ctx, event) })
+	}
+}
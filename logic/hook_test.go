@@ -0,0 +1,35 @@
+package bSdkLogic
+
+import (
+	"context"
+	"testing"
+
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+)
+
+func TestFireSecurityHooks(t *testing.T) {
+	hookMu.Lock()
+	saved := securityHooks
+	securityHooks = nil
+	hookMu.Unlock()
+	t.Cleanup(func() {
+		hookMu.Lock()
+		securityHooks = saved
+		hookMu.Unlock()
+	})
+
+	var received []string
+	RegisterSecurityHook(func(_ context.Context, _ *bSdkModels.SecurityEvent) {
+		panic("hook failure")
+	})
+	RegisterSecurityHook(func(_ context.Context, event *bSdkModels.SecurityEvent) {
+		received = append(received, event.Type)
+	})
+	RegisterSecurityHook(nil)
+
+	fireSecurityHooks(context.Background(), &bSdkModels.SecurityEvent{Type: SecurityEventRefreshTokenReuse})
+
+	if len(received) != 1 || received[0] != SecurityEventRefreshTokenReuse {
+		t.Fatalf("期望 panic 不影响后续钩子执行，实际收到: %v", received)
+	}
+}
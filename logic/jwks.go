@@ -0,0 +1,231 @@
+package bSdkLogic
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+)
+
+const (
+	jwksCacheTTL        = time.Hour   // JWKS 进程内缓存有效期
+	jwksRefreshInterval = time.Minute // 未命中 kid 时强制刷新的最小间隔，防止被恶意 kid 打爆
+)
+
+// jwksKeySet 进程级 JWKS 公钥缓存。
+//
+// JWKS 属于 SSO 的全局元数据，同一进程内使用相同 JWKS 端点的逻辑实例共享一份缓存。
+type jwksKeySet struct {
+	mu        sync.RWMutex
+	keys      []bSdkModels.JSONWebKey
+	fetchedAt time.Time
+}
+
+// jwksKeySets 按 JWKS 端点区分的公钥缓存（uri -> *jwksKeySet）。
+var jwksKeySets sync.Map
+
+// JwksLogic JWKS 公钥逻辑组件，负责拉取、缓存与检索 SSO 签名公钥。
+type JwksLogic struct {
+	log        *xLog.LogNamedLogger
+	cfg        *bSdkConfig.Config
+	httpClient *http.Client // 请求 SSO 使用的 HTTP 客户端，为 nil 时按配置创建
+}
+
+// NewJwks 创建并初始化一个 JwksLogic 实例。
+//
+// 参数:
+//   - ctx: 请求上下文，用于获取 SDK 配置。
+//
+// 返回值:
+//   - *JwksLogic: 共享进程级公钥缓存的逻辑实例指针。
+func NewJwks(ctx context.Context) *JwksLogic {
+	return NewJwksWith(DepsFromContext(ctx))
+}
+
+// NewJwksWith 使用显式依赖创建 JwksLogic。
+func NewJwksWith(deps Deps) *JwksLogic {
+	return &JwksLogic{
+		log:        xLog.WithName(xLog.NamedLOGC, "JwksLogic"),
+		cfg:        deps.Config,
+		httpClient: deps.HTTPClient,
+	}
+}
+
+// keySetFor 返回指定 JWKS 端点的公钥缓存。
+func keySetFor(uri string) *jwksKeySet {
+	value, _ := jwksKeySets.LoadOrStore(uri, &jwksKeySet{})
+	return value.(*jwksKeySet)
+}
+
+// GetKey 根据 kid 与算法检索签名公钥
+//
+// 优先使用缓存；缓存过期、JWKS 端点变更或未找到对应 kid 时会重新拉取（受最小刷新间隔限制），
+// 以兼容 SSO 的密钥轮换。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - kid: JWS 头部中的密钥标识，可为空。
+//   - alg: JWS 头部中的签名算法。
+//
+// 返回值:
+//   - *bSdkModels.JSONWebKey: 匹配的公钥。
+//   - *xError.Error: 端点缺失、拉取失败或找不到匹配公钥时返回错误。
+func (l *JwksLogic) GetKey(ctx context.Context, kid string, alg string) (*bSdkModels.JSONWebKey, *xError.Error) {
+	return l.getKey(ctx, sdkConfig(l.cfg), kid, alg)
+}
+
+// GetProviderKey 根据 kid 与算法检索指定身份提供方的签名公钥
+//
+// 公钥取自该提供方配置的 `jwks_uri`，各端点分别缓存；provider 为空时为默认提供方，等同于 `GetKey`。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - provider: 命名身份提供方名称，默认提供方为空。
+//   - kid: JWS 头部中的密钥标识，可为空。
+//   - alg: JWS 头部中的签名算法。
+//
+// 返回值:
+//   - *bSdkModels.JSONWebKey: 匹配的公钥。
+//   - *xError.Error: 提供方未注册、端点缺失、拉取失败或找不到匹配公钥时返回错误。
+func (l *JwksLogic) GetProviderKey(ctx context.Context, provider string, kid string, alg string) (*bSdkModels.JSONWebKey, *xError.Error) {
+	if provider == "" {
+		return l.GetKey(ctx, kid, alg)
+	}
+	cfg, ok := sdkConfig(l.cfg).Provider(provider)
+	if !ok {
+		return nil, xError.NewError(ctx, xError.NotExist, "身份提供方不存在", false, nil)
+	}
+	return l.getKey(ctx, cfg, kid, alg)
+}
+
+// getKey 在 cfg 配置的 JWKS 端点中检索签名公钥。
+func (l *JwksLogic) getKey(ctx context.Context, cfg *bSdkConfig.Config, kid string, alg string) (*bSdkModels.JSONWebKey, *xError.Error) {
+	jwksURI := cfg.Endpoints.JWKS
+	if jwksURI == "" {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "公钥集端点为空", false, nil)
+	}
+
+	keySet := keySetFor(jwksURI)
+	keySet.mu.RLock()
+	fresh := time.Since(keySet.fetchedAt) < jwksCacheTTL
+	key := keySet.find(kid, alg)
+	lastFetch := keySet.fetchedAt
+	keySet.mu.RUnlock()
+
+	if fresh && key != nil {
+		return key, nil
+	}
+	if fresh && time.Since(lastFetch) < jwksRefreshInterval {
+		return nil, xError.NewError(ctx, xError.SignatureInvalid, "未找到匹配的签名公钥", false, nil)
+	}
+
+	if xErr := l.refresh(ctx, cfg); xErr != nil {
+		return nil, xErr
+	}
+
+	keySet.mu.RLock()
+	key = keySet.find(kid, alg)
+	keySet.mu.RUnlock()
+	if key == nil {
+		return nil, xError.NewError(ctx, xError.SignatureInvalid, "未找到匹配的签名公钥", false, nil)
+	}
+	return key, nil
+}
+
+// Refresh 立即从 JWKS 端点拉取公钥并替换缓存。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//
+// 返回值:
+//   - *xError.Error: 端点缺失、请求失败或响应非法时返回错误。
+func (l *JwksLogic) Refresh(ctx context.Context) *xError.Error {
+	l.log.Info(ctx, "Refresh - 刷新签名公钥集")
+
+	return l.refresh(ctx, sdkConfig(l.cfg))
+}
+
+// refresh 从 cfg 配置的 JWKS 端点拉取公钥并替换该端点的缓存。
+func (l *JwksLogic) refresh(ctx context.Context, cfg *bSdkConfig.Config) *xError.Error {
+	jwksURI := cfg.Endpoints.JWKS
+	if jwksURI == "" {
+		return xError.NewError(ctx, xError.OperationFailed, "公钥集端点为空", false, nil)
+	}
+
+	var keySet bSdkModels.JSONWebKeySet
+	resp, err := cfg.NewRestyClientWith(l.httpClient).R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetResult(&keySet).
+		Get(jwksURI)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "获取签名公钥集失败", false, err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return xError.NewError(
+			ctx,
+			xError.OperationFailed,
+			xError.ErrMessage(fmt.Sprintf("获取签名公钥集失败，状态码: %d", resp.StatusCode())),
+			false,
+			nil,
+		)
+	}
+	if len(keySet.Keys) == 0 {
+		return xError.NewError(ctx, xError.OperationFailed, "签名公钥集为空", false, nil)
+	}
+
+	cached := keySetFor(jwksURI)
+	cached.mu.Lock()
+	cached.keys = keySet.Keys
+	cached.fetchedAt = time.Now()
+	cached.mu.Unlock()
+
+	l.log.Info(ctx, "JwksLogic|Refresh - 签名公钥集已更新", slog.Int("keys", len(keySet.Keys)))
+	return nil
+}
+
+// FetchedAt 返回最近一次成功拉取 JWKS 的时间，从未拉取时返回零值。
+func (l *JwksLogic) FetchedAt() time.Time {
+	jwksURI := sdkConfig(l.cfg).Endpoints.JWKS
+	if jwksURI == "" {
+		return time.Time{}
+	}
+	keySet := keySetFor(jwksURI)
+	keySet.mu.RLock()
+	defer keySet.mu.RUnlock()
+	return keySet.fetchedAt
+}
+
+// find 在缓存中查找匹配的签名公钥，调用方需持有读锁。
+//
+// 指定 kid 时严格按 kid 匹配；未指定 kid 时仅在唯一候选公钥的情况下返回，避免歧义。
+func (s *jwksKeySet) find(kid string, alg string) *bSdkModels.JSONWebKey {
+	var candidates []*bSdkModels.JSONWebKey
+	for i := range s.keys {
+		key := &s.keys[i]
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if key.Alg != "" && alg != "" && key.Alg != alg {
+			continue
+		}
+		if kid != "" {
+			if key.Kid == kid {
+				return key
+			}
+			continue
+		}
+		candidates = append(candidates, key)
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
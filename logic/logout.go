@@ -0,0 +1,438 @@
+package bSdkLogic
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkRepo "github.com/phalanx-labs/beacon-sso-sdk/repository"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"gorm.io/gorm"
+)
+
+const (
+	backChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout" // Back-Channel Logout 事件标识
+	logoutTokenClockSkew   = time.Minute * 2                                      // 登出令牌时间校验允许的时钟偏差
+	logoutTokenMaxAge      = time.Minute * 10                                     // 登出令牌（无 exp 时）的最大有效期
+
+	LogoutChannelBack  = "backchannel"  // 后端通道登出
+	LogoutChannelFront = "frontchannel" // 前端通道登出
+)
+
+// LogoutLogic 登出业务逻辑组件，处理 SSO 发起的 OIDC 登出通知。
+//
+// 该组件负责校验 `logout_token`、防止重放，并根据 sid/sub 清理本地缓存的
+// 令牌、刷新令牌家族、用户信息与自省结果，最后触发业务方注册的登出钩子。
+type LogoutLogic struct {
+	db                *gorm.DB                    // GORM 数据库实例
+	store             bSdkStore.Store             // 状态存储实例
+	log               *xLog.LogNamedLogger        // 日志实例
+	jwks              *JwksLogic                  // JWKS 公钥逻辑
+	tokenData         *bSdkRepo.OAuthTokenRepo    // OAuth Token 数据仓储实例
+	logoutData        *bSdkRepo.OAuthLogoutRepo   // 登出防重放数据仓储实例
+	userinfoData      *bSdkRepo.UserinfoRepo      // 业务层 Userinfo 数据仓储实例
+	introspectionData *bSdkRepo.IntrospectionRepo // 业务层 Introspection 数据仓储实例
+	revocation        *RevocationLogic            // 令牌吊销广播逻辑
+	family            *TokenFamilyLogic           // 刷新令牌家族逻辑
+	cfg               *bSdkConfig.Config          // SDK 配置，未注册时按需读取环境变量
+}
+
+// NewLogout 创建并初始化一个新的 LogoutLogic 业务逻辑实例。
+//
+// 参数:
+//   - ctx: 请求上下文，用于获取数据库与状态存储实例。
+//
+// 返回值:
+//   - *LogoutLogic: 配置完成的登出逻辑层实例指针。
+func NewLogout(ctx context.Context) *LogoutLogic {
+	return NewLogoutWith(DepsFromContext(ctx))
+}
+
+// NewLogoutWith 使用显式依赖创建 LogoutLogic。
+func NewLogoutWith(deps Deps) *LogoutLogic {
+	cfg := sdkConfig(deps.Config)
+	store := deps.store()
+
+	return &LogoutLogic{
+		db:                deps.DB,
+		store:             store,
+		log:               xLog.WithName(xLog.NamedLOGC, "LogoutLogic"),
+		jwks:              NewJwksWith(deps),
+		tokenData:         bSdkRepo.NewOAuthTokenRepoWith(deps.DB, store, cfg),
+		logoutData:        bSdkRepo.NewOAuthLogoutRepoWith(deps.DB, store, cfg),
+		userinfoData:      bSdkRepo.NewUserinfoRepoWith(deps.DB, store, cfg),
+		introspectionData: bSdkRepo.NewIntrospectionRepoWith(deps.DB, store, cfg),
+		revocation:        NewRevocationWith(deps),
+		family:            NewTokenFamilyWith(deps),
+		cfg:               deps.Config,
+	}
+}
+
+// BackChannel 处理 OIDC Back-Channel Logout 通知
+//
+// 该方法校验 SSO 推送的 `logout_token`，通过 jti 防止重放，然后清理
+// 与 sid（优先）或 sub 关联的全部本地令牌缓存并触发登出钩子。
+// 清理失败时释放 jti 的占用，SSO 重试时可以再次处理同一登出令牌。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - logoutToken: SSO 以表单参数 `logout_token` 推送的 JWT。
+//
+// 返回值:
+//   - *bSdkModels.LogoutEvent: 本次登出事件（包含已清理的令牌）。
+//   - *xError.Error: 令牌校验失败、重放或清理失败时返回错误。
+func (l *LogoutLogic) BackChannel(ctx context.Context, logoutToken string) (*bSdkModels.LogoutEvent, *xError.Error) {
+	l.log.Info(ctx, "BackChannel - 处理后端通道登出通知")
+
+	claims, xErr := l.VerifyLogoutToken(ctx, logoutToken)
+	if xErr != nil {
+		return nil, xErr
+	}
+
+	// jti 占用时长覆盖令牌剩余有效期，保证有效期内不可重放
+	ttl := logoutTokenMaxAge
+	if claims.ExpiresAt > 0 {
+		ttl = time.Until(time.Unix(claims.ExpiresAt, 0)) + logoutTokenClockSkew
+	}
+	if xErr = l.logoutData.ClaimJti(ctx, claims.JTI, ttl); xErr != nil {
+		return nil, xErr
+	}
+
+	event := &bSdkModels.LogoutEvent{
+		Channel:   LogoutChannelBack,
+		Issuer:    claims.Issuer,
+		Provider:  claims.Provider,
+		Subject:   claims.Subject,
+		SessionID: claims.SessionID,
+	}
+	if xErr = l.Terminate(ctx, event); xErr != nil {
+		// 清理失败时释放 jti，SSO 重试同一登出令牌时不会被判定为重放
+		if releaseErr := l.logoutData.ReleaseJti(ctx, claims.JTI); releaseErr != nil {
+			l.log.Warn(ctx, "LogoutLogic|BackChannel - 释放 jti 失败", slog.String("error", releaseErr.Error()))
+		}
+		return nil, xErr
+	}
+
+	return event, nil
+}
+
+// VerifyLogoutToken 校验 OIDC `logout_token` 的签名与声明
+//
+// 按 `iss` 声明选择身份提供方：与某个命名身份提供方的签发者一致时使用该提供方的配置，否则使用默认提供方
+// （上下文所属租户）的配置。校验规则（OpenID Connect Back-Channel Logout 1.0 §2.6）:
+//   - 使用该提供方 JWKS 中的公钥校验签名，拒绝 `none` 算法；
+//   - `iss` 必须与该提供方的签发者一致，`aud` 必须包含该提供方的客户端 ID；
+//   - `iat` 必须存在且不晚于当前时间（允许时钟偏差），存在 `exp` 时不得过期；
+//   - `jti` 必须存在；
+//   - `events` 必须包含 back-channel logout 事件；
+//   - `sid` 与 `sub` 至少存在一个；
+//   - 不得包含 `nonce`。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - logoutToken: 待校验的登出令牌。
+//
+// 返回值:
+//   - *bSdkModels.OAuthLogoutToken: 校验通过的声明。
+//   - *xError.Error: 任一规则不满足时返回错误。
+func (l *LogoutLogic) VerifyLogoutToken(ctx context.Context, logoutToken string) (*bSdkModels.OAuthLogoutToken, *xError.Error) {
+	l.log.Info(ctx, "VerifyLogoutToken - 校验登出令牌")
+
+	if logoutToken == "" {
+		return nil, xError.NewError(ctx, xError.ParameterEmpty, "登出令牌为空", false, nil)
+	}
+
+	token, err := bSdkUtil.ParseJWT(logoutToken)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌格式错误", false, err)
+	}
+	if token.Header.Alg == "" || token.Header.Alg == "none" {
+		return nil, xError.NewError(ctx, xError.SignatureInvalid, "登出令牌未签名", false, nil)
+	}
+
+	// iss 此时尚未经过签名校验，仅用于选择校验所用的公钥与配置
+	provider, cfg := l.logoutProvider(ctx, token.ClaimString("iss"))
+	key, xErr := l.jwks.GetProviderKey(ctx, provider, token.Header.Kid, token.Header.Alg)
+	if xErr != nil {
+		return nil, xErr
+	}
+	if err = token.Verify(key); err != nil {
+		return nil, xError.NewError(ctx, xError.SignatureInvalid, "登出令牌签名无效", false, err)
+	}
+
+	issuer := cfg.Endpoints.Issuer
+	if issuer == "" {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "签发者未配置", false, nil)
+	}
+	if token.ClaimString("iss") != issuer {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌签发者不匹配", false, nil)
+	}
+
+	clientID := cfg.Client.ID
+	audience := token.ClaimAudience()
+	if clientID == "" || !slices.Contains(audience, clientID) {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌受众不匹配", false, nil)
+	}
+
+	now := time.Now()
+	iat, ok := token.ClaimInt64("iat")
+	if !ok {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌缺少签发时间", false, nil)
+	}
+	issuedAt := time.Unix(iat, 0)
+	if issuedAt.After(now.Add(logoutTokenClockSkew)) {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌签发时间无效", false, nil)
+	}
+	exp, hasExp := token.ClaimInt64("exp")
+	if hasExp && time.Unix(exp, 0).Add(logoutTokenClockSkew).Before(now) {
+		return nil, xError.NewError(ctx, xError.TokenExpired, "登出令牌已过期", false, nil)
+	}
+	if !hasExp && issuedAt.Add(logoutTokenMaxAge).Before(now) {
+		return nil, xError.NewError(ctx, xError.TokenExpired, "登出令牌已过期", false, nil)
+	}
+
+	jti := token.ClaimString("jti")
+	if jti == "" {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌缺少 jti", false, nil)
+	}
+
+	events, ok := token.Claims["events"].(map[string]any)
+	if !ok {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌缺少 events", false, nil)
+	}
+	if _, ok = events[backChannelLogoutEvent].(map[string]any); !ok {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌事件类型错误", false, nil)
+	}
+
+	sid := token.ClaimString("sid")
+	sub := token.ClaimString("sub")
+	if sid == "" && sub == "" {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌缺少 sid 与 sub", false, nil)
+	}
+	if _, exist := token.Claims["nonce"]; exist {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "登出令牌不得包含 nonce", false, nil)
+	}
+
+	return &bSdkModels.OAuthLogoutToken{
+		Issuer:    issuer,
+		Subject:   sub,
+		Audience:  audience,
+		IssuedAt:  iat,
+		ExpiresAt: exp,
+		JTI:       jti,
+		SessionID: sid,
+		Raw:       token.Claims,
+		Provider:  provider,
+	}, nil
+}
+
+// logoutProvider 按签发者选择身份提供方，返回提供方名称与其配置；未匹配任何命名身份提供方时返回默认提供方的配置。
+func (l *LogoutLogic) logoutProvider(ctx context.Context, issuer string) (string, *bSdkConfig.Config) {
+	cfg := tenantConfig(ctx, l.cfg)
+	if issuer == "" || issuer == cfg.Endpoints.Issuer {
+		return "", cfg
+	}
+	root := sdkConfig(l.cfg)
+	for _, name := range root.ProviderNames() {
+		if provider, _ := root.Provider(name); provider.Endpoints.Issuer == issuer {
+			return name, provider
+		}
+	}
+	return "", cfg
+}
+
+// Terminate 清理登出事件关联的全部本地令牌并触发登出钩子
+//
+// 存在 sid 时仅清理该 SSO 会话下的令牌；否则清理该用户（sub）下的全部令牌。
+// 会话索引按身份提供方隔离，仅清理 `event.Provider` 签发的令牌，其他身份提供方的同名用户或会话不受影响。
+// 令牌所属的刷新令牌家族一并结束，此后无法再以该会话的刷新令牌换取新令牌。
+// 单个缓存条目清理失败仅记录告警，不阻断其他令牌的清理。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - event: 登出事件，需至少包含 SessionID 或 Subject，执行后 TokenFingerprints 会被填充。
+//
+// 返回值:
+//   - *xError.Error: 参数缺失或读取索引失败时返回错误。
+func (l *LogoutLogic) Terminate(ctx context.Context, event *bSdkModels.LogoutEvent) *xError.Error {
+	l.log.Info(ctx, "Terminate - 清理登出会话")
+
+	if event == nil || (event.SessionID == "" && event.Subject == "") {
+		return xError.NewError(ctx, xError.ParameterEmpty, "会话标识与用户标识均为空", false, nil)
+	}
+
+	var (
+		fingerprints []string
+		xErr         *xError.Error
+	)
+	if event.SessionID != "" {
+		fingerprints, xErr = l.tokenData.ListBySessionID(ctx, event.Provider, event.SessionID)
+	} else {
+		fingerprints, xErr = l.tokenData.ListBySubject(ctx, event.Provider, event.Subject)
+	}
+	if xErr != nil {
+		return xErr
+	}
+
+	l.purgeFingerprints(ctx, fingerprints)
+
+	indexSub := event.Subject
+	if event.SessionID != "" {
+		indexSub = ""
+	}
+	if delErr := l.tokenData.DeleteIndex(ctx, event.Provider, event.SessionID, indexSub); delErr != nil {
+		l.log.Warn(ctx, "LogoutLogic|Terminate - 清理会话索引失败", slog.String("error", delErr.Error()))
+	}
+
+	event.TokenFingerprints = fingerprints
+	l.log.Info(ctx, "LogoutLogic|Terminate - 会话已清理",
+		slog.String("channel", event.Channel),
+		slog.String("provider", event.Provider),
+		slog.String("sid", event.SessionID),
+		slog.String("sub", event.Subject),
+		slog.Int("tokens", len(fingerprints)),
+	)
+
+	fireLogoutHooks(ctx, event)
+	return nil
+}
+
+// FrontChannel 处理 OIDC Front-Channel Logout 通知
+//
+// SSO 在登出页中以 iframe 加载本地登记的 `frontchannel_logout_uri`，并（可选）携带 `iss` 与 `sid` 参数。
+// 该地址可被任意页面以 GET 触发，因此只清理浏览器会话 Cookie 能证明归属的会话：
+//   - Cookie 对应的令牌关联了 SSO 会话时，携带的 sid 必须与之一致，且 `iss` 所属的身份提供方必须是签发该令牌的提供方，
+//     校验通过后清理该会话下的全部令牌；
+//   - 无法由 Cookie 定位会话（未携带 Cookie、令牌已失效或未关联会话）时，仅清理 Cookie 对应的令牌，
+//     不按查询参数中的 sid 清理服务端会话，服务端会话由后端通道登出负责。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - issuer: 查询参数 `iss`。
+//   - sid: 查询参数 `sid`。
+//   - accessToken: 本地会话 Cookie 中的访问令牌，可为空。
+//
+// 返回值:
+//   - *bSdkModels.LogoutEvent: 本次登出事件。
+//   - *xError.Error: 参数校验失败、sid 与会话 Cookie 不一致或清理失败时返回错误。
+func (l *LogoutLogic) FrontChannel(ctx context.Context, issuer string, sid string, accessToken string) (*bSdkModels.LogoutEvent, *xError.Error) {
+	l.log.Info(ctx, "FrontChannel - 处理前端通道登出通知")
+
+	provider, xErr := l.verifyFrontChannelParams(ctx, issuer, sid)
+	if xErr != nil {
+		return nil, xErr
+	}
+
+	event := &bSdkModels.LogoutEvent{
+		Channel: LogoutChannelFront,
+		Issuer:  issuer,
+	}
+
+	// 由会话 Cookie 对应的令牌定位 SSO 会话
+	if accessToken != "" {
+		cacheToken, xErr := l.tokenData.Get(ctx, accessToken)
+		if xErr != nil {
+			l.log.Warn(ctx, "LogoutLogic|FrontChannel - 读取令牌缓存失败", slog.String("error", xErr.Error()))
+		} else if cacheToken != nil {
+			event.Provider = cacheToken.Provider
+			event.SessionID = cacheToken.SessionID
+			event.Subject = cacheToken.Subject
+		}
+	}
+
+	if event.SessionID != "" {
+		if sid != "" && (sid != event.SessionID || provider != event.Provider) {
+			return nil, xError.NewError(ctx, xError.TokenInvalid, "sid 与当前会话不匹配", false, nil)
+		}
+		if xErr := l.Terminate(ctx, event); xErr != nil {
+			return nil, xErr
+		}
+		return event, nil
+	}
+
+	// 无法定位 SSO 会话时，仅清理当前浏览器持有的令牌
+	if accessToken != "" {
+		fingerprint, err := bSdkUtil.TokenFingerprint(accessToken)
+		if err != nil {
+			return nil, xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+		}
+		event.TokenFingerprints = []string{fingerprint}
+		l.purgeFingerprints(ctx, event.TokenFingerprints)
+	}
+	fireLogoutHooks(ctx, event)
+	return event, nil
+}
+
+// verifyFrontChannelParams 校验前端通道登出参数，返回 `iss` 所属的身份提供方名称（默认提供方为空）
+//
+// 按 OpenID Connect Front-Channel Logout 1.0 §2，`iss` 与 `sid` 需同时出现；
+// 出现时 `iss` 必须与某个命名身份提供方或请求所属租户配置的签发者一致，防止第三方伪造会话登出。
+func (l *LogoutLogic) verifyFrontChannelParams(ctx context.Context, issuer string, sid string) (string, *xError.Error) {
+	if issuer == "" && sid == "" {
+		return "", nil
+	}
+	if issuer == "" || sid == "" {
+		return "", xError.NewError(ctx, xError.ParameterEmpty, "iss 与 sid 参数需同时提供", false, nil)
+	}
+
+	provider, cfg := l.logoutProvider(ctx, issuer)
+	expected := cfg.Endpoints.Issuer
+	if expected == "" {
+		return "", xError.NewError(ctx, xError.OperationFailed, "签发者未配置", false, nil)
+	}
+	if issuer != expected {
+		return "", xError.NewError(ctx, xError.TokenInvalid, "签发者不匹配", false, nil)
+	}
+	return provider, nil
+}
+
+// purgeFingerprints 按令牌指纹结束令牌所属的家族，清理令牌、用户信息与自省缓存并广播吊销，单条失败仅记录告警。
+func (l *LogoutLogic) purgeFingerprints(ctx context.Context, fingerprints []string) {
+	if len(fingerprints) == 0 {
+		return
+	}
+
+	// 令牌缓存删除后无法再定位家族，因此先结束家族，阻止以其刷新令牌继续刷新
+	ended := make(map[string]struct{})
+	for _, fingerprint := range fingerprints {
+		familyID, xErr := l.tokenData.FamilyIDByFingerprint(ctx, fingerprint)
+		if xErr != nil {
+			l.log.Warn(ctx, "LogoutLogic|purgeFingerprints - 读取令牌家族失败", slog.String("error", xErr.Error()))
+			continue
+		}
+		if _, exist := ended[familyID]; familyID == "" || exist {
+			continue
+		}
+		ended[familyID] = struct{}{}
+		if xErr = l.family.End(ctx, familyID); xErr != nil {
+			l.log.Warn(ctx, "LogoutLogic|purgeFingerprints - 结束令牌家族失败", slog.String("error", xErr.Error()))
+		}
+	}
+
+	for _, fingerprint := range fingerprints {
+		if delErr := l.tokenData.DeleteByFingerprint(ctx, fingerprint); delErr != nil {
+			l.log.Warn(ctx, "LogoutLogic|purgeFingerprints - 清理令牌缓存失败", slog.String("error", delErr.Error()))
+		}
+		if delErr := l.userinfoData.DeleteCacheByFingerprint(ctx, fingerprint); delErr != nil {
+			l.log.Warn(ctx, "LogoutLogic|purgeFingerprints - 清理用户信息缓存失败", slog.String("error", delErr.Error()))
+		}
+		if delErr := l.introspectionData.DeleteCacheByFingerprint(ctx, "access_token", fingerprint); delErr != nil {
+			l.log.Warn(ctx, "LogoutLogic|purgeFingerprints - 清理自省缓存失败", slog.String("error", delErr.Error()))
+		}
+	}
+
+	xErr := l.revocation.PublishFingerprints(ctx, &bSdkModels.RevocationMessage{
+		Reason:       RevocationReasonSsoLogout,
+		TokenType:    "access_token",
+		Fingerprints: fingerprints,
+	})
+	if xErr != nil {
+		l.log.Warn(ctx, "LogoutLogic|purgeFingerprints - 广播令牌吊销失败", slog.String("error", xErr.Error()))
+	}
+}
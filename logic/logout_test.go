@@ -0,0 +1,523 @@
+package bSdkLogic
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+)
+
+// failingStore 在 fail 置位时使会话索引查询失败，用于模拟状态存储故障
+type failingStore struct {
+	bSdkStore.Store
+	fail atomic.Bool
+}
+
+func (s *failingStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	if s.fail.Load() {
+		return nil, errors.New("状态存储不可用")
+	}
+	return s.Store.SMembers(ctx, key)
+}
+
+func TestLogoutLogicVerifyFrontChannelParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+	logic := &LogoutLogic{log: xLog.WithName(xLog.NamedLOGC, "LogoutLogic")}
+	t.Setenv(bSdkConst.EnvSsoIssuer.String(), "https://sso.example.com")
+
+	t.Run("未携带参数", func(t *testing.T) {
+		if _, xErr := logic.verifyFrontChannelParams(ctx, "", ""); xErr != nil {
+			t.Fatalf("期望校验通过: %v", xErr)
+		}
+	})
+
+	t.Run("参数不完整", func(t *testing.T) {
+		_, xErr := logic.verifyFrontChannelParams(ctx, "", "sid-1")
+		if xErr == nil || xErr.GetErrorCode().Code != xError.ParameterEmpty.Code {
+			t.Fatalf("期望参数为空错误")
+		}
+	})
+
+	t.Run("签发者不匹配", func(t *testing.T) {
+		_, xErr := logic.verifyFrontChannelParams(ctx, "https://evil.example.com", "sid-1")
+		if xErr == nil || xErr.GetErrorCode().Code != xError.TokenInvalid.Code {
+			t.Fatalf("期望令牌无效错误")
+		}
+	})
+
+	t.Run("校验通过", func(t *testing.T) {
+		if _, xErr := logic.verifyFrontChannelParams(ctx, "https://sso.example.com", "sid-1"); xErr != nil {
+			t.Fatalf("期望校验通过: %v", xErr)
+		}
+	})
+}
+
+func TestLogoutLogicFrontChannel(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+	cfg := bSdkConfig.New(bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Issuer: "https://sso.example.com"}))
+	logic := NewLogoutWith(Deps{Config: cfg})
+
+	// storeSession 缓存一个关联 SSO 会话的令牌，返回其访问令牌
+	storeSession := func(t *testing.T, name string) string {
+		t.Helper()
+		token := &bSdkModels.CacheOAuthToken{
+			AccessToken: "frontchannel-at-" + name,
+			TokenType:   "Bearer",
+			Expiry:      time.Now().Add(time.Hour).Format(time.RFC3339),
+			SessionID:   "frontchannel-sid-" + name,
+			Subject:     "user-1",
+		}
+		if xErr := logic.tokenData.Store(ctx, token); xErr != nil {
+			t.Fatalf("缓存令牌失败: %v", xErr)
+		}
+		return token.AccessToken
+	}
+	cached := func(t *testing.T, accessToken string) bool {
+		t.Helper()
+		token, xErr := logic.tokenData.Get(ctx, accessToken)
+		return xErr == nil && token.AccessToken != ""
+	}
+
+	t.Run("sid 与会话 Cookie 不一致时拒绝", func(t *testing.T) {
+		accessToken := storeSession(t, "mismatch")
+		_, xErr := logic.FrontChannel(ctx, "https://sso.example.com", "frontchannel-sid-other", accessToken)
+		if xErr == nil || xErr.GetErrorCode().Code != xError.TokenInvalid.Code {
+			t.Fatalf("期望令牌无效错误，实际 %v", xErr)
+		}
+		if !cached(t, accessToken) {
+			t.Fatalf("校验失败时不应清理会话")
+		}
+	})
+
+	t.Run("未携带会话 Cookie 时不按 sid 清理服务端会话", func(t *testing.T) {
+		accessToken := storeSession(t, "nocookie")
+		event, xErr := logic.FrontChannel(ctx, "https://sso.example.com", "frontchannel-sid-nocookie", "")
+		if xErr != nil {
+			t.Fatalf("期望仅清理本地 Cookie，实际错误: %v", xErr)
+		}
+		if event.SessionID != "" {
+			t.Fatalf("未证明会话归属时事件不应携带 sid: %s", event.SessionID)
+		}
+		if !cached(t, accessToken) {
+			t.Fatalf("未携带会话 Cookie 时不应清理服务端会话")
+		}
+	})
+
+	t.Run("sid 与会话 Cookie 一致时清理会话", func(t *testing.T) {
+		accessToken := storeSession(t, "match")
+		event, xErr := logic.FrontChannel(ctx, "https://sso.example.com", "frontchannel-sid-match", accessToken)
+		if xErr != nil {
+			t.Fatalf("期望清理成功，实际错误: %v", xErr)
+		}
+		if event.SessionID != "frontchannel-sid-match" || cached(t, accessToken) {
+			t.Fatalf("期望清理 Cookie 所属会话，事件 sid: %s", event.SessionID)
+		}
+	})
+
+	t.Run("未携带 sid 时按会话 Cookie 清理", func(t *testing.T) {
+		accessToken := storeSession(t, "cookie")
+		event, xErr := logic.FrontChannel(ctx, "", "", accessToken)
+		if xErr != nil {
+			t.Fatalf("期望清理成功，实际错误: %v", xErr)
+		}
+		if event.SessionID != "frontchannel-sid-cookie" || cached(t, accessToken) {
+			t.Fatalf("期望清理 Cookie 所属会话，事件 sid: %s", event.SessionID)
+		}
+	})
+}
+
+func TestLogoutLogicTerminateProvider(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+	cfg := bSdkConfig.New(bSdkConfig.WithProvider("partner"))
+	logic := NewLogoutWith(Deps{Config: cfg})
+
+	// 两个身份提供方签发了同一 sub 的令牌
+	tokens := map[string]string{"": "terminate-at-default", "partner": "terminate-at-partner"}
+	for provider, accessToken := range tokens {
+		token := &bSdkModels.CacheOAuthToken{
+			AccessToken: accessToken,
+			TokenType:   "Bearer",
+			Expiry:      time.Now().Add(time.Hour).Format(time.RFC3339),
+			Subject:     "user-1",
+			Provider:    provider,
+		}
+		if xErr := logic.tokenData.Store(ctx, token); xErr != nil {
+			t.Fatalf("缓存令牌失败: %v", xErr)
+		}
+	}
+	cached := func(accessToken string) bool {
+		token, xErr := logic.tokenData.Get(ctx, accessToken)
+		return xErr == nil && token.AccessToken != ""
+	}
+
+	if xErr := logic.Terminate(ctx, &bSdkModels.LogoutEvent{Subject: "user-1"}); xErr != nil {
+		t.Fatalf("清理失败: %v", xErr)
+	}
+	if cached(tokens[""]) || !cached(tokens["partner"]) {
+		t.Fatalf("默认提供方的登出只应清理其签发的令牌")
+	}
+
+	event := &bSdkModels.LogoutEvent{Provider: "partner", Subject: "user-1"}
+	if xErr := logic.Terminate(ctx, event); xErr != nil {
+		t.Fatalf("清理失败: %v", xErr)
+	}
+	if cached(tokens["partner"]) || len(event.TokenFingerprints) != 1 {
+		t.Fatalf("期望清理该提供方的令牌，实际清理 %d 个", len(event.TokenFingerprints))
+	}
+}
+
+// signLogoutToken 使用 RS256 签发测试用的登出令牌。
+func signLogoutToken(t *testing.T, key *rsa.PrivateKey, header map[string]any, claims map[string]any) string {
+	t.Helper()
+
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("序列化头部失败: %v", err)
+	}
+	claimsBytes, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("序列化载荷失败: %v", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerBytes) + "." + base64.RawURLEncoding.EncodeToString(claimsBytes)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// newJWKSServer 返回仅包含 key 对应 RS256 公钥的 JWKS 端点。
+func newJWKSServer(key *rsa.PrivateKey, kid string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []*bSdkModels.JSONWebKey{{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+}
+
+func TestLogoutLogicVerifyLogoutToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	srv := newJWKSServer(key, "k1")
+	defer srv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Issuer: "https://sso.example.com", JWKS: srv.URL}),
+	)
+	logic := NewLogoutWith(Deps{Config: cfg})
+
+	header := map[string]any{"alg": "RS256", "kid": "k1", "typ": "logout+jwt"}
+	validClaims := func() map[string]any {
+		return map[string]any{
+			"iss":    "https://sso.example.com",
+			"aud":    []string{"cid"},
+			"iat":    time.Now().Unix(),
+			"exp":    time.Now().Add(time.Minute).Unix(),
+			"jti":    "jti-" + strconv.FormatInt(time.Now().UnixNano(), 36),
+			"sid":    "sid-1",
+			"sub":    "user-1",
+			"events": map[string]any{backChannelLogoutEvent: map[string]any{}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		header map[string]any
+		mutate func(claims map[string]any)
+		code   *xError.ErrorCode // 为 nil 时期望校验通过
+	}{
+		{name: "校验通过"},
+		{name: "aud 为字符串", mutate: func(c map[string]any) { c["aud"] = "cid" }},
+		{name: "仅携带 sid", mutate: func(c map[string]any) { delete(c, "sub") }},
+		{name: "仅携带 sub", mutate: func(c map[string]any) { delete(c, "sid") }},
+		{name: "未携带 exp 且在最大有效期内", mutate: func(c map[string]any) { delete(c, "exp") }},
+		{name: "缺少 sid 与 sub", mutate: func(c map[string]any) { delete(c, "sid"); delete(c, "sub") }, code: xError.TokenInvalid},
+		{name: "缺少 events", mutate: func(c map[string]any) { delete(c, "events") }, code: xError.TokenInvalid},
+		{name: "events 不含登出事件", mutate: func(c map[string]any) { c["events"] = map[string]any{"other": map[string]any{}} }, code: xError.TokenInvalid},
+		{name: "events 成员不是对象", mutate: func(c map[string]any) { c["events"] = map[string]any{backChannelLogoutEvent: "yes"} }, code: xError.TokenInvalid},
+		{name: "包含 nonce", mutate: func(c map[string]any) { c["nonce"] = "n-1" }, code: xError.TokenInvalid},
+		{name: "缺少 jti", mutate: func(c map[string]any) { delete(c, "jti") }, code: xError.TokenInvalid},
+		{name: "签发者不匹配", mutate: func(c map[string]any) { c["iss"] = "https://evil.example.com" }, code: xError.TokenInvalid},
+		{name: "受众不匹配", mutate: func(c map[string]any) { c["aud"] = []string{"other"} }, code: xError.TokenInvalid},
+		{name: "缺少受众", mutate: func(c map[string]any) { delete(c, "aud") }, code: xError.TokenInvalid},
+		{name: "缺少 iat", mutate: func(c map[string]any) { delete(c, "iat") }, code: xError.TokenInvalid},
+		{name: "iat 晚于当前时间", mutate: func(c map[string]any) { c["iat"] = time.Now().Add(logoutTokenClockSkew + time.Minute).Unix() }, code: xError.TokenInvalid},
+		{name: "exp 已过期", mutate: func(c map[string]any) { c["exp"] = time.Now().Add(-logoutTokenClockSkew - time.Minute).Unix() }, code: xError.TokenExpired},
+		{name: "exp 在时钟偏差内", mutate: func(c map[string]any) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{name: "未携带 exp 且超过最大有效期", mutate: func(c map[string]any) {
+			delete(c, "exp")
+			c["iat"] = time.Now().Add(-logoutTokenMaxAge - time.Minute).Unix()
+		}, code: xError.TokenExpired},
+		{name: "未知 kid", header: map[string]any{"alg": "RS256", "kid": "k2"}, code: xError.SignatureInvalid},
+		{name: "公钥算法不一致", header: map[string]any{"alg": "RS512", "kid": "k1"}, code: xError.SignatureInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.mutate != nil {
+				tt.mutate(claims)
+			}
+			h := header
+			if tt.header != nil {
+				h = tt.header
+			}
+			token, xErr := logic.VerifyLogoutToken(ctx, signLogoutToken(t, key, h, claims))
+			if tt.code == nil {
+				if xErr != nil {
+					t.Fatalf("期望校验通过，实际错误: %v", xErr)
+				}
+				if token.JTI != claims["jti"] {
+					t.Fatalf("jti 解析不正确: %s", token.JTI)
+				}
+				return
+			}
+			if xErr == nil || xErr.GetErrorCode().Code != tt.code.Code {
+				t.Fatalf("期望错误码 %d，实际 %v", tt.code.Code, xErr)
+			}
+		})
+	}
+
+	t.Run("未签名", func(t *testing.T) {
+		raw := signLogoutToken(t, key, map[string]any{"alg": "none"}, validClaims())
+		if _, xErr := logic.VerifyLogoutToken(ctx, raw[:strings.LastIndex(raw, ".")+1]); xErr == nil || xErr.GetErrorCode().Code != xError.SignatureInvalid.Code {
+			t.Fatalf("期望签名无效错误，实际 %v", xErr)
+		}
+	})
+
+	t.Run("签名被篡改", func(t *testing.T) {
+		raw := signLogoutToken(t, key, header, validClaims())
+		forged := signLogoutToken(t, key, header, map[string]any{"sub": "admin"})
+		tampered := raw[:strings.Index(raw, ".")+1] + strings.Split(forged, ".")[1] + raw[strings.LastIndex(raw, "."):]
+		if _, xErr := logic.VerifyLogoutToken(ctx, tampered); xErr == nil || xErr.GetErrorCode().Code != xError.SignatureInvalid.Code {
+			t.Fatalf("期望签名无效错误，实际 %v", xErr)
+		}
+	})
+
+	t.Run("清理失败后允许重试", func(t *testing.T) {
+		store := &failingStore{Store: bSdkStore.NewMemoryStore(time.Minute)}
+		failing := NewLogoutWith(Deps{Config: cfg, Store: store})
+		raw := signLogoutToken(t, key, header, validClaims())
+
+		store.fail.Store(true)
+		if _, xErr := failing.BackChannel(ctx, raw); xErr == nil || xErr.GetErrorCode().Code == xError.RepeatOperation.Code {
+			t.Fatalf("期望清理失败错误，实际 %v", xErr)
+		}
+		store.fail.Store(false)
+		if _, xErr := failing.BackChannel(ctx, raw); xErr != nil {
+			t.Fatalf("清理失败后重试应成功，实际错误: %v", xErr)
+		}
+		if _, xErr := failing.BackChannel(ctx, raw); xErr == nil || xErr.GetErrorCode().Code != xError.RepeatOperation.Code {
+			t.Fatalf("成功处理后期望重放错误，实际 %v", xErr)
+		}
+	})
+
+	t.Run("jti 重放", func(t *testing.T) {
+		raw := signLogoutToken(t, key, header, validClaims())
+		if _, xErr := logic.BackChannel(ctx, raw); xErr != nil {
+			t.Fatalf("首次登出期望成功，实际错误: %v", xErr)
+		}
+		if _, xErr := logic.BackChannel(ctx, raw); xErr == nil || xErr.GetErrorCode().Code != xError.RepeatOperation.Code {
+			t.Fatalf("期望重放错误，实际 %v", xErr)
+		}
+	})
+}
+
+func TestLogoutLogicBackChannelProvider(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	defaultKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	partnerKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	defaultSrv := newJWKSServer(defaultKey, "default-k1")
+	defer defaultSrv.Close()
+	partnerSrv := newJWKSServer(partnerKey, "partner-k1")
+	defer partnerSrv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Issuer: "https://sso.example.com", JWKS: defaultSrv.URL}),
+		bSdkConfig.WithProvider("partner",
+			bSdkConfig.WithClient("pid", "psecret"),
+			bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Issuer: "https://partner.example.com", JWKS: partnerSrv.URL}),
+		),
+	)
+	logic := NewLogoutWith(Deps{Config: cfg})
+
+	// 两个身份提供方的令牌关联了相同的 sid
+	tokens := map[string]string{"": "backchannel-at-default", "partner": "backchannel-at-partner"}
+	for provider, accessToken := range tokens {
+		token := &bSdkModels.CacheOAuthToken{
+			AccessToken: accessToken,
+			TokenType:   "Bearer",
+			Expiry:      time.Now().Add(time.Hour).Format(time.RFC3339),
+			SessionID:   "shared-sid",
+			Provider:    provider,
+		}
+		if xErr := logic.tokenData.Store(ctx, token); xErr != nil {
+			t.Fatalf("缓存令牌失败: %v", xErr)
+		}
+	}
+
+	header := map[string]any{"alg": "RS256", "kid": "partner-k1"}
+	partnerClaims := func() map[string]any {
+		return map[string]any{
+			"iss":    "https://partner.example.com",
+			"aud":    "pid",
+			"iat":    time.Now().Unix(),
+			"jti":    "partner-jti-" + strconv.FormatInt(time.Now().UnixNano(), 36),
+			"sid":    "shared-sid",
+			"events": map[string]any{backChannelLogoutEvent: map[string]any{}},
+		}
+	}
+
+	t.Run("受众须为该提供方的客户端", func(t *testing.T) {
+		claims := partnerClaims()
+		claims["aud"] = "cid"
+		if _, xErr := logic.VerifyLogoutToken(ctx, signLogoutToken(t, partnerKey, header, claims)); xErr == nil || xErr.GetErrorCode().Code != xError.TokenInvalid.Code {
+			t.Fatalf("期望受众不匹配错误，实际 %v", xErr)
+		}
+	})
+
+	t.Run("须使用该提供方的公钥签名", func(t *testing.T) {
+		raw := signLogoutToken(t, defaultKey, map[string]any{"alg": "RS256", "kid": "default-k1"}, partnerClaims())
+		if _, xErr := logic.VerifyLogoutToken(ctx, raw); xErr == nil || xErr.GetErrorCode().Code != xError.SignatureInvalid.Code {
+			t.Fatalf("期望签名无效错误，实际 %v", xErr)
+		}
+	})
+
+	t.Run("仅清理该提供方的会话", func(t *testing.T) {
+		event, xErr := logic.BackChannel(ctx, signLogoutToken(t, partnerKey, header, partnerClaims()))
+		if xErr != nil {
+			t.Fatalf("期望登出成功，实际错误: %v", xErr)
+		}
+		if event.Provider != "partner" || len(event.TokenFingerprints) != 1 {
+			t.Fatalf("期望清理 partner 的 1 个令牌，实际提供方 %q，令牌 %d 个", event.Provider, len(event.TokenFingerprints))
+		}
+		if token, _ := logic.tokenData.Get(ctx, tokens["partner"]); token.AccessToken != "" {
+			t.Fatalf("期望 partner 的令牌已清理")
+		}
+		if token, _ := logic.tokenData.Get(ctx, tokens[""]); token.AccessToken == "" {
+			t.Fatalf("默认提供方的令牌不应被清理")
+		}
+	})
+
+	t.Run("前端通道登出须由签发令牌的提供方发起", func(t *testing.T) {
+		_, xErr := logic.FrontChannel(ctx, "https://partner.example.com", "shared-sid", tokens[""])
+		if xErr == nil || xErr.GetErrorCode().Code != xError.TokenInvalid.Code {
+			t.Fatalf("期望会话不匹配错误，实际 %v", xErr)
+		}
+		if token, _ := logic.tokenData.Get(ctx, tokens[""]); token.AccessToken == "" {
+			t.Fatalf("校验失败时不应清理会话")
+		}
+	})
+}
+
+func TestLogoutLogicBackChannelEndsFamily(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	jwksSrv := newJWKSServer(key, "k1")
+	defer jwksSrv.Close()
+	var calls atomic.Int32
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"ended-at-2","token_type":"Bearer","refresh_token":"ended-rt-2","expires_in":3600}`))
+	}))
+	defer tokenSrv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Issuer: "https://sso.example.com", JWKS: jwksSrv.URL, Token: tokenSrv.URL}),
+	)
+	deps := Deps{Config: cfg, Store: bSdkStore.NewMemoryStore(time.Minute)}
+	oauthLogic := newTestOAuth(t, deps)
+	logic := NewLogoutWith(deps)
+
+	login := &bSdkModels.CacheOAuthToken{
+		AccessToken:  "ended-at-1",
+		RefreshToken: "ended-rt-1",
+		TokenType:    "Bearer",
+		Expiry:       time.Now().Add(time.Hour).Format(time.RFC3339),
+		Subject:      "user-1",
+		SessionID:    "ended-sid",
+	}
+	if xErr := oauthLogic.family.Start(ctx, login); xErr != nil {
+		t.Fatalf("创建令牌家族失败: %v", xErr)
+	}
+	if xErr := oauthLogic.tokenData.Store(ctx, login); xErr != nil {
+		t.Fatalf("缓存令牌失败: %v", xErr)
+	}
+
+	raw := signLogoutToken(t, key, map[string]any{"alg": "RS256", "kid": "k1"}, map[string]any{
+		"iss":    "https://sso.example.com",
+		"aud":    "cid",
+		"iat":    time.Now().Unix(),
+		"jti":    "ended-jti",
+		"sid":    "ended-sid",
+		"events": map[string]any{backChannelLogoutEvent: map[string]any{}},
+	})
+	if _, xErr := logic.BackChannel(ctx, raw); xErr != nil {
+		t.Fatalf("期望登出成功，实际错误: %v", xErr)
+	}
+
+	if _, xErr := oauthLogic.RefreshByToken(ctx, "ended-rt-1"); xErr == nil || xErr.GetErrorCode().Code != bSdkConst.ErrRefreshTokenReused.Code {
+		t.Fatalf("登出后期望刷新令牌失效，实际 %v", xErr)
+	}
+	if _, xErr := oauthLogic.TokenSource(ctx, login, "ended-rt-1"); xErr == nil {
+		t.Fatalf("登出后期望携带访问令牌的刷新同样失败")
+	}
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("登出后不应再请求令牌端点，实际 %d 次", got)
+	}
+}
@@ -5,60 +5,216 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
 	xUtil "github.com/bamboo-services/bamboo-base-go/common/utility"
-	xCtxUtil "github.com/bamboo-services/bamboo-base-go/common/utility/context"
-	xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
-	"github.com/go-resty/resty/v2"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
 	bSdkRepo "github.com/phalanx-labs/beacon-sso-sdk/repository"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
 	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
-	"github.com/redis/go-redis/v9"
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
 
+const (
+	refreshLockTTL      = time.Second * 10       // 刷新锁最长持有时间
+	refreshWaitTimeout  = time.Second * 5        // 等待其他实例完成刷新的最长时间
+	refreshPollInterval = time.Millisecond * 100 // 等待期间轮询近期刷新结果的间隔
+	refreshTimeout      = time.Second * 15       // 共享刷新（等待锁 + 请求令牌端点）的最长时间
+)
+
+// refreshGroup 进程内刷新令牌 singleflight，合并同一实例上的并发刷新。
+var refreshGroup singleflight.Group
+
+// refreshResult singleflight 共享的刷新结果。
+type refreshResult struct {
+	token *oauth2.Token
+	xErr  *xError.Error
+}
+
 // OAuthLogic OAuth 业务逻辑组件，封装了身份认证流程的核心处理能力。
 //
-// 该结构体作为业务层的聚合器，整合了底层数据资源（GORM、Redis）和特定的数据仓储，
+// 该结构体作为业务层的聚合器，整合了底层数据资源（GORM、状态存储）和特定的数据仓储，
 // 用于处理诸如令牌颁发、用户信息检索及权限校验等复杂逻辑。
 type OAuthLogic struct {
-	db        *gorm.DB                 // GORM 数据库实例
-	rdb       *redis.Client            // Redis 客户端实例
-	log       *xLog.LogNamedLogger     // 日志实例
-	data      *bSdkRepo.OAuthRepo      // OAuth 数据仓储实例
-	tokenData *bSdkRepo.OAuthTokenRepo // OAuth Token 数据仓储实例
+	cfg         *bSdkConfig.Config         // SDK 配置，未注册时按需读取环境变量
+	db          *gorm.DB                   // GORM 数据库实例
+	store       bSdkStore.Store            // 状态存储实例
+	httpClient  *http.Client               // 请求 SSO 使用的 HTTP 客户端，为 nil 时按配置创建
+	log         *xLog.LogNamedLogger       // 日志实例
+	data        *bSdkRepo.OAuthRepo        // OAuth 数据仓储实例
+	tokenData   *bSdkRepo.OAuthTokenRepo   // OAuth Token 数据仓储实例
+	revocation  *RevocationLogic           // 令牌吊销广播逻辑
+	family      *TokenFamilyLogic          // 刷新令牌家族逻辑
+	refreshData *bSdkRepo.OAuthRefreshRepo // 刷新令牌并发控制数据仓储实例
+
+	provider  string                 // 命名身份提供方名称，默认提供方为空
+	providers map[string]*OAuthLogic // 已注册的命名身份提供方，仅默认提供方持有
 }
 
 // NewOAuth 创建并初始化一个新的 OAuthLogic 业务逻辑实例。
 //
-// 该函数通过组合 GORM 数据库实例和 Redis 客户端来构建 OAuth 业务层。
+// 该函数通过组合 GORM 数据库实例和状态存储来构建 OAuth 业务层。
 // 在初始化过程中，它会注入底层的 OAuth 数据仓储（包含默认 30 分钟的
 // 缓存策略）以及带有命名上下文的日志记录器，从而为 OAuth 认证流程
 // 提供完整的数据持久化、缓存加速和日志追踪能力。
 //
+// SDK 配置中注册了命名身份提供方（`providers`）时，会为每个提供方创建独立的逻辑实例，
+// 可通过 `Provider` 获取；各提供方的授权 State 缓存使用独立的键前缀。
+//
 // 参数:
-//   - ctx: 请求上下文，用于获取数据库和 Redis 实例。
+//   - ctx: 请求上下文，用于获取数据库与状态存储实例。
 //
 // 返回值:
 //   - *OAuthLogic: 配置完成的 OAuth 逻辑层实例指针。
 func NewOAuth(ctx context.Context) *OAuthLogic {
-	db := xCtxUtil.MustGetDB(ctx)
-	rdb := xCtxUtil.MustGetRDB(ctx)
+	return newOAuthWith(DepsFromContext(ctx))
+}
 
+// NewOAuthLogic 使用显式传入的配置、状态存储与 HTTP 客户端创建 OAuthLogic，不依赖 `xReg.Register` 的上下文注入。
+//
+// 参数:
+//   - cfg: SDK 配置，通常由 `bSdkConfig.NewReloader` 构建以支持热更新；为 nil 时按需读取环境变量。
+//   - store: 状态存储，为 nil 时使用进程内存储（仅适用于单实例）。
+//   - httpClient: 令牌交换、刷新与注销使用的 HTTP 客户端，为 nil 时按 `http` 配置创建。
+//
+// 返回值:
+//   - *OAuthLogic: 配置完成的 OAuth 逻辑层实例指针；需要数据库或吊销广播时使用 `NewOAuthWith`。
+//   - error: 令牌密钥缺失、非法或与进程内已配置的密钥冲突时返回错误。
+func NewOAuthLogic(cfg *bSdkConfig.Config, store bSdkStore.Store, httpClient *http.Client) (*OAuthLogic, error) {
+	return NewOAuthWith(Deps{Config: cfg, Store: store, HTTPClient: httpClient})
+}
+
+// NewOAuthWith 使用显式依赖创建 OAuthLogic，并按 `deps.Config` 配置进程级令牌密钥（参见 `bSdkUtil.ConfigureTokenKeys`）。
+//
+// 令牌密钥缺失、非法或与进程内已配置的密钥冲突时返回错误。
+func NewOAuthWith(deps Deps) (*OAuthLogic, error) {
+	if err := deps.configureTokenKeys(); err != nil {
+		return nil, err
+	}
+	return newOAuthWith(deps), nil
+}
+
+// newOAuthWith 使用显式依赖创建 OAuthLogic，不配置令牌密钥；上下文构造函数的令牌密钥由 `oAuthConfig` 启动节点配置。
+func newOAuthWith(deps Deps) *OAuthLogic {
+	deps.Store = deps.store()
+	cfg := deps.Config
+
+	logic := newOAuth(deps, "", deps.Store)
+	if cfg != nil && len(cfg.Providers) > 0 {
+		logic.providers = make(map[string]*OAuthLogic, len(cfg.Providers))
+		for _, name := range cfg.ProviderNames() {
+			logic.providers[name] = newOAuth(deps, name, bSdkStore.NewPrefixStore(deps.Store, cfg.ProviderKeyPrefix(name)))
+		}
+	}
+	return logic
+}
+
+// newOAuth 创建绑定到指定身份提供方的 OAuthLogic，stateStore 用于授权 State 缓存。
+//
+// 令牌、令牌家族与刷新控制等以令牌指纹为键的数据在各提供方之间共享，
+// 以便中间件根据令牌上记录的提供方完成校验；sid/sub 会话索引由令牌仓储按令牌记录的提供方隔离。
+func newOAuth(deps Deps, provider string, stateStore bSdkStore.Store) *OAuthLogic {
+	cfg := sdkConfig(deps.Config)
 	return &OAuthLogic{
-		db:        db,
-		rdb:       rdb,
-		log:       xLog.WithName(xLog.NamedLOGC, "OAuthLogic"),
-		data:      bSdkRepo.NewOAuthRepo(db, rdb),
-		tokenData: bSdkRepo.NewOAuthTokenRepo(db, rdb),
+		cfg:         deps.Config,
+		db:          deps.DB,
+		store:       deps.Store,
+		httpClient:  deps.HTTPClient,
+		log:         xLog.WithName(xLog.NamedLOGC, "OAuthLogic"),
+		data:        bSdkRepo.NewOAuthRepoWith(deps.DB, stateStore, cfg),
+		tokenData:   bSdkRepo.NewOAuthTokenRepoWith(deps.DB, deps.Store, cfg),
+		revocation:  NewRevocationWith(deps),
+		family:      NewTokenFamilyWith(deps),
+		refreshData: bSdkRepo.NewOAuthRefreshRepoWith(deps.DB, deps.Store, cfg),
+		provider:    provider,
 	}
 }
 
+// config 返回当前实例使用的 SDK 配置，默认提供方按上下文所属租户替换客户端。
+func (l *OAuthLogic) config(ctx context.Context) *bSdkConfig.Config {
+	// 提供方集合不支持热更新，当前快照中必然存在该提供方
+	cfg, _ := providerConfig(ctx, l.cfg, l.provider)
+	return cfg
+}
+
+// oauth2Config 返回当前身份提供方的 OAuth 配置，默认提供方按上下文所属租户选择客户端。
+func (l *OAuthLogic) oauth2Config(ctx context.Context) *oauth2.Config {
+	if l.provider != "" {
+		return l.config(ctx).OAuth2()
+	}
+	if oauth := tenantOAuth2(ctx, l.cfg); oauth != nil {
+		return oauth
+	}
+	if oauth, xErr := bSdkUtil.TryGetOAuthConfig(ctx); xErr == nil {
+		return oauth
+	}
+	return sdkConfig(l.cfg).OAuth2()
+}
+
+// Provider 返回指定命名身份提供方的逻辑实例
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - name: 提供方名称，为空时返回默认提供方（即当前实例）。
+//
+// 返回值:
+//   - *OAuthLogic: 绑定到该提供方的逻辑实例。
+//   - *xError.Error: 提供方未注册时返回 `NotExist` 错误。
+func (l *OAuthLogic) Provider(ctx context.Context, name string) (*OAuthLogic, *xError.Error) {
+	if name == "" || name == l.provider {
+		return l, nil
+	}
+	provider, ok := l.providers[name]
+	if !ok {
+		return nil, xError.NewError(ctx, xError.NotExist, "身份提供方不存在", false, nil)
+	}
+	return provider, nil
+}
+
+// forToken 返回令牌所属身份提供方的逻辑实例，并校验令牌签发者与该提供方一致。
+func (l *OAuthLogic) forToken(ctx context.Context, cacheToken *bSdkModels.CacheOAuthToken) (*OAuthLogic, *xError.Error) {
+	target := l
+	if cacheToken.Provider != l.provider {
+		target = l.providers[cacheToken.Provider]
+		if target == nil {
+			return nil, xError.NewError(ctx, xError.TokenInvalid, "令牌所属的身份提供方未注册", false, nil)
+		}
+	}
+	if xErr := target.verifyIssuer(ctx, cacheToken); xErr != nil {
+		return nil, xErr
+	}
+	return target, nil
+}
+
+// verifyIssuer 校验令牌关联的 ID Token 签发者（iss）与当前身份提供方配置的签发者一致，且受众（aud）包含本客户端 ID。
+//
+// 签发者按 `bSdkConfig.SameIssuer` 比较，忽略末尾的 `/`；未配置签发者或令牌没有 ID Token 时跳过校验。
+func (l *OAuthLogic) verifyIssuer(ctx context.Context, cacheToken *bSdkModels.CacheOAuthToken) *xError.Error {
+	cfg := l.config(ctx)
+	issuer := cfg.Endpoints.Issuer
+	if issuer == "" || cacheToken.IDToken == "" {
+		return nil
+	}
+	token, err := bSdkUtil.ParseJWT(cacheToken.IDToken)
+	if err != nil {
+		return xError.NewError(ctx, xError.TokenInvalid, "解析 ID Token 失败", false, err)
+	}
+	if !bSdkConfig.SameIssuer(token.ClaimString("iss"), issuer) {
+		return xError.NewError(ctx, xError.TokenInvalid, "令牌签发者与身份提供方不匹配", false, nil)
+	}
+	if !slices.Contains(token.ClaimAudience(), cfg.Client.ID) {
+		return xError.NewError(ctx, xError.TokenInvalid, "令牌受众不包含当前客户端", false, nil)
+	}
+	return nil
+}
+
 // Create 初始化并存储 OAuth 2.0 认证流程所需的 State 和 PKCE Verifier
 //
 // 该方法生成一个随机的 State 字符串和一个符合 OAuth 2.0 PKCE 规范的 Code Verifier，
@@ -108,7 +264,7 @@ func (l *OAuthLogic) BuildURL(ctx context.Context, oAuth *bSdkModels.CacheOAuth)
 	var authCodeConfig = []oauth2.AuthCodeOption{
 		oauth2.S256ChallengeOption(oAuth.Verifier),
 	}
-	authURL := bSdkUtil.GetOAuthConfig(ctx).AuthCodeURL(oAuth.State, authCodeConfig...)
+	authURL := l.oauth2Config(ctx).AuthCodeURL(oAuth.State, authCodeConfig...)
 	return authURL, nil
 }
 
@@ -172,7 +328,7 @@ func (l *OAuthLogic) Exchange(ctx context.Context, code string, verifier string)
 	var authCodeConfig = []oauth2.AuthCodeOption{
 		oauth2.VerifierOption(verifier),
 	}
-	getToken, oAuthErr := bSdkUtil.GetOAuthConfig(ctx).Exchange(ctx, code, authCodeConfig...)
+	getToken, oAuthErr := l.oauth2Config(ctx).Exchange(oauth2Context(ctx, l.config(ctx), l.httpClient), code, authCodeConfig...)
 	if oAuthErr != nil {
 		return nil, xError.NewError(ctx, xError.Unauthorized, "未登录", false, oAuthErr)
 	}
@@ -183,6 +339,18 @@ func (l *OAuthLogic) Exchange(ctx context.Context, code string, verifier string)
 		TokenType:    getToken.TokenType,
 		RefreshToken: getToken.RefreshToken,
 		Expiry:       getToken.Expiry.Format(time.RFC3339),
+		Provider:     l.provider,
+	}
+	bindTokenIdentity(cacheToken, tokenExtraString(getToken, "id_token"))
+	if xErr := l.verifyIssuer(ctx, cacheToken); xErr != nil {
+		// 签发者不匹配的令牌不会交给调用方，尽力在授权服务器注销，避免其在有效期内仍可使用
+		l.revokeIssued(ctx, getToken)
+		return nil, xErr
+	}
+	if familyErr := l.family.Start(ctx, cacheToken); familyErr != nil {
+		l.log.Warn(ctx, "Exchange - 创建令牌家族失败",
+			slog.String("error", familyErr.Error()),
+		)
 	}
 	if storeErr := l.tokenData.Store(ctx, cacheToken); storeErr != nil {
 		l.log.Warn(ctx, "Exchange - 缓存令牌失败",
@@ -193,10 +361,41 @@ func (l *OAuthLogic) Exchange(ctx context.Context, code string, verifier string)
 	return getToken, nil
 }
 
+// revokeIssued 尽力注销刚换取但被拒绝的令牌，未配置注销端点或注销失败时仅记录告警。
+func (l *OAuthLogic) revokeIssued(ctx context.Context, token *oauth2.Token) {
+	cfg := l.config(ctx)
+	if cfg.Endpoints.Revocation == "" {
+		l.log.Warn(ctx, "OAuthLogic|revokeIssued - 未配置注销端点，无法注销被拒绝的令牌")
+		return
+	}
+
+	// 先注销刷新令牌，授权服务器通常会一并注销其签发的访问令牌
+	issued := []struct{ tokenType, token string }{
+		{"refresh_token", token.RefreshToken},
+		{"access_token", token.AccessToken},
+	}
+	for _, item := range issued {
+		if item.token == "" {
+			continue
+		}
+		if xErr := revokeAtEndpoint(ctx, cfg, l.httpClient, item.tokenType, item.token); xErr != nil {
+			l.log.Warn(ctx, "OAuthLogic|revokeIssued - 注销令牌失败",
+				slog.String("token_type", item.tokenType),
+				slog.String("error", xErr.Error()),
+			)
+		}
+	}
+}
+
 // TokenSource 刷新令牌
 //
 // 该方法使用刷新令牌（Refresh Token）获取新的访问令牌。
-// 它会校验传入的刷新令牌与缓存中的一致性，不一致则清理缓存。
+// 已轮换的刷新令牌被再次使用时判定为重放，吊销整个令牌家族并返回 `ErrRefreshTokenReused` 错误；
+// 传入的刷新令牌与缓存不一致时清理缓存并返回 `TokenInvalid` 错误。
+//
+// 同一令牌家族的刷新通过进程内 singleflight 与 Redis 分布式锁串行执行；刷新结果以旧刷新令牌为键
+// 短暂缓存（默认 2 秒），仅供刷新进行中等待锁的并发请求取回新令牌，避免以同一刷新令牌重复刷新而被授权服务器判定为重放。
+// 刷新完成后再次提交旧刷新令牌的请求不会读取该结果，而是进入重放检测。
 //
 // 参数说明:
 //   - ctx: 请求上下文，用于传递请求范围的数据、控制超时及日志记录。
@@ -209,25 +408,149 @@ func (l *OAuthLogic) Exchange(ctx context.Context, code string, verifier string)
 func (l *OAuthLogic) TokenSource(ctx context.Context, cacheToken *bSdkModels.CacheOAuthToken, rt string) (*oauth2.Token, *xError.Error) {
 	l.log.Info(ctx, "TokenSource - 刷新令牌")
 
+	if rt == "" {
+		return nil, xError.NewError(ctx, xError.ParameterEmpty, "刷新令牌为空", false, nil)
+	}
+
+	// 令牌由其他身份提供方签发时交由对应提供方刷新
+	target, xErr := l.forToken(ctx, cacheToken)
+	if xErr != nil {
+		return nil, xErr
+	}
+	if target != l {
+		return target.TokenSource(ctx, cacheToken, rt)
+	}
+
+	fingerprint, err := bSdkUtil.TokenFingerprint(rt)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	lockKey := cacheToken.FamilyID
+	if lockKey == "" {
+		lockKey = fingerprint
+	}
+
+	// singleflight 以租户 + 家族 + 刷新令牌为键，不同租户或不同刷新令牌（如重放的旧令牌）不会共享结果
+	// 共享刷新脱离首个调用方的取消信号，避免其断开连接导致其他等待方一同失败；各调用方仍可因自身取消提前返回
+	ch := refreshGroup.DoChan(bSdkUtil.TenantNamespace(ctx)+lockKey+":"+fingerprint, func() (interface{}, error) {
+		sharedCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+		defer cancel()
+		token, xErr := l.lockedRefresh(sharedCtx, lockKey, cacheToken, rt)
+		return &refreshResult{token: token, xErr: xErr}, nil
+	})
+	select {
+	case res := <-ch:
+		result := res.Val.(*refreshResult)
+		return result.token, result.xErr
+	case <-ctx.Done():
+		return nil, xError.NewError(ctx, xError.Timeout, "等待令牌刷新超时", false, ctx.Err())
+	}
+}
+
+// RefreshByToken 仅凭刷新令牌刷新令牌
+//
+// 供只持有刷新令牌的调用方（如 `/sso/account/token/refresh`）使用：按令牌家族还原会话信息与所属身份提供方后交由
+// `TokenSource` 刷新，由家族所属提供方的令牌端点完成刷新，与携带访问令牌的刷新共享刷新锁、singleflight 与近期刷新结果。
+// 刷新令牌不属于任何家族（升级前登录或家族已过期）时以令牌指纹加锁，刷新后创建新家族。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - rt: 客户端提供的刷新令牌。
+//
+// 返回值:
+//   - *oauth2.Token: 刷新后的令牌对象。
+//   - *xError.Error: 刷新失败时返回错误信息，已轮换的刷新令牌被再次使用时为 `ErrRefreshTokenReused`。
+func (l *OAuthLogic) RefreshByToken(ctx context.Context, rt string) (*oauth2.Token, *xError.Error) {
+	if rt == "" {
+		return nil, xError.NewError(ctx, xError.ParameterEmpty, "刷新令牌为空", false, nil)
+	}
+
+	cacheToken := &bSdkModels.CacheOAuthToken{RefreshToken: rt}
+	family, xErr := l.family.Find(ctx, rt)
+	if xErr != nil {
+		return nil, xErr
+	}
+	if family != nil {
+		cacheToken.FamilyID = family.FamilyID
+		cacheToken.Subject = family.Subject
+		cacheToken.SessionID = family.SessionID
+		cacheToken.Provider = family.Provider
+	}
+	return l.TokenSource(ctx, cacheToken, rt)
+}
+
+// lockedRefresh 在分布式锁保护下刷新令牌
+//
+// 未获取到锁时轮询近期刷新结果，直至持锁实例完成刷新或等待超时；近期刷新结果仅对等待过锁的请求生效。
+func (l *OAuthLogic) lockedRefresh(ctx context.Context, lockKey string, cacheToken *bSdkModels.CacheOAuthToken, rt string) (*oauth2.Token, *xError.Error) {
+	owner := xUtil.Generate().RandomUpperString(32)
+	deadline := time.Now().Add(refreshWaitTimeout)
+	waited := false
+	for {
+		locked, xErr := l.refreshData.Lock(ctx, lockKey, owner, refreshLockTTL)
+		if xErr != nil {
+			return nil, xErr
+		}
+		if locked {
+			break
+		}
+		waited = true
+		if recent := l.recentRefresh(ctx, rt); recent != nil {
+			return recent, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, xError.NewError(ctx, xError.LockConflict, "令牌正在刷新，请稍后重试", false, nil)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, xError.NewError(ctx, xError.Timeout, "等待令牌刷新超时", false, ctx.Err())
+		case <-time.After(refreshPollInterval):
+		}
+	}
+	defer func() {
+		if xErr := l.refreshData.Unlock(context.WithoutCancel(ctx), lockKey, owner); xErr != nil {
+			l.log.Warn(ctx, "OAuthLogic|lockedRefresh - 释放刷新锁失败",
+				slog.String("error", xErr.Error()),
+			)
+		}
+	}()
+
+	// 等待期间可能已由其他实例完成刷新，获取锁后再次检查；未经等待直接获取锁的请求不读取近期刷新结果，
+	// 否则刷新完成后重放旧刷新令牌可在结果有效期内取得新令牌并绕过重放检测
+	if waited {
+		if recent := l.recentRefresh(ctx, rt); recent != nil {
+			return recent, nil
+		}
+	}
+	return l.refresh(ctx, cacheToken, rt)
+}
+
+// refresh 调用令牌端点刷新令牌，并更新令牌缓存、令牌家族与近期刷新结果。
+func (l *OAuthLogic) refresh(ctx context.Context, cacheToken *bSdkModels.CacheOAuthToken, rt string) (*oauth2.Token, *xError.Error) {
+	// 重放检测：已轮换的刷新令牌再次出现时吊销整个家族
+	if xErr := l.family.DetectReuse(ctx, rt); xErr != nil {
+		return nil, xErr
+	}
+
 	// 校验 RT 是否一致
 	if cacheToken.RefreshToken != rt {
-		return nil, l.tokenData.Delete(ctx, cacheToken.AccessToken)
+		if delErr := l.tokenData.Delete(ctx, cacheToken.AccessToken); delErr != nil {
+			l.log.Warn(ctx, "OAuthLogic|refresh - 清理令牌缓存失败",
+				slog.String("error", delErr.Error()),
+			)
+		}
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "刷新令牌不匹配", false, nil)
 	}
 
-	// 构造 oauth2.Token
-	parseTime, timeErr := time.Parse(time.RFC3339, cacheToken.Expiry)
-	if timeErr != nil {
-		return nil, xError.NewError(ctx, xError.OperationFailed, "解析令牌过期时间失败", false, timeErr)
-	}
+	// 构造 oauth2.Token：仅携带刷新令牌以强制访问令牌端点，
+	// 否则 oauth2 会在访问令牌未过期时直接返回旧令牌，提前刷新将不会生效
 	oldToke := &oauth2.Token{
-		AccessToken:  cacheToken.AccessToken,
 		TokenType:    cacheToken.TokenType,
 		RefreshToken: cacheToken.RefreshToken,
-		Expiry:       parseTime,
 	}
 
 	// 尝试刷新
-	tokenSource, err := bSdkUtil.GetOAuthConfig(ctx).TokenSource(ctx, oldToke).Token()
+	tokenSource, err := l.oauth2Config(ctx).TokenSource(oauth2Context(ctx, l.config(ctx), l.httpClient), oldToke).Token()
 	if err != nil {
 		return nil, xError.NewError(ctx, xError.Unauthorized, "未登录", false, err)
 	}
@@ -236,15 +559,88 @@ func (l *OAuthLogic) TokenSource(ctx context.Context, cacheToken *bSdkModels.Cac
 		TokenType:    tokenSource.TokenType,
 		RefreshToken: tokenSource.RefreshToken,
 		Expiry:       tokenSource.Expiry.Format(time.RFC3339),
+		IDToken:      cacheToken.IDToken,
+		Subject:      cacheToken.Subject,
+		SessionID:    cacheToken.SessionID,
+		FamilyID:     cacheToken.FamilyID,
+		Provider:     cacheToken.Provider,
+	}
+	bindTokenIdentity(newToken, tokenExtraString(tokenSource, "id_token"))
+	if familyErr := l.family.Rotate(ctx, rt, newToken); familyErr != nil {
+		l.log.Warn(ctx, "OAuthLogic|refresh - 轮换令牌家族失败",
+			slog.String("error", familyErr.Error()),
+		)
 	}
 	if storeErr := l.tokenData.Store(ctx, newToken); storeErr != nil {
-		l.log.Warn(ctx, "Exchange - 缓存令牌失败",
+		l.log.Warn(ctx, "OAuthLogic|refresh - 缓存令牌失败",
 			slog.String("error", storeErr.Error()),
 		)
 	}
+	// 旧访问令牌在有效期内仍可使用，但不再关联已轮换的刷新令牌，
+	// 避免刷新完成后仍携带旧访问令牌的请求触发自动刷新而被判定为重放
+	if cacheToken.AccessToken != "" && cacheToken.AccessToken != tokenSource.AccessToken &&
+		tokenSource.RefreshToken != "" && tokenSource.RefreshToken != rt {
+		retired := *cacheToken
+		retired.RefreshToken = ""
+		if storeErr := l.tokenData.Store(ctx, &retired); storeErr != nil {
+			l.log.Warn(ctx, "OAuthLogic|refresh - 更新旧令牌缓存失败",
+				slog.String("error", storeErr.Error()),
+			)
+		}
+	}
+	if recentErr := l.refreshData.SetRecent(ctx, rt, newToken); recentErr != nil {
+		l.log.Warn(ctx, "OAuthLogic|refresh - 缓存近期刷新结果失败",
+			slog.String("error", recentErr.Error()),
+		)
+	}
+
+	// 令牌轮换后广播旧令牌指纹，其他副本据此清理本地缓存
+	rotated := make([]string, 0, 2)
+	if cacheToken.AccessToken != "" && tokenSource.AccessToken != cacheToken.AccessToken {
+		rotated = append(rotated, cacheToken.AccessToken)
+	}
+	if tokenSource.RefreshToken != "" && tokenSource.RefreshToken != cacheToken.RefreshToken {
+		rotated = append(rotated, cacheToken.RefreshToken)
+	}
+	if len(rotated) > 0 {
+		if pubErr := l.revocation.Publish(ctx, RevocationReasonRotate, "", rotated...); pubErr != nil {
+			l.log.Warn(ctx, "OAuthLogic|refresh - 广播令牌吊销失败",
+				slog.String("error", pubErr.Error()),
+			)
+		}
+	}
 	return tokenSource, nil
 }
 
+// recentRefresh 读取该刷新令牌的近期刷新结果，未命中或读取失败时返回 nil。
+func (l *OAuthLogic) recentRefresh(ctx context.Context, rt string) *oauth2.Token {
+	recent, xErr := l.refreshData.GetRecent(ctx, rt)
+	if xErr != nil {
+		l.log.Warn(ctx, "OAuthLogic|recentRefresh - 读取近期刷新结果失败",
+			slog.String("error", xErr.Error()),
+		)
+		return nil
+	}
+	if recent == nil {
+		return nil
+	}
+
+	expiry, err := time.Parse(time.RFC3339, recent.Expiry)
+	if err != nil {
+		return nil
+	}
+	token := &oauth2.Token{
+		AccessToken:  recent.AccessToken,
+		TokenType:    recent.TokenType,
+		RefreshToken: recent.RefreshToken,
+		Expiry:       expiry,
+	}
+	if recent.IDToken != "" {
+		token = token.WithExtra(map[string]interface{}{"id_token": recent.IDToken})
+	}
+	return token
+}
+
 // GetToken 根据 AccessToken 从缓存中获取令牌信息
 //
 // 该方法供中间件调用，用于通过 Bearer Token 直接从 Redis 缓存中验证和获取令牌信息，
@@ -285,6 +681,9 @@ func (l *OAuthLogic) VerifyExpiry(ctx context.Context, accessToken string) (bool
 	if xErr != nil {
 		return false, xErr
 	}
+	if _, xErr = l.forToken(ctx, getToken); xErr != nil {
+		return false, xErr
+	}
 	parseTime, timeErr := time.Parse(time.RFC3339, getToken.Expiry)
 	if timeErr != nil {
 		return false, xError.NewError(ctx, xError.OperationFailed, "解析令牌过期时间失败", false, timeErr)
@@ -292,6 +691,66 @@ func (l *OAuthLogic) VerifyExpiry(ctx context.Context, accessToken string) (bool
 	return parseTime.Before(time.Now()), nil
 }
 
+// SessionConfig 返回当前配置快照中的本地会话 Cookie 配置，随配置热更新生效。
+func (l *OAuthLogic) SessionConfig() bSdkConfig.SessionConfig {
+	return sdkConfig(l.cfg).Session
+}
+
+// EnsureFresh 确保访问令牌在提前量之外仍然有效，必要时使用缓存的刷新令牌自动刷新
+//
+// 该方法供 Cookie 会话模式下的中间件调用：访问令牌距过期不足 skew 时，使用服务端保存的
+// 刷新令牌完成刷新（同样经过家族重放检测与并发控制），客户端无需自行调用刷新接口。
+// 刷新失败但访问令牌尚未过期时仅记录告警并继续放行，由后续请求再次尝试；检测到重放时始终拒绝。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - accessToken: 当前会话的访问令牌。
+//   - skew: 提前刷新量。
+//
+// 返回值:
+//   - *oauth2.Token: 刷新得到的新令牌；无需刷新时为 nil。
+//   - *xError.Error: 令牌无效、已过期且无法刷新时返回错误。
+func (l *OAuthLogic) EnsureFresh(ctx context.Context, accessToken string, skew time.Duration) (*oauth2.Token, *xError.Error) {
+	l.log.Info(ctx, "EnsureFresh - 检查令牌是否需要刷新")
+
+	cacheToken, xErr := l.tokenData.Get(ctx, accessToken)
+	if xErr != nil {
+		return nil, xErr
+	}
+	if cacheToken.AccessToken == "" {
+		return nil, xError.NewError(ctx, xError.TokenInvalid, "访问令牌无效", false, nil)
+	}
+	if _, xErr = l.forToken(ctx, cacheToken); xErr != nil {
+		return nil, xErr
+	}
+	expiry, timeErr := time.Parse(time.RFC3339, cacheToken.Expiry)
+	if timeErr != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "解析令牌过期时间失败", false, timeErr)
+	}
+
+	if time.Until(expiry) > skew {
+		return nil, nil
+	}
+	if cacheToken.RefreshToken == "" {
+		if expiry.Before(time.Now()) {
+			return nil, xError.NewError(ctx, xError.TokenExpired, "访问令牌已过期", false, nil)
+		}
+		return nil, nil
+	}
+
+	newToken, xErr := l.TokenSource(ctx, cacheToken, cacheToken.RefreshToken)
+	if xErr != nil {
+		if expiry.After(time.Now()) && xErr.GetErrorCode().Code != bSdkConst.ErrRefreshTokenReused.Code {
+			l.log.Warn(ctx, "OAuthLogic|EnsureFresh - 自动刷新失败，访问令牌仍在有效期内",
+				slog.String("error", xErr.Error()),
+			)
+			return nil, nil
+		}
+		return nil, xErr
+	}
+	return newToken, nil
+}
+
 // Logout 调用 OAuth2 Revocation Endpoint 注销指定令牌。
 //
 // 该方法会把指定 token 发送到 revocation endpoint 完成远端注销，
@@ -314,19 +773,67 @@ func (l *OAuthLogic) Logout(ctx context.Context, tokenType string, token string)
 		return xError.NewError(ctx, xError.ParameterEmpty, "令牌为空", false, nil)
 	}
 
-	revocationURI := xEnv.GetEnvString(bSdkConst.EnvSsoEndpointRevocationURI, "")
+	// 令牌由命名身份提供方签发时使用该提供方的注销端点，提供方未配置注销端点时仅清理本地缓存
+	target := l
+	if provider, ok := l.providers[l.tokenProvider(ctx, tokenType, token)]; ok {
+		target = provider
+	}
+	if target == l || target.config(ctx).Endpoints.Revocation != "" {
+		if xErr := revokeAtEndpoint(ctx, target.config(ctx), target.httpClient, tokenType, token); xErr != nil {
+			return xErr
+		}
+	}
+
+	if l.store != nil {
+		if delErr := l.tokenData.Delete(ctx, token); delErr != nil {
+			l.log.Warn(ctx, "OAuthLogic|Logout - 清理令牌缓存失败",
+				slog.String("token_fingerprint", bSdkUtil.RedactToken(token)),
+				slog.String("error", delErr.Error()),
+			)
+		}
+		if pubErr := l.revocation.Publish(ctx, RevocationReasonLogout, tokenType, token); pubErr != nil {
+			l.log.Warn(ctx, "OAuthLogic|Logout - 广播令牌吊销失败",
+				slog.String("error", pubErr.Error()),
+			)
+		}
+	}
+
+	return nil
+}
+
+// tokenProvider 返回令牌所属的命名身份提供方：访问令牌按令牌缓存、刷新令牌按令牌家族查询，未知令牌或默认提供方签发时为空。
+func (l *OAuthLogic) tokenProvider(ctx context.Context, tokenType string, token string) string {
+	if len(l.providers) == 0 {
+		return ""
+	}
+
+	switch tokenType {
+	case "access_token":
+		if cacheToken, xErr := l.tokenData.Get(ctx, token); xErr == nil {
+			return cacheToken.Provider
+		}
+	case "refresh_token":
+		if family, xErr := l.family.Find(ctx, token); xErr == nil && family != nil {
+			return family.Provider
+		}
+	}
+	return ""
+}
+
+// revokeAtEndpoint 调用 OAuth2 Revocation Endpoint（RFC 7009）注销令牌。
+func revokeAtEndpoint(ctx context.Context, cfg *bSdkConfig.Config, client *http.Client, tokenType string, token string) *xError.Error {
+	revocationURI := cfg.Endpoints.Revocation
 	if revocationURI == "" {
 		return xError.NewError(ctx, xError.OperationFailed, "注销端点为空", false, nil)
 	}
 
-	clientID := xEnv.GetEnvString(bSdkConst.EnvSsoClientID, "")
-	clientSecret := xEnv.GetEnvString(bSdkConst.EnvSsoClientSecret, "")
+	clientID := cfg.Client.ID
+	clientSecret := cfg.Client.Secret
 	if clientID == "" || clientSecret == "" {
 		return xError.NewError(ctx, xError.OperationFailed, "客户端配置缺失", false, nil)
 	}
 
-	client := resty.New()
-	resp, reqErr := client.R().
+	resp, reqErr := cfg.NewRestyClientWith(client).R().
 		SetContext(ctx).
 		SetHeader("Accept", "application/json").
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
@@ -349,15 +856,33 @@ func (l *OAuthLogic) Logout(ctx context.Context, tokenType string, token string)
 			nil,
 		)
 	}
+	return nil
+}
 
-	if l.rdb != nil {
-		if delErr := l.tokenData.Delete(ctx, token); delErr != nil {
-			l.log.Warn(ctx, "OAuthLogic|Logout - 清理令牌缓存失败",
-				slog.String("token", token),
-				slog.String("error", delErr.Error()),
-			)
-		}
+// tokenExtraString 读取令牌端点响应中的扩展字符串字段（如 `id_token`）。
+func tokenExtraString(token *oauth2.Token, key string) string {
+	if value, ok := token.Extra(key).(string); ok {
+		return value
 	}
+	return ""
+}
 
-	return nil
+// bindTokenIdentity 从 ID Token 中提取 `sub`/`sid` 写入缓存令牌，用于建立登出所需的会话索引。
+//
+// ID Token 直接来自令牌端点，此处仅解码不校验签名；解码失败时保留令牌原有的身份信息。
+func bindTokenIdentity(cacheToken *bSdkModels.CacheOAuthToken, idToken string) {
+	if idToken == "" {
+		return
+	}
+	claims, err := bSdkUtil.DecodeJWTClaims(idToken)
+	if err != nil {
+		return
+	}
+	cacheToken.IDToken = idToken
+	if sub, ok := claims["sub"].(string); ok && sub != "" {
+		cacheToken.Subject = sub
+	}
+	if sid, ok := claims["sid"].(string); ok && sid != "" {
+		cacheToken.SessionID = sid
+	}
 }
@@ -1,13 +1,25 @@
 package bSdkLogic
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	"golang.org/x/oauth2"
 )
 
 func TestOAuthLogicLogout(t *testing.T) {
@@ -65,3 +77,621 @@ func newOAuthTestGinContext() *gin.Context {
 	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
 	return ctx
 }
+
+func TestOAuthLogicProviders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginCtx := newOAuthTestGinContext()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Issuer: "https://sso.example.com"}),
+		bSdkConfig.WithProvider("partner",
+			bSdkConfig.WithClient("pid", "psecret"),
+			bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{
+				Auth:   "https://partner.example.com/authorize",
+				Token:  "https://partner.example.com/token",
+				Issuer: "https://partner.example.com",
+			}),
+		),
+	)
+	logic := NewOAuth(context.WithValue(context.Background(), bSdkConst.CtxSdkConfig, cfg))
+
+	t.Run("按名称获取提供方", func(t *testing.T) {
+		partner, xErr := logic.Provider(ginCtx, "partner")
+		if xErr != nil || partner.provider != "partner" {
+			t.Fatalf("期望获取 partner 提供方，实际错误: %v", xErr)
+		}
+		authURL, xErr := partner.BuildURL(ginCtx, &bSdkModels.CacheOAuth{State: "state", Verifier: oauth2.GenerateVerifier()})
+		if xErr != nil || !strings.HasPrefix(authURL, "https://partner.example.com/authorize?") {
+			t.Fatalf("授权地址应使用提供方配置: %s", authURL)
+		}
+		if _, xErr = logic.Provider(ginCtx, "unknown"); xErr == nil || xErr.GetErrorCode().Code != xError.NotExist.Code {
+			t.Fatalf("期望提供方不存在错误")
+		}
+	})
+
+	t.Run("按令牌所属提供方校验签发者", func(t *testing.T) {
+		target, xErr := logic.forToken(ginCtx, &bSdkModels.CacheOAuthToken{
+			Provider: "partner",
+			IDToken:  testIDToken(t, "https://partner.example.com/", "pid", "other"),
+		})
+		if xErr != nil || target.provider != "partner" {
+			t.Fatalf("期望路由到 partner 提供方（签发者忽略末尾的 /），实际错误: %v", xErr)
+		}
+
+		_, xErr = logic.forToken(ginCtx, &bSdkModels.CacheOAuthToken{
+			Provider: "partner",
+			IDToken:  testIDToken(t, "https://sso.example.com", "pid"),
+		})
+		if xErr == nil || xErr.GetErrorCode().Code != xError.TokenInvalid.Code {
+			t.Fatalf("签发者不匹配时期望令牌无效错误")
+		}
+
+		_, xErr = logic.forToken(ginCtx, &bSdkModels.CacheOAuthToken{
+			Provider: "partner",
+			IDToken:  testIDToken(t, "https://partner.example.com", "other"),
+		})
+		if xErr == nil || xErr.GetErrorCode().Code != xError.TokenInvalid.Code {
+			t.Fatalf("受众不包含客户端 ID 时期望令牌无效错误")
+		}
+
+		_, xErr = logic.forToken(ginCtx, &bSdkModels.CacheOAuthToken{Provider: "removed"})
+		if xErr == nil || xErr.GetErrorCode().Code != xError.TokenInvalid.Code {
+			t.Fatalf("提供方未注册时期望令牌无效错误")
+		}
+	})
+}
+
+// testIDToken 构造仅用于解码的未签名 ID Token。
+func testIDToken(t *testing.T, issuer string, audience ...string) string {
+	t.Helper()
+	claims, err := json.Marshal(map[string]any{"iss": issuer, "aud": audience, "sub": "user-1"})
+	if err != nil {
+		t.Fatalf("编码声明失败: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(claims) + "."
+}
+
+// countingTransport 统计经过的请求数。
+type countingTransport struct {
+	count atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewOAuthLogic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginCtx := newOAuthTestGinContext()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{
+			Auth:       "https://sso.example.com/authorize",
+			Revocation: srv.URL,
+		}),
+	)
+	transport := &countingTransport{}
+	store := bSdkStore.NewMemoryStore(time.Minute)
+	logic, err := NewOAuthLogic(cfg, store, &http.Client{Transport: transport})
+	if err != nil {
+		t.Fatalf("创建 OAuthLogic 失败: %v", err)
+	}
+
+	oAuth, xErr := logic.Create(ginCtx)
+	if xErr != nil {
+		t.Fatalf("创建 State 失败: %v", xErr)
+	}
+	verified, xErr := logic.Verify(ginCtx, oAuth.State)
+	if xErr != nil || verified.Verifier != oAuth.Verifier {
+		t.Fatalf("State 应保存在传入的状态存储中: %v", xErr)
+	}
+
+	if xErr = logic.Logout(ginCtx, "access_token", "token-value"); xErr != nil {
+		t.Fatalf("期望注销成功，实际错误: %v", xErr)
+	}
+	if transport.count.Load() != 1 {
+		t.Fatalf("注销请求应使用传入的 HTTP 客户端，实际请求数 %d", transport.count.Load())
+	}
+
+	conflict := bSdkConfig.New(bSdkConfig.WithClient("cid", "csecret"))
+	conflict.Token.HashKey = "another-hash-key"
+	if _, err = NewOAuthWith(Deps{Config: conflict}); err == nil {
+		t.Fatal("令牌密钥配置与进程内已配置的密钥冲突时应返回错误")
+	}
+}
+
+// newTestOAuth 使用显式依赖创建 OAuthLogic，失败时终止测试。
+func newTestOAuth(t *testing.T, deps Deps) *OAuthLogic {
+	t.Helper()
+	logic, err := NewOAuthWith(deps)
+	if err != nil {
+		t.Fatalf("创建 OAuthLogic 失败: %v", err)
+	}
+	return logic
+}
+
+func TestOAuthLogicRefreshCoalesce(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"coalesce-at-2","token_type":"Bearer","refresh_token":"coalesce-rt-2","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Token: srv.URL}),
+	)
+	oauthLogic := newTestOAuth(t, Deps{Config: cfg})
+	authLogic := NewAuthWith(Deps{Config: cfg})
+
+	// 近期刷新结果保存在进程级存储中，每次运行使用独立的刷新令牌
+	rt := "coalesce-rt-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	cacheToken := &bSdkModels.CacheOAuthToken{AccessToken: "coalesce-at-1", RefreshToken: rt}
+	if xErr := oauthLogic.family.Start(newOAuthTestGinContext(), cacheToken); xErr != nil {
+		t.Fatalf("创建令牌家族失败: %v", xErr)
+	}
+
+	// 首个调用方在刷新进行中断开，不应影响其他等待方
+	canceledCtx, cancel := context.WithCancel(newOAuthTestGinContext())
+	canceled := make(chan *xError.Error, 1)
+	go func() {
+		_, xErr := oauthLogic.TokenSource(canceledCtx, cacheToken, rt)
+		canceled <- xErr
+	}()
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("令牌端点未收到刷新请求")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	results := make(chan string, 2)
+	go func() {
+		token, xErr := oauthLogic.TokenSource(newOAuthTestGinContext(), cacheToken, rt)
+		if xErr != nil {
+			results <- "TokenSource: " + xErr.Error()
+			return
+		}
+		results <- token.AccessToken
+	}()
+	go func() {
+		resp, err := authLogic.RefreshToken(newOAuthTestGinContext(), rt)
+		if err != nil {
+			results <- "RefreshToken: " + err.Error()
+			return
+		}
+		results <- resp.AccessToken
+	}()
+
+	cancel()
+	if xErr := <-canceled; xErr == nil || xErr.GetErrorCode().Code != xError.Timeout.Code {
+		t.Fatalf("取消的调用方期望超时错误，实际 %v", xErr)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	for range 2 {
+		if got := <-results; got != "coalesce-at-2" {
+			t.Fatalf("期望取得刷新后的访问令牌，实际 %s", got)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("并发刷新应合并为 1 次令牌端点请求，实际 %d 次", got)
+	}
+
+	t.Run("刷新完成后重放旧刷新令牌", func(t *testing.T) {
+		// 仍在近期刷新结果的有效期内，但重放不应取回新令牌
+		_, xErr := oauthLogic.RefreshByToken(newOAuthTestGinContext(), rt)
+		if xErr == nil || xErr.GetErrorCode().Code != bSdkConst.ErrRefreshTokenReused.Code {
+			t.Fatalf("期望判定为重放，实际 %v", xErr)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Fatalf("重放的刷新令牌不应请求令牌端点，实际 %d 次", got)
+		}
+	})
+}
+
+func TestOAuthLogicRefreshMemoryStore(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := strconv.Itoa(int(calls.Add(1)))
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"memory-at-` + n + `","token_type":"Bearer","refresh_token":"memory-rt-` + n + `","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Token: srv.URL}),
+	)
+	// 两个实例共享同一内存存储，模拟同一状态存储下的多个副本
+	store := bSdkStore.NewMemoryStore(time.Minute)
+	replicas := []*OAuthLogic{
+		newTestOAuth(t, Deps{Config: cfg, Store: store}),
+		newTestOAuth(t, Deps{Config: cfg, Store: store}),
+	}
+
+	login := &bSdkModels.CacheOAuthToken{AccessToken: "memory-at-0", RefreshToken: "memory-rt-0", TokenType: "Bearer"}
+	if xErr := replicas[0].family.Start(ctx, login); xErr != nil {
+		t.Fatalf("创建令牌家族失败: %v", xErr)
+	}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	results := make(chan string, workers)
+	for i := range workers {
+		wg.Add(1)
+		go func(logic *OAuthLogic) {
+			defer wg.Done()
+			token, xErr := logic.TokenSource(newOAuthTestGinContext(), login, "memory-rt-0")
+			if xErr != nil {
+				results <- xErr.Error()
+				return
+			}
+			results <- token.AccessToken
+		}(replicas[i%len(replicas)])
+	}
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("令牌端点未收到刷新请求")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for got := range results {
+		if got != "memory-at-1" {
+			t.Fatalf("期望取得刷新后的访问令牌，实际 %s", got)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("并发刷新应只请求 1 次令牌端点，实际 %d 次", got)
+	}
+
+	t.Run("持有刷新锁时等待", func(t *testing.T) {
+		rotated := &bSdkModels.CacheOAuthToken{AccessToken: "memory-at-1", RefreshToken: "memory-rt-1", TokenType: "Bearer", FamilyID: login.FamilyID}
+		if locked, xErr := replicas[1].refreshData.Lock(ctx, login.FamilyID, "other-replica", time.Minute); xErr != nil || !locked {
+			t.Fatalf("获取刷新锁失败: %v, %v", locked, xErr)
+		}
+
+		done := make(chan *xError.Error, 1)
+		go func() {
+			_, xErr := replicas[0].TokenSource(newOAuthTestGinContext(), rotated, "memory-rt-1")
+			done <- xErr
+		}()
+		time.Sleep(3 * refreshPollInterval)
+		if got := calls.Load(); got != 1 {
+			t.Fatalf("其他副本持有刷新锁时不应请求令牌端点，实际 %d 次", got)
+		}
+
+		if xErr := replicas[1].refreshData.Unlock(ctx, login.FamilyID, "other-replica"); xErr != nil {
+			t.Fatalf("释放刷新锁失败: %v", xErr)
+		}
+		if xErr := <-done; xErr != nil {
+			t.Fatalf("释放刷新锁后期望刷新成功，实际 %v", xErr)
+		}
+		if got := calls.Load(); got != 2 {
+			t.Fatalf("释放刷新锁后应请求 1 次令牌端点，实际共 %d 次", got)
+		}
+	})
+
+	t.Run("重放旧刷新令牌", func(t *testing.T) {
+		_, xErr := replicas[1].TokenSource(ctx, login, "memory-rt-0")
+		if xErr == nil || xErr.GetErrorCode().Code != bSdkConst.ErrRefreshTokenReused.Code {
+			t.Fatalf("期望判定为重放，实际 %v", xErr)
+		}
+		if got := calls.Load(); got != 2 {
+			t.Fatalf("重放的刷新令牌不应请求令牌端点，实际 %d 次", got)
+		}
+	})
+}
+
+func TestOAuthLogicRefreshByTokenProvider(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	// newTokenServer 返回固定令牌的令牌端点，并统计请求次数
+	newTokenServer := func(calls *atomic.Int32, accessToken string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"` + accessToken + `","token_type":"Bearer","refresh_token":"` + accessToken + `-rt","expires_in":3600}`))
+		}))
+	}
+	var defaultCalls, partnerCalls atomic.Int32
+	defaultSrv := newTokenServer(&defaultCalls, "default-at")
+	defer defaultSrv.Close()
+	partnerSrv := newTokenServer(&partnerCalls, "provider-refresh-at-2")
+	defer partnerSrv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Token: defaultSrv.URL}),
+		bSdkConfig.WithProvider("partner",
+			bSdkConfig.WithClient("pid", "psecret"),
+			bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Token: partnerSrv.URL}),
+		),
+	)
+	logic := newTestOAuth(t, Deps{Config: cfg})
+
+	rt := "provider-refresh-rt-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	cacheToken := &bSdkModels.CacheOAuthToken{AccessToken: "provider-refresh-at-1", RefreshToken: rt, Provider: "partner"}
+	if xErr := logic.family.Start(ctx, cacheToken); xErr != nil {
+		t.Fatalf("创建令牌家族失败: %v", xErr)
+	}
+
+	token, xErr := logic.RefreshByToken(ctx, rt)
+	if xErr != nil || token.AccessToken != "provider-refresh-at-2" {
+		t.Fatalf("期望由 partner 提供方刷新，实际 %v, %v", token, xErr)
+	}
+	if partnerCalls.Load() != 1 || defaultCalls.Load() != 0 {
+		t.Fatalf("刷新应发往家族所属提供方的令牌端点，partner %d 次，默认 %d 次", partnerCalls.Load(), defaultCalls.Load())
+	}
+	cached, xErr := logic.GetToken(ctx, "provider-refresh-at-2")
+	if xErr != nil || cached.Provider != "partner" {
+		t.Fatalf("刷新后的令牌应记录所属提供方，实际 %+v, %v", cached, xErr)
+	}
+}
+
+func TestOAuthLogicEnsureFresh(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("解析表单失败: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("refresh_token") != "fresh-rt-ok" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"fresh-at-new","token_type":"Bearer","refresh_token":"fresh-rt-new","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Token: srv.URL}),
+	)
+	logic := newTestOAuth(t, Deps{Config: cfg, Store: bSdkStore.NewMemoryStore(time.Minute)})
+	skew := time.Minute
+
+	// store 写入一条指定剩余有效期的缓存令牌
+	store := func(at string, rt string, remaining time.Duration) {
+		t.Helper()
+		token := &bSdkModels.CacheOAuthToken{
+			AccessToken:  at,
+			RefreshToken: rt,
+			TokenType:    "Bearer",
+			Expiry:       time.Now().Add(remaining).Format(time.RFC3339),
+		}
+		if xErr := logic.tokenData.Store(ctx, token); xErr != nil {
+			t.Fatalf("缓存令牌失败: %v", xErr)
+		}
+	}
+
+	t.Run("未知令牌", func(t *testing.T) {
+		_, xErr := logic.EnsureFresh(ctx, "fresh-at-unknown", skew)
+		if xErr == nil || xErr.GetErrorCode().Code != xError.TokenInvalid.Code {
+			t.Fatalf("期望令牌无效错误，实际 %v", xErr)
+		}
+	})
+
+	t.Run("距过期超过提前量时不刷新", func(t *testing.T) {
+		store("fresh-at-valid", "fresh-rt-ok", time.Hour)
+		token, xErr := logic.EnsureFresh(ctx, "fresh-at-valid", skew)
+		if xErr != nil || token != nil {
+			t.Fatalf("期望无需刷新，实际 %+v, %v", token, xErr)
+		}
+		if got := calls.Load(); got != 0 {
+			t.Fatalf("无需刷新时不应请求令牌端点，实际 %d 次", got)
+		}
+	})
+
+	t.Run("临近过期时自动刷新", func(t *testing.T) {
+		store("fresh-at-near", "fresh-rt-ok", 30*time.Second)
+		token, xErr := logic.EnsureFresh(ctx, "fresh-at-near", skew)
+		if xErr != nil || token == nil || token.AccessToken != "fresh-at-new" {
+			t.Fatalf("期望刷新得到新令牌，实际 %+v, %v", token, xErr)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Fatalf("期望请求令牌端点 1 次，实际 %d 次", got)
+		}
+		cached, xErr := logic.GetToken(ctx, "fresh-at-new")
+		if xErr != nil || cached.RefreshToken != "fresh-rt-new" {
+			t.Fatalf("刷新后的令牌应写入缓存，实际 %+v, %v", cached, xErr)
+		}
+
+		// 刷新完成后携带旧访问令牌的请求继续放行，不再以已轮换的刷新令牌刷新
+		token, xErr = logic.EnsureFresh(ctx, "fresh-at-near", skew)
+		if xErr != nil || token != nil {
+			t.Fatalf("期望以旧访问令牌放行，实际 %+v, %v", token, xErr)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Fatalf("旧访问令牌不应再次触发刷新，实际 %d 次", got)
+		}
+	})
+
+	t.Run("刷新失败但仍在有效期内时放行", func(t *testing.T) {
+		store("fresh-at-grace", "fresh-rt-bad", 30*time.Second)
+		token, xErr := logic.EnsureFresh(ctx, "fresh-at-grace", skew)
+		if xErr != nil || token != nil {
+			t.Fatalf("期望保留原令牌放行，实际 %+v, %v", token, xErr)
+		}
+	})
+
+	t.Run("已过期且刷新失败", func(t *testing.T) {
+		store("fresh-at-dead", "fresh-rt-bad", -time.Minute)
+		if _, xErr := logic.EnsureFresh(ctx, "fresh-at-dead", skew); xErr == nil {
+			t.Fatalf("期望返回刷新失败的错误")
+		}
+	})
+
+	t.Run("无刷新令牌", func(t *testing.T) {
+		store("fresh-at-nort", "", 30*time.Second)
+		if token, xErr := logic.EnsureFresh(ctx, "fresh-at-nort", skew); xErr != nil || token != nil {
+			t.Fatalf("未过期时期望放行，实际 %+v, %v", token, xErr)
+		}
+
+		store("fresh-at-nort-expired", "", -time.Minute)
+		_, xErr := logic.EnsureFresh(ctx, "fresh-at-nort-expired", skew)
+		if xErr == nil || xErr.GetErrorCode().Code != xError.TokenExpired.Code {
+			t.Fatalf("期望令牌过期错误，实际 %v", xErr)
+		}
+	})
+}
+
+func TestOAuthLogicLogoutProvider(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	// newRevocationServer 返回记录客户端 ID 的注销端点
+	newRevocationServer := func(clients chan<- string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _, _ := r.BasicAuth()
+			clients <- user
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+	defaultClients := make(chan string, 4)
+	partnerClients := make(chan string, 4)
+	defaultSrv := newRevocationServer(defaultClients)
+	defer defaultSrv.Close()
+	partnerSrv := newRevocationServer(partnerClients)
+	defer partnerSrv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Revocation: defaultSrv.URL}),
+		bSdkConfig.WithProvider("partner",
+			bSdkConfig.WithClient("pid", "psecret"),
+			bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Revocation: partnerSrv.URL}),
+		),
+	)
+	logic := newTestOAuth(t, Deps{Config: cfg, Store: bSdkStore.NewMemoryStore(time.Minute)})
+
+	partnerToken := &bSdkModels.CacheOAuthToken{
+		AccessToken:  "logout-provider-at",
+		RefreshToken: "logout-provider-rt",
+		TokenType:    "Bearer",
+		Expiry:       time.Now().Add(time.Hour).Format(time.RFC3339),
+		Provider:     "partner",
+	}
+	if xErr := logic.family.Start(ctx, partnerToken); xErr != nil {
+		t.Fatalf("创建令牌家族失败: %v", xErr)
+	}
+	if xErr := logic.tokenData.Store(ctx, partnerToken); xErr != nil {
+		t.Fatalf("缓存令牌失败: %v", xErr)
+	}
+
+	tests := []struct {
+		name      string
+		tokenType string
+		token     string
+		clients   chan string
+		client    string
+	}{
+		{name: "partner 的刷新令牌", tokenType: "refresh_token", token: "logout-provider-rt", clients: partnerClients, client: "pid"},
+		{name: "partner 的访问令牌", tokenType: "access_token", token: "logout-provider-at", clients: partnerClients, client: "pid"},
+		{name: "未知刷新令牌", tokenType: "refresh_token", token: "logout-unknown-rt", clients: defaultClients, client: "cid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if xErr := logic.Logout(ctx, tt.tokenType, tt.token); xErr != nil {
+				t.Fatalf("期望注销成功，实际错误: %v", xErr)
+			}
+			select {
+			case client := <-tt.clients:
+				if client != tt.client {
+					t.Fatalf("期望以客户端 %s 注销，实际 %s", tt.client, client)
+				}
+			default:
+				t.Fatalf("期望请求令牌所属提供方的注销端点")
+			}
+			if len(defaultClients)+len(partnerClients) != 0 {
+				t.Fatalf("不应请求其他提供方的注销端点")
+			}
+		})
+	}
+}
+
+func TestOAuthLogicExchangeIssuerMismatch(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	idToken := testIDToken(t, "https://evil.example.com", "cid")
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"mismatch-at","token_type":"Bearer","refresh_token":"mismatch-rt","expires_in":3600,"id_token":"` + idToken + `"}`))
+	}))
+	defer tokenSrv.Close()
+	revoked := make(chan string, 4)
+	revocationSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("解析表单失败: %v", err)
+		}
+		revoked <- r.Form.Get("token")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer revocationSrv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{
+			Token:      tokenSrv.URL,
+			Revocation: revocationSrv.URL,
+			Issuer:     "https://sso.example.com",
+		}),
+	)
+	logic := newTestOAuth(t, Deps{Config: cfg, Store: bSdkStore.NewMemoryStore(time.Minute)})
+
+	_, xErr := logic.Exchange(ctx, "code", oauth2.GenerateVerifier())
+	if xErr == nil || xErr.GetErrorCode().Code != xError.TokenInvalid.Code {
+		t.Fatalf("期望签发者不匹配错误，实际 %v", xErr)
+	}
+	close(revoked)
+	var tokens []string
+	for token := range revoked {
+		tokens = append(tokens, token)
+	}
+	if strings.Join(tokens, ",") != "mismatch-rt,mismatch-at" {
+		t.Fatalf("期望注销换取到的刷新令牌与访问令牌，实际 %v", tokens)
+	}
+	if cached, _ := logic.GetToken(ctx, "mismatch-at"); cached != nil && cached.AccessToken != "" {
+		t.Fatalf("签发者不匹配的令牌不应写入缓存")
+	}
+}
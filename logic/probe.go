@@ -0,0 +1,237 @@
+package bSdkLogic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkClient "github.com/phalanx-labs/beacon-sso-sdk/client"
+	"github.com/phalanx-labs/beacon-sso-sdk/client/service"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// ProbeStatus 依赖自检状态
+type ProbeStatus string
+
+const (
+	ProbeOK      ProbeStatus = "ok"      // 检查通过
+	ProbeFailed  ProbeStatus = "failed"  // 检查失败
+	ProbeSkipped ProbeStatus = "skipped" // 依赖未配置，跳过检查
+)
+
+// 依赖自检项名称。
+const (
+	ProbeTokenEndpoint = "token_endpoint" // 令牌端点接受客户端凭证
+	ProbeGrpc          = "grpc"           // gRPC 服务接受 App 认证凭证
+	ProbeRedis         = "redis"          // Redis 可连通
+	ProbeJWKS          = "jwks"           // 签名公钥集可加载
+	ProbeDiscovery     = "discovery"      // 元数据按期刷新，仅用于健康检查
+)
+
+// errProbeSkipped 依赖未配置时由检查函数返回，结果记为 `ProbeSkipped`。
+var errProbeSkipped = errors.New("未配置")
+
+// ProbeCheck 单项依赖自检结果
+type ProbeCheck struct {
+	Name      string      `json:"name"`             // 检查项名称
+	Status    ProbeStatus `json:"status"`           // 检查状态
+	LatencyMS int64       `json:"latency_ms"`       // 检查耗时（毫秒）
+	AgeMS     int64       `json:"age_ms,omitempty"` // 数据距上次成功拉取的时长（毫秒），仅用于元数据与 JWKS
+	Error     string      `json:"error,omitempty"`  // 失败原因或跳过说明
+}
+
+// ProbeReport 依赖自检报告
+type ProbeReport struct {
+	OK     bool         `json:"ok"`     // 是否全部通过（跳过的检查不视为失败）
+	Checks []ProbeCheck `json:"checks"` // 各检查项结果，按固定顺序排列
+}
+
+// Err 汇总失败的检查项，全部通过时返回 nil
+func (r *ProbeReport) Err() error {
+	var errs []error
+	for _, check := range r.Checks {
+		if check.Status == ProbeFailed {
+			errs = append(errs, fmt.Errorf("%s: %s", check.Name, check.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ProbeLogic 依赖自检逻辑组件，用于在启动时验证 SDK 依赖的外部服务与凭证。
+type ProbeLogic struct {
+	log        *xLog.LogNamedLogger
+	cfg        *bSdkConfig.Config    // SDK 配置，未注册时按需读取环境变量
+	ssoClient  *bSdkClient.SsoClient // SsoClient，未注册时跳过 gRPC 检查
+	rdb        *redis.Client         // Redis 客户端，未注入时跳过 Redis 检查
+	jwks       *JwksLogic            // JWKS 公钥逻辑
+	httpClient *http.Client          // 请求 SSO 使用的 HTTP 客户端，为 nil 时按配置创建
+}
+
+// NewProbe 创建并初始化一个 ProbeLogic 实例。
+//
+// 参数:
+//   - ctx: 上下文，用于获取 SDK 配置、SsoClient 与 Redis 客户端；未注册的依赖在自检时跳过。
+//
+// 返回值:
+//   - *ProbeLogic: 依赖自检逻辑实例指针。
+func NewProbe(ctx context.Context) *ProbeLogic {
+	return NewProbeWith(DepsFromContext(ctx))
+}
+
+// NewProbeWith 使用显式依赖创建 ProbeLogic，未提供的依赖在自检时跳过。
+func NewProbeWith(deps Deps) *ProbeLogic {
+	return &ProbeLogic{
+		log:        xLog.WithName(xLog.NamedLOGC, "ProbeLogic"),
+		cfg:        deps.Config,
+		ssoClient:  deps.SsoClient,
+		rdb:        deps.Redis,
+		jwks:       NewJwksWith(deps),
+		httpClient: deps.HTTPClient,
+	}
+}
+
+// Run 并发执行全部依赖自检并返回报告
+//
+// 参数说明:
+//   - ctx: 上下文。
+//   - timeout: 单项检查的超时时间，不大于 0 时不限制。
+//
+// 返回值:
+//   - *ProbeReport: 自检报告，检查项顺序固定为令牌端点、gRPC、Redis、JWKS。
+func (l *ProbeLogic) Run(ctx context.Context, timeout time.Duration) *ProbeReport {
+	probes := []struct {
+		name  string
+		check func(ctx context.Context) error
+	}{
+		{ProbeTokenEndpoint, l.CheckTokenEndpoint},
+		{ProbeGrpc, l.CheckGrpc},
+		{ProbeRedis, l.CheckRedis},
+		{ProbeJWKS, l.CheckJWKS},
+	}
+
+	report := &ProbeReport{OK: true, Checks: make([]ProbeCheck, len(probes))}
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = runProbe(ctx, probe.name, timeout, probe.check)
+		}()
+	}
+	wg.Wait()
+
+	for _, check := range report.Checks {
+		if check.Status == ProbeFailed {
+			report.OK = false
+		}
+	}
+	return report
+}
+
+// runProbe 在超时限制内执行单项检查并记录耗时。
+func runProbe(ctx context.Context, name string, timeout time.Duration, check func(ctx context.Context) error) ProbeCheck {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check(ctx)
+	result := ProbeCheck{Name: name, Status: ProbeOK, LatencyMS: time.Since(start).Milliseconds()}
+	switch {
+	case errors.Is(err, errProbeSkipped):
+		result.Status, result.Error = ProbeSkipped, err.Error()
+	case err != nil:
+		result.Status, result.Error = ProbeFailed, err.Error()
+	}
+	return result
+}
+
+// CheckTokenEndpoint 检查令牌端点是否接受客户端凭证
+//
+// 使用客户端凭证以一个不存在的刷新令牌请求令牌端点：按 RFC 6749 §5.2，服务端先认证客户端，
+// 返回 `invalid_client` 或 401 表示凭证被拒绝，返回 `invalid_grant` 等其他错误则说明凭证有效。
+func (l *ProbeLogic) CheckTokenEndpoint(ctx context.Context) error {
+	cfg := sdkConfig(l.cfg)
+	if cfg == nil || cfg.Endpoints.Token == "" {
+		return fmt.Errorf("令牌端点%w", errProbeSkipped)
+	}
+
+	var body struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	resp, err := cfg.NewRestyClientWith(l.httpClient).R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetHeader("Content-Type", "application/x-www-form-urlencoded").
+		SetBasicAuth(cfg.Client.ID, cfg.Client.Secret).
+		SetFormData(map[string]string{
+			"grant_type":    "refresh_token",
+			"refresh_token": "beacon-sso-sdk-startup-probe",
+		}).
+		SetResult(&body).
+		SetError(&body).
+		Post(cfg.Endpoints.Token)
+	if err != nil {
+		return fmt.Errorf("请求令牌端点失败: %v", err)
+	}
+
+	switch {
+	case resp.StatusCode() == http.StatusUnauthorized || body.Error == "invalid_client":
+		return fmt.Errorf("令牌端点拒绝了客户端凭证: %s", strings.TrimSpace(body.Error+" "+body.ErrorDescription))
+	case resp.StatusCode() >= http.StatusInternalServerError:
+		return fmt.Errorf("令牌端点不可用，状态码: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// CheckGrpc 检查 gRPC 服务是否接受 App 认证凭证
+//
+// 调用只读的 `GetMerchantTags`，`Unauthenticated` 或 `PermissionDenied` 表示 `app-access-id`/`app-secret-key` 无效。
+func (l *ProbeLogic) CheckGrpc(ctx context.Context) error {
+	if l.ssoClient == nil {
+		return fmt.Errorf("SsoClient %w", errProbeSkipped)
+	}
+
+	_, err := l.ssoClient.Merchant.GetMerchantTags(grpcContext(ctx, l.cfg), &service.GetMerchantTagsRequest{EnabledOnly: true})
+	switch connect.CodeOf(err) {
+	case connect.CodeUnauthenticated, connect.CodePermissionDenied:
+		return fmt.Errorf("gRPC 服务拒绝了 App 认证凭证: %v", err)
+	}
+	if err != nil {
+		return fmt.Errorf("gRPC 服务不可用: %v", err)
+	}
+	return nil
+}
+
+// CheckRedis 检查 Redis 是否可连通
+func (l *ProbeLogic) CheckRedis(ctx context.Context) error {
+	if l.rdb == nil {
+		return fmt.Errorf("Redis 客户端%w", errProbeSkipped)
+	}
+	if err := l.rdb.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("Redis 不可用: %v", err)
+	}
+	return nil
+}
+
+// CheckJWKS 检查签名公钥集是否可加载，成功时同时刷新进程内公钥缓存
+func (l *ProbeLogic) CheckJWKS(ctx context.Context) error {
+	cfg := sdkConfig(l.cfg)
+	if cfg == nil || cfg.Endpoints.JWKS == "" {
+		return fmt.Errorf("公钥集端点%w", errProbeSkipped)
+	}
+	if xErr := l.jwks.Refresh(ctx); xErr != nil {
+		return fmt.Errorf("加载签名公钥集失败: %s", xErr.Error())
+	}
+	return nil
+}
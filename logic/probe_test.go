@@ -0,0 +1,61 @@
+package bSdkLogic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+)
+
+func TestProbeLogic(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if clientID, secret, _ := r.BasicAuth(); clientID != "cid" || secret != "csecret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		if r.PostFormValue("grant_type") != "refresh_token" {
+			t.Errorf("授权类型不正确: %s", r.PostFormValue("grant_type"))
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	defer srv.Close()
+
+	run := func(secret string) *ProbeReport {
+		cfg := bSdkConfig.New(
+			bSdkConfig.WithClient("cid", secret),
+			bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Token: srv.URL}),
+		)
+		ctx := context.WithValue(context.Background(), bSdkConst.CtxSdkConfig, cfg)
+		return NewProbe(ctx).Run(ctx, time.Second)
+	}
+
+	report := run("csecret")
+	if !report.OK || report.Err() != nil {
+		t.Fatalf("期望自检通过: %+v", report)
+	}
+	for _, check := range report.Checks {
+		want := ProbeSkipped
+		if check.Name == ProbeTokenEndpoint {
+			want = ProbeOK
+		}
+		if check.Status != want {
+			t.Fatalf("%s 状态不正确: %s %s", check.Name, check.Status, check.Error)
+		}
+	}
+
+	report = run("wrong")
+	if report.OK || report.Checks[0].Status != ProbeFailed {
+		t.Fatalf("期望令牌端点检查失败: %+v", report)
+	}
+	if err := report.Err(); err == nil || !strings.Contains(err.Error(), "拒绝了客户端凭证") {
+		t.Fatalf("错误信息不正确: %v", err)
+	}
+}
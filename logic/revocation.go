@@ -0,0 +1,202 @@
+package bSdkLogic
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkRepo "github.com/phalanx-labs/beacon-sso-sdk/repository"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	RevocationReasonLogout    = "logout"     // 用户主动登出
+	RevocationReasonRevoke    = "revoke"     // 令牌注销
+	RevocationReasonRotate    = "rotate"     // 刷新令牌轮换
+	RevocationReasonSsoLogout = "sso_logout" // SSO 发起的前/后端通道登出
+	RevocationReasonReuse     = "reuse"      // 刷新令牌重放，吊销整个令牌家族
+
+	revocationSubscribeTimeout = time.Second * 5 // 建立订阅时等待确认的超时时间
+)
+
+// revocationOrigin 当前进程的实例标识，用于在订阅时忽略自身发布的消息。
+var revocationOrigin = newRevocationOrigin()
+
+// RevocationLogic 令牌吊销广播逻辑组件
+//
+// 负责计算令牌指纹、在本地执行吊销处理函数并通过 Redis Pub/Sub 通知其他 SDK 实例，
+// 同时提供订阅能力，使每个实例在收到广播后立即清理进程内缓存。
+type RevocationLogic struct {
+	db   *gorm.DB                 // GORM 数据库实例
+	rdb  *redis.Client            // Redis 客户端实例
+	log  *xLog.LogNamedLogger     // 日志实例
+	data *bSdkRepo.RevocationRepo // 吊销广播数据仓储实例
+}
+
+// NewRevocation 创建并初始化一个新的 RevocationLogic 业务逻辑实例。
+//
+// 参数:
+//   - ctx: 请求上下文，用于获取数据库和 Redis 实例；未注入 Redis 时仅在本实例执行吊销处理函数。
+//
+// 返回值:
+//   - *RevocationLogic: 配置完成的吊销广播逻辑层实例指针。
+func NewRevocation(ctx context.Context) *RevocationLogic {
+	return NewRevocationWith(DepsFromContext(ctx))
+}
+
+// NewRevocationWith 使用显式依赖创建 RevocationLogic，`Deps.Redis` 为空时仅在本实例执行吊销处理函数。
+func NewRevocationWith(deps Deps) *RevocationLogic {
+	return &RevocationLogic{
+		db:   deps.DB,
+		rdb:  deps.Redis,
+		log:  xLog.WithName(xLog.NamedLOGC, "RevocationLogic"),
+		data: bSdkRepo.NewRevocationRepo(deps.DB, deps.Redis, deps.Store),
+	}
+}
+
+// Publish 广播令牌吊销
+//
+// 该方法计算令牌指纹，先在本实例执行已注册的吊销处理函数，再向集群广播。
+// 业务方自行注销令牌（例如管理后台强制下线）时，也可调用该方法通知所有副本。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - reason: 吊销原因，建议使用 `RevocationReason*` 常量。
+//   - tokenType: 令牌类型（如 "access_token"、"refresh_token"），可为空。
+//   - tokens: 被吊销的令牌明文，空值会被忽略。
+//
+// 返回值:
+//   - *xError.Error: 令牌为空或发布失败时返回错误。
+func (l *RevocationLogic) Publish(ctx context.Context, reason string, tokenType string, tokens ...string) *xError.Error {
+	fingerprints := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		fingerprint, err := bSdkUtil.TokenFingerprint(token)
+		if err != nil {
+			return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+		}
+		if fingerprint != "" {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+	}
+
+	return l.PublishFingerprints(ctx, &bSdkModels.RevocationMessage{
+		Reason:       reason,
+		TokenType:    tokenType,
+		Fingerprints: fingerprints,
+	})
+}
+
+// PublishFingerprints 按指纹广播令牌吊销
+//
+// 适用于业务方只持有令牌指纹（例如来自审计日志）的场景，指纹需由 `bSdkUtil.TokenFingerprint` 计算。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - message: 吊销消息，`Origin` 与 `IssuedAt` 会被自动填充。
+//
+// 返回值:
+//   - *xError.Error: 指纹为空或发布失败时返回错误。
+func (l *RevocationLogic) PublishFingerprints(ctx context.Context, message *bSdkModels.RevocationMessage) *xError.Error {
+	l.log.Info(ctx, "PublishFingerprints - 广播令牌吊销")
+
+	if message == nil || len(message.Fingerprints) == 0 {
+		return xError.NewError(ctx, xError.ParameterEmpty, "吊销指纹为空", false, nil)
+	}
+	message.Origin = revocationOrigin
+	message.IssuedAt = time.Now().Unix()
+
+	// 本实例不会处理自身发布的广播，因此先同步清理本地缓存
+	fireRevocationHandlers(ctx, message)
+
+	return l.data.Publish(ctx, message)
+}
+
+// RevocationSubscriber 令牌吊销广播订阅器，由 `RevocationLogic.Subscribe` 创建。
+type RevocationSubscriber struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Subscribe 启动后台协程订阅吊销广播
+//
+// 收到其他实例发布的消息后会执行已注册的吊销处理函数。订阅器生命周期与传入的上下文解耦，
+// 需调用 `Close` 停止；Redis 断线后由客户端自动重连，期间丢失的消息由本地缓存 TTL 兜底。
+//
+// 参数说明:
+//   - ctx: 初始化上下文。
+//
+// 返回值:
+//   - *RevocationSubscriber: 已启动的订阅器。
+func (l *RevocationLogic) Subscribe(ctx context.Context) *RevocationSubscriber {
+	l.log.Info(ctx, "Subscribe - 订阅令牌吊销广播")
+
+	if !l.data.Enabled() {
+		l.log.Info(ctx, "Subscribe - 未注入 Redis 客户端，跳过吊销广播订阅")
+		done := make(chan struct{})
+		close(done)
+		return &RevocationSubscriber{cancel: func() {}, done: done}
+	}
+
+	subCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	subscriber := &RevocationSubscriber{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	pubsub := l.data.Subscribe(subCtx)
+	if _, err := pubsub.ReceiveTimeout(subCtx, revocationSubscribeTimeout); err != nil {
+		l.log.Warn(ctx, "RevocationLogic|Subscribe - 订阅确认失败，将在后台重试", slog.String("error", err.Error()))
+	}
+
+	go func() {
+		defer close(subscriber.done)
+		defer func() { _ = pubsub.Close() }()
+
+		channel := pubsub.Channel()
+		for {
+			select {
+			case <-subCtx.Done():
+				return
+			case msg, ok := <-channel:
+				if !ok {
+					return
+				}
+				message, xErr := l.data.Decode(subCtx, msg.Payload)
+				if xErr != nil {
+					l.log.Warn(subCtx, "RevocationLogic|Subscribe - 解析吊销消息失败", slog.String("error", xErr.Error()))
+					continue
+				}
+				if message.Origin == revocationOrigin {
+					continue
+				}
+				// 其他实例的删除不会经过本实例的状态存储，需主动失效一级缓存
+				l.data.InvalidateLocal(message)
+				fireRevocationHandlers(subCtx, message)
+			}
+		}
+	}()
+
+	return subscriber
+}
+
+// Close 停止订阅并等待后台协程退出。
+func (s *RevocationSubscriber) Close() {
+	if s == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+func newRevocationOrigin() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
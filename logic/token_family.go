@@ -0,0 +1,333 @@
+package bSdkLogic
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	xUtil "github.com/bamboo-services/bamboo-base-go/common/utility"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkRepo "github.com/phalanx-labs/beacon-sso-sdk/repository"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"gorm.io/gorm"
+)
+
+const (
+	SecurityEventRefreshTokenReuse = "refresh_token_reuse" // 已轮换的刷新令牌被再次使用
+)
+
+// TokenFamilyLogic 刷新令牌家族逻辑组件
+//
+// 每次登录创建一个令牌家族，刷新轮换时沿用家族并记录当前有效的刷新令牌。
+// 已轮换的刷新令牌再次出现说明令牌可能已泄露：此时注销家族当前的刷新令牌、
+// 清理本地缓存并广播吊销，同时触发安全钩子，调用方会收到 `ErrRefreshTokenReused` 错误。
+type TokenFamilyLogic struct {
+	db                *gorm.DB                    // GORM 数据库实例
+	store             bSdkStore.Store             // 状态存储实例
+	log               *xLog.LogNamedLogger        // 日志实例
+	data              *bSdkRepo.TokenFamilyRepo   // 令牌家族数据仓储实例
+	tokenData         *bSdkRepo.OAuthTokenRepo    // OAuth Token 数据仓储实例
+	userinfoData      *bSdkRepo.UserinfoRepo      // 业务层 Userinfo 数据仓储实例
+	introspectionData *bSdkRepo.IntrospectionRepo // 业务层 Introspection 数据仓储实例
+	revocation        *RevocationLogic            // 令牌吊销广播逻辑
+	cfg               *bSdkConfig.Config          // SDK 配置，未注册时按需读取环境变量
+	httpClient        *http.Client                // 请求 SSO 使用的 HTTP 客户端，为 nil 时按配置创建
+}
+
+// NewTokenFamily 创建并初始化一个新的 TokenFamilyLogic 业务逻辑实例。
+//
+// 参数:
+//   - ctx: 请求上下文，用于获取数据库与状态存储实例。
+//
+// 返回值:
+//   - *TokenFamilyLogic: 配置完成的令牌家族逻辑层实例指针。
+func NewTokenFamily(ctx context.Context) *TokenFamilyLogic {
+	return NewTokenFamilyWith(DepsFromContext(ctx))
+}
+
+// NewTokenFamilyWith 使用显式依赖创建 TokenFamilyLogic。
+func NewTokenFamilyWith(deps Deps) *TokenFamilyLogic {
+	cfg := sdkConfig(deps.Config)
+	store := deps.store()
+
+	return &TokenFamilyLogic{
+		db:                deps.DB,
+		store:             store,
+		log:               xLog.WithName(xLog.NamedLOGC, "TokenFamilyLogic"),
+		data:              bSdkRepo.NewTokenFamilyRepoWith(deps.DB, store, cfg),
+		tokenData:         bSdkRepo.NewOAuthTokenRepoWith(deps.DB, store, cfg),
+		userinfoData:      bSdkRepo.NewUserinfoRepoWith(deps.DB, store, cfg),
+		introspectionData: bSdkRepo.NewIntrospectionRepoWith(deps.DB, store, cfg),
+		revocation:        NewRevocationWith(deps),
+		cfg:               deps.Config,
+		httpClient:        deps.HTTPClient,
+	}
+}
+
+// Start 为新登录的令牌创建家族，并将家族标识写入 `token.FamilyID`。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - token: 登录获得的令牌，需在写入令牌缓存之前调用。
+//
+// 返回值:
+//   - *xError.Error: 写入家族记录失败时返回错误。
+func (l *TokenFamilyLogic) Start(ctx context.Context, token *bSdkModels.CacheOAuthToken) *xError.Error {
+	l.log.Info(ctx, "Start - 创建令牌家族")
+
+	if token == nil {
+		return nil
+	}
+	return l.start(ctx, token, token.RefreshToken)
+}
+
+// start 以指定的刷新令牌作为家族当前刷新令牌创建家族。
+func (l *TokenFamilyLogic) start(ctx context.Context, token *bSdkModels.CacheOAuthToken, refreshToken string) *xError.Error {
+	if refreshToken == "" {
+		return nil
+	}
+
+	refreshFingerprint, err := bSdkUtil.TokenFingerprint(refreshToken)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	accessFingerprint, err := bSdkUtil.TokenFingerprint(token.AccessToken)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+
+	token.FamilyID = xUtil.Generate().RandomUpperString(32)
+	return l.data.Store(ctx, &bSdkModels.CacheTokenFamily{
+		FamilyID:           token.FamilyID,
+		RefreshToken:       refreshToken,
+		RefreshFingerprint: refreshFingerprint,
+		AccessFingerprint:  accessFingerprint,
+		Subject:            token.Subject,
+		SessionID:          token.SessionID,
+		Provider:           token.Provider,
+		CreatedAt:          time.Now().Unix(),
+	})
+}
+
+// Rotate 记录刷新轮换，新令牌沿用旧刷新令牌所属的家族。
+//
+// 旧刷新令牌不属于任何家族（升级前登录或家族已过期）时，为新令牌创建新家族。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - previousRefreshToken: 本次刷新使用的刷新令牌。
+//   - token: 刷新获得的新令牌，需在写入令牌缓存之前调用。
+//
+// 返回值:
+//   - *xError.Error: 读写家族记录失败时返回错误。
+func (l *TokenFamilyLogic) Rotate(ctx context.Context, previousRefreshToken string, token *bSdkModels.CacheOAuthToken) *xError.Error {
+	l.log.Info(ctx, "Rotate - 轮换令牌家族")
+
+	if token == nil {
+		return nil
+	}
+
+	familyID := token.FamilyID
+	if familyID == "" && previousRefreshToken != "" {
+		found, xErr := l.data.FindByRefreshToken(ctx, previousRefreshToken)
+		if xErr != nil {
+			return xErr
+		}
+		familyID = found
+	}
+	if familyID == "" {
+		return l.start(ctx, token, currentRefreshToken(token, previousRefreshToken))
+	}
+
+	family, xErr := l.data.Get(ctx, familyID)
+	if xErr != nil {
+		return xErr
+	}
+	if family.FamilyID == "" {
+		return l.start(ctx, token, currentRefreshToken(token, previousRefreshToken))
+	}
+
+	// 未返回新刷新令牌时授权服务器未执行轮换，家族当前刷新令牌保持不变
+	refreshFingerprint, err := bSdkUtil.TokenFingerprint(token.RefreshToken)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	accessFingerprint, err := bSdkUtil.TokenFingerprint(token.AccessToken)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	token.FamilyID = family.FamilyID
+	if token.RefreshToken != "" {
+		family.RefreshToken = token.RefreshToken
+		family.RefreshFingerprint = refreshFingerprint
+	}
+	family.AccessFingerprint = accessFingerprint
+	return l.data.Store(ctx, family)
+}
+
+// DetectReuse 检测刷新令牌重放
+//
+// 刷新令牌属于某个家族但不是该家族当前的刷新令牌时判定为重放：吊销整个家族并返回
+// `ErrRefreshTokenReused` 错误。家族已因重放或 SSO 登出被吊销时，其成员令牌再次出现同样返回该错误。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - refreshToken: 客户端提交的刷新令牌。
+//
+// 返回值:
+//   - *xError.Error: 检测到重放或读取家族失败时返回错误。
+func (l *TokenFamilyLogic) DetectReuse(ctx context.Context, refreshToken string) *xError.Error {
+	l.log.Info(ctx, "DetectReuse - 检测刷新令牌重放")
+
+	familyID, xErr := l.data.FindByRefreshToken(ctx, refreshToken)
+	if xErr != nil || familyID == "" {
+		return xErr
+	}
+
+	family, xErr := l.data.Get(ctx, familyID)
+	if xErr != nil {
+		return xErr
+	}
+	fingerprint, err := bSdkUtil.TokenFingerprint(refreshToken)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	if family.FamilyID != "" && family.RefreshFingerprint == fingerprint {
+		return nil
+	}
+
+	if family.FamilyID != "" {
+		l.revokeFamily(ctx, family, fingerprint)
+	}
+	return xError.NewError(ctx, bSdkConst.ErrRefreshTokenReused, "刷新令牌已失效，请重新登录", false, nil)
+}
+
+// Find 查询刷新令牌（含已轮换的）所属的家族记录，未知令牌或家族已过期时返回 nil。
+func (l *TokenFamilyLogic) Find(ctx context.Context, refreshToken string) (*bSdkModels.CacheTokenFamily, *xError.Error) {
+	familyID, xErr := l.data.FindByRefreshToken(ctx, refreshToken)
+	if xErr != nil || familyID == "" {
+		return nil, xErr
+	}
+
+	family, xErr := l.data.Get(ctx, familyID)
+	if xErr != nil || family.FamilyID == "" {
+		return nil, xErr
+	}
+	return family, nil
+}
+
+// End 结束令牌家族，SSO 登出终止会话后调用，使该家族的刷新令牌无法再换取新令牌。
+//
+// 删除家族记录并广播当前刷新令牌的吊销；刷新令牌索引保留至 TTL 到期，期间提交该家族的任一刷新令牌
+// 均由 `DetectReuse` 拒绝。会话已由 SSO 终止，因此不调用注销端点，也不触发安全钩子。
+//
+// 参数说明:
+//   - ctx: 请求上下文。
+//   - familyID: 家族标识。
+//
+// 返回值:
+//   - *xError.Error: 读取或删除家族记录失败时返回错误。
+func (l *TokenFamilyLogic) End(ctx context.Context, familyID string) *xError.Error {
+	l.log.Info(ctx, "End - 结束令牌家族")
+
+	family, xErr := l.data.Get(ctx, familyID)
+	if xErr != nil {
+		return xErr
+	}
+	if family.FamilyID == "" {
+		return nil
+	}
+	if xErr = l.data.Delete(ctx, family.FamilyID); xErr != nil {
+		return xErr
+	}
+
+	if family.RefreshFingerprint != "" {
+		xErr = l.revocation.PublishFingerprints(ctx, &bSdkModels.RevocationMessage{
+			Reason:       RevocationReasonSsoLogout,
+			TokenType:    "refresh_token",
+			Fingerprints: []string{family.RefreshFingerprint},
+		})
+		if xErr != nil {
+			l.log.Warn(ctx, "TokenFamilyLogic|End - 广播令牌吊销失败", slog.String("error", xErr.Error()))
+		}
+	}
+	return nil
+}
+
+// revokeFamily 吊销整个令牌家族，各步骤失败仅记录告警，保证尽可能多地完成清理。
+func (l *TokenFamilyLogic) revokeFamily(ctx context.Context, family *bSdkModels.CacheTokenFamily, reusedFingerprint string) {
+	l.log.Warn(ctx, "TokenFamilyLogic|revokeFamily - 检测到刷新令牌重放，吊销令牌家族",
+		slog.String("family_id", family.FamilyID),
+		slog.String("subject", family.Subject),
+		slog.String("provider", family.Provider),
+	)
+
+	// 在家族所属身份提供方的注销端点以该提供方的客户端凭证注销，命名身份提供方未配置注销端点时仅清理本地缓存
+	if family.RefreshToken != "" {
+		cfg, ok := providerConfig(ctx, l.cfg, family.Provider)
+		switch {
+		case !ok:
+			l.log.Warn(ctx, "TokenFamilyLogic|revokeFamily - 令牌家族所属的身份提供方未注册", slog.String("provider", family.Provider))
+		case family.Provider != "" && cfg.Endpoints.Revocation == "":
+		default:
+			if xErr := revokeAtEndpoint(ctx, cfg, l.httpClient, "refresh_token", family.RefreshToken); xErr != nil {
+				l.log.Warn(ctx, "TokenFamilyLogic|revokeFamily - 注销刷新令牌失败", slog.String("error", xErr.Error()))
+			}
+		}
+	}
+	if xErr := l.data.Delete(ctx, family.FamilyID); xErr != nil {
+		l.log.Warn(ctx, "TokenFamilyLogic|revokeFamily - 删除令牌家族失败", slog.String("error", xErr.Error()))
+	}
+
+	fingerprints := make([]string, 0, 3)
+	if family.AccessFingerprint != "" {
+		fingerprints = append(fingerprints, family.AccessFingerprint)
+		if delErr := l.tokenData.DeleteByFingerprint(ctx, family.AccessFingerprint); delErr != nil {
+			l.log.Warn(ctx, "TokenFamilyLogic|revokeFamily - 清理令牌缓存失败", slog.String("error", delErr.Error()))
+		}
+		if delErr := l.userinfoData.DeleteCacheByFingerprint(ctx, family.AccessFingerprint); delErr != nil {
+			l.log.Warn(ctx, "TokenFamilyLogic|revokeFamily - 清理用户信息缓存失败", slog.String("error", delErr.Error()))
+		}
+		if delErr := l.introspectionData.DeleteCacheByFingerprint(ctx, "access_token", family.AccessFingerprint); delErr != nil {
+			l.log.Warn(ctx, "TokenFamilyLogic|revokeFamily - 清理自省缓存失败", slog.String("error", delErr.Error()))
+		}
+	}
+	if family.RefreshFingerprint != "" {
+		fingerprints = append(fingerprints, family.RefreshFingerprint)
+	}
+	if reusedFingerprint != "" && reusedFingerprint != family.RefreshFingerprint {
+		fingerprints = append(fingerprints, reusedFingerprint)
+	}
+	if len(fingerprints) > 0 {
+		xErr := l.revocation.PublishFingerprints(ctx, &bSdkModels.RevocationMessage{
+			Reason:       RevocationReasonReuse,
+			Fingerprints: fingerprints,
+		})
+		if xErr != nil {
+			l.log.Warn(ctx, "TokenFamilyLogic|revokeFamily - 广播令牌吊销失败", slog.String("error", xErr.Error()))
+		}
+	}
+
+	fireSecurityHooks(ctx, &bSdkModels.SecurityEvent{
+		Type:             SecurityEventRefreshTokenReuse,
+		FamilyID:         family.FamilyID,
+		Subject:          family.Subject,
+		SessionID:        family.SessionID,
+		TokenFingerprint: reusedFingerprint,
+		OccurredAt:       time.Now().Unix(),
+	})
+}
+
+// currentRefreshToken 返回轮换后有效的刷新令牌，授权服务器未返回新刷新令牌时沿用旧值。
+func currentRefreshToken(token *bSdkModels.CacheOAuthToken, previousRefreshToken string) string {
+	if token.RefreshToken != "" {
+		return token.RefreshToken
+	}
+	return previousRefreshToken
+}
@@ -0,0 +1,225 @@
+package bSdkLogic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+)
+
+func TestTokenFamilyLogicReuse(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	hookMu.Lock()
+	saved := securityHooks
+	securityHooks = nil
+	hookMu.Unlock()
+	t.Cleanup(func() {
+		hookMu.Lock()
+		securityHooks = saved
+		hookMu.Unlock()
+	})
+	var events []*bSdkModels.SecurityEvent
+	RegisterSecurityHook(func(_ context.Context, event *bSdkModels.SecurityEvent) {
+		events = append(events, event)
+	})
+
+	var (
+		mu      sync.Mutex
+		revoked []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("解析表单失败: %v", err)
+		}
+		mu.Lock()
+		revoked = append(revoked, r.Form.Get("token"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Revocation: srv.URL}),
+		bSdkConfig.WithBusinessCache(true),
+	)
+	logic := NewTokenFamilyWith(Deps{Config: cfg, Store: bSdkStore.NewMemoryStore(time.Minute)})
+
+	expiry := time.Now().Add(time.Hour).Format(time.RFC3339)
+	login := &bSdkModels.CacheOAuthToken{
+		AccessToken:  "family-at-1",
+		RefreshToken: "family-rt-1",
+		TokenType:    "Bearer",
+		Expiry:       expiry,
+		Subject:      "user-1",
+		SessionID:    "sid-1",
+	}
+	if xErr := logic.Start(ctx, login); xErr != nil || login.FamilyID == "" {
+		t.Fatalf("创建令牌家族失败: %v", xErr)
+	}
+	if xErr := logic.tokenData.Store(ctx, login); xErr != nil {
+		t.Fatalf("缓存令牌失败: %v", xErr)
+	}
+	if xErr := logic.DetectReuse(ctx, "family-rt-1"); xErr != nil {
+		t.Fatalf("当前刷新令牌不应判定为重放: %v", xErr)
+	}
+
+	// 轮换：新令牌沿用家族，旧刷新令牌不再是家族当前的刷新令牌
+	rotated := &bSdkModels.CacheOAuthToken{
+		AccessToken:  "family-at-2",
+		RefreshToken: "family-rt-2",
+		TokenType:    "Bearer",
+		Expiry:       expiry,
+		Subject:      "user-1",
+		SessionID:    "sid-1",
+	}
+	if xErr := logic.Rotate(ctx, "family-rt-1", rotated); xErr != nil {
+		t.Fatalf("轮换令牌家族失败: %v", xErr)
+	}
+	if rotated.FamilyID != login.FamilyID {
+		t.Fatalf("轮换后应沿用家族 %s，实际 %s", login.FamilyID, rotated.FamilyID)
+	}
+	if xErr := logic.tokenData.Store(ctx, rotated); xErr != nil {
+		t.Fatalf("缓存令牌失败: %v", xErr)
+	}
+	if err := logic.userinfoData.StoreCache(ctx, "family-at-2", &bSdkModels.OAuthUserinfo{Sub: "user-1"}); err != nil {
+		t.Fatalf("写入用户信息缓存失败: %v", err)
+	}
+	if err := logic.introspectionData.StoreCache(ctx, "access_token", "family-at-2", &bSdkModels.OAuthIntrospection{Active: true}); err != nil {
+		t.Fatalf("写入自省缓存失败: %v", err)
+	}
+	if _, hit, _ := logic.userinfoData.GetCache(ctx, "family-at-2"); !hit {
+		t.Fatalf("期望用户信息缓存命中")
+	}
+	if _, hit, _ := logic.introspectionData.GetCache(ctx, "access_token", "family-at-2"); !hit {
+		t.Fatalf("期望自省缓存命中")
+	}
+	if xErr := logic.DetectReuse(ctx, "family-rt-2"); xErr != nil {
+		t.Fatalf("轮换后的刷新令牌不应判定为重放: %v", xErr)
+	}
+
+	// 重放旧刷新令牌
+	xErr := logic.DetectReuse(ctx, "family-rt-1")
+	if xErr == nil || xErr.GetErrorCode().Code != bSdkConst.ErrRefreshTokenReused.Code {
+		t.Fatalf("期望错误码 %d，实际 %v", bSdkConst.ErrRefreshTokenReused.Code, xErr)
+	}
+
+	t.Run("注销家族当前的刷新令牌", func(t *testing.T) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(revoked) != 1 || revoked[0] != "family-rt-2" {
+			t.Fatalf("期望注销 family-rt-2，实际 %v", revoked)
+		}
+	})
+
+	t.Run("删除家族记录", func(t *testing.T) {
+		family, xErr := logic.data.Get(ctx, login.FamilyID)
+		if xErr != nil || family.FamilyID != "" {
+			t.Fatalf("期望家族已删除，实际 %+v, %v", family, xErr)
+		}
+	})
+
+	t.Run("清理令牌与业务缓存", func(t *testing.T) {
+		token, xErr := logic.tokenData.Get(ctx, "family-at-2")
+		if xErr != nil || token.AccessToken != "" {
+			t.Fatalf("期望令牌缓存已清理，实际 %+v, %v", token, xErr)
+		}
+		if _, hit, err := logic.userinfoData.GetCache(ctx, "family-at-2"); err != nil || hit {
+			t.Fatalf("期望用户信息缓存已清理，命中 %v, %v", hit, err)
+		}
+		if _, hit, err := logic.introspectionData.GetCache(ctx, "access_token", "family-at-2"); err != nil || hit {
+			t.Fatalf("期望自省缓存已清理，命中 %v, %v", hit, err)
+		}
+	})
+
+	t.Run("触发安全钩子", func(t *testing.T) {
+		if len(events) != 1 || events[0].Type != SecurityEventRefreshTokenReuse || events[0].FamilyID != login.FamilyID {
+			t.Fatalf("期望收到家族 %s 的重放事件，实际 %+v", login.FamilyID, events)
+		}
+	})
+
+	t.Run("已吊销家族的成员令牌", func(t *testing.T) {
+		xErr := logic.DetectReuse(ctx, "family-rt-2")
+		if xErr == nil || xErr.GetErrorCode().Code != bSdkConst.ErrRefreshTokenReused.Code {
+			t.Fatalf("期望错误码 %d，实际 %v", bSdkConst.ErrRefreshTokenReused.Code, xErr)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(revoked) != 1 || len(events) != 1 {
+			t.Fatalf("家族已吊销时不应重复吊销，注销 %v，事件 %d 个", revoked, len(events))
+		}
+	})
+}
+
+func TestTokenFamilyLogicReuseProvider(t *testing.T) {
+	configureTestKeys(t)
+	gin.SetMode(gin.TestMode)
+	ctx := newOAuthTestGinContext()
+
+	type revocation struct{ clientID, token string }
+	var (
+		mu      sync.Mutex
+		revoked = make(map[string][]revocation)
+	)
+	// newRevocationServer 记录发往该注销端点的客户端标识与令牌
+	newRevocationServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientID, _, _ := r.BasicAuth()
+			if err := r.ParseForm(); err != nil {
+				t.Errorf("解析表单失败: %v", err)
+			}
+			mu.Lock()
+			revoked[name] = append(revoked[name], revocation{clientID: clientID, token: r.Form.Get("token")})
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+	defaultSrv := newRevocationServer("default")
+	defer defaultSrv.Close()
+	partnerSrv := newRevocationServer("partner")
+	defer partnerSrv.Close()
+
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("cid", "csecret"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Revocation: defaultSrv.URL}),
+		bSdkConfig.WithProvider("partner",
+			bSdkConfig.WithClient("pid", "psecret"),
+			bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Revocation: partnerSrv.URL}),
+		),
+	)
+	logic := NewTokenFamilyWith(Deps{Config: cfg, Store: bSdkStore.NewMemoryStore(time.Minute)})
+
+	expiry := time.Now().Add(time.Hour).Format(time.RFC3339)
+	login := &bSdkModels.CacheOAuthToken{AccessToken: "partner-family-at-1", RefreshToken: "partner-family-rt-1", Expiry: expiry, Provider: "partner"}
+	if xErr := logic.Start(ctx, login); xErr != nil {
+		t.Fatalf("创建令牌家族失败: %v", xErr)
+	}
+	rotated := &bSdkModels.CacheOAuthToken{AccessToken: "partner-family-at-2", RefreshToken: "partner-family-rt-2", Expiry: expiry, Provider: "partner"}
+	if xErr := logic.Rotate(ctx, "partner-family-rt-1", rotated); xErr != nil {
+		t.Fatalf("轮换令牌家族失败: %v", xErr)
+	}
+
+	xErr := logic.DetectReuse(ctx, "partner-family-rt-1")
+	if xErr == nil || xErr.GetErrorCode().Code != bSdkConst.ErrRefreshTokenReused.Code {
+		t.Fatalf("期望错误码 %d，实际 %v", bSdkConst.ErrRefreshTokenReused.Code, xErr)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(revoked["default"]) != 0 {
+		t.Fatalf("默认提供方不应收到其他提供方的刷新令牌，实际 %v", revoked["default"])
+	}
+	if got := revoked["partner"]; len(got) != 1 || got[0] != (revocation{clientID: "pid", token: "partner-family-rt-2"}) {
+		t.Fatalf("期望以 partner 的客户端凭证注销 partner-family-rt-2，实际 %v", got)
+	}
+}
@@ -6,28 +6,37 @@ import (
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
 	bSdkClient "github.com/phalanx-labs/beacon-sso-sdk/client"
 	pb "github.com/phalanx-labs/beacon-sso-sdk/client/api/beacon/sso/v1"
-	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 )
 
 // UserLogic 用户业务逻辑组件，封装当前用户信息获取流程。
 type UserLogic struct {
 	log       *xLog.LogNamedLogger // 日志实例
 	ssoClient bSdkClient.IUser     // SsoClient User 服务接口
+	cfg       *bSdkConfig.Config   // SDK 配置，用于按租户切换 App 认证凭证
 }
 
 // NewUser 创建并初始化一个新的 UserLogic 业务逻辑实例。
 //
 // 参数:
-//   - ctx: 请求上下文，用于获取 SsoClient 实例。
+//   - ctx: 请求上下文，用于获取 SsoClient 实例；未注册时各方法返回 `ErrSsoClientUnavailable`。
 //
 // 返回值:
 //   - *UserLogic: 配置完成的用户逻辑层实例指针。
 func NewUser(ctx context.Context) *UserLogic {
-	client := bSdkUtil.GetSsoClient(ctx)
-	return &UserLogic{
-		log:       xLog.WithName(xLog.NamedLOGC, "UserLogic"),
-		ssoClient: client.User,
+	return NewUserWith(DepsFromContext(ctx))
+}
+
+// NewUserWith 使用显式依赖创建 UserLogic，`Deps.SsoClient` 为空时各方法返回 `ErrSsoClientUnavailable`。
+func NewUserWith(deps Deps) *UserLogic {
+	logic := &UserLogic{
+		log: xLog.WithName(xLog.NamedLOGC, "UserLogic"),
+		cfg: deps.Config,
+	}
+	if deps.SsoClient != nil {
+		logic.ssoClient = deps.SsoClient.User
 	}
+	return logic
 }
 
 // GetCurrentUser 获取当前登录用户信息
@@ -41,7 +50,10 @@ func NewUser(ctx context.Context) *UserLogic {
 //   - error: 获取失败时返回错误。
 func (l *UserLogic) GetCurrentUser(ctx context.Context, accessToken string) (*pb.GetCurrentUserResponse, error) {
 	l.log.Info(ctx, "GetCurrentUser - 获取当前用户信息")
-	return l.ssoClient.GetCurrentUser(ctx, accessToken)
+	if l.ssoClient == nil {
+		return nil, ErrSsoClientUnavailable
+	}
+	return l.ssoClient.GetCurrentUser(grpcContext(ctx, l.cfg), accessToken)
 }
 
 // GetUserByID 根据用户 ID 获取用户详细信息
@@ -63,5 +75,8 @@ func (l *UserLogic) GetCurrentUser(ctx context.Context, accessToken string) (*pb
 //   - error: 获取失败时返回错误。
 func (l *UserLogic) GetUserByID(ctx context.Context, accessToken string, req *pb.GetUserByIDRequest) (*pb.GetUserByIDResponse, error) {
 	l.log.Info(ctx, "GetUserByID - 根据ID获取用户信息")
-	return l.ssoClient.GetUserByID(ctx, accessToken, req)
+	if l.ssoClient == nil {
+		return nil, ErrSsoClientUnavailable
+	}
+	return l.ssoClient.GetUserByID(grpcContext(ctx, l.cfg), accessToken, req)
 }
@@ -0,0 +1,26 @@
+package bSdkLogic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	bSdkClient "github.com/phalanx-labs/beacon-sso-sdk/client"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+)
+
+func TestUserLogicWithoutSsoClient(t *testing.T) {
+	contexts := map[string]context.Context{
+		"未注册": context.Background(),
+		"空实例": context.WithValue(context.Background(), bSdkConst.CtxSsoClient, (*bSdkClient.SsoClient)(nil)),
+	}
+	for name, ctx := range contexts {
+		logic := NewUser(ctx)
+		if _, err := logic.GetCurrentUser(ctx, "token"); !errors.Is(err, ErrSsoClientUnavailable) {
+			t.Fatalf("%s: 期望 ErrSsoClientUnavailable，实际 %v", name, err)
+		}
+		if _, err := logic.GetUserByID(ctx, "token", nil); !errors.Is(err, ErrSsoClientUnavailable) {
+			t.Fatalf("%s: 期望 ErrSsoClientUnavailable，实际 %v", name, err)
+		}
+	}
+}
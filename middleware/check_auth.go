@@ -2,55 +2,110 @@ package bSdkMiddle
 
 import (
 	"context"
+	"log/slog"
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
 	xHttp "github.com/bamboo-services/bamboo-base-go/defined/http"
 	xResult "github.com/bamboo-services/bamboo-base-go/major/result"
 	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
 // CheckAuth 检查用户身份认证信息
 //
 // 本函数是一个中间件工厂，用于生成 Gin 的 HandlerFunc。
-// 它利用提供的 `context.Context` 初始化数据库与 Redis 连接，
+// 它利用提供的 `context.Context` 获取数据库与状态存储，
 // 进而构建 OAuth 逻辑层。返回的中间件函数会执行以下逻辑：
 //
-//  1. 从请求头的 `Authorization` 字段提取访问令牌。
-//  2. 调用 `OAuthLogic` 验证令牌的有效性及过期时间。
-//  3. 若验证通过，调用 `ctx.Next()` 放行请求；否则中断请求并返回错误。
+//  1. 按 SDK 配置解析请求所属的租户（参见 `Tenant`），
+//     随后从请求头的 `Authorization` 字段提取访问令牌，缺失且开启 `session.cookie` 时读取本地会话 Cookie（`session.cookie_name`）；
+//     以 Cookie 认证的非安全方法请求须通过来源校验（参见 `bSdkUtil.CheckSessionOrigin`）。
+//  2. 调用 `OAuthLogic` 验证令牌的有效性及过期时间；令牌由命名身份提供方签发时，
+//     按令牌记录的提供方校验签发者并使用该提供方刷新。
+//  3. Cookie 会话模式下，令牌距过期不足 `session.refresh_skew` 时使用服务端保存的刷新令牌
+//     自动刷新，并以新令牌更新会话 Cookie（可通过 `session.auto_refresh` 关闭）。
+//  4. 若验证通过，调用 `ctx.Next()` 放行请求；否则中断请求并返回错误。
 //
 // 参数说明:
-//   - ctx: 上下文环境，必须包含通过 `xCtxUtil` 注入的 DB (*gorm.DB) 和 RDB (*redis.Client)。
+//   - ctx: 上下文环境，状态存储按 `SSO_STORAGE` 选择（默认使用通过 `xCtxUtil` 注入的 Redis）。
 //
 // 返回值:
 //   - gin.HandlerFunc: 配置好的 Gin 中间件处理函数。
 func CheckAuth(ctx context.Context) gin.HandlerFunc {
-	log := xLog.WithName(xLog.NamedMIDE, "CheckAuth")
+	return checkAuth(bSdkUtil.GetConfig(ctx), bSdkLogic.NewOAuth(ctx))
+}
+
+// CheckAuthWith 使用显式依赖创建身份认证中间件，行为同 `CheckAuth`。
+//
+// 参数说明:
+//   - deps: SDK 依赖，令牌校验与自动刷新使用 `Deps.Config`、`Deps.Store` 与 `Deps.HTTPClient`，
+//     会话 Cookie 与自动刷新设置按请求读取 `Deps.Config` 的当前快照。
+//
+// 返回值:
+//   - gin.HandlerFunc: 配置好的 Gin 中间件处理函数。
+//   - error: 令牌密钥缺失、非法或与进程内已配置的密钥冲突时返回错误（参见 `bSdkLogic.NewOAuthWith`）。
+func CheckAuthWith(deps bSdkLogic.Deps) (gin.HandlerFunc, error) {
+	oAuthLogic, err := bSdkLogic.NewOAuthWith(deps)
+	if err != nil {
+		return nil, err
+	}
+	return checkAuth(deps.Config, oAuthLogic), nil
+}
 
-	oAuthLogic := bSdkLogic.NewOAuth(ctx)
+// checkAuth 使用指定的 SDK 配置与 OAuth 逻辑创建身份认证中间件。
+func checkAuth(cfg *bSdkConfig.Config, oAuthLogic *bSdkLogic.OAuthLogic) gin.HandlerFunc {
+	log := xLog.WithName(xLog.NamedMIDE, "CheckAuth")
 
 	return func(c *gin.Context) {
 		log.Info(c, "检查用户身份认证信息")
+		if !bindTenant(c, cfg) {
+			return
+		}
 
-		// 获取用户身份令牌
+		// 获取用户身份令牌，请求头优先，其次为本地会话 Cookie
+		session := oAuthLogic.SessionConfig()
 		getAT := xHttp.GetToken(c, xHttp.HeaderAuthorization)
+		fromCookie := false
+		if getAT == "" {
+			getAT = bSdkUtil.GetSessionCookie(c, session)
+			fromCookie = getAT != ""
+		}
 		if getAT == "" {
 			xResult.AbortError(c, xError.ParameterEmpty, "需要访问令牌参数", nil)
 			return
 		}
-
-		isExpire, xErr := oAuthLogic.VerifyExpiry(c, getAT)
-		if xErr != nil {
-			xResult.AbortError(c, xErr.ErrorCode, xErr.ErrorMessage, xErr.Data)
+		if fromCookie && !bSdkUtil.CheckSessionOrigin(c, session) {
+			log.Warn(c, "会话 Cookie 请求来源校验失败", slog.String("origin", c.GetHeader("Origin")), slog.String("referer", c.GetHeader("Referer")))
+			xResult.AbortError(c, xError.Forbidden, "请求来源不可信", nil)
 			return
 		}
 
-		// 验证是否过期
-		if isExpire {
-			xResult.AbortError(c, xError.TokenExpired, "访问令牌已过期", nil)
-			return
+		if fromCookie && session.AutoRefresh {
+			// Cookie 会话模式：临近过期时由服务端自动刷新，无需客户端往返
+			newToken, xErr := oAuthLogic.EnsureFresh(c, getAT, session.RefreshSkew.Duration())
+			if xErr != nil {
+				xResult.AbortError(c, xErr.ErrorCode, xErr.ErrorMessage, xErr.Data)
+				return
+			}
+			if newToken != nil {
+				bSdkUtil.SetSessionCookie(c, session, newToken.AccessToken)
+				getAT = newToken.AccessToken
+			}
+		} else {
+			isExpire, xErr := oAuthLogic.VerifyExpiry(c, getAT)
+			if xErr != nil {
+				xResult.AbortError(c, xErr.ErrorCode, xErr.ErrorMessage, xErr.Data)
+				return
+			}
+
+			// 验证是否过期
+			if isExpire {
+				xResult.AbortError(c, xError.TokenExpired, "访问令牌已过期", nil)
+				return
+			}
 		}
 
 		// 存入 GIN 上下文
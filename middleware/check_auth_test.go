@@ -0,0 +1,187 @@
+package bSdkMiddle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	xHttp "github.com/bamboo-services/bamboo-base-go/defined/http"
+	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkRepo "github.com/phalanx-labs/beacon-sso-sdk/repository"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+func TestCheckAuthSessionCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if _, err := bSdkUtil.ConfigureTokenKeys(context.Background(), bSdkConfig.New(bSdkConfig.WithClient("cid", "csecret"))); err != nil {
+		t.Fatalf("配置令牌密钥失败: %v", err)
+	}
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"cookie-at-new","token_type":"Bearer","refresh_token":"cookie-rt-new","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	store := bSdkStore.NewMemoryStore(time.Minute)
+	tokens := bSdkRepo.NewOAuthTokenRepoWith(nil, store, nil)
+	save := func(at string, rt string, remaining time.Duration) {
+		t.Helper()
+		token := &bSdkModels.CacheOAuthToken{
+			AccessToken:  at,
+			RefreshToken: rt,
+			TokenType:    "Bearer",
+			Expiry:       time.Now().Add(remaining).Format(time.RFC3339),
+		}
+		if xErr := tokens.Store(context.Background(), token); xErr != nil {
+			t.Fatalf("缓存令牌失败: %v", xErr)
+		}
+	}
+
+	// serve 使用给定的会话配置执行一次经过 CheckAuth 的请求，返回响应与处理器看到的访问令牌
+	serve := func(session bSdkConfig.SessionConfig, header string, cookie *http.Cookie) (*httptest.ResponseRecorder, string) {
+		cfg := bSdkConfig.New(
+			bSdkConfig.WithClient("cid", "csecret"),
+			bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{Token: srv.URL}),
+			bSdkConfig.WithSession(session),
+		)
+		seen := ""
+		engine := gin.New()
+		engine.GET("/", newTestCheckAuth(t, bSdkLogic.Deps{Config: cfg, Store: store}), func(c *gin.Context) {
+			seen = c.GetString(xHttp.HeaderAuthorization.String())
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set(xHttp.HeaderAuthorization.String(), "Bearer "+header)
+		}
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+		return recorder, seen
+	}
+
+	session := bSdkConfig.SessionConfig{Cookie: true, CookieName: "app_session", CookieDomain: "example.com", AutoRefresh: true, RefreshSkew: bSdkConfig.Duration(time.Minute)}
+
+	t.Run("临近过期时刷新并更新 Cookie", func(t *testing.T) {
+		save("cookie-at-near", "cookie-rt-near", 30*time.Second)
+		recorder, seen := serve(session, "", &http.Cookie{Name: "app_session", Value: "cookie-at-near"})
+		if recorder.Code != http.StatusOK || seen != "cookie-at-new" {
+			t.Fatalf("期望以刷新后的令牌放行，实际状态 %d，令牌 %q", recorder.Code, seen)
+		}
+		cookies := recorder.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != "app_session" || cookies[0].Value != "cookie-at-new" || cookies[0].Domain != "example.com" {
+			t.Fatalf("期望按配置更新会话 Cookie，实际 %v", cookies)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Fatalf("期望请求令牌端点 1 次，实际 %d 次", got)
+		}
+	})
+
+	t.Run("关闭自动刷新时不刷新", func(t *testing.T) {
+		save("cookie-at-manual", "cookie-rt-manual", 30*time.Second)
+		disabled := session
+		disabled.AutoRefresh = false
+		recorder, seen := serve(disabled, "", &http.Cookie{Name: "app_session", Value: "cookie-at-manual"})
+		if recorder.Code != http.StatusOK || seen != "cookie-at-manual" {
+			t.Fatalf("期望以原令牌放行，实际状态 %d，令牌 %q", recorder.Code, seen)
+		}
+		if len(recorder.Result().Cookies()) != 0 || calls.Load() != 1 {
+			t.Fatalf("关闭自动刷新时不应刷新令牌或写入 Cookie")
+		}
+	})
+
+	t.Run("请求头令牌不自动刷新", func(t *testing.T) {
+		save("cookie-at-header", "cookie-rt-header", 30*time.Second)
+		recorder, seen := serve(session, "cookie-at-header", nil)
+		if recorder.Code != http.StatusOK || seen != "cookie-at-header" {
+			t.Fatalf("期望以请求头令牌放行，实际状态 %d，令牌 %q", recorder.Code, seen)
+		}
+		if len(recorder.Result().Cookies()) != 0 || calls.Load() != 1 {
+			t.Fatalf("请求头模式不应刷新令牌或写入 Cookie")
+		}
+	})
+
+	t.Run("按配置的名称读取 Cookie", func(t *testing.T) {
+		save("cookie-at-name", "", time.Hour)
+		recorder, seen := serve(session, "", &http.Cookie{Name: bSdkConfig.Default().Session.CookieName, Value: "cookie-at-name"})
+		if recorder.Code == http.StatusOK || seen != "" {
+			t.Fatalf("名称不匹配的 Cookie 不应通过认证，实际状态 %d", recorder.Code)
+		}
+	})
+
+	t.Run("未开启 Cookie 会话模式时不读取 Cookie", func(t *testing.T) {
+		save("cookie-at-off", "", time.Hour)
+		disabled := session
+		disabled.Cookie = false
+		recorder, seen := serve(disabled, "", &http.Cookie{Name: "app_session", Value: "cookie-at-off"})
+		if recorder.Code == http.StatusOK || seen != "" {
+			t.Fatalf("未开启 Cookie 会话模式时不应以 Cookie 通过认证，实际状态 %d", recorder.Code)
+		}
+	})
+
+	t.Run("Cookie 认证的跨站写请求被拒绝", func(t *testing.T) {
+		save("cookie-at-csrf", "", time.Hour)
+		cfg := bSdkConfig.New(bSdkConfig.WithClient("cid", "csecret"), bSdkConfig.WithSession(session))
+		engine := gin.New()
+		engine.POST("/", newTestCheckAuth(t, bSdkLogic.Deps{Config: cfg, Store: store}), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		post := func(origin string, header string) int {
+			req := httptest.NewRequest(http.MethodPost, "https://api.example.com/", nil)
+			req.AddCookie(&http.Cookie{Name: "app_session", Value: "cookie-at-csrf"})
+			if origin != "" {
+				req.Header.Set("Origin", origin)
+			}
+			if header != "" {
+				req.Header.Set(xHttp.HeaderAuthorization.String(), "Bearer "+header)
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+			return recorder.Code
+		}
+
+		if code := post("https://evil.example.net", ""); code == http.StatusOK {
+			t.Fatalf("跨站来源的 Cookie 写请求不应放行")
+		}
+		if code := post("", ""); code == http.StatusOK {
+			t.Fatalf("缺少来源的 Cookie 写请求不应放行")
+		}
+		if code := post("https://api.example.com", ""); code != http.StatusOK {
+			t.Fatalf("同源 Cookie 写请求应放行，实际状态 %d", code)
+		}
+		if code := post("https://evil.example.net", "cookie-at-csrf"); code != http.StatusOK {
+			t.Fatalf("请求头令牌不受来源校验影响，实际状态 %d", code)
+		}
+	})
+
+	t.Run("已过期且无刷新令牌", func(t *testing.T) {
+		save("cookie-at-expired", "", -time.Minute)
+		recorder, seen := serve(session, "", &http.Cookie{Name: "app_session", Value: "cookie-at-expired"})
+		if recorder.Code == http.StatusOK || seen != "" {
+			t.Fatalf("过期令牌不应通过认证，实际状态 %d", recorder.Code)
+		}
+	})
+}
+
+// newTestCheckAuth 使用显式依赖创建身份认证中间件，失败时终止测试。
+func newTestCheckAuth(t *testing.T, deps bSdkLogic.Deps) gin.HandlerFunc {
+	t.Helper()
+	middleware, err := CheckAuthWith(deps)
+	if err != nil {
+		t.Fatalf("创建身份认证中间件失败: %v", err)
+	}
+	return middleware
+}
@@ -0,0 +1,57 @@
+package bSdkMiddle
+
+import (
+	"context"
+	"log/slog"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	xResult "github.com/bamboo-services/bamboo-base-go/major/result"
+	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// Tenant 解析请求所属的租户
+//
+// 本函数是一个中间件工厂，按 SDK 配置的 `TenantResolver`、`hosts` 或 `path_prefix` 解析租户，
+// 并将租户名称与缓存命名空间写入请求上下文；后续的 OAuth 客户端、gRPC App 认证凭证与令牌缓存均按该租户选择。
+// 解析结果为未注册的租户时中断请求；未配置租户时不做任何处理。
+//
+// 参数说明:
+//   - ctx: 上下文环境，用于获取 SDK 配置。
+//
+// 返回值:
+//   - gin.HandlerFunc: 配置好的 Gin 中间件处理函数。
+func Tenant(ctx context.Context) gin.HandlerFunc {
+	return TenantWith(bSdkUtil.GetConfig(ctx))
+}
+
+// TenantWith 使用显式传入的 SDK 配置创建租户解析中间件，行为同 `Tenant`，cfg 为 nil 时不做任何处理。
+func TenantWith(cfg *bSdkConfig.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !bindTenant(c, cfg) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// bindTenant 解析请求所属的租户并写入请求上下文，租户未注册时中断请求并返回 false。
+func bindTenant(c *gin.Context, cfg *bSdkConfig.Config) bool {
+	cfg = cfg.Current()
+	if cfg == nil || (len(cfg.Tenants) == 0 && cfg.TenantResolver == nil) {
+		return true
+	}
+	name := cfg.ResolveTenant(c.Request)
+	if name == "" || name == bSdkUtil.GetTenant(c) {
+		return true
+	}
+	if _, ok := cfg.Tenants[name]; !ok {
+		xLog.WithName(xLog.NamedMIDE, "Tenant").Warn(c, "请求解析到未注册的租户", slog.String("tenant", name))
+		xResult.AbortError(c, xError.NotExist, "租户不存在", nil)
+		return false
+	}
+	c.Request = c.Request.WithContext(bSdkUtil.WithTenant(c.Request.Context(), name, cfg.TenantKeyPrefix(name)))
+	return true
+}
@@ -11,4 +11,5 @@ type CacheBusinessUserinfo struct {
 	Email             string `redis:"email" json:"email"`
 	Phone             string `redis:"phone" json:"phone"`
 	Raw               string `redis:"raw" json:"raw"`
+	FetchedAt         int64  `redis:"fetched_at" json:"fetched_at"` // 从 SSO 获取的时间（Unix 毫秒），用于判断是否过期
 }
@@ -10,9 +10,21 @@ package bSdkModels
 //   - TokenType: 令牌类型，通常为 "Bearer"。
 //   - RefreshToken: 刷新令牌，用于在访问令牌过期后获取新的令牌。
 //   - Expiry: 令牌过期时间，以 RFC3339 格式存储。
+//   - IDToken: OIDC ID Token（scope 包含 openid 时返回）。
+//   - Subject: 从 ID Token 中解析的用户标识（sub），用于登出时按用户定位令牌。
+//   - SessionID: 从 ID Token 中解析的 SSO 会话标识（sid），用于登出时按会话定位令牌。
+//   - FamilyID: 刷新令牌家族标识，同一次登录轮换产生的令牌共享该标识，用于重放检测。
+//   - Provider: 签发该令牌的命名身份提供方，默认提供方为空。
+//   - Tenant: 令牌所属的租户，未启用多租户时为空。
 type CacheOAuthToken struct {
 	AccessToken  string `redis:"access_token" json:"access_token"`
 	TokenType    string `redis:"token_type" json:"token_type"`
 	RefreshToken string `redis:"refresh_token" json:"refresh_token"`
 	Expiry       string `redis:"expiry" json:"expiry"` // RFC3339 格式
+	IDToken      string `redis:"id_token" json:"id_token,omitempty"`
+	Subject      string `redis:"subject" json:"subject,omitempty"`
+	SessionID    string `redis:"session_id" json:"session_id,omitempty"`
+	FamilyID     string `redis:"family_id" json:"family_id,omitempty"`
+	Provider     string `redis:"provider" json:"provider,omitempty"`
+	Tenant       string `redis:"tenant" json:"tenant,omitempty"`
 }
@@ -0,0 +1,21 @@
+package bSdkModels
+
+// JSONWebKey 表示 RFC 7517 JWKS 中的单个公钥。
+//
+// 仅保留签名校验所需字段：RSA 使用 N/E，EC 使用 Crv/X/Y，OKP（Ed25519）使用 Crv/X。
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet 表示 `jwks_uri` 端点返回的公钥集合。
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
@@ -0,0 +1,35 @@
+package bSdkModels
+
+// OAuthLogoutToken 表示 OIDC Back-Channel Logout 规范中已校验的 `logout_token` 声明。
+//
+// 该结构体仅在签名与声明校验全部通过后生成，Raw 字段保留原始声明以便业务扩展；
+// Provider 为按 `iss` 选出的命名身份提供方（默认提供方为空），不属于令牌声明。
+type OAuthLogoutToken struct {
+	Issuer    string         `json:"iss"`
+	Subject   string         `json:"sub,omitempty"`
+	Audience  []string       `json:"aud"`
+	IssuedAt  int64          `json:"iat"`
+	ExpiresAt int64          `json:"exp,omitempty"`
+	JTI       string         `json:"jti"`
+	SessionID string         `json:"sid,omitempty"`
+	Raw       map[string]any `json:"raw,omitempty"`
+	Provider  string         `json:"-"`
+}
+
+// LogoutEvent 表示一次由 SSO 发起的登出事件，供业务方通过登出钩子清理自身状态。
+//
+// 字段说明:
+//   - Channel: 登出通道（"backchannel" 或 "frontchannel"）。
+//   - Issuer: 发起登出的签发者。
+//   - Provider: 发起登出的命名身份提供方，默认提供方为空；仅清理该提供方签发的令牌。
+//   - Subject: 被登出的用户标识（sub），可能为空。
+//   - SessionID: 被登出的 SSO 会话标识（sid），可能为空。
+//   - TokenFingerprints: 本次已从缓存中清理的访问令牌指纹列表（见 `bSdkUtil.TokenFingerprint`）。
+type LogoutEvent struct {
+	Channel           string   `json:"channel"`
+	Issuer            string   `json:"issuer,omitempty"`
+	Provider          string   `json:"provider,omitempty"`
+	Subject           string   `json:"subject,omitempty"`
+	SessionID         string   `json:"session_id,omitempty"`
+	TokenFingerprints []string `json:"token_fingerprints,omitempty"`
+}
@@ -0,0 +1,36 @@
+package bSdkModels
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OAuthTokenRecord 令牌持久化记录
+//
+// 启用 `SSO_TOKEN_PERSISTENCE` 后，令牌在写入 Redis 的同时落库，Redis 被清空后仍可从数据库恢复会话；
+// 表中的 sub/sid/过期时间等字段可直接用于管理后台的会话统计。令牌字段与 Redis 缓存一样以信封加密存储，
+// 记录以令牌指纹唯一标识，删除为软删除以保留审计数据；`PurgeAt` 之后由清理任务物理删除。
+type OAuthTokenRecord struct {
+	ID                uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	AccessFingerprint string         `gorm:"type:char(64);uniqueIndex;not null" json:"access_fingerprint"` // 访问令牌指纹
+	AccessToken       string         `gorm:"type:text;not null" json:"-"`                                  // 访问令牌（加密）
+	RefreshToken      string         `gorm:"type:text" json:"-"`                                           // 刷新令牌（加密）
+	IDToken           string         `gorm:"type:text" json:"-"`                                           // ID Token（加密）
+	TokenType         string         `gorm:"type:varchar(32)" json:"token_type"`                           // 令牌类型
+	Expiry            time.Time      `gorm:"index" json:"expiry"`                                          // 访问令牌过期时间
+	PurgeAt           *time.Time     `gorm:"index" json:"purge_at,omitempty"`                              // 记录清理时间，含刷新令牌时不早于写入时间加令牌缓存 TTL
+	Subject           string         `gorm:"type:varchar(255);index" json:"subject,omitempty"`             // 用户标识（sub）
+	SessionID         string         `gorm:"type:varchar(255);index" json:"session_id,omitempty"`          // SSO 会话标识（sid）
+	FamilyID          string         `gorm:"type:varchar(64);index" json:"family_id,omitempty"`            // 刷新令牌家族标识
+	Provider          string         `gorm:"type:varchar(32);index" json:"provider,omitempty"`             // 命名身份提供方，默认提供方为空
+	Tenant            string         `gorm:"type:varchar(32);index" json:"tenant,omitempty"`               // 所属租户，未启用多租户时为空
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName 返回令牌持久化表名。
+func (OAuthTokenRecord) TableName() string {
+	return "sso_oauth_token"
+}
@@ -0,0 +1,13 @@
+package bSdkModels
+
+// RevocationMessage 令牌吊销广播消息
+//
+// 任一 SDK 实例注销、轮换令牌或处理 SSO 登出通知时，通过 Redis Pub/Sub 广播该消息，
+// 其他实例收到后立即清理本地（进程内）缓存。消息中仅包含令牌指纹，不包含令牌明文。
+type RevocationMessage struct {
+	Origin       string   `json:"origin"`               // 发布方实例标识，用于忽略自身消息
+	Reason       string   `json:"reason,omitempty"`     // 吊销原因（logout/revoke/rotate/backchannel 等）
+	TokenType    string   `json:"token_type,omitempty"` // 令牌类型（access_token/refresh_token）
+	Fingerprints []string `json:"fingerprints"`         // 令牌指纹列表
+	IssuedAt     int64    `json:"issued_at"`            // 发布时间（Unix 秒）
+}
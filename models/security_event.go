@@ -0,0 +1,14 @@
+package bSdkModels
+
+// SecurityEvent 安全事件
+//
+// SDK 检测到可疑行为（例如刷新令牌重放）并已完成处置后，通过安全钩子通知业务方，
+// 业务方可据此记录审计日志、告警或强制用户重新登录。事件中不包含任何令牌明文。
+type SecurityEvent struct {
+	Type             string `json:"type"`                        // 事件类型（如 refresh_token_reuse）
+	FamilyID         string `json:"family_id,omitempty"`         // 关联的令牌家族标识
+	Subject          string `json:"subject,omitempty"`           // 用户标识（sub）
+	SessionID        string `json:"session_id,omitempty"`        // SSO 会话标识（sid）
+	TokenFingerprint string `json:"token_fingerprint,omitempty"` // 触发事件的令牌指纹
+	OccurredAt       int64  `json:"occurred_at"`                 // 发生时间（Unix 秒）
+}
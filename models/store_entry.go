@@ -0,0 +1,21 @@
+package bSdkModels
+
+import "time"
+
+// StoreEntry 数据库状态存储记录
+//
+// `SSO_STORAGE=gorm` 时 SDK 的缓存数据以行的形式保存：字符串值的 field 为空，
+// 哈希的每个字段、集合的每个成员各占一行。同一键的所有行共享过期时间，
+// 过期行对读取不可见，并由 `bSdkStore.GormStore.PurgeExpired` 定期物理删除。
+type StoreEntry struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	StoreKey  string     `gorm:"type:varchar(191);not null;uniqueIndex:uk_sso_store_key_field,priority:1" json:"store_key"`        // 存储键
+	Field     string     `gorm:"type:varchar(191);not null;default:'';uniqueIndex:uk_sso_store_key_field,priority:2" json:"field"` // 哈希字段或集合成员
+	Value     string     `gorm:"type:text" json:"value"`                                                                           // 值
+	ExpiresAt *time.Time `gorm:"index" json:"expires_at,omitempty"`                                                                // 过期时间，为空表示不过期
+}
+
+// TableName 返回状态存储表名。
+func (StoreEntry) TableName() string {
+	return "sso_store"
+}
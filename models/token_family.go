@@ -0,0 +1,26 @@
+package bSdkModels
+
+// CacheTokenFamily 刷新令牌家族缓存
+//
+// 一次登录（授权码换取或密码登录）产生一个令牌家族，此后每次刷新轮换出的令牌均归属同一家族。
+// 家族仅记录当前有效的刷新令牌；已轮换的刷新令牌再次出现时即判定为重放，整个家族随之吊销。
+//
+// 字段说明:
+//   - FamilyID: 家族标识。
+//   - RefreshToken: 当前有效的刷新令牌，加密存储，吊销家族时用于调用注销端点。
+//   - RefreshFingerprint: 当前有效刷新令牌的指纹。
+//   - AccessFingerprint: 当前访问令牌的指纹，吊销家族时用于清理令牌缓存。
+//   - Subject: 用户标识（sub）。
+//   - SessionID: SSO 会话标识（sid）。
+//   - Provider: 签发该家族令牌的命名身份提供方，默认提供方为空；仅凭刷新令牌刷新与吊销家族时据此选择提供方。
+//   - CreatedAt: 家族创建时间（Unix 秒）。
+type CacheTokenFamily struct {
+	FamilyID           string `redis:"family_id" json:"family_id"`
+	RefreshToken       string `redis:"refresh_token" json:"-"`
+	RefreshFingerprint string `redis:"refresh_fp" json:"refresh_fp"`
+	AccessFingerprint  string `redis:"access_fp" json:"access_fp"`
+	Subject            string `redis:"subject" json:"subject,omitempty"`
+	SessionID          string `redis:"session_id" json:"session_id,omitempty"`
+	Provider           string `redis:"provider" json:"provider,omitempty"`
+	CreatedAt          int64  `redis:"created_at" json:"created_at"`
+}
@@ -0,0 +1,59 @@
+package bSdkRepo
+
+import (
+	"context"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	"gorm.io/gorm"
+)
+
+// BusinessLockRepo 业务层上游请求分布式锁数据仓储层。
+type BusinessLockRepo struct {
+	db    *gorm.DB
+	cache *bSdkCache.BusinessLockCache
+	log   *xLog.LogNamedLogger
+}
+
+// NewBusinessLockRepo 创建并初始化一个业务层上游请求分布式锁仓储实例。
+//
+// 缓存配置按 `SSO_CACHE_*` 环境变量读取，使用显式 SDK 配置时请改用 `NewBusinessLockRepoWith`。
+//
+// 参数:
+//   - db: 已初始化的 GORM 数据库实例（备用）。
+//   - store: 已初始化的状态存储，用于存放锁。
+//
+// 返回值:
+//   - *BusinessLockRepo: 配置完成的仓储实例指针。
+func NewBusinessLockRepo(db *gorm.DB, store bSdkStore.Store) *BusinessLockRepo {
+	return NewBusinessLockRepoWith(db, store, envConfig())
+}
+
+// NewBusinessLockRepoWith 按 SDK 配置创建业务层上游请求分布式锁仓储实例，cfg 为 nil 时使用默认配置。
+func NewBusinessLockRepoWith(db *gorm.DB, store bSdkStore.Store, cfg *bSdkConfig.Config) *BusinessLockRepo {
+	return &BusinessLockRepo{
+		db:    db,
+		cache: bSdkCache.NewBusinessLockCache(store, repoConfig(cfg).Cache),
+		log:   xLog.WithName(xLog.NamedREPO, "BusinessLockRepo"),
+	}
+}
+
+// Lock 尝试获取上游请求锁，锁已被占用时返回 false。
+func (r *BusinessLockRepo) Lock(ctx context.Context, key string, owner string) (bool, *xError.Error) {
+	locked, err := r.cache.Lock(ctx, key, owner)
+	if err != nil {
+		return false, xError.NewError(ctx, xError.OperationFailed, "获取上游请求锁失败", false, err)
+	}
+	return locked, nil
+}
+
+// Unlock 释放上游请求锁。
+func (r *BusinessLockRepo) Unlock(ctx context.Context, key string, owner string) *xError.Error {
+	if err := r.cache.Unlock(ctx, key, owner); err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "释放上游请求锁失败", false, err)
+	}
+	return nil
+}
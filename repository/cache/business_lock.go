@@ -0,0 +1,53 @@
+package bSdkCache
+
+import (
+	"context"
+	"fmt"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+)
+
+// BusinessLockCache 业务层上游请求分布式锁管理器
+//
+// 同一令牌的 Userinfo / Introspection 在多个实例上同时未命中缓存时，仅持锁实例请求 SSO，
+// 其余实例等待其写入业务缓存后直接读取。
+type BusinessLockCache bSdkStore.Cache
+
+// NewBusinessLockCache 创建并初始化一个业务层上游请求分布式锁管理器实例
+//
+// 参数:
+//   - store: 已初始化的状态存储，用于底层数据交互。
+//   - cfg: SDK 缓存配置，通常来自 SDK 配置的 `cache`。
+//
+// 返回值:
+//   - *BusinessLockCache: 配置完成的缓存管理器指针，锁默认最长持有 5 秒，可通过 `cache.entries.lock`（`SSO_CACHE_LOCK_*`）配置。
+func NewBusinessLockCache(store bSdkStore.Store, cfg bSdkConfig.CacheConfig) *BusinessLockCache {
+	c := BusinessLockCache(newStoreCache(store, cfg, CacheLock))
+	return &c
+}
+
+// Lock 尝试获取上游请求锁，成功返回 true。
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - key: 锁标识（请求类型:令牌指纹）。
+//   - owner: 持有者标识，释放时校验。
+func (c *BusinessLockCache) Lock(ctx context.Context, key string, owner string) (bool, error) {
+	if key == "" {
+		return false, fmt.Errorf("锁标识为空")
+	}
+
+	return c.Store.SetNX(ctx, bSdkConst.RedisBusinessFetchLock.GetWithPrefix(c.Prefix, key).String(), owner, c.TTL)
+}
+
+// Unlock 释放上游请求锁，仅当锁仍由 owner 持有时生效。
+func (c *BusinessLockCache) Unlock(ctx context.Context, key string, owner string) error {
+	if key == "" {
+		return fmt.Errorf("锁标识为空")
+	}
+
+	_, err := c.Store.CompareAndDelete(ctx, bSdkConst.RedisBusinessFetchLock.GetWithPrefix(c.Prefix, key).String(), owner)
+	return err
+}
@@ -0,0 +1,208 @@
+package bSdkCache
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+)
+
+// CacheName 缓存名称，小写形式为 SDK 配置 `cache.entries` 的键（如 `token`），
+// 对应环境变量 `SSO_CACHE_<NAME>_TTL`、`_MAX_TTL`、`_ENABLED`、`_PREFIX`，
+// 以及支持过期重验证的缓存的 `_STALE_TTL` 与 `_STALE_IF_ERROR_TTL`。
+type CacheName string
+
+const (
+	CacheState         CacheName = "STATE"         // 授权 State 缓存
+	CacheToken         CacheName = "TOKEN"         // 令牌缓存
+	CacheSession       CacheName = "SESSION"       // 会话（sid/sub）令牌索引
+	CacheUserinfo      CacheName = "USERINFO"      // 业务层 Userinfo 缓存
+	CacheIntrospection CacheName = "INTROSPECTION" // 业务层 Introspection 缓存
+	CacheFamily        CacheName = "FAMILY"        // 刷新令牌家族
+	CacheRefresh       CacheName = "REFRESH"       // 刷新锁与近期刷新结果
+	CacheLogout        CacheName = "LOGOUT"        // 登出令牌 jti 防重放
+	CacheLock          CacheName = "LOCK"          // 业务层上游请求分布式锁
+)
+
+// CacheConfig 单个缓存的配置
+type CacheConfig struct {
+	Name     CacheName     `json:"name"`    // 缓存名称
+	Enabled  bool          `json:"enabled"` // 是否启用
+	TTL      time.Duration `json:"ttl"`     // 默认有效期
+	MaxTTL   time.Duration `json:"max_ttl"` // 动态有效期上限，0 表示不限制
+	Prefix   string        `json:"prefix"`  // 键前缀，为空时使用 `xEnv.NoSqlPrefix`
+	Optional bool          `json:"-"`       // 是否允许关闭
+
+	StaleTTL        time.Duration `json:"stale_ttl"`          // 过期后仍可直接返回并后台刷新的时长（stale-while-revalidate）
+	StaleIfErrorTTL time.Duration `json:"stale_if_error_ttl"` // 过期后上游不可用时仍可返回的时长（stale-if-error）
+	Revalidate      bool          `json:"-"`                  // 是否支持过期重验证
+}
+
+// defaultCacheConfigs 各缓存的默认配置，Userinfo 与 Introspection 的启用状态默认跟随 `cache.business`。
+//
+// 默认的 MaxTTL 为动态有效期的上限，只配置 TTL 且超过默认上限时上限随 TTL 提高。
+var defaultCacheConfigs = []CacheConfig{
+	{Name: CacheState, Enabled: true, TTL: time.Minute * 15},
+	{Name: CacheToken, Enabled: true, TTL: time.Hour * 24 * 30},
+	{Name: CacheSession, Enabled: true, TTL: time.Hour * 24 * 30},
+	{Name: CacheUserinfo, TTL: time.Second * 30, Optional: true, Revalidate: true},
+	{Name: CacheIntrospection, TTL: time.Second * 30, MaxTTL: time.Second * 30, Optional: true},
+	{Name: CacheFamily, Enabled: true, TTL: time.Hour * 24 * 30},
+	{Name: CacheRefresh, Enabled: true, TTL: time.Second * 2},
+	{Name: CacheLogout, Enabled: true, TTL: time.Minute * 10},
+	{Name: CacheLock, Enabled: true, TTL: time.Second * 5},
+}
+
+// LoadCacheConfigs 按 SDK 配置的 `cache` 解析并校验全部缓存配置。
+//
+// 所有配置错误会一并返回，便于一次性修正；`cache.entries` 中未知的缓存名称同样视为错误。
+//
+// 参数:
+//   - cfg: SDK 缓存配置，`Business` 决定可关闭缓存（Userinfo 与 Introspection）的默认启用状态。
+//
+// 返回值:
+//   - []CacheConfig: 生效的缓存配置列表。
+//   - error: 配置非法时返回错误。
+func LoadCacheConfigs(cfg bSdkConfig.CacheConfig) ([]CacheConfig, error) {
+	list := make([]CacheConfig, 0, len(defaultCacheConfigs))
+	var errs []error
+	known := make(map[string]bool, len(defaultCacheConfigs))
+	for _, def := range defaultCacheConfigs {
+		known[def.Name.key()] = true
+		loaded, err := readCacheConfig(def, cfg)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		list = append(list, loaded)
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Entries)) {
+		if !known[name] {
+			errs = append(errs, fmt.Errorf("cache.entries.%s 不是已知的缓存", name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return list, nil
+}
+
+// GetCacheConfig 按 SDK 配置的 `cache` 获取指定缓存的配置，非法配置回退为默认值（启动时由 `LoadCacheConfigs` 报告）。
+func GetCacheConfig(cfg bSdkConfig.CacheConfig, name CacheName) CacheConfig {
+	for _, def := range defaultCacheConfigs {
+		if def.Name != name {
+			continue
+		}
+		if loaded, err := readCacheConfig(def, cfg); err == nil {
+			return loaded
+		}
+		def.Enabled = def.Enabled || (def.Optional && cfg.Business)
+		return def
+	}
+	return CacheConfig{Name: name, Enabled: true}
+}
+
+// newStoreCache 按缓存配置构建缓存基础结构。
+func newStoreCache(store bSdkStore.Store, cacheCfg bSdkConfig.CacheConfig, name CacheName) bSdkStore.Cache {
+	cfg := GetCacheConfig(cacheCfg, name)
+	return bSdkStore.Cache{
+		Store:   store,
+		TTL:     cfg.TTL,
+		MaxTTL:  cfg.MaxTTL,
+		Prefix:  cfg.Prefix,
+		Enabled: cfg.Enabled,
+
+		StaleTTL:        cfg.StaleTTL,
+		StaleIfErrorTTL: cfg.StaleIfErrorTTL,
+	}
+}
+
+// clampTTL 将动态有效期限制在缓存配置范围内，非正数时使用默认有效期。
+func clampTTL(ttl time.Duration, defaultTTL time.Duration, maxTTL time.Duration) time.Duration {
+	if ttl <= 0 {
+		return defaultTTL
+	}
+	if maxTTL > 0 && ttl > maxTTL {
+		return maxTTL
+	}
+	return ttl
+}
+
+// readCacheConfig 在默认配置基础上应用 `cache.entries` 中的覆盖项并校验。
+func readCacheConfig(def CacheConfig, cacheCfg bSdkConfig.CacheConfig) (CacheConfig, error) {
+	cfg := def
+	if def.Optional {
+		cfg.Enabled = cacheCfg.Business
+	}
+	key := def.Name.key()
+	entry, ok := cacheCfg.Entries[key]
+	if !ok {
+		return cfg, nil
+	}
+
+	var errs []error
+	switch {
+	case entry.TTL < 0:
+		errs = append(errs, fmt.Errorf("cache.entries.%s.ttl 不能为负数", key))
+	case entry.TTL > 0:
+		cfg.TTL = entry.TTL.Duration()
+	}
+	if entry.MaxTTL != nil {
+		if *entry.MaxTTL < 0 {
+			errs = append(errs, fmt.Errorf("cache.entries.%s.max_ttl 不能为负数", key))
+		} else {
+			cfg.MaxTTL = entry.MaxTTL.Duration()
+		}
+	}
+	if entry.Enabled != nil {
+		if !*entry.Enabled && !def.Optional {
+			errs = append(errs, fmt.Errorf("cache.entries.%s 为必需缓存，不可关闭", key))
+		} else {
+			cfg.Enabled = *entry.Enabled
+		}
+	}
+	if entry.Prefix != "" {
+		if strings.ContainsAny(entry.Prefix, " \t\r\n") {
+			errs = append(errs, fmt.Errorf("cache.entries.%s.prefix 不能包含空白字符: %q", key, entry.Prefix))
+		} else {
+			cfg.Prefix = entry.Prefix
+		}
+	}
+	for _, stale := range []struct {
+		option string
+		value  bSdkConfig.Duration
+		target *time.Duration
+	}{{"stale_ttl", entry.StaleTTL, &cfg.StaleTTL}, {"stale_if_error_ttl", entry.StaleIfErrorTTL, &cfg.StaleIfErrorTTL}} {
+		switch {
+		case stale.value == 0:
+		case !def.Revalidate:
+			errs = append(errs, fmt.Errorf("cache.entries.%s 不支持 %s", key, stale.option))
+		case stale.value < 0:
+			errs = append(errs, fmt.Errorf("cache.entries.%s.%s 不能为负数", key, stale.option))
+		default:
+			*stale.target = stale.value.Duration()
+		}
+	}
+	switch {
+	case cfg.MaxTTL <= 0 || cfg.TTL <= cfg.MaxTTL:
+	case entry.MaxTTL == nil:
+		cfg.MaxTTL = cfg.TTL
+	default:
+		errs = append(errs, fmt.Errorf("cache.entries.%s 的 ttl（%s）不能大于 max_ttl（%s）", key, cfg.TTL, cfg.MaxTTL))
+	}
+
+	if len(errs) > 0 {
+		return def, errors.Join(errs...)
+	}
+	return cfg, nil
+}
+
+// key 返回缓存在 SDK 配置 `cache.entries` 中的键。
+func (n CacheName) key() string {
+	return strings.ToLower(string(n))
+}
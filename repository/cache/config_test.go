@@ -0,0 +1,140 @@
+package bSdkCache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+)
+
+func TestReadCacheConfig(t *testing.T) {
+	def := func(name CacheName) CacheConfig {
+		for _, cfg := range defaultCacheConfigs {
+			if cfg.Name == name {
+				return cfg
+			}
+		}
+		t.Fatalf("未找到默认配置: %s", name)
+		return CacheConfig{}
+	}
+
+	// envCache 按环境变量读取 SDK 缓存配置
+	envCache := func(t *testing.T) bSdkConfig.CacheConfig {
+		t.Helper()
+		cfg, err := bSdkConfig.LoadEnv()
+		if err != nil {
+			t.Fatalf("读取环境变量失败: %v", err)
+		}
+		return cfg.Cache
+	}
+
+	t.Run("默认值与业务缓存开关", func(t *testing.T) {
+		cfg, err := readCacheConfig(def(CacheUserinfo), bSdkConfig.CacheConfig{Business: true})
+		if err != nil || !cfg.Enabled || cfg.TTL != 30*time.Second {
+			t.Fatalf("默认配置不正确: %+v %v", cfg, err)
+		}
+	})
+
+	t.Run("环境变量覆盖", func(t *testing.T) {
+		t.Setenv("SSO_CACHE_TOKEN_TTL", "168h")
+		t.Setenv("SSO_CACHE_TOKEN_PREFIX", "app:")
+		t.Setenv("SSO_CACHE_INTROSPECTION_TTL", "60")
+		t.Setenv("SSO_CACHE_INTROSPECTION_MAX_TTL", "5m")
+		t.Setenv("SSO_CACHE_INTROSPECTION_ENABLED", "false")
+
+		token, err := readCacheConfig(def(CacheToken), envCache(t))
+		if err != nil || token.TTL != 168*time.Hour || token.Prefix != "app:" {
+			t.Fatalf("令牌缓存配置不正确: %+v %v", token, err)
+		}
+		introspection, err := readCacheConfig(def(CacheIntrospection), envCache(t))
+		if err != nil || introspection.TTL != time.Minute || introspection.MaxTTL != 5*time.Minute || introspection.Enabled {
+			t.Fatalf("Introspection 缓存配置不正确: %+v %v", introspection, err)
+		}
+	})
+
+	t.Run("SDK 配置覆盖", func(t *testing.T) {
+		cacheCfg := bSdkConfig.New(
+			bSdkConfig.WithBusinessCache(true),
+			bSdkConfig.WithCacheEntry("token", bSdkConfig.CacheEntryConfig{TTL: bSdkConfig.Duration(time.Hour), Prefix: "svc:"}),
+		).Cache
+		token := GetCacheConfig(cacheCfg, CacheToken)
+		if token.TTL != time.Hour || token.MaxTTL != 0 || token.Prefix != "svc:" {
+			t.Fatalf("令牌缓存配置不正确: %+v", token)
+		}
+		if !GetCacheConfig(cacheCfg, CacheUserinfo).Enabled {
+			t.Fatalf("Userinfo 缓存应跟随 cache.business 启用")
+		}
+		if state := GetCacheConfig(cacheCfg, CacheState); state.TTL != 15*time.Minute || state.Prefix != "" {
+			t.Fatalf("未覆盖的缓存应使用默认值: %+v", state)
+		}
+	})
+
+	t.Run("仅配置 TTL 时上限随之提高", func(t *testing.T) {
+		t.Setenv("SSO_CACHE_INTROSPECTION_TTL", "60")
+
+		introspection, err := readCacheConfig(def(CacheIntrospection), envCache(t))
+		if err != nil || introspection.TTL != time.Minute || introspection.MaxTTL != time.Minute {
+			t.Fatalf("未显式配置 MAX_TTL 时上限应随 TTL 提高: %+v %v", introspection, err)
+		}
+
+		t.Setenv("SSO_CACHE_INTROSPECTION_TTL", "")
+		introspection, err = readCacheConfig(def(CacheIntrospection), envCache(t))
+		if err != nil || introspection.MaxTTL != 30*time.Second {
+			t.Fatalf("Introspection 缓存默认上限应为 30 秒: %+v %v", introspection, err)
+		}
+	})
+
+	t.Run("非法配置汇总报错", func(t *testing.T) {
+		t.Setenv("SSO_CACHE_STATE_TTL", "abc")
+		t.Setenv("SSO_CACHE_STATE_ENABLED", "false")
+		t.Setenv("SSO_CACHE_USERINFO_TTL", "2m")
+		t.Setenv("SSO_CACHE_USERINFO_MAX_TTL", "1m")
+		t.Setenv("SSO_CACHE_TOKEN_STALE_TTL", "1m")
+		t.Setenv("SSO_CACHE_SESION_TTL", "1m")
+
+		cfg, err := bSdkConfig.LoadEnv()
+		if err == nil || !strings.Contains(err.Error(), "SSO_CACHE_STATE_TTL") {
+			t.Fatalf("格式非法的环境变量应在读取时报错: %v", err)
+		}
+		_, err = LoadCacheConfigs(cfg.Cache)
+		if err == nil {
+			t.Fatalf("期望配置校验失败")
+		}
+		for _, want := range []string{"cache.entries.state 为必需缓存", "cache.entries.userinfo 的 ttl", "cache.entries.token 不支持 stale_ttl", "cache.entries.sesion 不是已知的缓存"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("错误信息缺少 %q: %v", want, err)
+			}
+		}
+		if GetCacheConfig(cfg.Cache, CacheState).TTL != 15*time.Minute {
+			t.Fatalf("非法配置应回退为默认值")
+		}
+	})
+
+	t.Run("过期重验证宽限期", func(t *testing.T) {
+		t.Setenv("SSO_CACHE_USERINFO_STALE_TTL", "30s")
+		t.Setenv("SSO_CACHE_USERINFO_STALE_IF_ERROR_TTL", "10m")
+
+		cfg, err := readCacheConfig(def(CacheUserinfo), envCache(t))
+		if err != nil || cfg.StaleTTL != 30*time.Second || cfg.StaleIfErrorTTL != 10*time.Minute {
+			t.Fatalf("宽限期配置不正确: %+v %v", cfg, err)
+		}
+		cache := UserinfoCache(bSdkStore.Cache{TTL: cfg.TTL, StaleTTL: cfg.StaleTTL, StaleIfErrorTTL: cfg.StaleIfErrorTTL})
+		if got := cache.Retention(); got != cfg.TTL+10*time.Minute {
+			t.Fatalf("保留时长应为 TTL 加较长的宽限期，实际 %s", got)
+		}
+	})
+
+	t.Run("动态有效期截断", func(t *testing.T) {
+		if got := clampTTL(0, time.Second, time.Minute); got != time.Second {
+			t.Fatalf("非正数应使用默认值，实际 %s", got)
+		}
+		if got := clampTTL(time.Hour, time.Second, time.Minute); got != time.Minute {
+			t.Fatalf("超过上限应截断，实际 %s", got)
+		}
+		if got := clampTTL(time.Hour, time.Second, 0); got != time.Hour {
+			t.Fatalf("上限为 0 时不截断，实际 %s", got)
+		}
+	})
+}
@@ -2,41 +2,39 @@ package bSdkCache
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	xUtil "github.com/bamboo-services/bamboo-base-go/common/utility"
-	xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
-	xCache "github.com/bamboo-services/bamboo-base-go/major/cache"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
-	"github.com/redis/go-redis/v9"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
 )
 
 // IntrospectionCache 业务层 Introspection 缓存管理器
 //
-// 该类型封装了与 Redis 的交互，用于缓存 SSO 令牌自省结果。
+// 该类型封装了与状态存储的交互，用于缓存 SSO 令牌自省结果。
 // 通过控制键值对的生命周期（TTL）来减少对 SSO 服务的重复请求。
-type IntrospectionCache xCache.Cache
+type IntrospectionCache bSdkStore.Cache
 
 // NewIntrospectionCache 创建并初始化一个 Introspection 缓存管理器实例
 //
 // 参数:
-//   - rdb: 已初始化的 Redis 客户端连接，用于底层数据交互。
+//   - store: 已初始化的状态存储，用于底层数据交互。
+//   - cfg: SDK 缓存配置，通常来自 SDK 配置的 `cache`。
 //
 // 返回值:
-//   - *IntrospectionCache: 配置完成的缓存管理器指针，默认 TTL 为 15 分钟。
-func NewIntrospectionCache(rdb *redis.Client) *IntrospectionCache {
-	return &IntrospectionCache{
-		RDB: rdb,
-		TTL: time.Second * 30,
-	}
+//   - *IntrospectionCache: 配置完成的缓存管理器指针，默认 TTL 与上限均为 30 秒，可通过 `cache.entries.introspection`（`SSO_CACHE_INTROSPECTION_*`）配置。
+func NewIntrospectionCache(store bSdkStore.Store, cfg bSdkConfig.CacheConfig) *IntrospectionCache {
+	c := IntrospectionCache(newStoreCache(store, cfg, CacheIntrospection))
+	return &c
 }
 
 // Get 从缓存中获取指定字段的值
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回未命中。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回未命中。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -48,7 +46,7 @@ func NewIntrospectionCache(rdb *redis.Client) *IntrospectionCache {
 //   - bool: 是否命中缓存（true 表示命中，false 表示未命中）。
 //   - error: 操作过程中发生的错误。
 func (c *IntrospectionCache) Get(ctx context.Context, key string, field string) (*string, bool, error) {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil, false, nil
 	}
 
@@ -59,11 +57,8 @@ func (c *IntrospectionCache) Get(ctx context.Context, key string, field string)
 		return nil, false, fmt.Errorf("字段为空")
 	}
 
-	value, err := c.RDB.HGet(ctx, c.buildKey(key), field).Result()
-	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return nil, false, nil
-		}
+	value, ok, err := localIntrospection.hget(ctx, c.Store, c.buildKey(key), field)
+	if err != nil || !ok {
 		return nil, false, err
 	}
 
@@ -72,7 +67,7 @@ func (c *IntrospectionCache) Get(ctx context.Context, key string, field string)
 
 // GetAllStruct 从缓存中获取完整的 Introspection 数据结构
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回未命中。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回未命中。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -83,7 +78,7 @@ func (c *IntrospectionCache) Get(ctx context.Context, key string, field string)
 //   - bool: 是否命中缓存（true 表示命中，false 表示未命中）。
 //   - error: 操作过程中发生的错误。
 func (c *IntrospectionCache) GetAllStruct(ctx context.Context, key string) (*bSdkModels.CacheBusinessIntrospection, bool, error) {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil, false, nil
 	}
 
@@ -91,7 +86,7 @@ func (c *IntrospectionCache) GetAllStruct(ctx context.Context, key string) (*bSd
 		return nil, false, fmt.Errorf("缓存键为空")
 	}
 
-	result, err := c.RDB.HGetAll(ctx, c.buildKey(key)).Result()
+	result, err := localIntrospection.hgetAll(ctx, c.Store, c.buildKey(key))
 	if err != nil {
 		return nil, false, err
 	}
@@ -118,7 +113,7 @@ func (c *IntrospectionCache) GetAllStruct(ctx context.Context, key string) (*bSd
 
 // Set 设置指定字段的值
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回 nil。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回 nil。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -129,7 +124,7 @@ func (c *IntrospectionCache) GetAllStruct(ctx context.Context, key string) (*bSd
 // 返回值:
 //   - error: 操作过程中发生的错误。
 func (c *IntrospectionCache) Set(ctx context.Context, key string, field string, value *string) error {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil
 	}
 
@@ -143,17 +138,14 @@ func (c *IntrospectionCache) Set(ctx context.Context, key string, field string,
 		return fmt.Errorf("缓存值为空")
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(key), field, *value).Err(); err != nil {
-		return err
-	}
-
-	return c.RDB.Expire(ctx, c.buildKey(key), c.TTL).Err()
+	defer localIntrospection.invalidate(ctx, c.Store, c.buildKey(key))
+	return c.Store.HSet(ctx, c.buildKey(key), map[string]string{field: *value}, c.TTL)
 }
 
 // SetAllStruct 将完整的 Introspection 数据结构存储到缓存
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回 nil。
-// TTL 会根据令牌的 ExpiresIn 字段动态计算，最大不超过 15 分钟。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回 nil。
+// TTL 会根据令牌的 ExpiresIn 字段动态计算，最大不超过 MaxTTL。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -163,7 +155,7 @@ func (c *IntrospectionCache) Set(ctx context.Context, key string, field string,
 // 返回值:
 //   - error: 操作过程中发生的错误。
 func (c *IntrospectionCache) SetAllStruct(ctx context.Context, key string, introspection *bSdkModels.CacheBusinessIntrospection) error {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil
 	}
 
@@ -174,12 +166,12 @@ func (c *IntrospectionCache) SetAllStruct(ctx context.Context, key string, intro
 		return fmt.Errorf("缓存值为空")
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(key), introspection).Err(); err != nil {
+	values, err := bSdkStore.EncodeHash(introspection)
+	if err != nil {
 		return err
 	}
-
-	ttl := c.calculateTTL(introspection.ExpiresIn)
-	return c.RDB.Expire(ctx, c.buildKey(key), ttl).Err()
+	defer localIntrospection.invalidate(ctx, c.Store, c.buildKey(key))
+	return c.Store.HSet(ctx, c.buildKey(key), values, c.calculateTTL(introspection.ExpiresIn))
 }
 
 // GetAll 从缓存中获取所有字段和值
@@ -196,12 +188,12 @@ func (c *IntrospectionCache) GetAll(ctx context.Context, key string) (map[string
 		return nil, fmt.Errorf("缓存键为空")
 	}
 
-	return c.RDB.HGetAll(ctx, c.buildKey(key)).Result()
+	return localIntrospection.hgetAll(ctx, c.Store, c.buildKey(key))
 }
 
 // SetAll 批量设置多个字段的值
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回 nil。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回 nil。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -211,7 +203,7 @@ func (c *IntrospectionCache) GetAll(ctx context.Context, key string) (map[string
 // 返回值:
 //   - error: 操作过程中发生的错误。
 func (c *IntrospectionCache) SetAll(ctx context.Context, key string, fields map[string]*string) error {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil
 	}
 
@@ -222,7 +214,7 @@ func (c *IntrospectionCache) SetAll(ctx context.Context, key string, fields map[
 		return nil
 	}
 
-	values := make(map[string]interface{}, len(fields))
+	values := make(map[string]string, len(fields))
 	for field, value := range fields {
 		if field == "" {
 			return fmt.Errorf("字段为空")
@@ -233,10 +225,8 @@ func (c *IntrospectionCache) SetAll(ctx context.Context, key string, fields map[
 		values[field] = *value
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(key), values).Err(); err != nil {
-		return err
-	}
-	return c.RDB.Expire(ctx, c.buildKey(key), c.TTL).Err()
+	defer localIntrospection.invalidate(ctx, c.Store, c.buildKey(key))
+	return c.Store.HSet(ctx, c.buildKey(key), values, c.TTL)
 }
 
 // Exists 检查指定字段是否存在
@@ -257,7 +247,7 @@ func (c *IntrospectionCache) Exists(ctx context.Context, key string, field strin
 		return false, fmt.Errorf("字段为空")
 	}
 
-	return c.RDB.HExists(ctx, c.buildKey(key), field).Result()
+	return c.Store.HExists(ctx, c.buildKey(key), field)
 }
 
 // Remove 从缓存中移除指定的字段
@@ -277,7 +267,8 @@ func (c *IntrospectionCache) Remove(ctx context.Context, key string, fields ...s
 		return nil
 	}
 
-	return c.RDB.HDel(ctx, c.buildKey(key), fields...).Err()
+	defer localIntrospection.invalidate(ctx, c.Store, c.buildKey(key))
+	return c.Store.HDel(ctx, c.buildKey(key), fields...)
 }
 
 // Delete 删除指定令牌的缓存数据
@@ -293,38 +284,117 @@ func (c *IntrospectionCache) Delete(ctx context.Context, key string) error {
 		return fmt.Errorf("缓存键为空")
 	}
 
-	return c.RDB.Del(ctx, c.buildKey(key)).Err()
+	defer localIntrospection.invalidate(ctx, c.Store, c.buildKey(key))
+	return c.Store.Delete(ctx, c.buildKey(key))
 }
 
-// calculateTTL 计算动态 TTL
+// MarkInvalid 写入无效令牌负缓存
 //
-// 根据令牌的 ExpiresIn 字段计算缓存的 TTL，最大不超过 15 分钟。
+// 负缓存与正常的自省结果分开存放，使用独立的较短 TTL，不受 `SSO_BUSINESS_CACHE` 开关影响。
 //
 // 参数:
-//   - expiresIn: 令牌的剩余有效时间（秒）。
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - key: 缓存键（格式为 请求类型:令牌指纹）。
+//   - ttl: 负缓存有效期。
 //
 // 返回值:
-//   - time.Duration: 计算后的 TTL。
-func (c *IntrospectionCache) calculateTTL(expiresIn int64) time.Duration {
-	if expiresIn <= 0 {
-		return c.TTL
+//   - error: 操作过程中发生的错误。
+func (c *IntrospectionCache) MarkInvalid(ctx context.Context, key string, ttl time.Duration) error {
+	if key == "" {
+		return fmt.Errorf("缓存键为空")
+	}
+
+	defer localIntrospection.invalidate(ctx, c.Store, c.invalidKey(key))
+	return c.Store.HSet(ctx, c.invalidKey(key), map[string]string{"invalid": "1"}, ttl)
+}
+
+// IsInvalid 检查令牌是否命中无效令牌负缓存
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - key: 缓存键（格式为 请求类型:令牌指纹）。
+//
+// 返回值:
+//   - bool: 是否命中负缓存。
+//   - error: 操作过程中发生的错误。
+func (c *IntrospectionCache) IsInvalid(ctx context.Context, key string) (bool, error) {
+	if key == "" {
+		return false, fmt.Errorf("缓存键为空")
+	}
+
+	_, ok, err := localIntrospection.hget(ctx, c.Store, c.invalidKey(key), "invalid")
+	return ok, err
+}
+
+// IncrInvalid 为限流维度累加一次无效令牌计数
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - scope: 限流维度（如 ip:客户端 IP）。
+//   - window: 计数窗口，首次计数时设置。
+//
+// 返回值:
+//   - int64: 当前窗口内的计数。
+//   - error: 操作过程中发生的错误。
+func (c *IntrospectionCache) IncrInvalid(ctx context.Context, scope string, window time.Duration) (int64, error) {
+	if scope == "" {
+		return 0, fmt.Errorf("限流维度为空")
+	}
+
+	return c.Store.Incr(ctx, c.limitKey(scope), window)
+}
+
+// InvalidCount 读取限流维度在当前窗口内的无效令牌计数
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - scope: 限流维度（如 ip:客户端 IP）。
+//
+// 返回值:
+//   - int64: 当前窗口内的计数，不存在时为 0。
+//   - error: 操作过程中发生的错误。
+func (c *IntrospectionCache) InvalidCount(ctx context.Context, scope string) (int64, error) {
+	if scope == "" {
+		return 0, fmt.Errorf("限流维度为空")
 	}
 
-	tokenTTL := time.Duration(expiresIn) * time.Second
-	if tokenTTL > c.TTL {
-		return c.TTL
+	value, ok, err := c.Store.Get(ctx, c.limitKey(scope))
+	if err != nil || !ok {
+		return 0, err
 	}
+	return strconv.ParseInt(value, 10, 64)
+}
 
-	return tokenTTL
+// calculateTTL 计算动态 TTL
+//
+// 根据令牌的 ExpiresIn 字段计算缓存的 TTL，未知时使用默认 TTL，最大不超过 MaxTTL（默认 30 秒）。
+//
+// 参数:
+//   - expiresIn: 令牌的剩余有效时间（秒）。
+//
+// 返回值:
+//   - time.Duration: 计算后的 TTL。
+func (c *IntrospectionCache) calculateTTL(expiresIn int64) time.Duration {
+	return clampTTL(time.Duration(expiresIn)*time.Second, c.TTL, c.MaxTTL)
 }
 
-// buildKey 构建 Redis 缓存键
+// buildKey 构建缓存键
 //
 // 参数:
-//   - key: 缓存键（已组合的键，格式为 tokenType:token）。
+//   - key: 缓存键（已组合的键，格式为 tokenType:令牌指纹）。
 //
 // 返回值:
 //   - string: 格式化后的缓存键。
 func (c *IntrospectionCache) buildKey(key string) string {
-	return bSdkConst.RedisBusinessIntrospection.Get(key).String()
+	return bSdkConst.RedisBusinessIntrospection.GetWithPrefix(c.Prefix, key).String()
+}
+
+// invalidKey 构建无效令牌负缓存键，以令牌指纹结尾，可被 `InvalidateLocal` 按指纹失效。
+func (c *IntrospectionCache) invalidKey(key string) string {
+	return c.buildKey("invalid:" + key)
+}
+
+// limitKey 构建无效令牌限流计数键。
+func (c *IntrospectionCache) limitKey(scope string) string {
+	return c.buildKey("limit:" + scope)
 }
@@ -0,0 +1,360 @@
+package bSdkCache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+)
+
+// LocalCache 进程内 LRU 缓存
+//
+// 该类型作为状态存储前的一级缓存，保存哈希键的完整字段快照（令牌类字段仍为密文）。
+// 容量达到上限时淘汰最久未访问的条目，条目超过 TTL 后视为未命中。
+// 条目同时按键末段的令牌指纹建立索引，按键或按指纹删除都无需遍历全部条目。
+type LocalCache struct {
+	name     string
+	capacity int
+	ttl      time.Duration
+
+	mu            sync.Mutex
+	order         *list.List
+	entries       map[string]*list.Element
+	byFingerprint map[string]map[string]struct{}
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// LocalCacheStats 进程内缓存的运行指标
+type LocalCacheStats struct {
+	Name      string `json:"name"`      // 缓存名称
+	Size      int    `json:"size"`      // 当前条目数
+	Capacity  int    `json:"capacity"`  // 最大条目数
+	Hits      uint64 `json:"hits"`      // 命中次数
+	Misses    uint64 `json:"misses"`    // 未命中次数
+	Evictions uint64 `json:"evictions"` // 因容量淘汰的条目数
+}
+
+type localEntry struct {
+	key      string
+	values   map[string]string
+	expireAt time.Time
+}
+
+// NewLocalCache 创建一个进程内 LRU 缓存
+//
+// 参数:
+//   - name: 缓存名称，用于指标输出。
+//   - capacity: 最大条目数，小于等于 0 时使用默认值。
+//   - ttl: 条目有效期，小于等于 0 时使用默认值。
+//
+// 返回值:
+//   - *LocalCache: 初始化完成的缓存指针。
+func NewLocalCache(name string, capacity int, ttl time.Duration) *LocalCache {
+	if capacity <= 0 {
+		capacity = bSdkConst.DefaultLocalCacheSize
+	}
+	if ttl <= 0 {
+		ttl = time.Duration(bSdkConst.DefaultLocalCacheTTL) * time.Second
+	}
+	return &LocalCache{
+		name:          name,
+		capacity:      capacity,
+		ttl:           ttl,
+		order:         list.New(),
+		entries:       make(map[string]*list.Element),
+		byFingerprint: make(map[string]map[string]struct{}),
+	}
+}
+
+// Get 读取条目的字段快照副本，未命中或已过期时返回 false。
+func (c *LocalCache) Get(key string) (map[string]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	entry := elem.Value.(*localEntry)
+	if time.Now().After(entry.expireAt) {
+		c.removeElement(elem)
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.hits.Add(1)
+	return copyValues(entry.values), true
+}
+
+// Set 写入条目的字段快照，必要时淘汰最久未访问的条目。
+func (c *LocalCache) Set(key string, values map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &localEntry{key: key, values: copyValues(values), expireAt: time.Now().Add(c.ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	fingerprint := keyFingerprint(key)
+	if c.byFingerprint[fingerprint] == nil {
+		c.byFingerprint[fingerprint] = make(map[string]struct{})
+	}
+	c.byFingerprint[fingerprint][key] = struct{}{}
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// Delete 删除指定条目。
+func (c *LocalCache) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// DeleteFingerprint 删除键以 `:<fingerprint>` 结尾的所有条目。
+func (c *LocalCache) DeleteFingerprint(fingerprint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.byFingerprint[fingerprint] {
+		c.removeElement(c.entries[key])
+	}
+}
+
+// Purge 清空全部条目，指标计数保持不变。
+func (c *LocalCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.byFingerprint = make(map[string]map[string]struct{})
+}
+
+// Stats 返回当前的运行指标。
+func (c *LocalCache) Stats() LocalCacheStats {
+	c.mu.Lock()
+	size := c.order.Len()
+	c.mu.Unlock()
+
+	return LocalCacheStats{
+		Name:      c.name,
+		Size:      size,
+		Capacity:  c.capacity,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+func (c *LocalCache) removeElement(elem *list.Element) {
+	key := elem.Value.(*localEntry).key
+	c.order.Remove(elem)
+	delete(c.entries, key)
+
+	fingerprint := keyFingerprint(key)
+	if keys := c.byFingerprint[fingerprint]; keys != nil {
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(c.byFingerprint, fingerprint)
+		}
+	}
+}
+
+// keyFingerprint 返回键最后一个 `:` 之后的部分，SDK 的令牌类缓存键均以令牌指纹结尾。
+func keyFingerprint(key string) string {
+	return key[strings.LastIndex(key, ":")+1:]
+}
+
+func copyValues(values map[string]string) map[string]string {
+	copied := make(map[string]string, len(values))
+	for field, value := range values {
+		copied[field] = value
+	}
+	return copied
+}
+
+// LocalStore 带进程内一级缓存的状态存储
+//
+// 包装 SDK 依赖的根状态存储，为令牌、Userinfo 与 Introspection 缓存各自维护一个进程内 LRU 一级缓存，
+// 存储调用直接透传给底层存储。一级缓存归属于该实例：同一底层存储之上的租户命名空间与身份提供方前缀包装
+// 共享它，条目以底层存储中的实际键索引（参见 `bSdkStore.KeyOf`）；互不相关的 SDK 实例各自持有独立的一级缓存。
+type LocalStore struct {
+	bSdkStore.Store
+
+	tiers [localTierCount]*LocalCache
+}
+
+// localTier 一级缓存类别
+type localTier int
+
+const (
+	localTokens localTier = iota
+	localUserinfo
+	localIntrospection
+	localTierCount // 类别数量，需保持在最后
+)
+
+var localTierNames = [localTierCount]string{"oauth_token", "userinfo", "introspection"}
+
+// NewLocalStore 按一级缓存配置包装状态存储
+//
+// 应在创建 SDK 依赖时对根状态存储调用一次（`storage` 启动节点会自动处理），
+// 在租户或身份提供方包装之上调用时一级缓存键将不含对应的命名空间。
+//
+// 参数:
+//   - store: 根状态存储，已是 `*LocalStore` 时直接返回。
+//   - cfg: 一级缓存配置（`Config.Cache.Local`），未启用时直接返回 store。
+//
+// 返回值:
+//   - bSdkStore.Store: 包装后的状态存储。
+func NewLocalStore(store bSdkStore.Store, cfg bSdkConfig.LocalCacheConfig) bSdkStore.Store {
+	if store == nil || !cfg.Enabled {
+		return store
+	}
+	if _, ok := store.(*LocalStore); ok {
+		return store
+	}
+	local := &LocalStore{Store: store}
+	for tier, name := range localTierNames {
+		local.tiers[tier] = NewLocalCache(name, cfg.Size, cfg.TTL.Duration())
+	}
+	return local
+}
+
+// Metrics 返回各一级缓存的运行指标。
+func (s *LocalStore) Metrics() []LocalCacheStats {
+	stats := make([]LocalCacheStats, 0, len(s.tiers))
+	for _, local := range s.tiers {
+		stats = append(stats, local.Stats())
+	}
+	return stats
+}
+
+// Close 停止底层存储的后台清理协程（如 `*bSdkStore.GormStore`），底层存储不支持时不做任何操作。
+func (s *LocalStore) Close() {
+	if closer, ok := s.Store.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// Invalidate 按令牌指纹删除令牌、Userinfo 与 Introspection 条目。
+//
+// 本实例的写入与删除会自动失效对应条目，该方法用于处理其他实例发起的变更（如吊销广播）。
+// 广播不携带租户与身份提供方，因此删除所有命名空间下以该指纹结尾的条目。
+func (s *LocalStore) Invalidate(fingerprints ...string) {
+	for _, fingerprint := range fingerprints {
+		if fingerprint == "" {
+			continue
+		}
+		for _, local := range s.tiers {
+			local.DeleteFingerprint(fingerprint)
+		}
+	}
+}
+
+// localStoreOf 沿租户与身份提供方包装查找 store 所基于的 `*LocalStore`，未启用一级缓存时返回 nil。
+func localStoreOf(store bSdkStore.Store) *LocalStore {
+	for {
+		switch wrapped := store.(type) {
+		case *LocalStore:
+			return wrapped
+		case *bSdkStore.PrefixStore:
+			store = wrapped.Store
+		case *bSdkStore.NamespaceStore:
+			store = wrapped.Store
+		default:
+			return nil
+		}
+	}
+}
+
+// get 返回 store 所属的一级缓存，未启用时返回 nil。
+func (t localTier) get(store bSdkStore.Store) *LocalCache {
+	if local := localStoreOf(store); local != nil {
+		return local.tiers[t]
+	}
+	return nil
+}
+
+// hgetAll 优先读取一级缓存，未命中时回源状态存储并回填非空结果。
+//
+// 一级缓存以状态存储中的实际键索引（参见 `bSdkStore.KeyOf`），不同租户与命名身份提供方的条目互不可见。
+func (t localTier) hgetAll(ctx context.Context, store bSdkStore.Store, key string) (map[string]string, error) {
+	local := t.get(store)
+	if local == nil {
+		return store.HGetAll(ctx, key)
+	}
+	localKey := bSdkStore.KeyOf(ctx, store, key)
+	if values, ok := local.Get(localKey); ok {
+		return values, nil
+	}
+
+	values, err := store.HGetAll(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) > 0 {
+		local.Set(localKey, values)
+	}
+	return values, nil
+}
+
+// hget 优先从一级缓存的字段快照读取单个字段，未命中时回源状态存储。
+func (t localTier) hget(ctx context.Context, store bSdkStore.Store, key string, field string) (string, bool, error) {
+	if local := t.get(store); local != nil {
+		if values, ok := local.Get(bSdkStore.KeyOf(ctx, store, key)); ok {
+			value, exists := values[field]
+			return value, exists, nil
+		}
+	}
+	return store.HGet(ctx, key, field)
+}
+
+// invalidate 删除一级缓存中的指定键，在对应的状态存储写入或删除时调用。
+func (t localTier) invalidate(ctx context.Context, store bSdkStore.Store, keys ...string) {
+	local := t.get(store)
+	if local == nil {
+		return
+	}
+	for _, key := range keys {
+		local.Delete(bSdkStore.KeyOf(ctx, store, key))
+	}
+}
+
+// LocalCacheMetrics 返回 store 所属的一级缓存指标，未启用一级缓存时返回空切片。
+func LocalCacheMetrics(store bSdkStore.Store) []LocalCacheStats {
+	if local := localStoreOf(store); local != nil {
+		return local.Metrics()
+	}
+	return []LocalCacheStats{}
+}
+
+// InvalidateLocal 按令牌指纹删除 store 所属一级缓存中的条目（参见 `LocalStore.Invalidate`），未启用一级缓存时无操作。
+func InvalidateLocal(store bSdkStore.Store, fingerprints ...string) {
+	if local := localStoreOf(store); local != nil {
+		local.Invalidate(fingerprints...)
+	}
+}
@@ -0,0 +1,153 @@
+package bSdkCache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+)
+
+func TestLocalCache(t *testing.T) {
+	t.Run("命中与副本隔离", func(t *testing.T) {
+		cache := NewLocalCache("test", 2, time.Second)
+		cache.Set("a", map[string]string{"f": "1"})
+
+		values, ok := cache.Get("a")
+		if !ok || values["f"] != "1" {
+			t.Fatalf("读取结果不正确: %v %v", values, ok)
+		}
+		values["f"] = "changed"
+		if values, _ := cache.Get("a"); values["f"] != "1" {
+			t.Fatalf("修改返回值不应影响缓存: %v", values)
+		}
+		if _, ok := cache.Get("missing"); ok {
+			t.Fatalf("不存在的键不应命中")
+		}
+
+		stats := cache.Stats()
+		if stats.Hits != 2 || stats.Misses != 1 || stats.Size != 1 {
+			t.Fatalf("指标不正确: %+v", stats)
+		}
+	})
+
+	t.Run("容量淘汰最久未访问条目", func(t *testing.T) {
+		cache := NewLocalCache("test", 2, time.Second)
+		cache.Set("a", map[string]string{"f": "1"})
+		cache.Set("b", map[string]string{"f": "2"})
+		cache.Get("a")
+		cache.Set("c", map[string]string{"f": "3"})
+
+		if _, ok := cache.Get("b"); ok {
+			t.Fatalf("最久未访问的条目应被淘汰")
+		}
+		if _, ok := cache.Get("a"); !ok {
+			t.Fatalf("最近访问的条目应保留")
+		}
+		if stats := cache.Stats(); stats.Evictions != 1 || stats.Size != 2 {
+			t.Fatalf("淘汰指标不正确: %+v", stats)
+		}
+	})
+
+	t.Run("过期与失效", func(t *testing.T) {
+		cache := NewLocalCache("test", 10, 20*time.Millisecond)
+		cache.Set("expire", map[string]string{"f": "1"})
+		time.Sleep(30 * time.Millisecond)
+		if _, ok := cache.Get("expire"); ok {
+			t.Fatalf("过期条目不应命中")
+		}
+
+		cache.Set("bearer:fp1", map[string]string{"f": "1"})
+		cache.Set("basic:fp1", map[string]string{"f": "1"})
+		cache.Set("bearer:fp2", map[string]string{"f": "1"})
+		cache.DeleteFingerprint("fp1")
+		if stats := cache.Stats(); stats.Size != 1 {
+			t.Fatalf("按条件失效后应剩余 1 个条目: %+v", stats)
+		}
+
+		cache.Delete("bearer:fp2")
+		if _, ok := cache.Get("bearer:fp2"); ok {
+			t.Fatalf("已删除的条目不应命中")
+		}
+	})
+}
+
+type localNamespaceKey struct{}
+
+func TestLocalTier(t *testing.T) {
+	raw := bSdkStore.NewMemoryStore(0)
+	defer raw.Close()
+	root := NewLocalStore(raw, bSdkConfig.LocalCacheConfig{Enabled: true, Size: 10, TTL: bSdkConfig.Duration(time.Minute)})
+	store := bSdkStore.NewNamespaceStore(root, func(ctx context.Context) string {
+		ns, _ := ctx.Value(localNamespaceKey{}).(string)
+		return ns
+	})
+	provider := bSdkStore.NewPrefixStore(store, "p:")
+
+	bg := context.Background()
+	tenantA := context.WithValue(bg, localNamespaceKey{}, "tenant:a:")
+	tenantB := context.WithValue(bg, localNamespaceKey{}, "tenant:b:")
+	key := "oauth:tk:fp1"
+	_ = raw.HSet(bg, "tenant:a:"+key, map[string]string{"f": "a"}, time.Minute)
+	_ = raw.HSet(bg, "tenant:b:"+key, map[string]string{"f": "b"}, time.Minute)
+	_ = raw.HSet(bg, "tenant:a:p:"+key, map[string]string{"f": "pa"}, time.Minute)
+
+	t.Run("按实际键隔离租户与身份提供方", func(t *testing.T) {
+		for _, tc := range []struct {
+			ctx   context.Context
+			store bSdkStore.Store
+			want  string
+		}{
+			{tenantA, store, "a"},
+			{tenantB, store, "b"},
+			{tenantA, provider, "pa"},
+			{tenantA, store, "a"},
+		} {
+			values, err := localTokens.hgetAll(tc.ctx, tc.store, key)
+			if err != nil || values["f"] != tc.want {
+				t.Fatalf("期望读取到 %q，实际 %v, %v", tc.want, values, err)
+			}
+		}
+		if stats := localTokens.get(store).Stats(); stats.Size != 3 || stats.Hits != 1 {
+			t.Fatalf("期望缓存 3 个条目并命中 1 次: %+v", stats)
+		}
+	})
+
+	t.Run("失效指定命名空间的条目", func(t *testing.T) {
+		_ = raw.HSet(bg, "tenant:a:"+key, map[string]string{"f": "a2"}, time.Minute)
+		if value, _, _ := localTokens.hget(tenantA, store, key, "f"); value != "a" {
+			t.Fatalf("失效前应命中一级缓存，实际 %q", value)
+		}
+		localTokens.invalidate(tenantA, store, key)
+		if value, _, _ := localTokens.hget(tenantA, store, key, "f"); value != "a2" {
+			t.Fatalf("失效后应回源状态存储，实际 %q", value)
+		}
+		if value, _, _ := localTokens.hget(tenantA, provider, key, "f"); value != "pa" {
+			t.Fatalf("不应失效其他身份提供方的条目，实际 %q", value)
+		}
+	})
+
+	t.Run("按指纹失效所有命名空间", func(t *testing.T) {
+		InvalidateLocal(provider, "fp1")
+		if stats := localTokens.get(store).Stats(); stats.Size != 0 {
+			t.Fatalf("期望清空所有命名空间下的条目: %+v", stats)
+		}
+	})
+
+	t.Run("一级缓存归属于状态存储实例", func(t *testing.T) {
+		if len(LocalCacheMetrics(provider)) != int(localTierCount) {
+			t.Fatalf("期望从包装后的状态存储读取到一级缓存指标")
+		}
+		other := NewLocalStore(raw, bSdkConfig.LocalCacheConfig{Enabled: true})
+		if localTokens.get(other) == localTokens.get(store) {
+			t.Fatalf("不同实例不应共享一级缓存")
+		}
+		if NewLocalStore(root, bSdkConfig.LocalCacheConfig{Enabled: true}) != root {
+			t.Fatalf("重复包装应返回原实例")
+		}
+		if localTokens.get(raw) != nil || len(LocalCacheMetrics(raw)) != 0 {
+			t.Fatalf("未包装的状态存储不应使用一级缓存")
+		}
+	})
+}
@@ -2,40 +2,37 @@ package bSdkCache
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"time"
 
-	xCache "github.com/bamboo-services/bamboo-base-go/major/cache"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
-	"github.com/redis/go-redis/v9"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
 )
 
 // OAuthCache OAuth 2.0 认证流程的缓存管理器
 //
-// 该类型封装了与 Redis 的交互，用于临时存储 OAuth 上下文信息（如 State 和 PKCE Verifier）。
+// 该类型封装了与状态存储（Redis、内存或数据库）的交互，用于临时存储 OAuth 上下文信息（如 State 和 PKCE Verifier）。
 // 它通过控制键值对的生命周期（TTL）来确保认证状态的有效性和安全性。
 //
 // 注意: 该实现非并发安全，不建议在多 goroutine 中共享同一实例操作。
-type OAuthCache xCache.Cache
+type OAuthCache bSdkStore.Cache
 
 // NewOAuthCache 创建并初始化一个 OAuth 2.0 缓存管理器实例
 //
-// 该函数封装了 Redis 客户端的注入逻辑，并为新实例设置默认的键值对生存时间（TTL）。
+// 该函数封装了状态存储的注入逻辑，并为新实例设置默认的键值对生存时间（TTL）。
 // 它被设计为在仓储层或服务层初始化时调用，以便为 OAuth 流程中的临时数据（如
 // state 和 code_verifier）提供快速的存储支持。
 //
 // 参数:
-//   - rdb: 已初始化的 Redis 客户端连接，用于底层数据交互。
+//   - store: 已初始化的状态存储，用于底层数据交互。
+//   - cfg: SDK 缓存配置，通常来自 SDK 配置的 `cache`。
 //
 // 返回值:
-//   - *OAuthCache: 配置完成的缓存管理器指针，默认 TTL 为 30 分钟。
-func NewOAuthCache(rdb *redis.Client) *OAuthCache {
-	return &OAuthCache{
-		RDB: rdb,
-		TTL: time.Minute * 15,
-	}
+//   - *OAuthCache: 配置完成的缓存管理器指针，默认 TTL 为 15 分钟，可通过 `cache.entries.state`（`SSO_CACHE_STATE_*`）配置。
+func NewOAuthCache(store bSdkStore.Store, cfg bSdkConfig.CacheConfig) *OAuthCache {
+	c := OAuthCache(newStoreCache(store, cfg, CacheState))
+	return &c
 }
 
 func (c *OAuthCache) Get(ctx context.Context, key string, field string) (*string, bool, error) {
@@ -46,11 +43,8 @@ func (c *OAuthCache) Get(ctx context.Context, key string, field string) (*string
 		return nil, false, fmt.Errorf("字段为空")
 	}
 
-	value, err := c.RDB.HGet(ctx, c.buildKey(key), field).Result()
-	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return nil, false, nil
-		}
+	value, ok, err := c.Store.HGet(ctx, c.buildKey(key), field)
+	if err != nil || !ok {
 		return nil, false, err
 	}
 
@@ -68,11 +62,7 @@ func (c *OAuthCache) Set(ctx context.Context, key string, field string, value *s
 		return fmt.Errorf("缓存值为空")
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(key), field, *value).Err(); err != nil {
-		return err
-	}
-
-	return c.RDB.Expire(ctx, c.buildKey(key), c.TTL).Err()
+	return c.Store.HSet(ctx, c.buildKey(key), map[string]string{field: *value}, c.TTL)
 }
 
 func (c *OAuthCache) GetAllStruct(ctx context.Context, key string) (*bSdkModels.CacheOAuth, error) {
@@ -80,7 +70,7 @@ func (c *OAuthCache) GetAllStruct(ctx context.Context, key string) (*bSdkModels.
 		return nil, fmt.Errorf("状态为空")
 	}
 
-	result, err := c.RDB.HGetAll(ctx, c.buildKey(key)).Result()
+	result, err := c.Store.HGetAll(ctx, c.buildKey(key))
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +85,7 @@ func (c *OAuthCache) GetAll(ctx context.Context, key string) (map[string]string,
 		return nil, fmt.Errorf("状态为空")
 	}
 
-	return c.RDB.HGetAll(ctx, c.buildKey(key)).Result()
+	return c.Store.HGetAll(ctx, c.buildKey(key))
 }
 
 func (c *OAuthCache) SetAll(ctx context.Context, key string, fields map[string]*string) error {
@@ -106,7 +96,7 @@ func (c *OAuthCache) SetAll(ctx context.Context, key string, fields map[string]*
 		return nil
 	}
 
-	values := make(map[string]interface{}, len(fields))
+	values := make(map[string]string, len(fields))
 	for field, value := range fields {
 		if field == "" {
 			return fmt.Errorf("字段为空")
@@ -117,10 +107,7 @@ func (c *OAuthCache) SetAll(ctx context.Context, key string, fields map[string]*
 		values[field] = *value
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(key), values).Err(); err != nil {
-		return err
-	}
-	return c.RDB.Expire(ctx, c.buildKey(key), c.TTL).Err()
+	return c.Store.HSet(ctx, c.buildKey(key), values, c.TTL)
 }
 
 func (c *OAuthCache) SetAllStruct(ctx context.Context, key string, fields *bSdkModels.CacheOAuth) error {
@@ -131,10 +118,11 @@ func (c *OAuthCache) SetAllStruct(ctx context.Context, key string, fields *bSdkM
 		return fmt.Errorf("缓存值为空")
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(key), fields).Err(); err != nil {
+	values, err := bSdkStore.EncodeHash(fields)
+	if err != nil {
 		return err
 	}
-	return c.RDB.Expire(ctx, c.buildKey(key), c.TTL).Err()
+	return c.Store.HSet(ctx, c.buildKey(key), values, c.TTL)
 }
 
 func (c *OAuthCache) Exists(ctx context.Context, key string, field string) (bool, error) {
@@ -145,7 +133,7 @@ func (c *OAuthCache) Exists(ctx context.Context, key string, field string) (bool
 		return false, fmt.Errorf("字段为空")
 	}
 
-	return c.RDB.HExists(ctx, c.buildKey(key), field).Result()
+	return c.Store.HExists(ctx, c.buildKey(key), field)
 }
 
 func (c *OAuthCache) Remove(ctx context.Context, key string, fields ...string) error {
@@ -156,7 +144,7 @@ func (c *OAuthCache) Remove(ctx context.Context, key string, fields ...string) e
 		return nil
 	}
 
-	return c.RDB.HDel(ctx, c.buildKey(key), fields...).Err()
+	return c.Store.HDel(ctx, c.buildKey(key), fields...)
 }
 
 func (c *OAuthCache) Delete(ctx context.Context, key string) error {
@@ -164,9 +152,9 @@ func (c *OAuthCache) Delete(ctx context.Context, key string) error {
 		return fmt.Errorf("状态为空")
 	}
 
-	return c.RDB.Del(ctx, c.buildKey(key)).Err()
+	return c.Store.Delete(ctx, c.buildKey(key))
 }
 
 func (c *OAuthCache) buildKey(state string) string {
-	return bSdkConst.RedisOAuthState.Get(state).String()
+	return bSdkConst.RedisOAuthState.GetWithPrefix(c.Prefix, state).String()
 }
@@ -0,0 +1,65 @@
+package bSdkCache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+)
+
+// OAuthLogoutCache 登出令牌防重放缓存管理器
+//
+// 该类型记录已处理过的 `logout_token` 的 jti，确保同一登出令牌只会被处理一次。
+type OAuthLogoutCache bSdkStore.Cache
+
+// NewOAuthLogoutCache 创建并初始化一个登出令牌防重放缓存管理器实例
+//
+// 参数:
+//   - store: 已初始化的状态存储，用于底层数据交互。
+//   - cfg: SDK 缓存配置，通常来自 SDK 配置的 `cache`。
+//
+// 返回值:
+//   - *OAuthLogoutCache: 配置完成的缓存管理器指针，默认 TTL 为 10 分钟，可通过 `cache.entries.logout`（`SSO_CACHE_LOGOUT_*`）配置。
+func NewOAuthLogoutCache(store bSdkStore.Store, cfg bSdkConfig.CacheConfig) *OAuthLogoutCache {
+	c := OAuthLogoutCache(newStoreCache(store, cfg, CacheLogout))
+	return &c
+}
+
+// Claim 尝试占用指定 jti，首次占用返回 true，重复出现返回 false。
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - jti: 登出令牌的唯一标识。
+//   - ttl: 占用记录的保留时长，小于等于 0 时使用默认 TTL，超过 MaxTTL 时截断。
+//
+// 返回值:
+//   - bool: 是否首次占用。
+//   - error: 操作过程中发生的错误。
+func (c *OAuthLogoutCache) Claim(ctx context.Context, jti string, ttl time.Duration) (bool, error) {
+	if jti == "" {
+		return false, fmt.Errorf("jti 为空")
+	}
+	ttl = clampTTL(ttl, c.TTL, c.MaxTTL)
+
+	return c.Store.SetNX(ctx, bSdkConst.RedisOAuthLogoutJti.GetWithPrefix(c.Prefix, jti).String(), strconv.FormatInt(time.Now().Unix(), 10), ttl)
+}
+
+// Release 释放指定 jti 的占用记录，使同一登出令牌可以再次处理。
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - jti: 登出令牌的唯一标识。
+//
+// 返回值:
+//   - error: 操作过程中发生的错误。
+func (c *OAuthLogoutCache) Release(ctx context.Context, jti string) error {
+	if jti == "" {
+		return fmt.Errorf("jti 为空")
+	}
+
+	return c.Store.Delete(ctx, bSdkConst.RedisOAuthLogoutJti.GetWithPrefix(c.Prefix, jti).String())
+}
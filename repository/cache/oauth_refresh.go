@@ -0,0 +1,101 @@
+package bSdkCache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// OAuthRefreshCache 刷新令牌并发控制缓存管理器
+//
+// 提供按令牌家族的分布式锁，以及"近期刷新结果"缓存：同一刷新令牌被并发提交时，
+// 仅持锁的请求真正调用令牌端点，等待锁的请求直接取回本次刷新得到的新令牌。
+// 近期刷新结果整体加密存储。
+type OAuthRefreshCache bSdkStore.Cache
+
+// NewOAuthRefreshCache 创建并初始化一个刷新令牌并发控制缓存管理器实例
+//
+// 参数:
+//   - store: 已初始化的状态存储，用于底层数据交互。
+//   - cfg: SDK 缓存配置，通常来自 SDK 配置的 `cache`。
+//
+// 返回值:
+//   - *OAuthRefreshCache: 配置完成的缓存管理器指针，近期刷新结果默认保留 2 秒，可通过 `cache.entries.refresh`（`SSO_CACHE_REFRESH_*`）配置。
+func NewOAuthRefreshCache(store bSdkStore.Store, cfg bSdkConfig.CacheConfig) *OAuthRefreshCache {
+	c := OAuthRefreshCache(newStoreCache(store, cfg, CacheRefresh))
+	return &c
+}
+
+// Lock 尝试获取刷新锁，成功返回 true。
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - key: 锁标识（家族 ID 或刷新令牌指纹）。
+//   - owner: 持有者标识，释放时校验。
+//   - ttl: 锁的最长持有时间，防止持有者崩溃后死锁。
+func (c *OAuthRefreshCache) Lock(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	if key == "" {
+		return false, fmt.Errorf("锁标识为空")
+	}
+
+	return c.Store.SetNX(ctx, bSdkConst.RedisOAuthRefreshLock.GetWithPrefix(c.Prefix, key).String(), owner, clampTTL(ttl, c.TTL, c.MaxTTL))
+}
+
+// Unlock 释放刷新锁，仅当锁仍由 owner 持有时生效。
+func (c *OAuthRefreshCache) Unlock(ctx context.Context, key string, owner string) error {
+	if key == "" {
+		return fmt.Errorf("锁标识为空")
+	}
+
+	_, err := c.Store.CompareAndDelete(ctx, bSdkConst.RedisOAuthRefreshLock.GetWithPrefix(c.Prefix, key).String(), owner)
+	return err
+}
+
+// GetRecent 读取以旧刷新令牌指纹为键的近期刷新结果，不存在时返回 nil。
+func (c *OAuthRefreshCache) GetRecent(ctx context.Context, refreshFingerprint string) (*bSdkModels.CacheOAuthToken, error) {
+	if refreshFingerprint == "" {
+		return nil, fmt.Errorf("刷新令牌指纹为空")
+	}
+
+	value, ok, err := c.Store.Get(ctx, bSdkConst.RedisOAuthRefreshRecent.GetWithPrefix(c.Prefix, refreshFingerprint).String())
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	plain, err := bSdkUtil.DecryptToken(value, refreshFingerprint)
+	if err != nil {
+		return nil, err
+	}
+	var token bSdkModels.CacheOAuthToken
+	if err = json.Unmarshal([]byte(plain), &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// SetRecent 写入近期刷新结果。
+func (c *OAuthRefreshCache) SetRecent(ctx context.Context, refreshFingerprint string, token *bSdkModels.CacheOAuthToken) error {
+	if refreshFingerprint == "" {
+		return fmt.Errorf("刷新令牌指纹为空")
+	}
+	if token == nil {
+		return fmt.Errorf("缓存值为空")
+	}
+
+	encoded, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	sealed, err := bSdkUtil.EncryptToken(string(encoded), refreshFingerprint)
+	if err != nil {
+		return err
+	}
+	return c.Store.Set(ctx, bSdkConst.RedisOAuthRefreshRecent.GetWithPrefix(c.Prefix, refreshFingerprint).String(), sealed, c.TTL)
+}
@@ -0,0 +1,85 @@
+package bSdkCache
+
+import (
+	"context"
+	"fmt"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+)
+
+// OAuthSessionCache OAuth 会话索引缓存管理器
+//
+// 该类型使用集合维护 SSO 会话（sid）与用户（sub）到本地访问令牌的反向索引，
+// 以便在收到 SSO 发起的登出通知时一次性定位并清理所有关联令牌。
+type OAuthSessionCache bSdkStore.Cache
+
+// NewOAuthSessionCache 创建并初始化一个 OAuth 会话索引缓存管理器实例
+//
+// 参数:
+//   - store: 已初始化的状态存储，用于底层数据交互。
+//   - cfg: SDK 缓存配置，通常来自 SDK 配置的 `cache`。
+//
+// 返回值:
+//   - *OAuthSessionCache: 配置完成的缓存管理器指针，默认 TTL 与令牌缓存一致为 30 天，可通过 `cache.entries.session`（`SSO_CACHE_SESSION_*`）配置。
+func NewOAuthSessionCache(store bSdkStore.Store, cfg bSdkConfig.CacheConfig) *OAuthSessionCache {
+	c := OAuthSessionCache(newStoreCache(store, cfg, CacheSession))
+	return &c
+}
+
+// AddBySessionID 将访问令牌加入指定 sid 的索引集合。
+func (c *OAuthSessionCache) AddBySessionID(ctx context.Context, sid string, accessToken string) error {
+	if sid == "" {
+		return fmt.Errorf("会话标识为空")
+	}
+	return c.add(ctx, bSdkConst.RedisOAuthSessionSid.GetWithPrefix(c.Prefix, sid).String(), accessToken)
+}
+
+// AddBySubject 将访问令牌加入指定 sub 的索引集合。
+func (c *OAuthSessionCache) AddBySubject(ctx context.Context, sub string, accessToken string) error {
+	if sub == "" {
+		return fmt.Errorf("用户标识为空")
+	}
+	return c.add(ctx, bSdkConst.RedisOAuthSessionSub.GetWithPrefix(c.Prefix, sub).String(), accessToken)
+}
+
+// MembersBySessionID 获取指定 sid 索引下的全部访问令牌。
+func (c *OAuthSessionCache) MembersBySessionID(ctx context.Context, sid string) ([]string, error) {
+	if sid == "" {
+		return nil, fmt.Errorf("会话标识为空")
+	}
+	return c.Store.SMembers(ctx, bSdkConst.RedisOAuthSessionSid.GetWithPrefix(c.Prefix, sid).String())
+}
+
+// MembersBySubject 获取指定 sub 索引下的全部访问令牌。
+func (c *OAuthSessionCache) MembersBySubject(ctx context.Context, sub string) ([]string, error) {
+	if sub == "" {
+		return nil, fmt.Errorf("用户标识为空")
+	}
+	return c.Store.SMembers(ctx, bSdkConst.RedisOAuthSessionSub.GetWithPrefix(c.Prefix, sub).String())
+}
+
+// DeleteBySessionID 删除指定 sid 的索引集合。
+func (c *OAuthSessionCache) DeleteBySessionID(ctx context.Context, sid string) error {
+	if sid == "" {
+		return fmt.Errorf("会话标识为空")
+	}
+	return c.Store.Delete(ctx, bSdkConst.RedisOAuthSessionSid.GetWithPrefix(c.Prefix, sid).String())
+}
+
+// DeleteBySubject 删除指定 sub 的索引集合。
+func (c *OAuthSessionCache) DeleteBySubject(ctx context.Context, sub string) error {
+	if sub == "" {
+		return fmt.Errorf("用户标识为空")
+	}
+	return c.Store.Delete(ctx, bSdkConst.RedisOAuthSessionSub.GetWithPrefix(c.Prefix, sub).String())
+}
+
+func (c *OAuthSessionCache) add(ctx context.Context, key string, accessToken string) error {
+	if accessToken == "" {
+		return fmt.Errorf("令牌为空")
+	}
+
+	return c.Store.SAdd(ctx, key, accessToken, c.TTL)
+}
@@ -2,34 +2,35 @@ package bSdkCache
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"time"
 
-	xCache "github.com/bamboo-services/bamboo-base-go/major/cache"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
-	"github.com/redis/go-redis/v9"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
 // OAuthTokenCache OAuth 2.0 令牌缓存管理器
 //
-// 该类型封装了与 Redis 的交互，用于缓存已换取的 OAuth 访问令牌信息。
+// 该类型封装了与状态存储的交互，用于缓存已换取的 OAuth 访问令牌信息。
 // 通过控制键值对的生命周期（TTL）来减少对 OAuth2 平台的重复验证请求。
-type OAuthTokenCache xCache.Cache
+//
+// 缓存键由令牌指纹（HMAC）派生，`access_token`、`refresh_token` 与 `id_token` 字段以信封加密存储，
+// 存储中不会出现任何令牌明文。
+type OAuthTokenCache bSdkStore.Cache
 
 // NewOAuthTokenCache 创建并初始化一个 OAuth 令牌缓存管理器实例
 //
 // 参数:
-//   - rdb: 已初始化的 Redis 客户端连接，用于底层数据交互。
+//   - store: 已初始化的状态存储，用于底层数据交互。
+//   - cfg: SDK 缓存配置，通常来自 SDK 配置的 `cache`。
 //
 // 返回值:
-//   - *OAuthTokenCache: 配置完成的缓存管理器指针，默认 TTL 为 30 天。
-func NewOAuthTokenCache(rdb *redis.Client) *OAuthTokenCache {
-	return &OAuthTokenCache{
-		RDB: rdb,
-		TTL: time.Hour * 24 * 30,
-	}
+//   - *OAuthTokenCache: 配置完成的缓存管理器指针，默认 TTL 为 30 天，可通过 `cache.entries.token`（`SSO_CACHE_TOKEN_*`）配置。
+func NewOAuthTokenCache(store bSdkStore.Store, cfg bSdkConfig.CacheConfig) *OAuthTokenCache {
+	c := OAuthTokenCache(newStoreCache(store, cfg, CacheToken))
+	return &c
 }
 
 func (c *OAuthTokenCache) Get(ctx context.Context, key string, field string) (*string, bool, error) {
@@ -40,15 +41,20 @@ func (c *OAuthTokenCache) Get(ctx context.Context, key string, field string) (*s
 		return nil, false, fmt.Errorf("字段为空")
 	}
 
-	value, err := c.RDB.HGet(ctx, c.buildKey(key), field).Result()
+	cacheKey, fingerprint, err := c.buildKey(key)
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return nil, false, nil
-		}
+		return nil, false, err
+	}
+	value, ok, err := localTokens.hget(ctx, c.Store, cacheKey, field)
+	if err != nil || !ok {
 		return nil, false, err
 	}
 
-	return &value, true, nil
+	plain, err := openTokenField(field, value, fingerprint)
+	if err != nil {
+		return nil, false, err
+	}
+	return &plain, true, nil
 }
 
 func (c *OAuthTokenCache) Set(ctx context.Context, key string, field string, value *string) error {
@@ -62,28 +68,60 @@ func (c *OAuthTokenCache) Set(ctx context.Context, key string, field string, val
 		return fmt.Errorf("缓存值为空")
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(key), field, *value).Err(); err != nil {
+	cacheKey, fingerprint, err := c.buildKey(key)
+	if err != nil {
 		return err
 	}
-
-	return c.RDB.Expire(ctx, c.buildKey(key), c.TTL).Err()
+	sealed, err := sealTokenField(field, *value, fingerprint)
+	if err != nil {
+		return err
+	}
+	defer localTokens.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.HSet(ctx, cacheKey, map[string]string{field: sealed}, c.TTL)
 }
 
 func (c *OAuthTokenCache) GetAllStruct(ctx context.Context, key string) (*bSdkModels.CacheOAuthToken, error) {
+	values, _, err := c.GetSealedStruct(ctx, key)
+	return values, err
+}
+
+// GetSealedStruct 读取令牌缓存，并报告是否存在需要使用活动密钥重新加密的字段。
+//
+// 返回值:
+//   - *bSdkModels.CacheOAuthToken: 解密后的令牌信息。
+//   - bool: 是否存在由非活动密钥加密的字段。
+//   - error: 读取或解密失败时返回错误。
+func (c *OAuthTokenCache) GetSealedStruct(ctx context.Context, key string) (*bSdkModels.CacheOAuthToken, bool, error) {
+	if key == "" {
+		return nil, false, fmt.Errorf("令牌为空")
+	}
+
+	cacheKey, fingerprint, err := c.buildKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	result, err := localTokens.hgetAll(ctx, c.Store, cacheKey)
+	if err != nil {
+		return nil, false, err
+	}
+	return decodeOAuthToken(result, func(value string) (string, bool, error) {
+		return bSdkUtil.OpenToken(value, fingerprint)
+	})
+}
+
+// GetLegacyStruct 读取旧格式（以令牌明文为键）的令牌缓存，仅用于迁移期间兼容历史数据。
+func (c *OAuthTokenCache) GetLegacyStruct(ctx context.Context, key string) (*bSdkModels.CacheOAuthToken, error) {
 	if key == "" {
 		return nil, fmt.Errorf("令牌为空")
 	}
 
-	result, err := c.RDB.HGetAll(ctx, c.buildKey(key)).Result()
+	result, err := c.Store.HGetAll(ctx, c.legacyKey(key))
 	if err != nil {
 		return nil, err
 	}
-	return &bSdkModels.CacheOAuthToken{
-		AccessToken:  result["access_token"],
-		TokenType:    result["token_type"],
-		RefreshToken: result["refresh_token"],
-		Expiry:       result["expiry"],
-	}, nil
+	// 旧格式缓存的令牌字段均为明文，由调用方迁移为新格式
+	values, _, err := decodeOAuthToken(result, openLegacyToken)
+	return values, err
 }
 
 func (c *OAuthTokenCache) GetAll(ctx context.Context, key string) (map[string]string, error) {
@@ -91,7 +129,20 @@ func (c *OAuthTokenCache) GetAll(ctx context.Context, key string) (map[string]st
 		return nil, fmt.Errorf("令牌为空")
 	}
 
-	return c.RDB.HGetAll(ctx, c.buildKey(key)).Result()
+	cacheKey, fingerprint, err := c.buildKey(key)
+	if err != nil {
+		return nil, err
+	}
+	result, err := localTokens.hgetAll(ctx, c.Store, cacheKey)
+	if err != nil {
+		return nil, err
+	}
+	for field, value := range result {
+		if result[field], err = openTokenField(field, value, fingerprint); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 func (c *OAuthTokenCache) SetAll(ctx context.Context, key string, fields map[string]*string) error {
@@ -102,7 +153,11 @@ func (c *OAuthTokenCache) SetAll(ctx context.Context, key string, fields map[str
 		return nil
 	}
 
-	values := make(map[string]interface{}, len(fields))
+	cacheKey, fingerprint, err := c.buildKey(key)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]string, len(fields))
 	for field, value := range fields {
 		if field == "" {
 			return fmt.Errorf("字段为空")
@@ -110,16 +165,45 @@ func (c *OAuthTokenCache) SetAll(ctx context.Context, key string, fields map[str
 		if value == nil {
 			return fmt.Errorf("缓存值为空")
 		}
-		values[field] = *value
+		sealed, err := sealTokenField(field, *value, fingerprint)
+		if err != nil {
+			return err
+		}
+		values[field] = sealed
+	}
+
+	defer localTokens.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.HSet(ctx, cacheKey, values, c.TTL)
+}
+
+func (c *OAuthTokenCache) SetAllStruct(ctx context.Context, key string, fields *bSdkModels.CacheOAuthToken) error {
+	if key == "" {
+		return fmt.Errorf("令牌为空")
+	}
+	if fields == nil {
+		return fmt.Errorf("缓存值为空")
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(key), values).Err(); err != nil {
+	cacheKey, fingerprint, err := c.buildKey(key)
+	if err != nil {
+		return err
+	}
+	sealed, err := sealOAuthToken(fields, fingerprint)
+	if err != nil {
+		return err
+	}
+	values, err := bSdkStore.EncodeHash(sealed)
+	if err != nil {
 		return err
 	}
-	return c.RDB.Expire(ctx, c.buildKey(key), c.TTL).Err()
+	defer localTokens.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.HSet(ctx, cacheKey, values, c.TTL)
 }
 
-func (c *OAuthTokenCache) SetAllStruct(ctx context.Context, key string, fields *bSdkModels.CacheOAuthToken) error {
+// Reseal 使用当前活动密钥重新加密令牌缓存中的敏感字段，保留原有 TTL。
+//
+// 仅在缓存仍存在时写入，避免与并发删除（登出、吊销）竞争而复活已删除的缓存。
+func (c *OAuthTokenCache) Reseal(ctx context.Context, key string, fields *bSdkModels.CacheOAuthToken) error {
 	if key == "" {
 		return fmt.Errorf("令牌为空")
 	}
@@ -127,10 +211,21 @@ func (c *OAuthTokenCache) SetAllStruct(ctx context.Context, key string, fields *
 		return fmt.Errorf("缓存值为空")
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(key), fields).Err(); err != nil {
+	cacheKey, fingerprint, err := c.buildKey(key)
+	if err != nil {
 		return err
 	}
-	return c.RDB.Expire(ctx, c.buildKey(key), c.TTL).Err()
+	sealed, err := sealOAuthToken(fields, fingerprint)
+	if err != nil {
+		return err
+	}
+	defer localTokens.invalidate(ctx, c.Store, cacheKey)
+	_, err = c.Store.HSetExisting(ctx, cacheKey, map[string]string{
+		"access_token":  sealed.AccessToken,
+		"refresh_token": sealed.RefreshToken,
+		"id_token":      sealed.IDToken,
+	})
+	return err
 }
 
 func (c *OAuthTokenCache) Exists(ctx context.Context, key string, field string) (bool, error) {
@@ -141,7 +236,11 @@ func (c *OAuthTokenCache) Exists(ctx context.Context, key string, field string)
 		return false, fmt.Errorf("字段为空")
 	}
 
-	return c.RDB.HExists(ctx, c.buildKey(key), field).Result()
+	cacheKey, _, err := c.buildKey(key)
+	if err != nil {
+		return false, err
+	}
+	return c.Store.HExists(ctx, cacheKey, field)
 }
 
 func (c *OAuthTokenCache) Remove(ctx context.Context, key string, fields ...string) error {
@@ -152,7 +251,12 @@ func (c *OAuthTokenCache) Remove(ctx context.Context, key string, fields ...stri
 		return nil
 	}
 
-	return c.RDB.HDel(ctx, c.buildKey(key), fields...).Err()
+	cacheKey, _, err := c.buildKey(key)
+	if err != nil {
+		return err
+	}
+	defer localTokens.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.HDel(ctx, cacheKey, fields...)
 }
 
 func (c *OAuthTokenCache) Delete(ctx context.Context, key string) error {
@@ -160,9 +264,130 @@ func (c *OAuthTokenCache) Delete(ctx context.Context, key string) error {
 		return fmt.Errorf("令牌为空")
 	}
 
-	return c.RDB.Del(ctx, c.buildKey(key)).Err()
+	cacheKey, _, err := c.buildKey(key)
+	if err != nil {
+		return err
+	}
+	defer localTokens.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.Delete(ctx, cacheKey, c.legacyKey(key))
+}
+
+// DeleteLegacy 删除旧格式（以令牌明文为键）的令牌缓存。
+func (c *OAuthTokenCache) DeleteLegacy(ctx context.Context, key string) error {
+	if key == "" {
+		return fmt.Errorf("令牌为空")
+	}
+
+	return c.Store.Delete(ctx, c.legacyKey(key))
+}
+
+// DeleteByFingerprint 根据令牌指纹删除令牌缓存，用于仅持有指纹的场景（会话索引、吊销广播）。
+func (c *OAuthTokenCache) DeleteByFingerprint(ctx context.Context, fingerprint string) error {
+	if fingerprint == "" {
+		return fmt.Errorf("令牌指纹为空")
+	}
+
+	defer localTokens.invalidate(ctx, c.Store, bSdkConst.RedisOAuthToken.GetWithPrefix(c.Prefix, fingerprint).String())
+	return c.Store.Delete(ctx, bSdkConst.RedisOAuthToken.GetWithPrefix(c.Prefix, fingerprint).String())
+}
+
+// FamilyIDByFingerprint 根据令牌指纹读取令牌所属的刷新令牌家族，令牌不存在或未关联家族时返回空字符串。
+func (c *OAuthTokenCache) FamilyIDByFingerprint(ctx context.Context, fingerprint string) (string, error) {
+	if fingerprint == "" {
+		return "", fmt.Errorf("令牌指纹为空")
+	}
+
+	familyID, _, err := localTokens.hget(ctx, c.Store, bSdkConst.RedisOAuthToken.GetWithPrefix(c.Prefix, fingerprint).String(), "family_id")
+	return familyID, err
+}
+
+// buildKey 构建缓存键，同时返回访问令牌指纹，用于绑定令牌字段的密文（参见 `bSdkUtil.EncryptToken`）。
+func (c *OAuthTokenCache) buildKey(token string) (string, string, error) {
+	fingerprint, err := bSdkUtil.TokenFingerprint(token)
+	if err != nil {
+		return "", "", err
+	}
+	return bSdkConst.RedisOAuthToken.GetWithPrefix(c.Prefix, fingerprint).String(), fingerprint, nil
+}
+
+func (c *OAuthTokenCache) legacyKey(token string) string {
+	return bSdkConst.RedisOAuthTokenLegacy.GetWithPrefix(c.Prefix, token).String()
+}
+
+// decodeOAuthToken 将哈希字段还原为令牌结构，使用 opener 解密敏感字段并报告是否需要重新加密。
+func decodeOAuthToken(result map[string]string, opener func(string) (string, bool, error)) (*bSdkModels.CacheOAuthToken, bool, error) {
+	var stale bool
+	open := func(field string) (string, error) {
+		plain, fieldStale, err := opener(result[field])
+		stale = stale || fieldStale
+		return plain, err
+	}
+
+	accessToken, err := open("access_token")
+	if err != nil {
+		return nil, false, err
+	}
+	refreshToken, err := open("refresh_token")
+	if err != nil {
+		return nil, false, err
+	}
+	idToken, err := open("id_token")
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &bSdkModels.CacheOAuthToken{
+		AccessToken:  accessToken,
+		TokenType:    result["token_type"],
+		RefreshToken: refreshToken,
+		Expiry:       result["expiry"],
+		IDToken:      idToken,
+		Subject:      result["subject"],
+		SessionID:    result["session_id"],
+		FamilyID:     result["family_id"],
+		Provider:     result["provider"],
+		Tenant:       result["tenant"],
+	}, stale, nil
+}
+
+// openLegacyToken 读取旧格式缓存中的明文令牌字段，非空值始终标记为需要重新加密。
+func openLegacyToken(value string) (string, bool, error) {
+	return value, value != "", nil
+}
+
+// sealOAuthToken 返回敏感字段已加密并绑定访问令牌指纹的令牌结构副本。
+func sealOAuthToken(fields *bSdkModels.CacheOAuthToken, fingerprint string) (*bSdkModels.CacheOAuthToken, error) {
+	sealed := *fields
+	var err error
+	if sealed.AccessToken, err = bSdkUtil.EncryptToken(fields.AccessToken, fingerprint); err != nil {
+		return nil, err
+	}
+	if sealed.RefreshToken, err = bSdkUtil.EncryptToken(fields.RefreshToken, fingerprint); err != nil {
+		return nil, err
+	}
+	if sealed.IDToken, err = bSdkUtil.EncryptToken(fields.IDToken, fingerprint); err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// isSealedTokenField 判断字段是否为需要加密存储的令牌类字段。
+func isSealedTokenField(field string) bool {
+	return field == "access_token" || field == "refresh_token" || field == "id_token"
 }
 
-func (c *OAuthTokenCache) buildKey(token string) string {
-	return bSdkConst.RedisOAuthToken.Get(token).String()
+// sealTokenField 对令牌类字段加密，其余字段原样返回。
+func sealTokenField(field string, value string, fingerprint string) (string, error) {
+	if !isSealedTokenField(field) {
+		return value, nil
+	}
+	return bSdkUtil.EncryptToken(value, fingerprint)
+}
+
+// openTokenField 对令牌类字段解密，其余字段原样返回。
+func openTokenField(field string, value string, fingerprint string) (string, error) {
+	if !isSealedTokenField(field) {
+		return value, nil
+	}
+	return bSdkUtil.DecryptToken(value, fingerprint)
 }
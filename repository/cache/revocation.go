@@ -0,0 +1,62 @@
+package bSdkCache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	xCache "github.com/bamboo-services/bamboo-base-go/major/cache"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// RevocationCache 令牌吊销广播通道
+//
+// 基于 Redis Pub/Sub 实现，仅负责消息的序列化、发布与订阅，不做持久化；
+// 订阅方离线期间的消息会丢失，因此本地缓存仍需依赖自身 TTL 兜底。
+type RevocationCache xCache.Cache
+
+// NewRevocationCache 创建并初始化一个令牌吊销广播通道实例
+//
+// 参数:
+//   - rdb: 已初始化的 Redis 客户端连接。
+//
+// 返回值:
+//   - *RevocationCache: 配置完成的广播通道指针。
+func NewRevocationCache(rdb *redis.Client) *RevocationCache {
+	return &RevocationCache{
+		RDB: rdb,
+	}
+}
+
+// Publish 将吊销消息发布到广播频道。
+func (c *RevocationCache) Publish(ctx context.Context, message *bSdkModels.RevocationMessage) error {
+	if message == nil {
+		return fmt.Errorf("吊销消息为空")
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return c.RDB.Publish(ctx, c.channel(), payload).Err()
+}
+
+// Subscribe 订阅广播频道，调用方负责关闭返回的订阅。
+func (c *RevocationCache) Subscribe(ctx context.Context) *redis.PubSub {
+	return c.RDB.Subscribe(ctx, c.channel())
+}
+
+// Decode 解析广播频道中收到的消息体。
+func (c *RevocationCache) Decode(payload string) (*bSdkModels.RevocationMessage, error) {
+	var message bSdkModels.RevocationMessage
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (c *RevocationCache) channel() string {
+	return bSdkConst.RedisRevocationChannel.Get().String()
+}
@@ -0,0 +1,105 @@
+package bSdkCache
+
+import (
+	"context"
+	"fmt"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// TokenFamilyCache 刷新令牌家族缓存管理器
+//
+// 家族记录以 Hash 存储，当前刷新令牌加密保存；刷新令牌指纹到家族的索引以 String 存储，
+// 已轮换的刷新令牌索引会保留到 TTL 到期，用于识别重放。
+type TokenFamilyCache bSdkStore.Cache
+
+// NewTokenFamilyCache 创建并初始化一个刷新令牌家族缓存管理器实例
+//
+// 参数:
+//   - store: 已初始化的状态存储，用于底层数据交互。
+//   - cfg: SDK 缓存配置，通常来自 SDK 配置的 `cache`。
+//
+// 返回值:
+//   - *TokenFamilyCache: 配置完成的缓存管理器指针，默认 TTL 与令牌缓存一致为 30 天，可通过 `cache.entries.family`（`SSO_CACHE_FAMILY_*`）配置。
+func NewTokenFamilyCache(store bSdkStore.Store, cfg bSdkConfig.CacheConfig) *TokenFamilyCache {
+	c := TokenFamilyCache(newStoreCache(store, cfg, CacheFamily))
+	return &c
+}
+
+// Get 读取家族记录，不存在时返回 `FamilyID` 为空的结构。
+func (c *TokenFamilyCache) Get(ctx context.Context, familyID string) (*bSdkModels.CacheTokenFamily, error) {
+	if familyID == "" {
+		return nil, fmt.Errorf("家族标识为空")
+	}
+
+	result, err := c.Store.HGetAll(ctx, bSdkConst.RedisOAuthTokenFamily.GetWithPrefix(c.Prefix, familyID).String())
+	if err != nil {
+		return nil, err
+	}
+	var family bSdkModels.CacheTokenFamily
+	if err = bSdkStore.DecodeHash(result, &family); err != nil {
+		return nil, err
+	}
+	if family.RefreshToken != "" {
+		plain, err := bSdkUtil.DecryptToken(family.RefreshToken, familyID)
+		if err != nil {
+			return nil, err
+		}
+		family.RefreshToken = plain
+	}
+	return &family, nil
+}
+
+// Set 写入家族记录并刷新 TTL。
+func (c *TokenFamilyCache) Set(ctx context.Context, family *bSdkModels.CacheTokenFamily) error {
+	if family == nil || family.FamilyID == "" {
+		return fmt.Errorf("家族标识为空")
+	}
+
+	sealed := *family
+	var err error
+	if sealed.RefreshToken, err = bSdkUtil.EncryptToken(family.RefreshToken, family.FamilyID); err != nil {
+		return err
+	}
+
+	values, err := bSdkStore.EncodeHash(&sealed)
+	if err != nil {
+		return err
+	}
+	return c.Store.HSet(ctx, bSdkConst.RedisOAuthTokenFamily.GetWithPrefix(c.Prefix, family.FamilyID).String(), values, c.TTL)
+}
+
+// Delete 删除家族记录。
+func (c *TokenFamilyCache) Delete(ctx context.Context, familyID string) error {
+	if familyID == "" {
+		return fmt.Errorf("家族标识为空")
+	}
+
+	return c.Store.Delete(ctx, bSdkConst.RedisOAuthTokenFamily.GetWithPrefix(c.Prefix, familyID).String())
+}
+
+// SetIndex 记录刷新令牌指纹所属的家族。
+func (c *TokenFamilyCache) SetIndex(ctx context.Context, refreshFingerprint string, familyID string) error {
+	if refreshFingerprint == "" {
+		return fmt.Errorf("刷新令牌指纹为空")
+	}
+	if familyID == "" {
+		return fmt.Errorf("家族标识为空")
+	}
+
+	return c.Store.Set(ctx, bSdkConst.RedisOAuthTokenFamilyRT.GetWithPrefix(c.Prefix, refreshFingerprint).String(), familyID, c.TTL)
+}
+
+// GetIndex 查询刷新令牌指纹所属的家族，不存在时返回空字符串。
+func (c *TokenFamilyCache) GetIndex(ctx context.Context, refreshFingerprint string) (string, error) {
+	if refreshFingerprint == "" {
+		return "", fmt.Errorf("刷新令牌指纹为空")
+	}
+
+	familyID, _, err := c.Store.Get(ctx, bSdkConst.RedisOAuthTokenFamilyRT.GetWithPrefix(c.Prefix, refreshFingerprint).String())
+	return familyID, err
+}
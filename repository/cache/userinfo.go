@@ -2,40 +2,39 @@ package bSdkCache
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
-	xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
-	xCache "github.com/bamboo-services/bamboo-base-go/major/cache"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
-	"github.com/redis/go-redis/v9"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
 // UserinfoCache 业务层 Userinfo 缓存管理器
 //
-// 该类型封装了与 Redis 的交互，用于缓存 SSO 用户信息。
+// 该类型封装了与状态存储的交互，用于缓存 SSO 用户信息。
 // 通过控制键值对的生命周期（TTL）来减少对 SSO 服务的重复请求。
-type UserinfoCache xCache.Cache
+type UserinfoCache bSdkStore.Cache
 
 // NewUserinfoCache 创建并初始化一个 Userinfo 缓存管理器实例
 //
 // 参数:
-//   - rdb: 已初始化的 Redis 客户端连接，用于底层数据交互。
+//   - store: 已初始化的状态存储，用于底层数据交互。
+//   - cfg: SDK 缓存配置，通常来自 SDK 配置的 `cache`。
 //
 // 返回值:
-//   - *UserinfoCache: 配置完成的缓存管理器指针，默认 TTL 为 30 秒。
-func NewUserinfoCache(rdb *redis.Client) *UserinfoCache {
-	return &UserinfoCache{
-		RDB: rdb,
-		TTL: time.Second * 30,
-	}
+//   - *UserinfoCache: 配置完成的缓存管理器指针，默认 TTL 为 30 秒，可通过 `cache.entries.userinfo`（`SSO_CACHE_USERINFO_*`）配置。
+func NewUserinfoCache(store bSdkStore.Store, cfg bSdkConfig.CacheConfig) *UserinfoCache {
+	c := UserinfoCache(newStoreCache(store, cfg, CacheUserinfo))
+	return &c
 }
 
 // Get 从缓存中获取指定字段的值
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回未命中。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回未命中。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -47,7 +46,7 @@ func NewUserinfoCache(rdb *redis.Client) *UserinfoCache {
 //   - bool: 是否命中缓存（true 表示命中，false 表示未命中）。
 //   - error: 操作过程中发生的错误。
 func (c *UserinfoCache) Get(ctx context.Context, accessToken string, field string) (*string, bool, error) {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil, false, nil
 	}
 
@@ -58,11 +57,12 @@ func (c *UserinfoCache) Get(ctx context.Context, accessToken string, field strin
 		return nil, false, fmt.Errorf("字段为空")
 	}
 
-	value, err := c.RDB.HGet(ctx, c.buildKey(accessToken), field).Result()
+	cacheKey, err := c.buildKey(accessToken)
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return nil, false, nil
-		}
+		return nil, false, err
+	}
+	value, ok, err := localUserinfo.hget(ctx, c.Store, cacheKey, field)
+	if err != nil || !ok {
 		return nil, false, err
 	}
 
@@ -71,7 +71,7 @@ func (c *UserinfoCache) Get(ctx context.Context, accessToken string, field strin
 
 // GetAllStruct 从缓存中获取完整的 Userinfo 数据结构
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回未命中。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回未命中。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -82,7 +82,7 @@ func (c *UserinfoCache) Get(ctx context.Context, accessToken string, field strin
 //   - bool: 是否命中缓存（true 表示命中，false 表示未命中）。
 //   - error: 操作过程中发生的错误。
 func (c *UserinfoCache) GetAllStruct(ctx context.Context, accessToken string) (*bSdkModels.CacheBusinessUserinfo, bool, error) {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil, false, nil
 	}
 
@@ -90,7 +90,11 @@ func (c *UserinfoCache) GetAllStruct(ctx context.Context, accessToken string) (*
 		return nil, false, fmt.Errorf("令牌为空")
 	}
 
-	result, err := c.RDB.HGetAll(ctx, c.buildKey(accessToken)).Result()
+	cacheKey, err := c.buildKey(accessToken)
+	if err != nil {
+		return nil, false, err
+	}
+	result, err := localUserinfo.hgetAll(ctx, c.Store, cacheKey)
 	if err != nil {
 		return nil, false, err
 	}
@@ -99,6 +103,8 @@ func (c *UserinfoCache) GetAllStruct(ctx context.Context, accessToken string) (*
 		return nil, false, nil
 	}
 
+	// 旧版本写入的缓存没有 fetched_at，按 0 处理（视为未过期）
+	fetchedAt, _ := strconv.ParseInt(result["fetched_at"], 10, 64)
 	return &bSdkModels.CacheBusinessUserinfo{
 		Sub:               result["sub"],
 		Nickname:          result["nickname"],
@@ -106,12 +112,13 @@ func (c *UserinfoCache) GetAllStruct(ctx context.Context, accessToken string) (*
 		Email:             result["email"],
 		Phone:             result["phone"],
 		Raw:               result["raw"],
+		FetchedAt:         fetchedAt,
 	}, true, nil
 }
 
 // Set 设置指定字段的值
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回 nil。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回 nil。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -122,7 +129,7 @@ func (c *UserinfoCache) GetAllStruct(ctx context.Context, accessToken string) (*
 // 返回值:
 //   - error: 操作过程中发生的错误。
 func (c *UserinfoCache) Set(ctx context.Context, accessToken string, field string, value *string) error {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil
 	}
 
@@ -136,16 +143,17 @@ func (c *UserinfoCache) Set(ctx context.Context, accessToken string, field strin
 		return fmt.Errorf("缓存值为空")
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(accessToken), field, *value).Err(); err != nil {
+	cacheKey, err := c.buildKey(accessToken)
+	if err != nil {
 		return err
 	}
-
-	return c.RDB.Expire(ctx, c.buildKey(accessToken), c.TTL).Err()
+	defer localUserinfo.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.HSet(ctx, cacheKey, map[string]string{field: *value}, c.Retention())
 }
 
 // SetAllStruct 将完整的 Userinfo 数据结构存储到缓存
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回 nil。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回 nil。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -155,7 +163,7 @@ func (c *UserinfoCache) Set(ctx context.Context, accessToken string, field strin
 // 返回值:
 //   - error: 操作过程中发生的错误。
 func (c *UserinfoCache) SetAllStruct(ctx context.Context, accessToken string, userinfo *bSdkModels.CacheBusinessUserinfo) error {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil
 	}
 
@@ -166,11 +174,24 @@ func (c *UserinfoCache) SetAllStruct(ctx context.Context, accessToken string, us
 		return fmt.Errorf("缓存值为空")
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(accessToken), userinfo).Err(); err != nil {
+	values, err := bSdkStore.EncodeHash(userinfo)
+	if err != nil {
 		return err
 	}
+	cacheKey, err := c.buildKey(accessToken)
+	if err != nil {
+		return err
+	}
+	defer localUserinfo.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.HSet(ctx, cacheKey, values, c.Retention())
+}
 
-	return c.RDB.Expire(ctx, c.buildKey(accessToken), c.TTL).Err()
+// Retention 返回缓存条目的实际保留时长
+//
+// 配置了 stale-while-revalidate 或 stale-if-error 时，条目会在新鲜期（TTL）之后继续保留
+// 两者中较长的宽限期，是否可用由调用方根据 `fetched_at` 判断。
+func (c *UserinfoCache) Retention() time.Duration {
+	return c.TTL + max(c.StaleTTL, c.StaleIfErrorTTL)
 }
 
 // GetAll 从缓存中获取所有字段和值
@@ -187,12 +208,16 @@ func (c *UserinfoCache) GetAll(ctx context.Context, accessToken string) (map[str
 		return nil, fmt.Errorf("令牌为空")
 	}
 
-	return c.RDB.HGetAll(ctx, c.buildKey(accessToken)).Result()
+	cacheKey, err := c.buildKey(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return localUserinfo.hgetAll(ctx, c.Store, cacheKey)
 }
 
 // SetAll 批量设置多个字段的值
 //
-// 该方法会检查缓存开关环境变量，如果缓存未启用则直接返回 nil。
+// 该方法会检查缓存启用配置，如果缓存未启用则直接返回 nil。
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -202,7 +227,7 @@ func (c *UserinfoCache) GetAll(ctx context.Context, accessToken string) (map[str
 // 返回值:
 //   - error: 操作过程中发生的错误。
 func (c *UserinfoCache) SetAll(ctx context.Context, accessToken string, fields map[string]*string) error {
-	if !xEnv.GetEnvBool(bSdkConst.EnvSsoBusinessCache, false) {
+	if !c.Enabled {
 		return nil
 	}
 
@@ -213,7 +238,7 @@ func (c *UserinfoCache) SetAll(ctx context.Context, accessToken string, fields m
 		return nil
 	}
 
-	values := make(map[string]interface{}, len(fields))
+	values := make(map[string]string, len(fields))
 	for field, value := range fields {
 		if field == "" {
 			return fmt.Errorf("字段为空")
@@ -224,10 +249,12 @@ func (c *UserinfoCache) SetAll(ctx context.Context, accessToken string, fields m
 		values[field] = *value
 	}
 
-	if err := c.RDB.HSet(ctx, c.buildKey(accessToken), values).Err(); err != nil {
+	cacheKey, err := c.buildKey(accessToken)
+	if err != nil {
 		return err
 	}
-	return c.RDB.Expire(ctx, c.buildKey(accessToken), c.TTL).Err()
+	defer localUserinfo.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.HSet(ctx, cacheKey, values, c.Retention())
 }
 
 // Exists 检查指定字段是否存在
@@ -248,7 +275,11 @@ func (c *UserinfoCache) Exists(ctx context.Context, accessToken string, field st
 		return false, fmt.Errorf("字段为空")
 	}
 
-	return c.RDB.HExists(ctx, c.buildKey(accessToken), field).Result()
+	cacheKey, err := c.buildKey(accessToken)
+	if err != nil {
+		return false, err
+	}
+	return c.Store.HExists(ctx, cacheKey, field)
 }
 
 // Remove 从缓存中移除指定的字段
@@ -268,7 +299,12 @@ func (c *UserinfoCache) Remove(ctx context.Context, accessToken string, fields .
 		return nil
 	}
 
-	return c.RDB.HDel(ctx, c.buildKey(accessToken), fields...).Err()
+	cacheKey, err := c.buildKey(accessToken)
+	if err != nil {
+		return err
+	}
+	defer localUserinfo.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.HDel(ctx, cacheKey, fields...)
 }
 
 // Delete 删除指定令牌的缓存数据
@@ -284,16 +320,43 @@ func (c *UserinfoCache) Delete(ctx context.Context, accessToken string) error {
 		return fmt.Errorf("令牌为空")
 	}
 
-	return c.RDB.Del(ctx, c.buildKey(accessToken)).Err()
+	cacheKey, err := c.buildKey(accessToken)
+	if err != nil {
+		return err
+	}
+	defer localUserinfo.invalidate(ctx, c.Store, cacheKey)
+	return c.Store.Delete(ctx, cacheKey)
 }
 
-// buildKey 构建 Redis 缓存键
+// DeleteByFingerprint 根据令牌指纹删除缓存数据
 //
 // 参数:
-//   - accessToken: 访问令牌。
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - fingerprint: 令牌指纹（`bSdkUtil.TokenFingerprint` 的结果）。
+//
+// 返回值:
+//   - error: 操作过程中发生的错误。
+func (c *UserinfoCache) DeleteByFingerprint(ctx context.Context, fingerprint string) error {
+	if fingerprint == "" {
+		return fmt.Errorf("令牌指纹为空")
+	}
+
+	defer localUserinfo.invalidate(ctx, c.Store, bSdkConst.RedisBusinessUserinfo.GetWithPrefix(c.Prefix, fingerprint).String())
+	return c.Store.Delete(ctx, bSdkConst.RedisBusinessUserinfo.GetWithPrefix(c.Prefix, fingerprint).String())
+}
+
+// buildKey 构建缓存键
+//
+// 参数:
+//   - accessToken: 访问令牌，键中仅包含其指纹。
 //
 // 返回值:
 //   - string: 格式化后的缓存键。
-func (c *UserinfoCache) buildKey(accessToken string) string {
-	return bSdkConst.RedisBusinessUserinfo.Get(accessToken).String()
+//   - error: 令牌密钥不可用时返回错误。
+func (c *UserinfoCache) buildKey(accessToken string) (string, error) {
+	fingerprint, err := bSdkUtil.TokenFingerprint(accessToken)
+	if err != nil {
+		return "", err
+	}
+	return bSdkConst.RedisBusinessUserinfo.GetWithPrefix(c.Prefix, fingerprint).String(), nil
 }
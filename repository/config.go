@@ -0,0 +1,19 @@
+package bSdkRepo
+
+import (
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+)
+
+// envConfig 返回进程级 SDK 配置（`SSO_CONFIG_FILE` 与环境变量），供未传入显式配置的仓储构造函数使用，非法的环境变量回退为默认值。
+func envConfig() *bSdkConfig.Config {
+	cfg, _ := bSdkConfig.Process()
+	return cfg
+}
+
+// repoConfig 返回仓储使用的 SDK 配置快照，cfg 为 nil 时使用默认配置。
+func repoConfig(cfg *bSdkConfig.Config) *bSdkConfig.Config {
+	if cfg == nil {
+		return bSdkConfig.Default()
+	}
+	return cfg.Current()
+}
@@ -0,0 +1,202 @@
+package bSdkDatabase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OAuthTokenStore 令牌持久化存储
+//
+// 该类型封装了令牌记录表的读写，令牌字段写入前加密、读取后解密，
+// 与 `bSdkCache.OAuthTokenCache` 使用同一套密钥环。
+type OAuthTokenStore struct {
+	DB        *gorm.DB
+	Retention time.Duration // 含刷新令牌的记录自最近一次写入起的保留时长，与令牌缓存 TTL 一致
+
+	janitor *bSdkStore.Janitor
+}
+
+// NewOAuthTokenStore 创建并初始化一个令牌持久化存储实例
+//
+// 参数:
+//   - db: 已初始化的 GORM 数据库实例。
+//   - retention: 含刷新令牌的记录的保留时长，通常为令牌缓存的 TTL；刷新令牌的有效期长于访问令牌，
+//     记录需保留到与 Redis 中的令牌缓存同时过期。
+//
+// 返回值:
+//   - *OAuthTokenStore: 配置完成的持久化存储指针。
+func NewOAuthTokenStore(db *gorm.DB, retention time.Duration) *OAuthTokenStore {
+	return &OAuthTokenStore{DB: db, Retention: retention}
+}
+
+// AutoMigrate 自动迁移令牌持久化表结构。
+func AutoMigrate(ctx context.Context, db *gorm.DB) error {
+	if db == nil {
+		return fmt.Errorf("数据库实例为空")
+	}
+	return db.WithContext(ctx).AutoMigrate(&bSdkModels.OAuthTokenRecord{})
+}
+
+// Save 按访问令牌指纹写入或更新令牌记录，已软删除的同指纹记录会被恢复。
+func (s *OAuthTokenStore) Save(ctx context.Context, token *bSdkModels.CacheOAuthToken) error {
+	if token == nil || token.AccessToken == "" {
+		return fmt.Errorf("令牌为空")
+	}
+
+	fingerprint, err := bSdkUtil.TokenFingerprint(token.AccessToken)
+	if err != nil {
+		return err
+	}
+	record := &bSdkModels.OAuthTokenRecord{
+		AccessFingerprint: fingerprint,
+		TokenType:         token.TokenType,
+		Subject:           token.Subject,
+		SessionID:         token.SessionID,
+		FamilyID:          token.FamilyID,
+		Provider:          token.Provider,
+		Tenant:            token.Tenant,
+	}
+	if expiry, err := time.Parse(time.RFC3339, token.Expiry); err == nil {
+		record.Expiry = expiry
+	}
+	purgeAt := record.Expiry
+	if retained := time.Now().Add(s.Retention); token.RefreshToken != "" && retained.After(purgeAt) {
+		purgeAt = retained
+	}
+	record.PurgeAt = &purgeAt
+	if record.AccessToken, err = bSdkUtil.EncryptToken(token.AccessToken, fingerprint); err != nil {
+		return err
+	}
+	if record.RefreshToken, err = bSdkUtil.EncryptToken(token.RefreshToken, fingerprint); err != nil {
+		return err
+	}
+	if record.IDToken, err = bSdkUtil.EncryptToken(token.IDToken, fingerprint); err != nil {
+		return err
+	}
+
+	return s.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "access_fingerprint"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"access_token", "refresh_token", "id_token", "token_type", "expiry", "purge_at",
+			"subject", "session_id", "family_id", "provider", "tenant", "updated_at", "deleted_at",
+		}),
+	}).Create(record).Error
+}
+
+// Get 根据访问令牌指纹读取令牌，不存在时返回 nil。
+func (s *OAuthTokenStore) Get(ctx context.Context, fingerprint string) (*bSdkModels.CacheOAuthToken, error) {
+	if fingerprint == "" {
+		return nil, fmt.Errorf("令牌指纹为空")
+	}
+
+	var record bSdkModels.OAuthTokenRecord
+	err := s.DB.WithContext(ctx).Where("access_fingerprint = ?", fingerprint).Take(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	token := &bSdkModels.CacheOAuthToken{
+		TokenType: record.TokenType,
+		Expiry:    record.Expiry.Format(time.RFC3339),
+		Subject:   record.Subject,
+		SessionID: record.SessionID,
+		FamilyID:  record.FamilyID,
+		Provider:  record.Provider,
+		Tenant:    record.Tenant,
+	}
+	if token.AccessToken, err = bSdkUtil.DecryptToken(record.AccessToken, fingerprint); err != nil {
+		return nil, err
+	}
+	if token.RefreshToken, err = bSdkUtil.DecryptToken(record.RefreshToken, fingerprint); err != nil {
+		return nil, err
+	}
+	if token.IDToken, err = bSdkUtil.DecryptToken(record.IDToken, fingerprint); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Delete 根据访问令牌指纹软删除上下文所属租户的令牌记录。
+func (s *OAuthTokenStore) Delete(ctx context.Context, fingerprint string) error {
+	if fingerprint == "" {
+		return fmt.Errorf("令牌指纹为空")
+	}
+
+	return s.DB.WithContext(ctx).
+		Where("access_fingerprint = ? AND tenant = ?", fingerprint, bSdkUtil.GetTenant(ctx)).
+		Delete(&bSdkModels.OAuthTokenRecord{}).Error
+}
+
+// FingerprintsBySessionID 列出上下文所属租户中指定身份提供方 sid 下未删除的访问令牌指纹，provider 为空表示默认提供方。
+func (s *OAuthTokenStore) FingerprintsBySessionID(ctx context.Context, provider string, sid string) ([]string, error) {
+	if sid == "" {
+		return nil, fmt.Errorf("会话标识为空")
+	}
+	return s.fingerprints(ctx, "provider = ? AND session_id = ?", provider, sid)
+}
+
+// FingerprintsBySubject 列出上下文所属租户中指定身份提供方 sub 下未删除的访问令牌指纹，provider 为空表示默认提供方。
+func (s *OAuthTokenStore) FingerprintsBySubject(ctx context.Context, provider string, sub string) ([]string, error) {
+	if sub == "" {
+		return nil, fmt.Errorf("用户标识为空")
+	}
+	return s.fingerprints(ctx, "provider = ? AND subject = ?", provider, sub)
+}
+
+// PurgeExpired 物理删除清理时间早于 before 的令牌记录（含已软删除的记录）。
+//
+// 按 `purge_at` 判断，刷新令牌仍可使用的记录不会因访问令牌过期而被删除；
+// 未记录清理时间的旧记录按最近一次写入时间加保留时长判断。
+//
+// 返回值:
+//   - int64: 删除的记录数。
+//   - error: 删除失败时返回错误。
+func (s *OAuthTokenStore) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := s.DB.WithContext(ctx).Unscoped().
+		Where("purge_at < ? OR (purge_at IS NULL AND updated_at < ?)", before, before.Add(-s.Retention)).
+		Delete(&bSdkModels.OAuthTokenRecord{})
+	return result.RowsAffected, result.Error
+}
+
+// StartJanitor 启动后台协程，按 interval 定期调用 `PurgeExpired` 清理已到清理时间的记录
+//
+// 参数:
+//   - interval: 清理周期，`<= 0` 时不启动。
+//   - onResult: 每次清理后的回调（可为 nil），用于记录删除行数或错误。
+//
+// 重复调用会先停止已启动的清理协程；使用完毕后调用 `Close` 停止。
+func (s *OAuthTokenStore) StartJanitor(interval time.Duration, onResult func(count int64, err error)) {
+	s.janitor.Close()
+	s.janitor = bSdkStore.NewJanitor(interval, func(ctx context.Context) {
+		count, err := s.PurgeExpired(ctx, time.Now())
+		if onResult != nil {
+			onResult(count, err)
+		}
+	})
+}
+
+// Close 停止后台清理协程。
+func (s *OAuthTokenStore) Close() {
+	s.janitor.Close()
+}
+
+// fingerprints 按条件列出上下文所属租户下未删除的访问令牌指纹。
+func (s *OAuthTokenStore) fingerprints(ctx context.Context, query string, args ...any) ([]string, error) {
+	var fingerprints []string
+	err := s.DB.WithContext(ctx).Model(&bSdkModels.OAuthTokenRecord{}).
+		Where(query, args...).
+		Where("tenant = ?", bSdkUtil.GetTenant(ctx)).
+		Pluck("access_fingerprint", &fingerprints).Error
+	return fingerprints, err
+}
@@ -0,0 +1,230 @@
+//go:build sqlite && cgo
+
+package bSdkDatabase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newSQLiteDB 创建已迁移令牌表的内存 SQLite 数据库，并配置令牌加密密钥。
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	if _, err := bSdkUtil.ConfigureTokenKeys(context.Background(), bSdkConfig.New(bSdkConfig.WithClient("cid", "csecret"))); err != nil {
+		t.Fatalf("配置令牌密钥失败: %v", err)
+	}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接池失败: %v", err)
+	}
+	// 内存数据库按连接隔离，限制为单连接保证所有查询访问同一个库
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err = AutoMigrate(context.Background(), db); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+	return db
+}
+
+func TestOAuthTokenStore(t *testing.T) {
+	ctx := bSdkUtil.WithTenant(context.Background(), "shop-a", "shop-a")
+	db := newSQLiteDB(t)
+	store := NewOAuthTokenStore(db, time.Hour)
+
+	token := &bSdkModels.CacheOAuthToken{
+		AccessToken:  "store-at",
+		RefreshToken: "store-rt",
+		IDToken:      "store-id",
+		TokenType:    "Bearer",
+		Expiry:       time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		Subject:      "user-1",
+		SessionID:    "sid-1",
+		Tenant:       "shop-a",
+	}
+	fingerprint, _ := bSdkUtil.TokenFingerprint(token.AccessToken)
+
+	t.Run("写入并读取", func(t *testing.T) {
+		if err := store.Save(ctx, token); err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+		var record bSdkModels.OAuthTokenRecord
+		if err := db.Take(&record).Error; err != nil {
+			t.Fatalf("读取记录失败: %v", err)
+		}
+		if record.AccessToken == token.AccessToken || record.RefreshToken == token.RefreshToken {
+			t.Fatalf("令牌字段应加密存储")
+		}
+
+		got, err := store.Get(ctx, fingerprint)
+		if err != nil || got == nil {
+			t.Fatalf("读取失败: %v", err)
+		}
+		if got.AccessToken != token.AccessToken || got.RefreshToken != token.RefreshToken || got.IDToken != token.IDToken {
+			t.Fatalf("令牌解密结果不正确: %+v", got)
+		}
+		if got.SessionID != "sid-1" || got.Subject != "user-1" || got.Tenant != "shop-a" || got.Expiry != token.Expiry {
+			t.Fatalf("令牌属性不正确: %+v", got)
+		}
+	})
+
+	t.Run("软删除后不可读取", func(t *testing.T) {
+		if err := store.Delete(ctx, fingerprint); err != nil {
+			t.Fatalf("删除失败: %v", err)
+		}
+		got, err := store.Get(ctx, fingerprint)
+		if err != nil || got != nil {
+			t.Fatalf("期望软删除后读取不到，实际 %+v, %v", got, err)
+		}
+		fingerprints, err := store.FingerprintsBySessionID(ctx, "", "sid-1")
+		if err != nil || len(fingerprints) != 0 {
+			t.Fatalf("软删除的记录不应出现在会话索引中: %v, %v", fingerprints, err)
+		}
+	})
+
+	t.Run("同指纹写入恢复软删除记录", func(t *testing.T) {
+		restored := *token
+		restored.RefreshToken = "store-rt-2"
+		restored.SessionID = "sid-2"
+		if err := store.Save(ctx, &restored); err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+
+		got, err := store.Get(ctx, fingerprint)
+		if err != nil || got == nil {
+			t.Fatalf("期望恢复记录，实际错误: %v", err)
+		}
+		if got.RefreshToken != "store-rt-2" || got.SessionID != "sid-2" {
+			t.Fatalf("恢复的记录应使用新值: %+v", got)
+		}
+		var count int64
+		db.Unscoped().Model(&bSdkModels.OAuthTokenRecord{}).Count(&count)
+		if count != 1 {
+			t.Fatalf("同指纹应只有 1 条记录，实际 %d 条", count)
+		}
+		fingerprints, err := store.FingerprintsBySessionID(ctx, "", "sid-2")
+		if err != nil || len(fingerprints) != 1 || fingerprints[0] != fingerprint {
+			t.Fatalf("恢复的记录应出现在会话索引中: %v, %v", fingerprints, err)
+		}
+	})
+
+	t.Run("清理过期记录", func(t *testing.T) {
+		expired := []*bSdkModels.CacheOAuthToken{
+			{AccessToken: "expired-at-1", Expiry: time.Now().Add(-time.Hour).Format(time.RFC3339), Tenant: "shop-a"},
+			{AccessToken: "expired-at-2", Expiry: time.Now().Add(-time.Hour).Format(time.RFC3339), Tenant: "shop-a"},
+		}
+		// 访问令牌已过期但刷新令牌仍可使用的记录需保留到令牌缓存过期
+		refreshable := &bSdkModels.CacheOAuthToken{
+			AccessToken:  "expired-at-3",
+			RefreshToken: "refreshable-rt",
+			Expiry:       time.Now().Add(-time.Hour).Format(time.RFC3339),
+			Tenant:       "shop-a",
+		}
+		if err := store.Save(ctx, refreshable); err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+		for _, item := range expired {
+			if err := store.Save(ctx, item); err != nil {
+				t.Fatalf("写入失败: %v", err)
+			}
+		}
+		// 已软删除的过期记录同样应被物理删除
+		expiredFingerprint, _ := bSdkUtil.TokenFingerprint("expired-at-2")
+		if err := store.Delete(ctx, expiredFingerprint); err != nil {
+			t.Fatalf("删除失败: %v", err)
+		}
+
+		purged, err := store.PurgeExpired(ctx, time.Now())
+		if err != nil || purged != 2 {
+			t.Fatalf("期望清理 2 条过期记录，实际 %d, %v", purged, err)
+		}
+		var count int64
+		db.Unscoped().Model(&bSdkModels.OAuthTokenRecord{}).Count(&count)
+		if count != 2 {
+			t.Fatalf("未过期与刷新令牌仍可使用的记录应保留，剩余 %d 条", count)
+		}
+
+		refreshableFingerprint, _ := bSdkUtil.TokenFingerprint(refreshable.AccessToken)
+		if got, err := store.Get(ctx, refreshableFingerprint); err != nil || got == nil || got.RefreshToken != refreshable.RefreshToken {
+			t.Fatalf("访问令牌过期后仍应可读取刷新令牌: %+v, %v", got, err)
+		}
+		if err = store.Delete(ctx, refreshableFingerprint); err != nil {
+			t.Fatalf("删除失败: %v", err)
+		}
+	})
+
+	t.Run("会话索引按身份提供方隔离", func(t *testing.T) {
+		partner := &bSdkModels.CacheOAuthToken{
+			AccessToken: "partner-at",
+			Expiry:      time.Now().Add(time.Hour).Format(time.RFC3339),
+			Subject:     "user-1",
+			SessionID:   "sid-2",
+			Provider:    "partner",
+			Tenant:      "shop-a",
+		}
+		if err := store.Save(ctx, partner); err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+		partnerFingerprint, _ := bSdkUtil.TokenFingerprint(partner.AccessToken)
+
+		fingerprints, err := store.FingerprintsBySubject(ctx, "", "user-1")
+		if err != nil || len(fingerprints) != 1 || fingerprints[0] != fingerprint {
+			t.Fatalf("默认提供方不应列出其他提供方的令牌: %v, %v", fingerprints, err)
+		}
+		fingerprints, err = store.FingerprintsBySessionID(ctx, "partner", "sid-2")
+		if err != nil || len(fingerprints) != 1 || fingerprints[0] != partnerFingerprint {
+			t.Fatalf("期望仅列出该提供方的令牌: %v, %v", fingerprints, err)
+		}
+	})
+
+	t.Run("按租户隔离", func(t *testing.T) {
+		other := bSdkUtil.WithTenant(context.Background(), "shop-b", "shop-b")
+		tenantB := &bSdkModels.CacheOAuthToken{
+			AccessToken: "shop-b-at",
+			Expiry:      time.Now().Add(time.Hour).Format(time.RFC3339),
+			Subject:     "user-1",
+			SessionID:   "sid-2",
+			Tenant:      "shop-b",
+		}
+		if err := store.Save(other, tenantB); err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+		tenantBFingerprint, _ := bSdkUtil.TokenFingerprint(tenantB.AccessToken)
+
+		fingerprints, err := store.FingerprintsBySessionID(ctx, "", "sid-2")
+		if err != nil || len(fingerprints) != 1 || fingerprints[0] != fingerprint {
+			t.Fatalf("会话索引不应列出其他租户的令牌: %v, %v", fingerprints, err)
+		}
+		fingerprints, err = store.FingerprintsBySubject(other, "", "user-1")
+		if err != nil || len(fingerprints) != 1 || fingerprints[0] != tenantBFingerprint {
+			t.Fatalf("用户索引应仅列出本租户的令牌: %v, %v", fingerprints, err)
+		}
+
+		// 其他租户按指纹删除不影响本租户的记录
+		if err = store.Delete(other, fingerprint); err != nil {
+			t.Fatalf("删除失败: %v", err)
+		}
+		if got, err := store.Get(ctx, fingerprint); err != nil || got == nil {
+			t.Fatalf("其他租户不应删除本租户的记录: %+v, %v", got, err)
+		}
+		if err = store.Delete(other, tenantBFingerprint); err != nil {
+			t.Fatalf("删除失败: %v", err)
+		}
+		if got, err := store.Get(other, tenantBFingerprint); err != nil || got != nil {
+			t.Fatalf("期望删除本租户的记录，实际 %+v, %v", got, err)
+		}
+	})
+}
@@ -3,12 +3,15 @@ package bSdkRepo
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
 	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
-	"github.com/redis/go-redis/v9"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 	"gorm.io/gorm"
 )
 
@@ -23,20 +26,32 @@ type IntrospectionRepo struct {
 
 // NewIntrospectionRepo 创建并初始化一个 Introspection 仓储实例。
 //
+// 缓存配置按 `SSO_CACHE_*` 环境变量读取，使用显式 SDK 配置时请改用 `NewIntrospectionRepoWith`。
+//
 // 参数:
 //   - db: 已初始化的 GORM 数据库实例（备用）。
-//   - rdb: 已初始化的 Redis 客户端，用于缓存数据。
+//   - store: 已初始化的状态存储，用于缓存数据。
 //
 // 返回值:
 //   - *IntrospectionRepo: 配置完成的 Introspection 仓储实例指针。
-func NewIntrospectionRepo(db *gorm.DB, rdb *redis.Client) *IntrospectionRepo {
+func NewIntrospectionRepo(db *gorm.DB, store bSdkStore.Store) *IntrospectionRepo {
+	return NewIntrospectionRepoWith(db, store, envConfig())
+}
+
+// NewIntrospectionRepoWith 按 SDK 配置创建 Introspection 仓储实例，cfg 为 nil 时使用默认配置。
+func NewIntrospectionRepoWith(db *gorm.DB, store bSdkStore.Store, cfg *bSdkConfig.Config) *IntrospectionRepo {
 	return &IntrospectionRepo{
 		db:    db,
-		cache: bSdkCache.NewIntrospectionCache(rdb),
+		cache: bSdkCache.NewIntrospectionCache(store, repoConfig(cfg).Cache),
 		log:   xLog.WithName(xLog.NamedREPO, "IntrospectionRepo"),
 	}
 }
 
+// CacheEnabled 报告业务缓存是否启用（`cache.entries.introspection.enabled`，默认跟随 `cache.business`）。
+func (r *IntrospectionRepo) CacheEnabled() bool {
+	return r.cache.Enabled
+}
+
 // GetCache 从缓存中获取令牌自省结果
 //
 // 参数:
@@ -53,8 +68,11 @@ func (r *IntrospectionRepo) GetCache(ctx context.Context, tokenType string, toke
 		return nil, false, nil
 	}
 
-	cacheKey := tokenType + ":" + token
-	cacheValue, exists, err := r.cache.GetAllStruct(ctx, cacheKey)
+	fingerprint, err := bSdkUtil.TokenFingerprint(token)
+	if err != nil {
+		return nil, false, err
+	}
+	cacheValue, exists, err := r.cache.GetAllStruct(ctx, tokenType+":"+fingerprint)
 	if err != nil {
 		return nil, false, err
 	}
@@ -114,8 +132,11 @@ func (r *IntrospectionRepo) StoreCache(ctx context.Context, tokenType string, to
 		cacheIntrospection.Raw = string(rawJSON)
 	}
 
-	cacheKey := tokenType + ":" + token
-	return r.cache.SetAllStruct(ctx, cacheKey, cacheIntrospection)
+	fingerprint, err := bSdkUtil.TokenFingerprint(token)
+	if err != nil {
+		return err
+	}
+	return r.cache.SetAllStruct(ctx, tokenType+":"+fingerprint, cacheIntrospection)
 }
 
 // DeleteCache 删除令牌自省缓存
@@ -132,10 +153,101 @@ func (r *IntrospectionRepo) DeleteCache(ctx context.Context, tokenType string, t
 		return xError.NewError(ctx, xError.ParameterEmpty, "令牌类型或令牌为空", false, nil)
 	}
 
-	cacheKey := tokenType + ":" + token
-	if err := r.cache.Delete(ctx, cacheKey); err != nil {
+	fingerprint, err := bSdkUtil.TokenFingerprint(token)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	return r.DeleteCacheByFingerprint(ctx, tokenType, fingerprint)
+}
+
+// DeleteCacheByFingerprint 根据令牌指纹删除令牌自省缓存
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - tokenType: 令牌类型（如 "access_token"、"refresh_token"）。
+//   - fingerprint: 令牌指纹。
+//
+// 返回值:
+//   - *xError.Error: 操作过程中发生的错误。
+func (r *IntrospectionRepo) DeleteCacheByFingerprint(ctx context.Context, tokenType string, fingerprint string) *xError.Error {
+	if tokenType == "" || fingerprint == "" {
+		return xError.NewError(ctx, xError.ParameterEmpty, "令牌类型或令牌指纹为空", false, nil)
+	}
+
+	if err := r.cache.Delete(ctx, tokenType+":"+fingerprint); err != nil {
 		return xError.NewError(ctx, xError.OperationFailed, "删除令牌自省缓存失败", false, err)
 	}
 
 	return nil
 }
+
+// IsInvalid 检查令牌是否命中无效令牌负缓存
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - kind: 请求类型（如 "userinfo"、"introspection:access_token"）。
+//   - token: 令牌值。
+//
+// 返回值:
+//   - bool: 是否命中负缓存。
+//   - error: 操作过程中发生的错误。
+func (r *IntrospectionRepo) IsInvalid(ctx context.Context, kind string, token string) (bool, error) {
+	if kind == "" || token == "" {
+		return false, nil
+	}
+
+	fingerprint, err := bSdkUtil.TokenFingerprint(token)
+	if err != nil {
+		return false, err
+	}
+	return r.cache.IsInvalid(ctx, kind+":"+fingerprint)
+}
+
+// MarkInvalid 写入无效令牌负缓存，ttl 小于等于 0 时跳过
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - kind: 请求类型（如 "userinfo"、"introspection:access_token"）。
+//   - token: 令牌值。
+//   - ttl: 负缓存有效期，通常来自 SDK 配置的 `business.negative_cache_ttl`。
+//
+// 返回值:
+//   - error: 操作过程中发生的错误。
+func (r *IntrospectionRepo) MarkInvalid(ctx context.Context, kind string, token string, ttl time.Duration) error {
+	if kind == "" || token == "" || ttl <= 0 {
+		return nil
+	}
+
+	fingerprint, err := bSdkUtil.TokenFingerprint(token)
+	if err != nil {
+		return err
+	}
+	return r.cache.MarkInvalid(ctx, kind+":"+fingerprint, ttl)
+}
+
+// IncrInvalid 为限流维度累加一次无效令牌计数
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - scope: 限流维度（见 `bSdkUtil.InvalidTokenScopes`）。
+//   - window: 限流窗口，通常来自 SDK 配置的 `business.invalid_token_window`。
+//
+// 返回值:
+//   - int64: 当前窗口内的计数。
+//   - error: 操作过程中发生的错误。
+func (r *IntrospectionRepo) IncrInvalid(ctx context.Context, scope string, window time.Duration) (int64, error) {
+	return r.cache.IncrInvalid(ctx, scope, window)
+}
+
+// InvalidCount 读取限流维度在当前窗口内的无效令牌计数
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - scope: 限流维度（见 `bSdkUtil.InvalidTokenScopes`）。
+//
+// 返回值:
+//   - int64: 当前窗口内的计数。
+//   - error: 操作过程中发生的错误。
+func (r *IntrospectionRepo) InvalidCount(ctx context.Context, scope string) (int64, error) {
+	return r.cache.InvalidCount(ctx, scope)
+}
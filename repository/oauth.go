@@ -5,9 +5,10 @@ import (
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
 	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
-	"github.com/redis/go-redis/v9"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
 	"gorm.io/gorm"
 )
 
@@ -27,16 +28,23 @@ type OAuthRepo struct {
 // 和缓存操作。它内部会初始化关联的缓存适配器（默认 TTL 为 30 分钟）
 // 以及带有命名上下文的日志记录器。
 //
+// 缓存配置按 `SSO_CACHE_*` 环境变量读取，使用显式 SDK 配置时请改用 `NewOAuthRepoWith`。
+//
 // 参数:
 //   - db: 已初始化的 GORM 数据库实例，用于数据库交互。
-//   - rdb: 已初始化的 Redis 客户端，用于缓存数据。
+//   - store: 已初始化的状态存储，用于缓存数据。
 //
 // 返回值:
 //   - *OAuthRepo: 配置完成的 OAuth 仓储实例指针。
-func NewOAuthRepo(db *gorm.DB, rdb *redis.Client) *OAuthRepo {
+func NewOAuthRepo(db *gorm.DB, store bSdkStore.Store) *OAuthRepo {
+	return NewOAuthRepoWith(db, store, envConfig())
+}
+
+// NewOAuthRepoWith 按 SDK 配置创建 OAuth 仓储实例，cfg 为 nil 时使用默认配置。
+func NewOAuthRepoWith(db *gorm.DB, store bSdkStore.Store, cfg *bSdkConfig.Config) *OAuthRepo {
 	return &OAuthRepo{
 		db:    db,
-		cache: bSdkCache.NewOAuthCache(rdb),
+		cache: bSdkCache.NewOAuthCache(store, repoConfig(cfg).Cache),
 		log:   xLog.WithName(xLog.NamedREPO, "OAuthRepo"),
 	}
 }
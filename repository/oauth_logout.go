@@ -0,0 +1,87 @@
+package bSdkRepo
+
+import (
+	"context"
+	"time"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	"gorm.io/gorm"
+)
+
+// OAuthLogoutRepo 登出数据仓储层，负责登出令牌的防重放记录。
+type OAuthLogoutRepo struct {
+	db    *gorm.DB
+	cache *bSdkCache.OAuthLogoutCache
+	log   *xLog.LogNamedLogger
+}
+
+// NewOAuthLogoutRepo 创建并初始化一个登出仓储实例。
+//
+// 缓存配置按 `SSO_CACHE_*` 环境变量读取，使用显式 SDK 配置时请改用 `NewOAuthLogoutRepoWith`。
+//
+// 参数:
+//   - db: 已初始化的 GORM 数据库实例（备用）。
+//   - store: 已初始化的状态存储，用于缓存数据。
+//
+// 返回值:
+//   - *OAuthLogoutRepo: 配置完成的登出仓储实例指针。
+func NewOAuthLogoutRepo(db *gorm.DB, store bSdkStore.Store) *OAuthLogoutRepo {
+	return NewOAuthLogoutRepoWith(db, store, envConfig())
+}
+
+// NewOAuthLogoutRepoWith 按 SDK 配置创建登出仓储实例，cfg 为 nil 时使用默认配置。
+func NewOAuthLogoutRepoWith(db *gorm.DB, store bSdkStore.Store, cfg *bSdkConfig.Config) *OAuthLogoutRepo {
+	return &OAuthLogoutRepo{
+		db:    db,
+		cache: bSdkCache.NewOAuthLogoutCache(store, repoConfig(cfg).Cache),
+		log:   xLog.WithName(xLog.NamedREPO, "OAuthLogoutRepo"),
+	}
+}
+
+// ClaimJti 占用登出令牌的 jti，重复出现时返回 `RepeatOperation` 错误。
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - jti: 登出令牌的唯一标识。
+//   - ttl: 占用记录的保留时长，小于等于 0 时使用默认 TTL。
+//
+// 返回值:
+//   - *xError.Error: jti 为空、重复或写入失败时返回错误。
+func (r *OAuthLogoutRepo) ClaimJti(ctx context.Context, jti string, ttl time.Duration) *xError.Error {
+	if jti == "" {
+		return xError.NewError(ctx, xError.ParameterEmpty, "jti 为空", false, nil)
+	}
+
+	claimed, err := r.cache.Claim(ctx, jti, ttl)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "写入登出令牌记录失败", false, err)
+	}
+	if !claimed {
+		return xError.NewError(ctx, xError.RepeatOperation, "登出令牌已被使用", false, nil)
+	}
+
+	return nil
+}
+
+// ReleaseJti 释放登出令牌 jti 的占用，用于处理失败后允许 SSO 重试。
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - jti: 登出令牌的唯一标识。
+//
+// 返回值:
+//   - *xError.Error: jti 为空或删除失败时返回错误。
+func (r *OAuthLogoutRepo) ReleaseJti(ctx context.Context, jti string) *xError.Error {
+	if jti == "" {
+		return xError.NewError(ctx, xError.ParameterEmpty, "jti 为空", false, nil)
+	}
+
+	if err := r.cache.Release(ctx, jti); err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "删除登出令牌记录失败", false, err)
+	}
+	return nil
+}
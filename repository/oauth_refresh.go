@@ -0,0 +1,95 @@
+package bSdkRepo
+
+import (
+	"context"
+	"time"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"gorm.io/gorm"
+)
+
+// OAuthRefreshRepo 刷新令牌并发控制数据仓储层，负责刷新锁与近期刷新结果的读写。
+type OAuthRefreshRepo struct {
+	db    *gorm.DB
+	cache *bSdkCache.OAuthRefreshCache
+	log   *xLog.LogNamedLogger
+}
+
+// NewOAuthRefreshRepo 创建并初始化一个刷新令牌并发控制仓储实例。
+//
+// 缓存配置按 `SSO_CACHE_*` 环境变量读取，使用显式 SDK 配置时请改用 `NewOAuthRefreshRepoWith`。
+//
+// 参数:
+//   - db: 已初始化的 GORM 数据库实例（备用）。
+//   - store: 已初始化的状态存储，用于缓存数据。
+//
+// 返回值:
+//   - *OAuthRefreshRepo: 配置完成的仓储实例指针。
+func NewOAuthRefreshRepo(db *gorm.DB, store bSdkStore.Store) *OAuthRefreshRepo {
+	return NewOAuthRefreshRepoWith(db, store, envConfig())
+}
+
+// NewOAuthRefreshRepoWith 按 SDK 配置创建刷新令牌并发控制仓储实例，cfg 为 nil 时使用默认配置。
+func NewOAuthRefreshRepoWith(db *gorm.DB, store bSdkStore.Store, cfg *bSdkConfig.Config) *OAuthRefreshRepo {
+	return &OAuthRefreshRepo{
+		db:    db,
+		cache: bSdkCache.NewOAuthRefreshCache(store, repoConfig(cfg).Cache),
+		log:   xLog.WithName(xLog.NamedREPO, "OAuthRefreshRepo"),
+	}
+}
+
+// Lock 尝试获取刷新锁，锁已被占用时返回 false。
+func (r *OAuthRefreshRepo) Lock(ctx context.Context, key string, owner string, ttl time.Duration) (bool, *xError.Error) {
+	locked, err := r.cache.Lock(ctx, key, owner, ttl)
+	if err != nil {
+		return false, xError.NewError(ctx, xError.OperationFailed, "获取刷新锁失败", false, err)
+	}
+	return locked, nil
+}
+
+// Unlock 释放刷新锁。
+func (r *OAuthRefreshRepo) Unlock(ctx context.Context, key string, owner string) *xError.Error {
+	if err := r.cache.Unlock(ctx, key, owner); err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "释放刷新锁失败", false, err)
+	}
+	return nil
+}
+
+// GetRecent 根据旧刷新令牌读取近期刷新结果，不存在时返回 nil。
+func (r *OAuthRefreshRepo) GetRecent(ctx context.Context, refreshToken string) (*bSdkModels.CacheOAuthToken, *xError.Error) {
+	if refreshToken == "" {
+		return nil, xError.NewError(ctx, xError.ParameterEmpty, "刷新令牌为空", false, nil)
+	}
+
+	fingerprint, err := bSdkUtil.TokenFingerprint(refreshToken)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	token, err := r.cache.GetRecent(ctx, fingerprint)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "读取近期刷新结果失败", false, err)
+	}
+	return token, nil
+}
+
+// SetRecent 以旧刷新令牌为键写入近期刷新结果。
+func (r *OAuthRefreshRepo) SetRecent(ctx context.Context, refreshToken string, token *bSdkModels.CacheOAuthToken) *xError.Error {
+	if refreshToken == "" {
+		return xError.NewError(ctx, xError.ParameterEmpty, "刷新令牌为空", false, nil)
+	}
+
+	fingerprint, err := bSdkUtil.TokenFingerprint(refreshToken)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	if err = r.cache.SetRecent(ctx, fingerprint, token); err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "写入近期刷新结果失败", false, err)
+	}
+	return nil
+}
@@ -2,49 +2,114 @@ package bSdkRepo
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
 	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
-	"github.com/redis/go-redis/v9"
+	bSdkDatabase "github.com/phalanx-labs/beacon-sso-sdk/repository/database"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 	"gorm.io/gorm"
 )
 
 // OAuthTokenRepo OAuth 令牌数据仓储层，负责管理已换取的访问令牌缓存。
 //
 // 该结构体专注于 Token 的缓存管理，与 OAuthRepo 分离以保持职责单一。
+// 启用令牌持久化（`token.persistence`）后令牌同时写入数据库：Redis 作为热缓存，
+// 缓存未命中时回源数据库并回填缓存，会话索引查询合并两者结果。
+//
+// 令牌以访问令牌指纹为键，在各身份提供方之间共享；sid/sub 会话索引按令牌所属的身份提供方隔离，
+// 命名身份提供方的索引位于其缓存键前缀下，不同提供方的同名用户或会话互不影响。
 type OAuthTokenRepo struct {
-	db    *gorm.DB
-	cache *bSdkCache.OAuthTokenCache
-	log   *xLog.LogNamedLogger
+	db      *gorm.DB
+	cache   *bSdkCache.OAuthTokenCache
+	session *bSdkCache.OAuthSessionCache  // 默认身份提供方的会话索引
+	base    bSdkStore.Store               // 底层状态存储，用于派生命名身份提供方的会话索引
+	cfg     *bSdkConfig.Config            // SDK 配置，提供缓存配置与身份提供方键前缀
+	store   *bSdkDatabase.OAuthTokenStore // 持久化存储，未启用时为 nil
+	legacy  bool                          // 是否读取并迁移旧格式令牌缓存
+	log     *xLog.LogNamedLogger
 }
 
 // NewOAuthTokenRepo 创建并初始化一个 OAuth 令牌仓储实例。
 //
+// 令牌存储与缓存配置按 `SSO_TOKEN_PERSISTENCE`、`SSO_TOKEN_LEGACY_READ`（默认关闭）与 `SSO_CACHE_*` 环境变量读取，
+// 使用显式 SDK 配置时请改用 `NewOAuthTokenRepoWith`。
+//
+// 参数:
+//   - db: 已初始化的 GORM 数据库实例，启用令牌持久化时使用。
+//   - store: 已初始化的状态存储，用于缓存数据。
+//
+// 返回值:
+//   - *OAuthTokenRepo: 配置完成的令牌仓储实例指针。
+func NewOAuthTokenRepo(db *gorm.DB, store bSdkStore.Store) *OAuthTokenRepo {
+	return NewOAuthTokenRepoWith(db, store, envConfig())
+}
+
+// NewOAuthTokenRepoWith 按 SDK 配置创建 OAuth 令牌仓储实例。
+//
 // 参数:
-//   - db: 已初始化的 GORM 数据库实例（备用）。
-//   - rdb: 已初始化的 Redis 客户端，用于缓存数据。
+//   - db: 已初始化的 GORM 数据库实例，`token.persistence` 为 true 时用于令牌持久化。
+//   - store: 已初始化的状态存储，用于缓存数据。
+//   - cfg: SDK 配置，读取其中的 `token` 与 `cache`；为 nil 时使用默认配置。
 //
 // 返回值:
 //   - *OAuthTokenRepo: 配置完成的令牌仓储实例指针。
-func NewOAuthTokenRepo(db *gorm.DB, rdb *redis.Client) *OAuthTokenRepo {
-	return &OAuthTokenRepo{
-		db:    db,
-		cache: bSdkCache.NewOAuthTokenCache(rdb),
-		log:   xLog.WithName(xLog.NamedREPO, "OAuthTokenRepo"),
+func NewOAuthTokenRepoWith(db *gorm.DB, store bSdkStore.Store, cfg *bSdkConfig.Config) *OAuthTokenRepo {
+	cfg = repoConfig(cfg)
+	repo := &OAuthTokenRepo{
+		db:      db,
+		cache:   bSdkCache.NewOAuthTokenCache(store, cfg.Cache),
+		session: bSdkCache.NewOAuthSessionCache(store, cfg.Cache),
+		base:    store,
+		cfg:     cfg,
+		legacy:  cfg.Token.LegacyRead,
+		log:     xLog.WithName(xLog.NamedREPO, "OAuthTokenRepo"),
 	}
+	if db != nil && cfg.Token.Persistence {
+		repo.store = bSdkDatabase.NewOAuthTokenStore(db, repo.cache.TTL)
+	}
+	return repo
 }
 
 func (r *OAuthTokenRepo) Store(ctx context.Context, token *bSdkModels.CacheOAuthToken) *xError.Error {
 	if token == nil || token.AccessToken == "" {
 		return xError.NewError(ctx, xError.ParameterEmpty, "令牌为空", false, nil)
 	}
+	if token.Tenant == "" {
+		token.Tenant = bSdkUtil.GetTenant(ctx)
+	}
+	fingerprint, err := bSdkUtil.TokenFingerprint(token.AccessToken)
+	if err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
 
+	if r.store != nil {
+		if err := r.store.Save(ctx, token); err != nil {
+			return xError.NewError(ctx, xError.OperationFailed, "写入令牌持久化存储失败", false, err)
+		}
+	}
 	if err := r.cache.SetAllStruct(ctx, token.AccessToken, token); err != nil {
 		return xError.NewError(ctx, xError.OperationFailed, "写入令牌缓存失败", false, err)
 	}
 
+	// 建立 sid/sub 反向索引，供登出通知定位令牌；失败不影响令牌本身的写入
+	session := r.sessionFor(token.Provider)
+	if token.SessionID != "" {
+		if err := session.AddBySessionID(ctx, token.SessionID, fingerprint); err != nil {
+			r.log.Warn(ctx, "OAuthTokenRepo|Store - 写入会话索引失败", slog.String("error", err.Error()))
+		}
+	}
+	if token.Subject != "" {
+		if err := session.AddBySubject(ctx, token.Subject, fingerprint); err != nil {
+			r.log.Warn(ctx, "OAuthTokenRepo|Store - 写入用户索引失败", slog.String("error", err.Error()))
+		}
+	}
+
 	return nil
 }
 
@@ -53,14 +118,87 @@ func (r *OAuthTokenRepo) Get(ctx context.Context, accessToken string) (*bSdkMode
 		return nil, xError.NewError(ctx, xError.ParameterEmpty, "令牌为空", false, nil)
 	}
 
-	values, err := r.cache.GetAllStruct(ctx, accessToken)
+	values, stale, err := r.cache.GetSealedStruct(ctx, accessToken)
 	if err != nil {
 		return nil, xError.NewError(ctx, xError.OperationFailed, "读取令牌缓存失败", false, err)
 	}
+	if values.AccessToken == "" && r.legacy {
+		legacy, xErr := r.migrateLegacy(ctx, accessToken)
+		if xErr != nil || legacy.AccessToken != "" {
+			return legacy, xErr
+		}
+	}
+	if values.AccessToken == "" && r.store != nil {
+		return r.loadFromStore(ctx, accessToken)
+	}
+	if stale && values.AccessToken != "" {
+		// 缓存由非活动密钥加密，使用活动密钥惰性重新加密；失败不影响本次读取
+		if err = r.cache.Reseal(ctx, accessToken, values); err != nil {
+			r.log.Warn(ctx, "OAuthTokenRepo|Get - 令牌缓存重新加密失败", slog.String("error", err.Error()))
+		}
+	}
 
 	return values, nil
 }
 
+// migrateLegacy 读取旧格式（明文键、明文值）的令牌缓存，命中时改写为新格式并删除旧键。
+//
+// 迁移失败仅记录告警并返回读取到的旧数据，旧键会在原 TTL 到期后自然淘汰。
+func (r *OAuthTokenRepo) migrateLegacy(ctx context.Context, accessToken string) (*bSdkModels.CacheOAuthToken, *xError.Error) {
+	legacy, err := r.cache.GetLegacyStruct(ctx, accessToken)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "读取令牌缓存失败", false, err)
+	}
+	if legacy.AccessToken == "" {
+		return legacy, nil
+	}
+
+	r.log.Info(ctx, "OAuthTokenRepo|migrateLegacy - 迁移旧格式令牌缓存")
+	if xErr := r.Store(ctx, legacy); xErr != nil {
+		r.log.Warn(ctx, "OAuthTokenRepo|migrateLegacy - 写入新格式缓存失败", slog.String("error", xErr.Error()))
+		return legacy, nil
+	}
+	if err = r.cache.DeleteLegacy(ctx, accessToken); err != nil {
+		r.log.Warn(ctx, "OAuthTokenRepo|migrateLegacy - 删除旧格式缓存失败", slog.String("error", err.Error()))
+	}
+
+	return legacy, nil
+}
+
+// loadFromStore 缓存未命中时从持久化存储读取令牌并回填缓存，回填失败仅记录告警。
+func (r *OAuthTokenRepo) loadFromStore(ctx context.Context, accessToken string) (*bSdkModels.CacheOAuthToken, *xError.Error) {
+	fingerprint, err := bSdkUtil.TokenFingerprint(accessToken)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	token, err := r.store.Get(ctx, fingerprint)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "读取令牌持久化存储失败", false, err)
+	}
+	if token == nil || token.Tenant != bSdkUtil.GetTenant(ctx) {
+		// 持久化存储不区分命名空间，其他租户的令牌视为未命中
+		return &bSdkModels.CacheOAuthToken{}, nil
+	}
+
+	r.log.Info(ctx, "OAuthTokenRepo|loadFromStore - 从持久化存储恢复令牌缓存")
+	if err = r.cache.SetAllStruct(ctx, token.AccessToken, token); err != nil {
+		r.log.Warn(ctx, "OAuthTokenRepo|loadFromStore - 回填令牌缓存失败", slog.String("error", err.Error()))
+		return token, nil
+	}
+	session := r.sessionFor(token.Provider)
+	if token.SessionID != "" {
+		if err = session.AddBySessionID(ctx, token.SessionID, fingerprint); err != nil {
+			r.log.Warn(ctx, "OAuthTokenRepo|loadFromStore - 回填会话索引失败", slog.String("error", err.Error()))
+		}
+	}
+	if token.Subject != "" {
+		if err = session.AddBySubject(ctx, token.Subject, fingerprint); err != nil {
+			r.log.Warn(ctx, "OAuthTokenRepo|loadFromStore - 回填用户索引失败", slog.String("error", err.Error()))
+		}
+	}
+	return token, nil
+}
+
 func (r *OAuthTokenRepo) Delete(ctx context.Context, accessToken string) *xError.Error {
 	if accessToken == "" {
 		return xError.NewError(ctx, xError.ParameterEmpty, "令牌为空", false, nil)
@@ -69,6 +207,152 @@ func (r *OAuthTokenRepo) Delete(ctx context.Context, accessToken string) *xError
 	if err := r.cache.Delete(ctx, accessToken); err != nil {
 		return xError.NewError(ctx, xError.OperationFailed, "删除令牌缓存失败", false, err)
 	}
+	if r.store != nil {
+		fingerprint, err := bSdkUtil.TokenFingerprint(accessToken)
+		if err != nil {
+			return xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+		}
+		if err = r.store.Delete(ctx, fingerprint); err != nil {
+			return xError.NewError(ctx, xError.OperationFailed, "删除令牌持久化记录失败", false, err)
+		}
+	}
 
 	return nil
 }
+
+// DeleteByFingerprint 根据令牌指纹删除令牌缓存。
+func (r *OAuthTokenRepo) DeleteByFingerprint(ctx context.Context, fingerprint string) *xError.Error {
+	if fingerprint == "" {
+		return xError.NewError(ctx, xError.ParameterEmpty, "令牌指纹为空", false, nil)
+	}
+
+	if err := r.cache.DeleteByFingerprint(ctx, fingerprint); err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "删除令牌缓存失败", false, err)
+	}
+	if r.store != nil {
+		if err := r.store.Delete(ctx, fingerprint); err != nil {
+			return xError.NewError(ctx, xError.OperationFailed, "删除令牌持久化记录失败", false, err)
+		}
+	}
+
+	return nil
+}
+
+// FamilyIDByFingerprint 根据访问令牌指纹查询其所属的刷新令牌家族，缓存未命中时回源持久化存储；未关联家族时返回空字符串。
+func (r *OAuthTokenRepo) FamilyIDByFingerprint(ctx context.Context, fingerprint string) (string, *xError.Error) {
+	if fingerprint == "" {
+		return "", xError.NewError(ctx, xError.ParameterEmpty, "令牌指纹为空", false, nil)
+	}
+
+	familyID, err := r.cache.FamilyIDByFingerprint(ctx, fingerprint)
+	if err != nil {
+		return "", xError.NewError(ctx, xError.OperationFailed, "读取令牌缓存失败", false, err)
+	}
+	if familyID == "" && r.store != nil {
+		token, storeErr := r.store.Get(ctx, fingerprint)
+		if storeErr != nil {
+			return "", xError.NewError(ctx, xError.OperationFailed, "读取令牌持久化存储失败", false, storeErr)
+		}
+		if token != nil && token.Tenant == bSdkUtil.GetTenant(ctx) {
+			familyID = token.FamilyID
+		}
+	}
+	return familyID, nil
+}
+
+// ListBySessionID 根据 SSO 会话标识（sid）列出指定身份提供方下本地关联的访问令牌指纹，provider 为空表示默认提供方。
+func (r *OAuthTokenRepo) ListBySessionID(ctx context.Context, provider string, sid string) ([]string, *xError.Error) {
+	if sid == "" {
+		return nil, xError.NewError(ctx, xError.ParameterEmpty, "会话标识为空", false, nil)
+	}
+
+	tokens, err := r.sessionFor(provider).MembersBySessionID(ctx, sid)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "读取会话索引失败", false, err)
+	}
+	if r.store != nil {
+		stored, storeErr := r.store.FingerprintsBySessionID(ctx, provider, sid)
+		if storeErr != nil {
+			return nil, xError.NewError(ctx, xError.OperationFailed, "读取会话持久化记录失败", false, storeErr)
+		}
+		tokens = mergeFingerprints(tokens, stored)
+	}
+
+	return tokens, nil
+}
+
+// ListBySubject 根据用户标识（sub）列出指定身份提供方下本地关联的访问令牌指纹，provider 为空表示默认提供方。
+func (r *OAuthTokenRepo) ListBySubject(ctx context.Context, provider string, sub string) ([]string, *xError.Error) {
+	if sub == "" {
+		return nil, xError.NewError(ctx, xError.ParameterEmpty, "用户标识为空", false, nil)
+	}
+
+	tokens, err := r.sessionFor(provider).MembersBySubject(ctx, sub)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "读取用户索引失败", false, err)
+	}
+	if r.store != nil {
+		stored, storeErr := r.store.FingerprintsBySubject(ctx, provider, sub)
+		if storeErr != nil {
+			return nil, xError.NewError(ctx, xError.OperationFailed, "读取用户持久化记录失败", false, storeErr)
+		}
+		tokens = mergeFingerprints(tokens, stored)
+	}
+
+	return tokens, nil
+}
+
+// DeleteIndex 删除指定身份提供方的 sid/sub 反向索引，参数为空时跳过对应索引。
+func (r *OAuthTokenRepo) DeleteIndex(ctx context.Context, provider string, sid string, sub string) *xError.Error {
+	session := r.sessionFor(provider)
+	if sid != "" {
+		if err := session.DeleteBySessionID(ctx, sid); err != nil {
+			return xError.NewError(ctx, xError.OperationFailed, "删除会话索引失败", false, err)
+		}
+	}
+	if sub != "" {
+		if err := session.DeleteBySubject(ctx, sub); err != nil {
+			return xError.NewError(ctx, xError.OperationFailed, "删除用户索引失败", false, err)
+		}
+	}
+
+	return nil
+}
+
+// PurgeExpired 清理持久化存储中清理时间早于 before 的令牌记录，未启用持久化时直接返回；
+// 启用持久化时由启动节点 `tokenPersistence` 按 `storage.purge_interval` 定期调用。
+func (r *OAuthTokenRepo) PurgeExpired(ctx context.Context, before time.Time) (int64, *xError.Error) {
+	if r.store == nil {
+		return 0, nil
+	}
+
+	count, err := r.store.PurgeExpired(ctx, before)
+	if err != nil {
+		return 0, xError.NewError(ctx, xError.OperationFailed, "清理过期令牌记录失败", false, err)
+	}
+	return count, nil
+}
+
+// sessionFor 返回身份提供方的会话索引，命名身份提供方的索引位于其缓存键前缀下。
+func (r *OAuthTokenRepo) sessionFor(provider string) *bSdkCache.OAuthSessionCache {
+	if provider == "" {
+		return r.session
+	}
+	return bSdkCache.NewOAuthSessionCache(bSdkStore.NewPrefixStore(r.base, r.cfg.ProviderKeyPrefix(provider)), r.cfg.Cache)
+}
+
+// mergeFingerprints 合并两组令牌指纹并去重，保持首次出现的顺序。
+func mergeFingerprints(groups ...[]string) []string {
+	seen := make(map[string]struct{})
+	merged := make([]string, 0)
+	for _, group := range groups {
+		for _, fingerprint := range group {
+			if _, exist := seen[fingerprint]; exist {
+				continue
+			}
+			seen[fingerprint] = struct{}{}
+			merged = append(merged, fingerprint)
+		}
+	}
+	return merged
+}
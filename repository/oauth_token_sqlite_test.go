@@ -0,0 +1,144 @@
+//go:build sqlite && cgo
+
+package bSdkRepo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkDatabase "github.com/phalanx-labs/beacon-sso-sdk/repository/database"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newSQLiteDB 创建已迁移令牌表的内存 SQLite 数据库，并配置令牌加密密钥。
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	if _, err := bSdkUtil.ConfigureTokenKeys(context.Background(), bSdkConfig.New(bSdkConfig.WithClient("cid", "csecret"))); err != nil {
+		t.Fatalf("配置令牌密钥失败: %v", err)
+	}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接池失败: %v", err)
+	}
+	// 内存数据库按连接隔离，限制为单连接保证所有查询访问同一个库
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err = bSdkDatabase.AutoMigrate(context.Background(), db); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+	return db
+}
+
+func TestOAuthTokenRepoPersistence(t *testing.T) {
+	db := newSQLiteDB(t)
+	cfg := &bSdkConfig.Config{Token: bSdkConfig.TokenConfig{Persistence: true}}
+	tenantCtx := bSdkUtil.WithTenant(context.Background(), "shop-a", "tenant:shop-a:")
+
+	// newRepo 使用独立的状态存储创建仓储，模拟 Redis 被清空后的缓存未命中
+	newRepo := func() (*OAuthTokenRepo, bSdkStore.Store) {
+		store := bSdkStore.NewMemoryStore(time.Minute)
+		return NewOAuthTokenRepoWith(db, store, cfg), store
+	}
+
+	writer, _ := newRepo()
+	token := &bSdkModels.CacheOAuthToken{
+		AccessToken:  "persist-at",
+		RefreshToken: "persist-rt",
+		TokenType:    "Bearer",
+		Expiry:       time.Now().Add(time.Hour).Format(time.RFC3339),
+		Subject:      "user-1",
+		SessionID:    "sid-1",
+	}
+	if xErr := writer.Store(tenantCtx, token); xErr != nil {
+		t.Fatalf("写入令牌失败: %v", xErr)
+	}
+	if token.Tenant != "shop-a" {
+		t.Fatalf("写入时应记录所属租户，实际 %q", token.Tenant)
+	}
+	fingerprint, _ := bSdkUtil.TokenFingerprint(token.AccessToken)
+
+	t.Run("缓存未命中时从数据库恢复并回填", func(t *testing.T) {
+		repo, store := newRepo()
+		got, xErr := repo.Get(tenantCtx, token.AccessToken)
+		if xErr != nil || got.AccessToken != token.AccessToken || got.RefreshToken != token.RefreshToken {
+			t.Fatalf("期望从数据库恢复令牌，实际 %+v, %v", got, xErr)
+		}
+
+		cached, _, err := bSdkCache.NewOAuthTokenCache(store, cfg.Cache).GetSealedStruct(tenantCtx, token.AccessToken)
+		if err != nil || cached.AccessToken != token.AccessToken {
+			t.Fatalf("恢复后应回填缓存，实际 %+v, %v", cached, err)
+		}
+		members, err := bSdkCache.NewOAuthSessionCache(store, cfg.Cache).MembersBySessionID(tenantCtx, "sid-1")
+		if err != nil || len(members) != 1 || members[0] != fingerprint {
+			t.Fatalf("恢复后应回填会话索引，实际 %v, %v", members, err)
+		}
+	})
+
+	t.Run("其他租户视为未命中", func(t *testing.T) {
+		repo, store := newRepo()
+		otherCtx := bSdkUtil.WithTenant(context.Background(), "shop-b", "tenant:shop-b:")
+		got, xErr := repo.Get(otherCtx, token.AccessToken)
+		if xErr != nil || got.AccessToken != "" {
+			t.Fatalf("其他租户不应读取到令牌，实际 %+v, %v", got, xErr)
+		}
+		got, xErr = repo.Get(context.Background(), token.AccessToken)
+		if xErr != nil || got.AccessToken != "" {
+			t.Fatalf("未绑定租户时不应读取到租户令牌，实际 %+v, %v", got, xErr)
+		}
+		if cached, _, _ := bSdkCache.NewOAuthTokenCache(store, cfg.Cache).GetSealedStruct(otherCtx, token.AccessToken); cached.AccessToken != "" {
+			t.Fatalf("未命中时不应回填缓存")
+		}
+	})
+
+	t.Run("会话索引合并持久化记录", func(t *testing.T) {
+		repo, _ := newRepo()
+		fingerprints, xErr := repo.ListBySessionID(tenantCtx, "", "sid-1")
+		if xErr != nil || len(fingerprints) != 1 || fingerprints[0] != fingerprint {
+			t.Fatalf("期望从数据库列出会话令牌，实际 %v, %v", fingerprints, xErr)
+		}
+	})
+
+	t.Run("删除同时软删除持久化记录", func(t *testing.T) {
+		if xErr := writer.Delete(tenantCtx, token.AccessToken); xErr != nil {
+			t.Fatalf("删除失败: %v", xErr)
+		}
+		repo, _ := newRepo()
+		got, xErr := repo.Get(tenantCtx, token.AccessToken)
+		if xErr != nil || got.AccessToken != "" {
+			t.Fatalf("删除后不应再从数据库恢复，实际 %+v, %v", got, xErr)
+		}
+	})
+
+	t.Run("清理过期记录", func(t *testing.T) {
+		expired := &bSdkModels.CacheOAuthToken{
+			AccessToken: "persist-expired-at",
+			Expiry:      time.Now().Add(-time.Hour).Format(time.RFC3339),
+		}
+		if xErr := writer.Store(tenantCtx, expired); xErr != nil {
+			t.Fatalf("写入令牌失败: %v", xErr)
+		}
+		purged, xErr := writer.PurgeExpired(tenantCtx, time.Now())
+		if xErr != nil || purged != 1 {
+			t.Fatalf("期望清理 1 条过期记录，实际 %d, %v", purged, xErr)
+		}
+
+		disabled := NewOAuthTokenRepoWith(db, bSdkStore.NewMemoryStore(time.Minute), &bSdkConfig.Config{})
+		if purged, xErr = disabled.PurgeExpired(tenantCtx, time.Now()); xErr != nil || purged != 0 {
+			t.Fatalf("未启用持久化时不应清理，实际 %d, %v", purged, xErr)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package bSdkRepo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+func TestOAuthTokenRepoLegacyRead(t *testing.T) {
+	ctx := context.Background()
+	if _, err := bSdkUtil.ConfigureTokenKeys(ctx, bSdkConfig.New(bSdkConfig.WithClient("cid", "csecret"))); err != nil {
+		t.Fatalf("配置令牌密钥失败: %v", err)
+	}
+	store := bSdkStore.NewMemoryStore(time.Minute)
+	legacyKey := bSdkConst.RedisOAuthTokenLegacy.GetWithPrefix("", "legacy-at").String()
+	writeLegacy := func() {
+		t.Helper()
+		err := store.HSet(ctx, legacyKey, map[string]string{
+			"access_token":  "legacy-at",
+			"refresh_token": "legacy-rt",
+			"token_type":    "Bearer",
+			"expiry":        time.Now().Add(time.Hour).Format(time.RFC3339),
+		}, time.Minute)
+		if err != nil {
+			t.Fatalf("写入旧格式缓存失败: %v", err)
+		}
+	}
+
+	t.Run("默认不读取旧格式缓存", func(t *testing.T) {
+		writeLegacy()
+		repo := NewOAuthTokenRepoWith(nil, store, bSdkConfig.Default())
+		got, xErr := repo.Get(ctx, "legacy-at")
+		if xErr != nil || got.AccessToken != "" {
+			t.Fatalf("未开启兼容读取时不应读取旧格式缓存，实际 %+v, %v", got, xErr)
+		}
+		if values, _ := store.HGetAll(ctx, legacyKey); len(values) == 0 {
+			t.Fatalf("未开启兼容读取时不应删除旧格式缓存")
+		}
+	})
+
+	t.Run("开启后迁移旧格式缓存", func(t *testing.T) {
+		writeLegacy()
+		repo := NewOAuthTokenRepoWith(nil, store, &bSdkConfig.Config{Token: bSdkConfig.TokenConfig{LegacyRead: true}})
+		got, xErr := repo.Get(ctx, "legacy-at")
+		if xErr != nil || got.AccessToken != "legacy-at" || got.RefreshToken != "legacy-rt" {
+			t.Fatalf("期望读取到旧格式缓存，实际 %+v, %v", got, xErr)
+		}
+		if values, _ := store.HGetAll(ctx, legacyKey); len(values) != 0 {
+			t.Fatalf("迁移后应删除旧格式缓存，剩余 %v", values)
+		}
+
+		// 迁移后关闭兼容读取仍可读取新格式缓存
+		off := NewOAuthTokenRepoWith(nil, store, &bSdkConfig.Config{})
+		if got, xErr = off.Get(ctx, "legacy-at"); xErr != nil || got.RefreshToken != "legacy-rt" {
+			t.Fatalf("期望读取到迁移后的缓存，实际 %+v, %v", got, xErr)
+		}
+	})
+}
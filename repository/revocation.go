@@ -0,0 +1,87 @@
+package bSdkRepo
+
+import (
+	"context"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// RevocationRepo 令牌吊销广播数据仓储层，负责吊销消息的发布与订阅。
+type RevocationRepo struct {
+	db    *gorm.DB
+	store bSdkStore.Store
+	cache *bSdkCache.RevocationCache
+	log   *xLog.LogNamedLogger
+}
+
+// NewRevocationRepo 创建并初始化一个令牌吊销广播仓储实例。
+//
+// 参数:
+//   - db: 已初始化的 GORM 数据库实例（备用）。
+//   - rdb: 已初始化的 Redis 客户端，用于发布与订阅广播；为 nil 时（非 Redis 存储）广播被禁用。
+//   - store: SDK 状态存储，收到广播时失效其所属的一级缓存（参见 `bSdkCache.NewLocalStore`）。
+//
+// 返回值:
+//   - *RevocationRepo: 配置完成的仓储实例指针。
+func NewRevocationRepo(db *gorm.DB, rdb *redis.Client, store bSdkStore.Store) *RevocationRepo {
+	repo := &RevocationRepo{
+		db:    db,
+		store: store,
+		log:   xLog.WithName(xLog.NamedREPO, "RevocationRepo"),
+	}
+	if rdb != nil {
+		repo.cache = bSdkCache.NewRevocationCache(rdb)
+	}
+	return repo
+}
+
+// Enabled 报告是否具备跨实例广播能力（即是否注入了 Redis 客户端）。
+func (r *RevocationRepo) Enabled() bool {
+	return r.cache != nil
+}
+
+// Publish 发布吊销消息，广播被禁用时直接返回。
+func (r *RevocationRepo) Publish(ctx context.Context, message *bSdkModels.RevocationMessage) *xError.Error {
+	if message == nil || len(message.Fingerprints) == 0 {
+		return xError.NewError(ctx, xError.ParameterEmpty, "吊销指纹为空", false, nil)
+	}
+
+	if r.cache == nil {
+		return nil
+	}
+	if err := r.cache.Publish(ctx, message); err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "发布吊销消息失败", false, err)
+	}
+	return nil
+}
+
+// Subscribe 订阅吊销广播，调用方负责关闭返回的订阅；广播被禁用时返回 nil。
+func (r *RevocationRepo) Subscribe(ctx context.Context) *redis.PubSub {
+	if r.cache == nil {
+		return nil
+	}
+	return r.cache.Subscribe(ctx)
+}
+
+// Decode 解析收到的吊销消息。
+func (r *RevocationRepo) Decode(ctx context.Context, payload string) (*bSdkModels.RevocationMessage, *xError.Error) {
+	message, err := r.cache.Decode(payload)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "解析吊销消息失败", false, err)
+	}
+	return message, nil
+}
+
+// InvalidateLocal 按指纹删除状态存储所属的一级缓存条目（一级缓存未启用时无操作）。
+func (r *RevocationRepo) InvalidateLocal(message *bSdkModels.RevocationMessage) {
+	if message == nil {
+		return
+	}
+	bSdkCache.InvalidateLocal(r.store, message.Fingerprints...)
+}
@@ -0,0 +1,267 @@
+package bSdkStore
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormStore 基于数据库的状态存储实现
+//
+// 数据保存在 `sso_store` 表中（见 `bSdkModels.StoreEntry`），过期行对读取不可见，
+// 由 `StartJanitor` 启动的后台协程定期调用 `PurgeExpired` 物理删除。适用于无 Redis 但需要多副本共享状态的部署。
+type GormStore struct {
+	DB *gorm.DB
+
+	janitor *Janitor
+}
+
+// NewGormStore 创建并初始化一个数据库状态存储实例
+//
+// 参数:
+//   - db: 已初始化的 GORM 数据库实例，表结构需已通过 `AutoMigrate` 迁移。
+//
+// 返回值:
+//   - *GormStore: 配置完成的存储实例指针。
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{DB: db}
+}
+
+// AutoMigrate 自动迁移状态存储表结构。
+func (s *GormStore) AutoMigrate(ctx context.Context) error {
+	return s.DB.WithContext(ctx).AutoMigrate(&bSdkModels.StoreEntry{})
+}
+
+func (s *GormStore) Get(ctx context.Context, key string) (string, bool, error) {
+	return s.HGet(ctx, key, "")
+}
+
+func (s *GormStore) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("store_key = ?", key).Delete(&bSdkModels.StoreEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&bSdkModels.StoreEntry{StoreKey: key, Value: value, ExpiresAt: expiresAt(ttl)}).Error
+	})
+}
+
+func (s *GormStore) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	created := false
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.purgeKey(tx, key); err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&bSdkModels.StoreEntry{StoreKey: key, Value: value, ExpiresAt: expiresAt(ttl)})
+		created = result.RowsAffected > 0
+		return result.Error
+	})
+	return created, err
+}
+
+func (s *GormStore) CompareAndDelete(ctx context.Context, key string, value string) (bool, error) {
+	result := s.live(ctx, key).Where("field = ? AND value = ?", "", value).Delete(&bSdkModels.StoreEntry{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *GormStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	var value int64
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.purgeKey(tx, key); err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&bSdkModels.StoreEntry{StoreKey: key, Value: "1", ExpiresAt: expiresAt(ttl)})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			value = 1
+			return nil
+		}
+
+		var entry bSdkModels.StoreEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("store_key = ? AND field = ?", key, "").Take(&entry).Error; err != nil {
+			return err
+		}
+		current, err := strconv.ParseInt(entry.Value, 10, 64)
+		if err != nil {
+			return err
+		}
+		value = current + 1
+		return tx.Model(&entry).Update("value", strconv.FormatInt(value, 10)).Error
+	})
+	return value, err
+}
+
+func (s *GormStore) HGet(ctx context.Context, key string, field string) (string, bool, error) {
+	var entry bSdkModels.StoreEntry
+	err := s.live(ctx, key).Where("field = ?", field).Take(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return entry.Value, true, nil
+}
+
+func (s *GormStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	var entries []bSdkModels.StoreEntry
+	if err := s.live(ctx, key).Where("field <> ?", "").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		result[entry.Field] = entry.Value
+	}
+	return result, nil
+}
+
+func (s *GormStore) HSet(ctx context.Context, key string, values map[string]string, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.purgeKey(tx, key); err != nil {
+			return err
+		}
+		return s.upsert(tx, key, values, ttl)
+	})
+}
+
+func (s *GormStore) HSetExisting(ctx context.Context, key string, values map[string]string) (bool, error) {
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	written := false
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var entry bSdkModels.StoreEntry
+		err := tx.Where("store_key = ? AND (expires_at IS NULL OR expires_at > ?)", key, time.Now()).Take(&entry).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		rows := make([]bSdkModels.StoreEntry, 0, len(values))
+		for field, value := range values {
+			rows = append(rows, bSdkModels.StoreEntry{StoreKey: key, Field: field, Value: value, ExpiresAt: entry.ExpiresAt})
+		}
+		written = true
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "store_key"}, {Name: "field"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value"}),
+		}).Create(&rows).Error
+	})
+	return written, err
+}
+
+func (s *GormStore) HExists(ctx context.Context, key string, field string) (bool, error) {
+	var count int64
+	err := s.live(ctx, key).Model(&bSdkModels.StoreEntry{}).Where("field = ?", field).Count(&count).Error
+	return count > 0, err
+}
+
+func (s *GormStore) HDel(ctx context.Context, key string, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return s.DB.WithContext(ctx).Where("store_key = ? AND field IN ?", key, fields).Delete(&bSdkModels.StoreEntry{}).Error
+}
+
+func (s *GormStore) SAdd(ctx context.Context, key string, member string, ttl time.Duration) error {
+	return s.HSet(ctx, key, map[string]string{member: ""}, ttl)
+}
+
+func (s *GormStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	members := make([]string, 0)
+	err := s.live(ctx, key).Model(&bSdkModels.StoreEntry{}).Where("field <> ?", "").Pluck("field", &members).Error
+	return members, err
+}
+
+func (s *GormStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.DB.WithContext(ctx).Where("store_key IN ?", keys).Delete(&bSdkModels.StoreEntry{}).Error
+}
+
+// PurgeExpired 物理删除所有已过期的记录，返回删除的行数。
+func (s *GormStore) PurgeExpired(ctx context.Context) (int64, error) {
+	result := s.DB.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&bSdkModels.StoreEntry{})
+	return result.RowsAffected, result.Error
+}
+
+// StartJanitor 启动后台协程，按 interval 定期调用 `PurgeExpired`
+//
+// 参数:
+//   - interval: 清理周期，`<= 0` 时不启动。
+//   - onResult: 每次清理后的回调（可为 nil），用于记录删除行数或错误。
+//
+// 重复调用会先停止已启动的清理协程；使用完毕后调用 `Close` 停止。
+func (s *GormStore) StartJanitor(interval time.Duration, onResult func(count int64, err error)) {
+	s.janitor.Close()
+	s.janitor = NewJanitor(interval, func(ctx context.Context) {
+		count, err := s.PurgeExpired(ctx)
+		if onResult != nil {
+			onResult(count, err)
+		}
+	})
+}
+
+// Close 停止后台清理协程。
+func (s *GormStore) Close() {
+	s.janitor.Close()
+}
+
+// live 构建仅包含指定键未过期记录的查询。
+func (s *GormStore) live(ctx context.Context, key string) *gorm.DB {
+	return s.DB.WithContext(ctx).Where("store_key = ? AND (expires_at IS NULL OR expires_at > ?)", key, time.Now())
+}
+
+// purgeKey 删除指定键已过期的记录，避免写入新字段时旧数据随过期时间刷新而“复活”。
+func (s *GormStore) purgeKey(tx *gorm.DB, key string) error {
+	return tx.Where("store_key = ? AND expires_at <= ?", key, time.Now()).Delete(&bSdkModels.StoreEntry{}).Error
+}
+
+// upsert 写入字段并按 ttl 刷新整个键的过期时间。
+func (s *GormStore) upsert(tx *gorm.DB, key string, values map[string]string, ttl time.Duration) error {
+	expires := expiresAt(ttl)
+	rows := make([]bSdkModels.StoreEntry, 0, len(values))
+	for field, value := range values {
+		rows = append(rows, bSdkModels.StoreEntry{StoreKey: key, Field: field, Value: value, ExpiresAt: expires})
+	}
+	columns := []string{"value"}
+	if expires != nil {
+		columns = append(columns, "expires_at")
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "store_key"}, {Name: "field"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).Create(&rows).Error; err != nil {
+		return err
+	}
+	if expires == nil {
+		return nil
+	}
+	return tx.Model(&bSdkModels.StoreEntry{}).Where("store_key = ?", key).Update("expires_at", expires).Error
+}
+
+func expiresAt(ttl time.Duration) *time.Time {
+	if ttl <= 0 {
+		return nil
+	}
+	expires := time.Now().Add(ttl)
+	return &expires
+}
@@ -0,0 +1,102 @@
+package bSdkStore
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// EncodeHash 将带有 `redis` 标签的结构体转换为哈希字段映射
+//
+// 编码规则与 go-redis 的 `HSet(struct)` 保持一致（布尔值编码为 "1"/"0"），
+// 从而保证切换存储实现前后写入的数据格式相同。未设置 `redis` 标签或标签为 "-" 的字段会被忽略。
+func EncodeHash(v any) (map[string]string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("哈希编码仅支持结构体，实际为 %s", rv.Kind())
+	}
+
+	rt := rv.Type()
+	values := make(map[string]string, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		tag := rt.Field(i).Tag.Get("redis")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		field := rv.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			values[tag] = field.String()
+		case reflect.Bool:
+			if field.Bool() {
+				values[tag] = "1"
+			} else {
+				values[tag] = "0"
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			values[tag] = strconv.FormatInt(field.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			values[tag] = strconv.FormatUint(field.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			values[tag] = strconv.FormatFloat(field.Float(), 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("字段 %s 的类型 %s 不支持哈希编码", tag, field.Kind())
+		}
+	}
+	return values, nil
+}
+
+// DecodeHash 将哈希字段映射写入带有 `redis` 标签的结构体指针，缺失的字段保持零值。
+func DecodeHash(values map[string]string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("哈希解码仅支持结构体指针")
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		tag := rt.Field(i).Tag.Get("redis")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		raw, ok := values[tag]
+		if !ok {
+			continue
+		}
+
+		field := rv.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(raw)
+		case reflect.Bool:
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				return fmt.Errorf("字段 %s 解码失败: %w", tag, err)
+			}
+			field.SetBool(parsed)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			parsed, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return fmt.Errorf("字段 %s 解码失败: %w", tag, err)
+			}
+			field.SetInt(parsed)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			parsed, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				return fmt.Errorf("字段 %s 解码失败: %w", tag, err)
+			}
+			field.SetUint(parsed)
+		case reflect.Float32, reflect.Float64:
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return fmt.Errorf("字段 %s 解码失败: %w", tag, err)
+			}
+			field.SetFloat(parsed)
+		default:
+			return fmt.Errorf("字段 %s 的类型 %s 不支持哈希解码", tag, field.Kind())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package bSdkStore
+
+import (
+	"context"
+	"time"
+)
+
+// Janitor 按固定周期在后台执行清理函数的协程
+//
+// 用于数据库中的过期记录：与 Redis 和内存存储不同，数据库不会自动淘汰过期行，需要定期物理删除。
+type Janitor struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewJanitor 启动后台清理协程
+//
+// 参数:
+//   - interval: 清理周期，`<= 0` 时不启动并返回 nil。
+//   - purge: 清理函数，传入的上下文在 `Close` 时取消。
+//
+// 返回值:
+//   - *Janitor: 已启动的清理协程，使用完毕后调用 `Close` 停止。
+func NewJanitor(interval time.Duration, purge func(ctx context.Context)) *Janitor {
+	if interval <= 0 || purge == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &Janitor{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purge(ctx)
+			}
+		}
+	}()
+	return j
+}
+
+// Close 停止后台清理协程并等待其退出，可重复调用。
+func (j *Janitor) Close() {
+	if j == nil {
+		return
+	}
+	j.cancel()
+	<-j.done
+}
@@ -0,0 +1,32 @@
+package bSdkStore
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJanitor(t *testing.T) {
+	if NewJanitor(0, func(context.Context) {}) != nil {
+		t.Fatalf("清理周期为 0 时不应启动")
+	}
+
+	var runs atomic.Int32
+	janitor := NewJanitor(10*time.Millisecond, func(context.Context) { runs.Add(1) })
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if runs.Load() < 2 {
+		t.Fatalf("期望按周期执行清理，实际 %d 次", runs.Load())
+	}
+
+	janitor.Close()
+	stopped := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != stopped {
+		t.Fatalf("Close 后不应继续清理")
+	}
+	janitor.Close()
+}
@@ -0,0 +1,295 @@
+package bSdkStore
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// errWrongType 对非字符串类型的键执行计数器操作时返回。
+var errWrongType = errors.New("键的值类型不匹配")
+
+// memoryEntry 内存存储中的单个键，按写入方式保存字符串、哈希或集合值。
+type memoryEntry struct {
+	value    string
+	hash     map[string]string
+	set      map[string]struct{}
+	expireAt time.Time // 零值表示不过期
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+// MemoryStore 基于进程内存的状态存储实现
+//
+// 过期键在读取时惰性淘汰，并由后台协程按 cleanupInterval 周期清理。
+// 数据不在进程间共享，仅适用于单实例应用与测试。
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// NewMemoryStore 创建并初始化一个内存状态存储实例
+//
+// 参数:
+//   - cleanupInterval: 后台清理过期键的周期，`<= 0` 时不启动后台清理，仅在读取时惰性淘汰。
+//
+// 返回值:
+//   - *MemoryStore: 配置完成的存储实例指针，使用完毕后可调用 `Close` 停止后台清理。
+func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+		stop:    make(chan struct{}),
+	}
+	if cleanupInterval > 0 {
+		go s.janitor(cleanupInterval)
+	}
+	return s
+}
+
+// Close 停止后台清理协程。
+func (s *MemoryStore) Close() {
+	s.once.Do(func() { close(s.stop) })
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	if entry == nil || entry.hash != nil || entry.set != nil {
+		return "", false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &memoryEntry{value: value, expireAt: expireAt(ttl)}
+	return nil
+}
+
+func (s *MemoryStore) SetNX(_ context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.load(key) != nil {
+		return false, nil
+	}
+	s.entries[key] = &memoryEntry{value: value, expireAt: expireAt(ttl)}
+	return true, nil
+}
+
+func (s *MemoryStore) CompareAndDelete(_ context.Context, key string, value string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	if entry == nil || entry.hash != nil || entry.set != nil || entry.value != value {
+		return false, nil
+	}
+	delete(s.entries, key)
+	return true, nil
+}
+
+func (s *MemoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	if entry == nil {
+		s.entries[key] = &memoryEntry{value: "1", expireAt: expireAt(ttl)}
+		return 1, nil
+	}
+	if entry.hash != nil || entry.set != nil {
+		return 0, errWrongType
+	}
+	value, err := strconv.ParseInt(entry.value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	value++
+	entry.value = strconv.FormatInt(value, 10)
+	return value, nil
+}
+
+func (s *MemoryStore) HGet(_ context.Context, key string, field string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	if entry == nil || entry.hash == nil {
+		return "", false, nil
+	}
+	value, ok := entry.hash[field]
+	return value, ok, nil
+}
+
+func (s *MemoryStore) HGetAll(_ context.Context, key string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]string)
+	if entry := s.load(key); entry != nil {
+		for field, value := range entry.hash {
+			result[field] = value
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) HSet(_ context.Context, key string, values map[string]string, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	if entry == nil || entry.hash == nil {
+		entry = &memoryEntry{hash: make(map[string]string, len(values))}
+		s.entries[key] = entry
+	}
+	for field, value := range values {
+		entry.hash[field] = value
+	}
+	if ttl > 0 {
+		entry.expireAt = expireAt(ttl)
+	}
+	return nil
+}
+
+func (s *MemoryStore) HSetExisting(_ context.Context, key string, values map[string]string) (bool, error) {
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	if entry == nil || entry.hash == nil {
+		return false, nil
+	}
+	for field, value := range values {
+		entry.hash[field] = value
+	}
+	return true, nil
+}
+
+func (s *MemoryStore) HExists(_ context.Context, key string, field string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	if entry == nil || entry.hash == nil {
+		return false, nil
+	}
+	_, ok := entry.hash[field]
+	return ok, nil
+}
+
+func (s *MemoryStore) HDel(_ context.Context, key string, fields ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	if entry == nil || entry.hash == nil {
+		return nil
+	}
+	for _, field := range fields {
+		delete(entry.hash, field)
+	}
+	if len(entry.hash) == 0 {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) SAdd(_ context.Context, key string, member string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	if entry == nil || entry.set == nil {
+		entry = &memoryEntry{set: make(map[string]struct{})}
+		s.entries[key] = entry
+	}
+	entry.set[member] = struct{}{}
+	if ttl > 0 {
+		entry.expireAt = expireAt(ttl)
+	}
+	return nil
+}
+
+func (s *MemoryStore) SMembers(_ context.Context, key string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := make([]string, 0)
+	if entry := s.load(key); entry != nil {
+		for member := range entry.set {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// load 读取未过期的键，已过期的键会被顺带删除；调用方需持有锁。
+func (s *MemoryStore) load(key string) *memoryEntry {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	if entry.expired(time.Now()) {
+		delete(s.entries, key)
+		return nil
+	}
+	return entry
+}
+
+// janitor 周期性清理已过期的键，避免长时间不读取的键占用内存。
+func (s *MemoryStore) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, entry := range s.entries {
+				if entry.expired(now) {
+					delete(s.entries, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func expireAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
@@ -0,0 +1,102 @@
+package bSdkStore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(0)
+	defer store.Close()
+
+	t.Run("字符串与过期", func(t *testing.T) {
+		if err := store.Set(ctx, "k", "v", 20*time.Millisecond); err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+		if value, ok, _ := store.Get(ctx, "k"); !ok || value != "v" {
+			t.Fatalf("读取结果不正确: %q %v", value, ok)
+		}
+		time.Sleep(30 * time.Millisecond)
+		if _, ok, _ := store.Get(ctx, "k"); ok {
+			t.Fatalf("过期键不应可见")
+		}
+	})
+
+	t.Run("SetNX 与 CompareAndDelete", func(t *testing.T) {
+		if ok, _ := store.SetNX(ctx, "lock", "a", time.Second); !ok {
+			t.Fatalf("首次加锁应成功")
+		}
+		if ok, _ := store.SetNX(ctx, "lock", "b", time.Second); ok {
+			t.Fatalf("重复加锁应失败")
+		}
+		if ok, _ := store.CompareAndDelete(ctx, "lock", "b"); ok {
+			t.Fatalf("持有者不匹配时不应删除")
+		}
+		if ok, _ := store.CompareAndDelete(ctx, "lock", "a"); !ok {
+			t.Fatalf("持有者匹配时应删除")
+		}
+	})
+
+	t.Run("Incr 固定窗口", func(t *testing.T) {
+		for want := int64(1); want <= 3; want++ {
+			if got, err := store.Incr(ctx, "counter", 20*time.Millisecond); err != nil || got != want {
+				t.Fatalf("期望计数 %d，实际 %d（%v）", want, got, err)
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		if got, _ := store.Incr(ctx, "counter", time.Second); got != 1 {
+			t.Fatalf("窗口过期后应重新计数，实际 %d", got)
+		}
+	})
+
+	t.Run("哈希仅在存在时更新", func(t *testing.T) {
+		if ok, _ := store.HSetExisting(ctx, "h", map[string]string{"a": "1"}); ok {
+			t.Fatalf("键不存在时不应写入")
+		}
+		_ = store.HSet(ctx, "h", map[string]string{"a": "1", "b": "2"}, time.Second)
+		if ok, _ := store.HSetExisting(ctx, "h", map[string]string{"a": "3"}); !ok {
+			t.Fatalf("键存在时应写入")
+		}
+		values, _ := store.HGetAll(ctx, "h")
+		if values["a"] != "3" || values["b"] != "2" {
+			t.Fatalf("哈希内容不正确: %v", values)
+		}
+	})
+
+	t.Run("集合", func(t *testing.T) {
+		_ = store.SAdd(ctx, "s", "x", time.Second)
+		_ = store.SAdd(ctx, "s", "x", time.Second)
+		_ = store.SAdd(ctx, "s", "y", time.Second)
+		if members, _ := store.SMembers(ctx, "s"); len(members) != 2 {
+			t.Fatalf("集合成员数量不正确: %v", members)
+		}
+		_ = store.Delete(ctx, "s")
+		if members, _ := store.SMembers(ctx, "s"); len(members) != 0 {
+			t.Fatalf("删除后集合应为空: %v", members)
+		}
+	})
+}
+
+func TestHashCodec(t *testing.T) {
+	source := &bSdkModels.CacheBusinessIntrospection{Active: true, TokenType: "Bearer", Exp: 1700000000, ExpiresIn: 60}
+
+	values, err := EncodeHash(source)
+	if err != nil {
+		t.Fatalf("编码失败: %v", err)
+	}
+	if values["active"] != "1" || values["is_expired"] != "0" || values["exp"] != "1700000000" {
+		t.Fatalf("编码结果与 go-redis 不一致: %v", values)
+	}
+
+	var decoded bSdkModels.CacheBusinessIntrospection
+	if err = DecodeHash(values, &decoded); err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	if decoded != *source {
+		t.Fatalf("往返结果不一致: %+v", decoded)
+	}
+}
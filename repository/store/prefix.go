@@ -0,0 +1,198 @@
+package bSdkStore
+
+import (
+	"context"
+	"time"
+)
+
+// PrefixStore 为全部键附加固定前缀的状态存储包装
+//
+// 用于在同一底层存储中隔离各命名身份提供方的缓存，例如授权 State 与业务缓存，
+// 避免不同提供方之间的数据互相可见。
+type PrefixStore struct {
+	Store  Store
+	Prefix string
+}
+
+// NewPrefixStore 创建一个为全部键附加 prefix 的状态存储
+//
+// 参数:
+//   - store: 底层状态存储。
+//   - prefix: 键前缀，为空时直接返回 store。
+//
+// 返回值:
+//   - Store: 包装后的状态存储。
+func NewPrefixStore(store Store, prefix string) Store {
+	if prefix == "" {
+		return store
+	}
+	return &PrefixStore{Store: store, Prefix: prefix}
+}
+
+func (s *PrefixStore) key(key string) string {
+	return s.Prefix + key
+}
+
+func (s *PrefixStore) Get(ctx context.Context, key string) (string, bool, error) {
+	return s.Store.Get(ctx, s.key(key))
+}
+
+func (s *PrefixStore) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return s.Store.Set(ctx, s.key(key), value, ttl)
+}
+
+func (s *PrefixStore) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	return s.Store.SetNX(ctx, s.key(key), value, ttl)
+}
+
+func (s *PrefixStore) CompareAndDelete(ctx context.Context, key string, value string) (bool, error) {
+	return s.Store.CompareAndDelete(ctx, s.key(key), value)
+}
+
+func (s *PrefixStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return s.Store.Incr(ctx, s.key(key), ttl)
+}
+
+func (s *PrefixStore) HGet(ctx context.Context, key string, field string) (string, bool, error) {
+	return s.Store.HGet(ctx, s.key(key), field)
+}
+
+func (s *PrefixStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return s.Store.HGetAll(ctx, s.key(key))
+}
+
+func (s *PrefixStore) HSet(ctx context.Context, key string, values map[string]string, ttl time.Duration) error {
+	return s.Store.HSet(ctx, s.key(key), values, ttl)
+}
+
+func (s *PrefixStore) HSetExisting(ctx context.Context, key string, values map[string]string) (bool, error) {
+	return s.Store.HSetExisting(ctx, s.key(key), values)
+}
+
+func (s *PrefixStore) HExists(ctx context.Context, key string, field string) (bool, error) {
+	return s.Store.HExists(ctx, s.key(key), field)
+}
+
+func (s *PrefixStore) HDel(ctx context.Context, key string, fields ...string) error {
+	return s.Store.HDel(ctx, s.key(key), fields...)
+}
+
+func (s *PrefixStore) SAdd(ctx context.Context, key string, member string, ttl time.Duration) error {
+	return s.Store.SAdd(ctx, s.key(key), member, ttl)
+}
+
+func (s *PrefixStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	return s.Store.SMembers(ctx, s.key(key))
+}
+
+func (s *PrefixStore) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.key(key)
+	}
+	return s.Store.Delete(ctx, prefixed...)
+}
+
+// NamespaceStore 按上下文为全部键附加命名空间的状态存储包装
+//
+// 命名空间由 Namespace 从每次调用的上下文中解析（例如按请求所属的租户），为空时直接使用原键，
+// 从而在同一底层存储中隔离不同租户的令牌与缓存。
+type NamespaceStore struct {
+	Store     Store
+	Namespace func(ctx context.Context) string
+}
+
+// NewNamespaceStore 创建一个按上下文附加命名空间的状态存储
+//
+// 参数:
+//   - store: 底层状态存储，已是 `*NamespaceStore` 时直接返回。
+//   - namespace: 从上下文解析命名空间的函数。
+//
+// 返回值:
+//   - Store: 包装后的状态存储。
+func NewNamespaceStore(store Store, namespace func(ctx context.Context) string) Store {
+	if _, ok := store.(*NamespaceStore); ok {
+		return store
+	}
+	return &NamespaceStore{Store: store, Namespace: namespace}
+}
+
+// KeyOf 返回 key 经 store 逐层附加前缀与命名空间后，在底层状态存储中的实际键。
+//
+// 用于在状态存储之外按实际键索引数据（如进程内一级缓存），使不同租户与命名身份提供方的同名键互不冲突。
+func KeyOf(ctx context.Context, store Store, key string) string {
+	for {
+		switch wrapped := store.(type) {
+		case *PrefixStore:
+			key, store = wrapped.key(key), wrapped.Store
+		case *NamespaceStore:
+			key, store = wrapped.key(ctx, key), wrapped.Store
+		default:
+			return key
+		}
+	}
+}
+
+func (s *NamespaceStore) key(ctx context.Context, key string) string {
+	return s.Namespace(ctx) + key
+}
+
+func (s *NamespaceStore) Get(ctx context.Context, key string) (string, bool, error) {
+	return s.Store.Get(ctx, s.key(ctx, key))
+}
+
+func (s *NamespaceStore) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return s.Store.Set(ctx, s.key(ctx, key), value, ttl)
+}
+
+func (s *NamespaceStore) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	return s.Store.SetNX(ctx, s.key(ctx, key), value, ttl)
+}
+
+func (s *NamespaceStore) CompareAndDelete(ctx context.Context, key string, value string) (bool, error) {
+	return s.Store.CompareAndDelete(ctx, s.key(ctx, key), value)
+}
+
+func (s *NamespaceStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return s.Store.Incr(ctx, s.key(ctx, key), ttl)
+}
+
+func (s *NamespaceStore) HGet(ctx context.Context, key string, field string) (string, bool, error) {
+	return s.Store.HGet(ctx, s.key(ctx, key), field)
+}
+
+func (s *NamespaceStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return s.Store.HGetAll(ctx, s.key(ctx, key))
+}
+
+func (s *NamespaceStore) HSet(ctx context.Context, key string, values map[string]string, ttl time.Duration) error {
+	return s.Store.HSet(ctx, s.key(ctx, key), values, ttl)
+}
+
+func (s *NamespaceStore) HSetExisting(ctx context.Context, key string, values map[string]string) (bool, error) {
+	return s.Store.HSetExisting(ctx, s.key(ctx, key), values)
+}
+
+func (s *NamespaceStore) HExists(ctx context.Context, key string, field string) (bool, error) {
+	return s.Store.HExists(ctx, s.key(ctx, key), field)
+}
+
+func (s *NamespaceStore) HDel(ctx context.Context, key string, fields ...string) error {
+	return s.Store.HDel(ctx, s.key(ctx, key), fields...)
+}
+
+func (s *NamespaceStore) SAdd(ctx context.Context, key string, member string, ttl time.Duration) error {
+	return s.Store.SAdd(ctx, s.key(ctx, key), member, ttl)
+}
+
+func (s *NamespaceStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	return s.Store.SMembers(ctx, s.key(ctx, key))
+}
+
+func (s *NamespaceStore) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.key(ctx, key)
+	}
+	return s.Store.Delete(ctx, prefixed...)
+}
@@ -0,0 +1,61 @@
+package bSdkStore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type namespaceKey struct{}
+
+func TestNamespaceStore(t *testing.T) {
+	raw := NewMemoryStore(0)
+	defer raw.Close()
+
+	namespace := func(ctx context.Context) string {
+		ns, _ := ctx.Value(namespaceKey{}).(string)
+		return ns
+	}
+	store := NewNamespaceStore(raw, namespace)
+	if NewNamespaceStore(store, namespace) != store {
+		t.Fatalf("不应重复包装")
+	}
+
+	root := context.Background()
+	tenant := context.WithValue(root, namespaceKey{}, "tenant:a:")
+	if err := store.Set(root, "k", "root", time.Minute); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if err := store.HSet(tenant, "h", map[string]string{"f": "a"}, time.Minute); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+
+	if _, ok, _ := store.Get(tenant, "k"); ok {
+		t.Fatalf("租户不应读取到根命名空间的键")
+	}
+	if value, ok, _ := raw.HGet(root, "tenant:a:h", "f"); !ok || value != "a" {
+		t.Fatalf("底层键未附加命名空间: %q %v", value, ok)
+	}
+	if got := KeyOf(tenant, store, "h"); got != "tenant:a:h" {
+		t.Fatalf("实际键不正确: %q", got)
+	}
+	if got := KeyOf(tenant, NewPrefixStore(store, "p:"), "h"); got != "tenant:a:p:h" {
+		t.Fatalf("前缀包装命名空间时实际键不正确: %q", got)
+	}
+	if got := KeyOf(tenant, raw, "h"); got != "h" {
+		t.Fatalf("未包装的存储应使用原键: %q", got)
+	}
+
+	if err := NewPrefixStore(store, "p:").Delete(tenant, "h"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if exists, _ := raw.HExists(root, "tenant:a:h", "f"); !exists {
+		t.Fatalf("前缀不同的键不应被删除")
+	}
+	if err := store.Delete(tenant, "h"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if exists, _ := raw.HExists(root, "tenant:a:h", "f"); exists {
+		t.Fatalf("命名空间内的键应被删除")
+	}
+}
@@ -0,0 +1,173 @@
+package bSdkStore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// compareAndDeleteScript 仅在键值匹配时删除键，保证比较与删除的原子性。
+var compareAndDeleteScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// incrScript 自增计数器，仅在首次创建时设置过期时间。
+var incrScript = redis.NewScript(`
+local value = redis.call("INCR", KEYS[1])
+if value == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return value
+`)
+
+// RedisStore 基于 Redis 的状态存储实现
+type RedisStore struct {
+	RDB *redis.Client
+}
+
+// NewRedisStore 创建并初始化一个 Redis 状态存储实例
+//
+// 参数:
+//   - rdb: 已初始化的 Redis 客户端连接。
+//
+// 返回值:
+//   - *RedisStore: 配置完成的存储实例指针。
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{RDB: rdb}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := s.RDB.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return s.RDB.Set(ctx, key, value, normalizeTTL(ttl)).Err()
+}
+
+func (s *RedisStore) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	return s.RDB.SetNX(ctx, key, value, normalizeTTL(ttl)).Result()
+}
+
+func (s *RedisStore) CompareAndDelete(ctx context.Context, key string, value string) (bool, error) {
+	deleted, err := compareAndDeleteScript.Run(ctx, s.RDB, []string{key}, value).Int()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, s.RDB, []string{key}, normalizeTTL(ttl).Milliseconds()).Int64()
+}
+
+func (s *RedisStore) HGet(ctx context.Context, key string, field string) (string, bool, error) {
+	value, err := s.RDB.HGet(ctx, key, field).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (s *RedisStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return s.RDB.HGetAll(ctx, key).Result()
+}
+
+func (s *RedisStore) HSet(ctx context.Context, key string, values map[string]string, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	_, err := s.RDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, toRedisValues(values))
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) HSetExisting(ctx context.Context, key string, values map[string]string) (bool, error) {
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	written := false
+	err := s.RDB.Watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil || exists == 0 {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, toRedisValues(values))
+			return nil
+		})
+		written = err == nil
+		return err
+	}, key)
+	return written, err
+}
+
+func (s *RedisStore) HExists(ctx context.Context, key string, field string) (bool, error) {
+	return s.RDB.HExists(ctx, key, field).Result()
+}
+
+func (s *RedisStore) HDel(ctx context.Context, key string, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return s.RDB.HDel(ctx, key, fields...).Err()
+}
+
+func (s *RedisStore) SAdd(ctx context.Context, key string, member string, ttl time.Duration) error {
+	_, err := s.RDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, member)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	return s.RDB.SMembers(ctx, key).Result()
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.RDB.Del(ctx, keys...).Err()
+}
+
+// normalizeTTL 将非正数 TTL 转换为 Redis 的“不过期”语义。
+func normalizeTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return 0
+	}
+	return ttl
+}
+
+func toRedisValues(values map[string]string) map[string]interface{} {
+	converted := make(map[string]interface{}, len(values))
+	for field, value := range values {
+		converted[field] = value
+	}
+	return converted
+}
@@ -0,0 +1,69 @@
+package bSdkStore
+
+import (
+	"context"
+	"time"
+)
+
+// Driver 状态存储驱动类型
+type Driver string
+
+const (
+	DriverRedis  Driver = "redis"  // Redis 存储，适用于多副本部署（默认）
+	DriverMemory Driver = "memory" // 进程内存储，适用于单实例应用与测试
+	DriverGorm   Driver = "gorm"   // 数据库存储，适用于无 Redis 的多副本部署
+)
+
+// Store SDK 状态存储接口
+//
+// 该接口抽象了 SDK 所需的最小键值能力（字符串、哈希、集合与过期时间），
+// 用于保存授权 State、令牌、Userinfo 与 Introspection 等缓存数据。
+// 所有实现都需保证：过期的键对读取不可见；`ttl <= 0` 表示不设置过期时间。
+type Store interface {
+	// Get 读取字符串值，键不存在时第二个返回值为 false。
+	Get(ctx context.Context, key string) (string, bool, error)
+	// Set 写入字符串值并设置过期时间。
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	// SetNX 仅在键不存在时写入字符串值，返回是否写入成功。
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+	// CompareAndDelete 仅在键的当前值等于 value 时删除，返回是否删除成功。
+	CompareAndDelete(ctx context.Context, key string, value string) (bool, error)
+	// Incr 将计数器加一并返回新值；键不存在时以 1 创建并设置 ttl，后续自增不刷新过期时间（固定窗口）。
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+
+	// HGet 读取哈希字段，字段不存在时第二个返回值为 false。
+	HGet(ctx context.Context, key string, field string) (string, bool, error)
+	// HGetAll 读取哈希的全部字段，键不存在时返回空映射。
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	// HSet 写入哈希字段并将整个键的过期时间重置为 ttl。
+	HSet(ctx context.Context, key string, values map[string]string, ttl time.Duration) error
+	// HSetExisting 仅在键存在时写入哈希字段并保留原过期时间，返回是否写入。
+	HSetExisting(ctx context.Context, key string, values map[string]string) (bool, error)
+	// HExists 判断哈希字段是否存在。
+	HExists(ctx context.Context, key string, field string) (bool, error)
+	// HDel 删除哈希字段。
+	HDel(ctx context.Context, key string, fields ...string) error
+
+	// SAdd 向集合添加成员并将整个键的过期时间重置为 ttl。
+	SAdd(ctx context.Context, key string, member string, ttl time.Duration) error
+	// SMembers 读取集合的全部成员。
+	SMembers(ctx context.Context, key string) ([]string, error)
+
+	// Delete 删除一个或多个键，不存在的键会被忽略。
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Cache 基于 Store 的缓存基础结构
+//
+// 与 `xCache.Cache` 对应，SDK 内的缓存管理器通过 `type X bSdkStore.Cache` 声明，
+// 从而与具体的存储实现解耦。
+type Cache struct {
+	Store   Store
+	TTL     time.Duration // 默认有效期
+	MaxTTL  time.Duration // 动态有效期上限，0 表示不限制
+	Prefix  string        // 键前缀，为空时使用 `xEnv.NoSqlPrefix`
+	Enabled bool          // 是否启用，仅对可关闭的缓存生效
+
+	StaleTTL        time.Duration // 过期后仍可直接返回并后台刷新的时长
+	StaleIfErrorTTL time.Duration // 过期后上游不可用时仍可返回的时长
+}
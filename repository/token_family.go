@@ -0,0 +1,103 @@
+package bSdkRepo
+
+import (
+	"context"
+
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+	"gorm.io/gorm"
+)
+
+// TokenFamilyRepo 刷新令牌家族数据仓储层，负责家族记录与刷新令牌索引的读写。
+type TokenFamilyRepo struct {
+	db    *gorm.DB
+	cache *bSdkCache.TokenFamilyCache
+	log   *xLog.LogNamedLogger
+}
+
+// NewTokenFamilyRepo 创建并初始化一个刷新令牌家族仓储实例。
+//
+// 缓存配置按 `SSO_CACHE_*` 环境变量读取，使用显式 SDK 配置时请改用 `NewTokenFamilyRepoWith`。
+//
+// 参数:
+//   - db: 已初始化的 GORM 数据库实例（备用）。
+//   - store: 已初始化的状态存储，用于缓存数据。
+//
+// 返回值:
+//   - *TokenFamilyRepo: 配置完成的家族仓储实例指针。
+func NewTokenFamilyRepo(db *gorm.DB, store bSdkStore.Store) *TokenFamilyRepo {
+	return NewTokenFamilyRepoWith(db, store, envConfig())
+}
+
+// NewTokenFamilyRepoWith 按 SDK 配置创建刷新令牌家族仓储实例，cfg 为 nil 时使用默认配置。
+func NewTokenFamilyRepoWith(db *gorm.DB, store bSdkStore.Store, cfg *bSdkConfig.Config) *TokenFamilyRepo {
+	return &TokenFamilyRepo{
+		db:    db,
+		cache: bSdkCache.NewTokenFamilyCache(store, repoConfig(cfg).Cache),
+		log:   xLog.WithName(xLog.NamedREPO, "TokenFamilyRepo"),
+	}
+}
+
+// Store 写入家族记录，并为当前刷新令牌建立到家族的索引。
+func (r *TokenFamilyRepo) Store(ctx context.Context, family *bSdkModels.CacheTokenFamily) *xError.Error {
+	if family == nil || family.FamilyID == "" {
+		return xError.NewError(ctx, xError.ParameterEmpty, "家族标识为空", false, nil)
+	}
+
+	if err := r.cache.Set(ctx, family); err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "写入令牌家族失败", false, err)
+	}
+	if family.RefreshFingerprint != "" {
+		if err := r.cache.SetIndex(ctx, family.RefreshFingerprint, family.FamilyID); err != nil {
+			return xError.NewError(ctx, xError.OperationFailed, "写入刷新令牌索引失败", false, err)
+		}
+	}
+	return nil
+}
+
+// Get 读取家族记录，不存在时返回 `FamilyID` 为空的结构。
+func (r *TokenFamilyRepo) Get(ctx context.Context, familyID string) (*bSdkModels.CacheTokenFamily, *xError.Error) {
+	if familyID == "" {
+		return nil, xError.NewError(ctx, xError.ParameterEmpty, "家族标识为空", false, nil)
+	}
+
+	family, err := r.cache.Get(ctx, familyID)
+	if err != nil {
+		return nil, xError.NewError(ctx, xError.OperationFailed, "读取令牌家族失败", false, err)
+	}
+	return family, nil
+}
+
+// FindByRefreshToken 查询刷新令牌（含已轮换的）所属的家族标识，未知令牌返回空字符串。
+func (r *TokenFamilyRepo) FindByRefreshToken(ctx context.Context, refreshToken string) (string, *xError.Error) {
+	if refreshToken == "" {
+		return "", xError.NewError(ctx, xError.ParameterEmpty, "刷新令牌为空", false, nil)
+	}
+
+	fingerprint, err := bSdkUtil.TokenFingerprint(refreshToken)
+	if err != nil {
+		return "", xError.NewError(ctx, xError.OperationFailed, "计算令牌指纹失败", false, err)
+	}
+	familyID, err := r.cache.GetIndex(ctx, fingerprint)
+	if err != nil {
+		return "", xError.NewError(ctx, xError.OperationFailed, "读取刷新令牌索引失败", false, err)
+	}
+	return familyID, nil
+}
+
+// Delete 删除家族记录；刷新令牌索引保留至 TTL 到期，以便继续识别重放。
+func (r *TokenFamilyRepo) Delete(ctx context.Context, familyID string) *xError.Error {
+	if familyID == "" {
+		return xError.NewError(ctx, xError.ParameterEmpty, "家族标识为空", false, nil)
+	}
+
+	if err := r.cache.Delete(ctx, familyID); err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "删除令牌家族失败", false, err)
+	}
+	return nil
+}
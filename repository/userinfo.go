@@ -3,12 +3,14 @@ package bSdkRepo
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
 	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
-	"github.com/redis/go-redis/v9"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
 	"gorm.io/gorm"
 )
 
@@ -23,21 +25,43 @@ type UserinfoRepo struct {
 
 // NewUserinfoRepo 创建并初始化一个 Userinfo 仓储实例。
 //
+// 缓存配置按 `SSO_CACHE_*` 环境变量读取，使用显式 SDK 配置时请改用 `NewUserinfoRepoWith`。
+//
 // 参数:
 //   - db: 已初始化的 GORM 数据库实例（备用）。
-//   - rdb: 已初始化的 Redis 客户端，用于缓存数据。
+//   - store: 已初始化的状态存储，用于缓存数据。
 //
 // 返回值:
 //   - *UserinfoRepo: 配置完成的 Userinfo 仓储实例指针。
-func NewUserinfoRepo(db *gorm.DB, rdb *redis.Client) *UserinfoRepo {
+func NewUserinfoRepo(db *gorm.DB, store bSdkStore.Store) *UserinfoRepo {
+	return NewUserinfoRepoWith(db, store, envConfig())
+}
+
+// NewUserinfoRepoWith 按 SDK 配置创建 Userinfo 仓储实例，cfg 为 nil 时使用默认配置。
+func NewUserinfoRepoWith(db *gorm.DB, store bSdkStore.Store, cfg *bSdkConfig.Config) *UserinfoRepo {
 	return &UserinfoRepo{
 		db:    db,
-		cache: bSdkCache.NewUserinfoCache(rdb),
+		cache: bSdkCache.NewUserinfoCache(store, repoConfig(cfg).Cache),
 		log:   xLog.WithName(xLog.NamedREPO, "UserinfoRepo"),
 	}
 }
 
-// GetCache 从缓存中获取用户信息
+// CacheEnabled 报告业务缓存是否启用（`cache.entries.userinfo.enabled`，默认跟随 `cache.business`）。
+func (r *UserinfoRepo) CacheEnabled() bool {
+	return r.cache.Enabled
+}
+
+// UserinfoFreshness 用户信息缓存条目的新鲜度
+type UserinfoFreshness int
+
+const (
+	UserinfoMiss         UserinfoFreshness = iota // 未命中
+	UserinfoFresh                                 // 处于新鲜期（TTL）内，可直接使用
+	UserinfoStale                                 // 处于 stale-while-revalidate 宽限期内，可直接使用并后台刷新
+	UserinfoStaleIfError                          // 处于 stale-if-error 宽限期内，仅在上游不可用时使用
+)
+
+// GetCache 从缓存中获取新鲜期内的用户信息
 //
 // 参数:
 //   - ctx: 上下文对象，用于传递请求上下文。
@@ -45,19 +69,45 @@ func NewUserinfoRepo(db *gorm.DB, rdb *redis.Client) *UserinfoRepo {
 //
 // 返回值:
 //   - *bSdkModels.OAuthUserinfo: 缓存的用户信息对象。
-//   - bool: 是否命中缓存（true 表示命中，false 表示未命中）。
+//   - bool: 是否命中缓存（true 表示命中，false 表示未命中或已过新鲜期）。
 //   - error: 操作过程中发生的错误。
 func (r *UserinfoRepo) GetCache(ctx context.Context, accessToken string) (*bSdkModels.OAuthUserinfo, bool, error) {
+	userinfo, freshness, err := r.LookupCache(ctx, accessToken)
+	if err != nil || freshness != UserinfoFresh {
+		return nil, false, err
+	}
+	return userinfo, true, nil
+}
+
+// LookupCache 从缓存中获取用户信息及其新鲜度
+//
+// 超过新鲜期的条目在 `cache.entries.userinfo` 的 `stale_ttl` 与 `stale_if_error_ttl`
+// 宽限期内仍会返回，由调用方根据新鲜度决定是否使用；超出全部宽限期时视为未命中。
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - accessToken: 访问令牌，用作缓存键。
+//
+// 返回值:
+//   - *bSdkModels.OAuthUserinfo: 缓存的用户信息对象，未命中时为 nil。
+//   - UserinfoFreshness: 缓存条目的新鲜度。
+//   - error: 操作过程中发生的错误。
+func (r *UserinfoRepo) LookupCache(ctx context.Context, accessToken string) (*bSdkModels.OAuthUserinfo, UserinfoFreshness, error) {
 	if accessToken == "" {
-		return nil, false, nil
+		return nil, UserinfoMiss, nil
 	}
 
 	cacheValue, exists, err := r.cache.GetAllStruct(ctx, accessToken)
 	if err != nil {
-		return nil, false, err
+		return nil, UserinfoMiss, err
 	}
 	if !exists {
-		return nil, false, nil
+		return nil, UserinfoMiss, nil
+	}
+
+	freshness := r.freshness(cacheValue.FetchedAt)
+	if freshness == UserinfoMiss {
+		return nil, UserinfoMiss, nil
 	}
 
 	userinfo := &bSdkModels.OAuthUserinfo{
@@ -71,12 +121,31 @@ func (r *UserinfoRepo) GetCache(ctx context.Context, accessToken string) (*bSdkM
 	if cacheValue.Raw != "" {
 		var raw map[string]any
 		if err := json.Unmarshal([]byte(cacheValue.Raw), &raw); err != nil {
-			return nil, false, err
+			return nil, UserinfoMiss, err
 		}
 		userinfo.Raw = raw
 	}
 
-	return userinfo, true, nil
+	return userinfo, freshness, nil
+}
+
+// freshness 根据获取时间计算缓存条目的新鲜度，未记录获取时间的旧条目视为新鲜。
+func (r *UserinfoRepo) freshness(fetchedAt int64) UserinfoFreshness {
+	if fetchedAt <= 0 {
+		return UserinfoFresh
+	}
+
+	age := time.Since(time.UnixMilli(fetchedAt))
+	switch {
+	case age <= r.cache.TTL:
+		return UserinfoFresh
+	case age <= r.cache.TTL+r.cache.StaleTTL:
+		return UserinfoStale
+	case age <= r.cache.TTL+r.cache.StaleIfErrorTTL:
+		return UserinfoStaleIfError
+	default:
+		return UserinfoMiss
+	}
 }
 
 // StoreCache 将用户信息存储到缓存
@@ -99,6 +168,7 @@ func (r *UserinfoRepo) StoreCache(ctx context.Context, accessToken string, useri
 		PreferredUsername: userinfo.PreferredUsername,
 		Email:             userinfo.Email,
 		Phone:             userinfo.Phone,
+		FetchedAt:         time.Now().UnixMilli(),
 	}
 
 	if userinfo.Raw != nil {
@@ -131,3 +201,23 @@ func (r *UserinfoRepo) DeleteCache(ctx context.Context, accessToken string) *xEr
 
 	return nil
 }
+
+// DeleteCacheByFingerprint 根据令牌指纹删除用户信息缓存
+//
+// 参数:
+//   - ctx: 上下文对象，用于传递请求上下文。
+//   - fingerprint: 访问令牌指纹。
+//
+// 返回值:
+//   - *xError.Error: 操作过程中发生的错误。
+func (r *UserinfoRepo) DeleteCacheByFingerprint(ctx context.Context, fingerprint string) *xError.Error {
+	if fingerprint == "" {
+		return xError.NewError(ctx, xError.ParameterEmpty, "令牌指纹为空", false, nil)
+	}
+
+	if err := r.cache.DeleteByFingerprint(ctx, fingerprint); err != nil {
+		return xError.NewError(ctx, xError.OperationFailed, "删除用户信息缓存失败", false, err)
+	}
+
+	return nil
+}
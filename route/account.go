@@ -2,7 +2,6 @@ package bSdkRoute
 
 import (
 	"github.com/gin-gonic/gin"
-	bSdkHandler "github.com/phalanx-labs/beacon-sso-sdk/handler"
 	bSdkMiddle "github.com/phalanx-labs/beacon-sso-sdk/middleware"
 )
 
@@ -16,8 +15,9 @@ import (
 //   - POST /account/token/revoke - 注销令牌（需要认证）
 func (r *Route) AccountRouter(route *gin.RouterGroup) {
 	group := route.Group("/sso/account")
+	group.Use(bSdkMiddle.TenantWith(r.config))
 
-	accountHandler := bSdkHandler.NewAccountHandler(r.ctx)
+	accountHandler := r.accountHandler
 
 	// 公开接口
 	group.POST("/register/email", accountHandler.RegisterByEmail)
@@ -25,6 +25,6 @@ func (r *Route) AccountRouter(route *gin.RouterGroup) {
 	group.POST("/token/refresh", accountHandler.RefreshToken)
 
 	// 需要认证的接口
-	group.POST("/password/change", bSdkMiddle.CheckAuth(r.ctx), accountHandler.ChangePassword)
-	group.POST("/token/revoke", bSdkMiddle.CheckAuth(r.ctx), accountHandler.RevokeToken)
+	group.POST("/password/change", r.checkAuth, accountHandler.ChangePassword)
+	group.POST("/token/revoke", r.checkAuth, accountHandler.RevokeToken)
 }
@@ -0,0 +1,19 @@
+package bSdkRoute
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRouter 注册健康检查路由
+//
+// 该路由组包含以下端点（公开，建议仅在内网暴露）：
+//   - GET /health/live - 存活探针，不请求外部依赖
+//   - GET /health/ready - 就绪探针，依赖不可用时返回 503
+func (r *Route) HealthRouter(route *gin.RouterGroup) {
+	group := route.Group("/sso/health")
+
+	healthHandler := r.healthHandler
+
+	group.GET("/live", healthHandler.Liveness)
+	group.GET("/ready", healthHandler.Readiness)
+}
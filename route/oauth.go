@@ -2,7 +2,7 @@ package bSdkRoute
 
 import (
 	"github.com/gin-gonic/gin"
-	bSdkHandler "github.com/phalanx-labs/beacon-sso-sdk/handler"
+	bSdkMiddle "github.com/phalanx-labs/beacon-sso-sdk/middleware"
 )
 
 // OAuthRouter 注册 OAuth 相关路由
@@ -10,15 +10,24 @@ import (
 // 该路由组包含以下端点：
 //   - GET /oauth/login - OAuth 登录跳转
 //   - GET /oauth/callback - OAuth 登录回调（授权码换取令牌）
+//   - GET /oauth/:provider/login - 命名身份提供方登录跳转
+//   - GET /oauth/:provider/callback - 命名身份提供方登录回调
 //   - POST /oauth/refresh - OAuth 刷新令牌（使用 Refresh Token 换取新令牌）
 //   - POST /oauth/logout - OAuth 登出
+//   - POST /oauth/backchannel-logout - OIDC 后端通道登出（由 SSO 调用）
+//   - GET /oauth/frontchannel-logout - OIDC 前端通道登出（由 SSO 登出页 iframe 加载）
 func (r *Route) OAuthRouter(route *gin.RouterGroup) {
 	group := route.Group("/sso/oauth")
+	group.Use(bSdkMiddle.TenantWith(r.config))
 
-	authHandler := bSdkHandler.NewAuthHandler(r.ctx)
+	authHandler := r.authHandler
 
 	group.GET("/login", authHandler.Login)
 	group.GET("/callback", authHandler.Callback)
+	group.GET("/:provider/login", authHandler.ProviderLogin)
+	group.GET("/:provider/callback", authHandler.ProviderCallback)
 	group.POST("/refresh", authHandler.Refresh)
 	group.POST("/logout", authHandler.Logout)
+	group.POST("/backchannel-logout", authHandler.BackChannelLogout)
+	group.GET("/frontchannel-logout", authHandler.FrontChannelLogout)
 }
@@ -2,24 +2,86 @@ package bSdkRoute
 
 import (
 	"context"
+
+	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkHandler "github.com/phalanx-labs/beacon-sso-sdk/handler"
+	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
+	bSdkMiddle "github.com/phalanx-labs/beacon-sso-sdk/middleware"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
 // Route 路由注册器
 //
-// 该结构体封装了上下文，用于在路由注册时传递给 Handler 初始化。
+// 该结构体持有创建时构建的 Handler 与身份认证中间件，路由注册时直接复用。
 type Route struct {
-	ctx context.Context // 上下文，用于控制取消和超时
+	config         *bSdkConfig.Config          // SDK 配置，用于租户解析中间件
+	authHandler    *bSdkHandler.AuthHandler    // OAuth 登录回调处理器
+	accountHandler *bSdkHandler.AccountHandler // 账户处理器
+	userHandler    *bSdkHandler.UserHandler    // 用户处理器
+	healthHandler  *bSdkHandler.HealthHandler  // 健康检查处理器
+	checkAuth      gin.HandlerFunc             // 身份认证中间件
 }
 
 // NewRoute 创建并返回一个新的 Route 实例
 //
+// 从 `xReg.Register` 注入的上下文中读取依赖，令牌密钥由 `oAuthConfig` 启动节点配置。
+//
 // 参数:
-//   - ctx: 请求上下文，用于初始化 Handler。
+//   - ctx: 启动节点（`bSdkStartup.NewStartupConfig`）注册后的上下文。
 //
 // 返回值:
 //   - *Route: 配置完成的路由注册器实例指针。
 func NewRoute(ctx context.Context) *Route {
 	return &Route{
-		ctx: ctx,
+		config:         bSdkUtil.GetConfig(ctx),
+		authHandler:    bSdkHandler.NewAuthHandler(ctx),
+		accountHandler: bSdkHandler.NewAccountHandler(ctx),
+		userHandler:    bSdkHandler.NewUserHandler(ctx),
+		healthHandler:  bSdkHandler.NewHealthHandler(ctx),
+		checkAuth:      bSdkMiddle.CheckAuth(ctx),
 	}
 }
+
+// NewRouteWith 使用显式依赖创建 Route 实例
+//
+// 适用于不使用 `xReg.Register` 上下文注入的服务，依赖的降级规则参见 `bSdkLogic.Deps`；
+// 进程级令牌密钥按 `deps.Config` 配置（参见 `bSdkLogic.NewOAuthWith`），无需另行调用 `bSdkUtil.ConfigureTokenKeys`。
+//
+// 参数:
+//   - deps: SDK 依赖。
+//
+// 返回值:
+//   - *Route: 配置完成的路由注册器实例指针。
+//   - error: 令牌密钥缺失、非法或与进程内已配置的密钥冲突时返回错误。
+func NewRouteWith(deps bSdkLogic.Deps) (*Route, error) {
+	authHandler, err := bSdkHandler.NewAuthHandlerWith(deps)
+	if err != nil {
+		return nil, err
+	}
+	accountHandler, err := bSdkHandler.NewAccountHandlerWith(deps)
+	if err != nil {
+		return nil, err
+	}
+	userHandler, err := bSdkHandler.NewUserHandlerWith(deps)
+	if err != nil {
+		return nil, err
+	}
+	healthHandler, err := bSdkHandler.NewHealthHandlerWith(deps)
+	if err != nil {
+		return nil, err
+	}
+	checkAuth, err := bSdkMiddle.CheckAuthWith(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Route{
+		config:         deps.Config,
+		authHandler:    authHandler,
+		accountHandler: accountHandler,
+		userHandler:    userHandler,
+		healthHandler:  healthHandler,
+		checkAuth:      checkAuth,
+	}, nil
+}
@@ -2,7 +2,6 @@ package bSdkRoute
 
 import (
 	"github.com/gin-gonic/gin"
-	bSdkHandler "github.com/phalanx-labs/beacon-sso-sdk/handler"
 	bSdkMiddle "github.com/phalanx-labs/beacon-sso-sdk/middleware"
 )
 
@@ -13,10 +12,11 @@ import (
 //   - GET /user/by-id - 根据ID获取用户信息（需要认证）
 func (r *Route) UserRouter(route *gin.RouterGroup) {
 	group := route.Group("/sso/user")
+	group.Use(bSdkMiddle.TenantWith(r.config))
 
-	userHandler := bSdkHandler.NewUserHandler(r.ctx)
+	userHandler := r.userHandler
 
 	// 需要认证的接口
-	group.GET("/userinfo", bSdkMiddle.CheckAuth(r.ctx), userHandler.GetCurrentUser)
-	group.GET("/by-id", bSdkMiddle.CheckAuth(r.ctx), userHandler.GetUserByID)
+	group.GET("/userinfo", r.checkAuth, userHandler.GetCurrentUser)
+	group.GET("/by-id", r.checkAuth, userHandler.GetUserByID)
 }
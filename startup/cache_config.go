@@ -0,0 +1,50 @@
+package bSdkStartup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// cacheConfig 校验各缓存的 TTL、上限、启用状态与键前缀配置并注册依赖项。
+//
+// 配置来自 SDK 配置的 `cache.entries`（环境变量 `SSO_CACHE_<NAME>_*`），任一配置非法时启动失败并列出全部错误。
+// Userinfo 与 Introspection 缓存的默认启用状态来自 `cache.business`（`SSO_BUSINESS_CACHE`）。
+// 未注册 `sdkConfig` 节点时使用进程级配置（`bSdkConfig.Process`）。
+//
+// 注册的上下文键为 `CtxCacheConfig`，值为 `[]bSdkCache.CacheConfig`。
+func cacheConfig() xRegNode.RegNodeList {
+	return xRegNode.RegNodeList{
+		Key: bSdkConst.CtxCacheConfig,
+		Node: func(ctx context.Context) (any, error) {
+			log := xLog.WithName(xLog.NamedINIT)
+			log.Info(ctx, "加载缓存配置")
+
+			sdkCfg := bSdkUtil.GetConfig(ctx).Current()
+			if sdkCfg == nil {
+				sdkCfg, _ = bSdkConfig.Process()
+			}
+			configs, err := bSdkCache.LoadCacheConfigs(sdkCfg.Cache)
+			if err != nil {
+				return nil, fmt.Errorf("缓存配置非法: %w", err)
+			}
+			for _, cfg := range configs {
+				log.Debug(ctx, "缓存配置",
+					slog.String("name", string(cfg.Name)),
+					slog.Bool("enabled", cfg.Enabled),
+					slog.Duration("ttl", cfg.TTL),
+					slog.Duration("max_ttl", cfg.MaxTTL),
+					slog.String("prefix", cfg.Prefix),
+				)
+			}
+			return configs, nil
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package bSdkStartup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// Check 在注册节点之前校验 SDK 的全部启动配置，所有问题汇总为一个错误返回。
+//
+// 注册节点按顺序执行，遇到第一个错误即中止启动；`Check` 不注册任何依赖、不连接 gRPC、Redis 与数据库，
+// 而是一次性列出各节点的配置问题（以节点名称为前缀），便于在启动前决定直接退出或排除部分节点降级运行。
+// 配置了 `SSO_WELL_KNOWN_URI` 时会请求一次元数据。
+//
+// 参数:
+//   - ctx: 上下文。
+//   - exclude: 要排除的注册节点名称列表（可选），同 `NewStartupConfig`，被排除的节点不参与校验。
+//
+// 返回值:
+//   - error: 使用 `errors.Join` 汇总的配置错误，配置完整时为 nil。
+func Check(ctx context.Context, exclude ...string) error {
+	return check(ctx, nil, exclude)
+}
+
+// CheckWith 使用以代码构建的 SDK 配置执行启动校验，参见 `Check` 与 `NewStartupConfigWith`。
+func CheckWith(ctx context.Context, cfg *bSdkConfig.Config, exclude ...string) error {
+	return check(ctx, cfg, exclude)
+}
+
+// check 逐项校验未被排除的注册节点，preset 为空时从配置文件与环境变量加载 SDK 配置。
+//
+// 由 `errors.Join` 汇总的节点错误会被展开，使每一项问题都带有节点名称前缀。
+func check(ctx context.Context, preset *bSdkConfig.Config, exclude []string) error {
+	included := func(name string) bool { return !slices.Contains(exclude, name) }
+
+	var errs []error
+	add := func(name string, err error) {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				errs = append(errs, fmt.Errorf("%s: %w", name, e))
+			}
+			return
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	// SDK 配置加载失败时仍以未校验的原始配置继续检查其余节点
+	cfg, err := loadConfig(ctx, preset)
+	if err != nil {
+		if included("sdkConfig") || included("oAuthConfig") {
+			add("sdkConfig", err)
+		}
+		cfg = preset
+		if cfg == nil {
+			cfg, _ = bSdkConfig.Process()
+		}
+	}
+
+	if included("oAuthConfig") && cfg != nil {
+		if _, keyErr := bSdkUtil.CheckTokenKeys(ctx, cfg); keyErr != nil {
+			add("oAuthConfig", fmt.Errorf("令牌密钥配置错误: %w", keyErr))
+		}
+	}
+	if included("ssoClient") && cfg != nil {
+		add("ssoClient", checkSsoClient(cfg))
+	}
+	// 缓存与状态存储配置读取失败时按默认值校验
+	settings := cfg
+	if settings == nil {
+		settings = bSdkConfig.Default()
+	}
+	if included("cacheConfig") {
+		if _, cacheErr := bSdkCache.LoadCacheConfigs(settings.Cache); cacheErr != nil {
+			add("cacheConfig", cacheErr)
+		}
+	}
+	if included("storage") {
+		_, storageErr := storageDriver(settings)
+		add("storage", storageErr)
+	}
+	if included("startupProbe") {
+		_, _, probeErr := probeSettings(settings)
+		add("startupProbe", probeErr)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,64 @@
+package bSdkStartup
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+)
+
+func TestCheckWith(t *testing.T) {
+	cfg := bSdkConfig.New(
+		bSdkConfig.WithClient("client-id", ""),
+		bSdkConfig.WithRedirectURI("https://app.example.com/callback"),
+		bSdkConfig.WithStorage("etcd"),
+		bSdkConfig.WithProbe("strict", 0),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{
+			Auth:          "https://sso.example.com/oauth2/authorize",
+			Token:         "https://sso.example.com/oauth2/token",
+			Userinfo:      "https://sso.example.com/oauth2/userinfo",
+			Introspection: "https://sso.example.com/oauth2/introspect",
+			Revocation:    "https://sso.example.com/oauth2/revoke",
+		}),
+	)
+
+	err := CheckWith(context.Background(), cfg)
+	if err == nil {
+		t.Fatalf("配置缺失时应返回错误")
+	}
+	for _, want := range []string{"sdkConfig:", "ssoClient: grpc.host", "ssoClient: client.secret", "storage:", "startupProbe:"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("汇总错误缺少 %q: %v", want, err)
+		}
+	}
+
+	err = CheckWith(context.Background(), cfg, "ssoClient", "storage", "startupProbe")
+	if err == nil || strings.Contains(err.Error(), "ssoClient:") || strings.Contains(err.Error(), "storage:") {
+		t.Fatalf("被排除的节点不应参与校验: %v", err)
+	}
+
+	cfg = bSdkConfig.New(
+		bSdkConfig.WithClient("client-id", "client-secret"),
+		bSdkConfig.WithRedirectURI("https://app.example.com/callback"),
+		bSdkConfig.WithEndpoints(bSdkConfig.EndpointConfig{
+			Auth:          "https://sso.example.com/oauth2/authorize",
+			Token:         "https://sso.example.com/oauth2/token",
+			Userinfo:      "https://sso.example.com/oauth2/userinfo",
+			Introspection: "https://sso.example.com/oauth2/introspect",
+			Revocation:    "https://sso.example.com/oauth2/revoke",
+		}),
+	)
+	if err := CheckWith(context.Background(), cfg, "ssoClient", "storage", "startupProbe"); err != nil {
+		t.Fatalf("配置完整时不应返回错误: %v", err)
+	}
+}
+
+func TestSsoClientReturnsError(t *testing.T) {
+	cfg := bSdkConfig.New(bSdkConfig.WithClient("client-id", "client-secret"))
+	ctx := context.WithValue(context.Background(), bSdkConst.CtxSdkConfig, cfg)
+	if _, err := ssoClient().Node(ctx); err == nil || !strings.Contains(err.Error(), "grpc.port") {
+		t.Fatalf("gRPC 配置缺失时应返回错误而不是 panic: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package bSdkStartup
+
+import (
+	"context"
+	"log/slog"
+	"syscall"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// configReload 启动 SDK 配置的后台热更新并注册依赖项。
+//
+// 配置了元数据端点且 `endpoints.refresh_interval`（`SSO_WELL_KNOWN_REFRESH`）大于 0 时，按该间隔在后台刷新元数据；
+// 通过 `client.secret_ref`（`SSO_CLIENT_SECRET_FILE`）读取密钥且 `secrets.refresh_interval`（`SSO_SECRET_REFRESH`）大于 0 时，
+// 按该间隔重新读取密钥，用于轮换；
+// `reload.on_sighup`（`SSO_RELOAD_ON_SIGHUP`）开启时，收到 SIGHUP 会重新读取配置文件与环境变量（例如轮换客户端密钥后）。
+// SDK 配置不是由 `sdkConfig` 节点加载时跳过。
+//
+// 注册的上下文键为 `CtxConfigReloader`，值为 `*bSdkConfig.Reloader`，需调用其 `Close` 停止后台任务。
+func configReload() xRegNode.RegNodeList {
+	return xRegNode.RegNodeList{
+		Key: bSdkConst.CtxConfigReloader,
+		Node: func(ctx context.Context) (any, error) {
+			log := xLog.WithName(xLog.NamedINIT)
+			log.Info(ctx, "初始化 SDK 配置热更新")
+
+			reloader := bSdkUtil.GetConfigReloader(ctx)
+			if reloader == nil {
+				log.Info(ctx, "SDK 配置不支持热更新，跳过")
+				return reloader, nil
+			}
+
+			reloadLog := xLog.WithName(xLog.NamedLOGC, "ConfigReloader")
+			started := reloader.Start(ctx, func(changed bool, err error) {
+				switch {
+				case err != nil:
+					reloadLog.Warn(ctx, "刷新 SSO 元数据或客户端密钥失败，继续使用当前配置", slog.String("error", err.Error()))
+				case changed:
+					reloadLog.Info(ctx, "SSO 元数据或客户端密钥已变化，配置已更新")
+				}
+			})
+			if started {
+				log.Info(ctx, "已启动 SDK 配置后台刷新",
+					slog.String("refresh_interval", reloader.Status().Interval.String()),
+				)
+			}
+
+			if reloader.Config().Current().Reload.OnSighup {
+				reloader.NotifyReload(ctx, func(err error) {
+					if err != nil {
+						reloadLog.Warn(ctx, "重新加载 SDK 配置失败，继续使用当前配置", slog.String("error", err.Error()))
+						return
+					}
+					reloadLog.Info(ctx, "SDK 配置已重新加载")
+				}, syscall.SIGHUP)
+				log.Info(ctx, "已监听 SIGHUP 以重新加载 SDK 配置")
+			}
+			return reloader, nil
+		},
+	}
+}
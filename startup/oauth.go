@@ -4,37 +4,30 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"net/http"
+	"strings"
 
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
 	xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
 	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
-	"github.com/go-resty/resty/v2"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
-	"golang.org/x/oauth2"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
 // oAuthConfig 初始化 OAuth2 配置并注册依赖项。
 //
-// 该函数负责从环境变量或 Well-Known 元数据端点加载 OAuth2 客户端所需的配置信息，
-// 并将其封装为 `oauth2.Config` 对象注册到依赖注入容器中。
+// 该函数基于 SDK 配置（`sdkConfig` 节点注册的 `*bSdkConfig.Config`）构建 `oauth2.Config`。
+// 未注册 `sdkConfig` 节点时会按 `SSO_CONFIG_FILE` 与 `SSO_*` 环境变量加载配置：
+//  1. 如果配置了 `SSO_WELL_KNOWN_URI`，将请求 OpenID Connect 的元数据，自动补全 Authorization、Token、
+//     Userinfo、Introspection、Revocation 与 JWKS 端点以及 Issuer 标识；
+//  2. 显式配置的 `SSO_ENDPOINT_*` 优先于元数据。
 //
-// 配置加载逻辑优先级：
-//  1. 如果设置了 `SSO_WELL_KNOWN_URI` 环境变量，函数将发起 HTTP GET 请求获取
-//     OpenID Connect 的元数据，从而自动解析 Authorization、Token、Userinfo、Introspection 与 Revocation 端点。
-//  2. 否则，将尝试从 `SSO_ENDPOINT_*` 相关的环境变量读取端点地址。
+// 配置缺失或非法（如 ClientID、Secret、RedirectURL 为空）时返回汇总后的错误，启动失败。
+// 同时按配置设置进程级令牌密钥（参见 `bSdkUtil.ConfigureTokenKeys`），密钥缺失或非法时启动失败。
+// 初始化完成后输出默认提供方与各命名身份提供方的能力报告（参见 `bSdkConfig.Capabilities`）。
+// 解析出的端点只保存在 SDK 配置中，不会回写进程环境变量。
 //
-// 函数会校验必要的配置（如 ClientID, Secret, RedirectURL 等），如果缺失则会触发 Panic。
-//
-// 返回的注册节点列表将包含以下键：
-//   - `CtxOAuthConfig`: 存储初始化后的 `*oauth2.Config` 实例。
-//   - `CtxOAuthUserinfoURI`: 存储用于获取用户信息的端点地址字符串。
-//
-// 参数:
-//   - ctx: 用于日志记录和 HTTP 请求的上下文。
-//
-// 返回值:
-//   - func(ctx context.Context) (any, error): 返回一个函数，该函数在调用时返回 OAuth2 配置和元数据 URI 的注册节点列表。
+// 注册的上下文键为 `CtxOAuthConfig`，值为 `*oauth2.Config`。
 func oAuthConfig() xRegNode.RegNodeList {
 	return xRegNode.RegNodeList{
 		Key: bSdkConst.CtxOAuthConfig,
@@ -42,95 +35,71 @@ func oAuthConfig() xRegNode.RegNodeList {
 			log := xLog.WithName(xLog.NamedINIT)
 			log.Info(ctx, "初始化 OAuth 配置")
 
-			var (
-				wkAuthURI          string // well-known 登录端点
-				wkTokenURI         string // well-known 令牌端点
-				wkUserinfoURI      string // well-known 获取用户信息端点
-				wkIntrospectionURI string // well-known 令牌自省端点
-				wkRevocationURI    string // well-known 令牌注销端点
-			)
-			if getWellKnown := xEnv.GetEnvString(bSdkConst.EnvSsoWellKnownURI, ""); getWellKnown != "" {
-				log.Info(ctx, "使用 SSO_WELL_KNOWN_URI 环境变量配置 OAuth2 Endpoint")
-
-				client := resty.New()
-				wellKnown := make(map[string]any)
-				resp, err := client.R().
-					SetContext(ctx).
-					SetHeader("Accept", "application/json").
-					SetResult(&wellKnown).
-					Get(getWellKnown)
-
-				if err != nil {
-					return nil, fmt.Errorf("无法获取 SSO_WELL_KNOWN_URI 环境变量配置的元数据: %v", err)
-				}
-				if resp.StatusCode() != http.StatusOK {
-					return nil, fmt.Errorf("SSO_WELL_KNOWN_URI 返回非成功状态码: %d", resp.StatusCode())
-				}
-
-				wkAuthURI = readWellKnownURI(wellKnown, "authorization_endpoint")
-				wkTokenURI = readWellKnownURI(wellKnown, "token_endpoint")
-				wkUserinfoURI = readWellKnownURI(wellKnown, "userinfo_endpoint")
-				wkIntrospectionURI = readWellKnownURI(wellKnown, "introspection_endpoint")
-				wkRevocationURI = readWellKnownURI(wellKnown, "revocation_endpoint")
+			cfg, err := currentConfig(ctx)
+			if err != nil {
+				return nil, err
 			}
 
-			// 获取环境变量
-			clientID := xEnv.GetEnvString(bSdkConst.EnvSsoClientID, "")
-			clientSecret := xEnv.GetEnvString(bSdkConst.EnvSsoClientSecret, "")
-			clientRedirectURI := xEnv.GetEnvString(bSdkConst.EnvSsoRedirectURI, "")
-			authURI := xEnv.GetEnvString(bSdkConst.EnvSsoEndpointAuthURI, wkAuthURI)
-			tokenURI := xEnv.GetEnvString(bSdkConst.EnvSsoEndpointTokenURI, wkTokenURI)
-			userinfoURI := xEnv.GetEnvString(bSdkConst.EnvSsoEndpointUserinfoURI, wkUserinfoURI)
-			introspectionURI := xEnv.GetEnvString(bSdkConst.EnvSsoEndpointIntrospectionURI, wkIntrospectionURI)
-			revocationURI := xEnv.GetEnvString(bSdkConst.EnvSsoEndpointRevocationURI, wkRevocationURI)
-
-			if clientID == "" || clientSecret == "" || clientRedirectURI == "" || authURI == "" || tokenURI == "" || userinfoURI == "" || introspectionURI == "" || revocationURI == "" {
-				xLog.Panic(ctx, "SSO 客户端配置缺失",
-					slog.String("client_id", clientID),
-					slog.String("client_secret", clientSecret),
-					slog.String("redirect_uri", clientRedirectURI),
-					slog.String("auth_uri", authURI),
-					slog.String("token_uri", tokenURI),
-					slog.String("userinfo_uri", userinfoURI),
-					slog.String("introspection_uri", introspectionURI),
-					slog.String("revocation_uri", revocationURI),
+			// 令牌指纹密钥与加密密钥环：未配置时由已读取的客户端密钥派生，两者都不可用时启动失败
+			ring, keyErr := bSdkUtil.ConfigureTokenKeys(ctx, cfg)
+			if keyErr != nil {
+				return nil, fmt.Errorf("令牌密钥配置错误: %w", keyErr)
+			}
+			if token := cfg.Current().Token; token.HashKey == "" || ring.Active.ID == bSdkUtil.DerivedTokenKeyID {
+				log.Warn(ctx, "未配置令牌指纹密钥或加密密钥，将由客户端密钥派生；轮换客户端密钥并重启后已缓存与持久化的令牌将无法读取",
+					slog.Bool("hash_key", token.HashKey != ""),
+					slog.String("active_key_id", ring.Active.ID),
 				)
 			}
 
-			// 调用跳转
-			oAuthConfig := &oauth2.Config{
-				ClientID:     clientID,
-				ClientSecret: clientSecret,
-				RedirectURL:  clientRedirectURI,
-				Scopes:       []string{"openid", "profile", "email", "phone"},
-				Endpoint: oauth2.Endpoint{
-					AuthURL:  authURI,
-					TokenURL: tokenURI,
-				},
+			// 前端通道登出地址仅需与 SSO 侧登记保持一致，格式已在配置校验中检查，能力缺失时仅告警
+			if frontChannelURI := cfg.Client.FrontchannelLogoutURI; frontChannelURI != "" {
+				parsed, err := bSdkConfig.CheckFrontChannelLogoutURI(frontChannelURI)
+				if err != nil {
+					return nil, err
+				}
+				if !strings.HasSuffix(strings.TrimRight(parsed.Path, "/"), frontChannelLogoutPath) {
+					log.Warn(ctx, "前端通道登出地址与 SDK 路由不一致，请确认已自行挂载处理器",
+						slog.String("frontchannel_logout_uri", frontChannelURI),
+					)
+				}
+				if !cfg.Endpoints.FrontchannelLogoutSupported {
+					log.Warn(ctx, "SSO 未声明支持前端通道登出", slog.String("frontchannel_logout_uri", frontChannelURI))
+				}
+				if cfg.Endpoints.Issuer == "" {
+					log.Warn(ctx, "签发者未配置，前端通道登出将无法校验 iss/sid 参数")
+				}
 			}
 
-			// 同时设置环境变量，供其他逻辑层使用
-			if envErr := xEnv.SetEnv(bSdkConst.EnvSsoEndpointUserinfoURI, userinfoURI); envErr != nil {
-				return nil, fmt.Errorf("设置环境变量失败: %v", envErr)
-			}
-			if envErr := xEnv.SetEnv(bSdkConst.EnvSsoEndpointIntrospectionURI, introspectionURI); envErr != nil {
-				return nil, fmt.Errorf("设置环境变量失败: %v", envErr)
-			}
-			if envErr := xEnv.SetEnv(bSdkConst.EnvSsoEndpointRevocationURI, revocationURI); envErr != nil {
-				return nil, fmt.Errorf("设置环境变量失败: %v", envErr)
-			}
-			if envErr := xEnv.SetEnv(bSdkConst.EnvSsoRedirectURI, clientRedirectURI); envErr != nil {
-				return nil, fmt.Errorf("设置环境变量失败: %v", envErr)
+			logCapabilities(ctx, log, "", cfg)
+			for _, name := range cfg.ProviderNames() {
+				provider, _ := cfg.Provider(name)
+				logCapabilities(ctx, log, name, provider)
 			}
 
-			return oAuthConfig, nil
+			return cfg.OAuth2(), nil
 		},
 	}
 }
 
+// logCapabilities 输出身份提供方的能力报告，并对 SDK 依赖但未声明的能力告警。
+func logCapabilities(ctx context.Context, log *xLog.LogNamedLogger, provider string, cfg *bSdkConfig.Config) {
+	capabilities := cfg.Capabilities()
+	log.Info(ctx, "身份提供方能力", slog.String("provider", provider), slog.Any("capabilities", capabilities))
+	if !capabilities.Discovered {
+		return
+	}
+	if !capabilities.PKCES256 {
+		log.Warn(ctx, "身份提供方元数据未声明支持 PKCE S256，授权码流程可能失败", slog.String("provider", provider))
+	}
+	if capabilities.RequirePAR {
+		log.Warn(ctx, "身份提供方要求使用 PAR，SDK 的登录跳转未使用 PAR，授权请求可能被拒绝", slog.String("provider", provider))
+	}
+}
+
 // oAuthRedirectURI 初始化并注册 OAuth2 重定向 URI 的依赖注入节点。
 //
-// 该函数从环境变量 `SSO_REDIRECT_URI` 中读取配置的回调地址，
+// 该函数从 SDK 配置中读取回调地址（`client.redirect_uri`，对应环境变量 `SSO_REDIRECT_URI`），
 // 并将其注册到依赖注入容器中，以便在 OAuth2 认证流程中使用。
 //
 // 注册的上下文键为 `CtxOAuthUserinfoURI`。
@@ -138,21 +107,13 @@ func oAuthRedirectURI() xRegNode.RegNodeList {
 	return xRegNode.RegNodeList{
 		Key: bSdkConst.CtxOAuthUserinfoURI,
 		Node: func(ctx context.Context) (any, error) {
+			if cfg := bSdkUtil.GetConfig(ctx); cfg != nil {
+				return cfg.Client.RedirectURI, nil
+			}
 			return xEnv.GetEnvString(bSdkConst.EnvSsoRedirectURI, ""), nil
 		},
 	}
 }
 
-func readWellKnownURI(wellKnown map[string]any, field string) string {
-	value, exist := wellKnown[field]
-	if !exist {
-		return ""
-	}
-
-	uri, ok := value.(string)
-	if !ok {
-		return ""
-	}
-
-	return uri
-}
+// frontChannelLogoutPath SDK 默认挂载的前端通道登出路由路径。
+const frontChannelLogoutPath = "/sso/oauth/frontchannel-logout"
@@ -7,7 +7,7 @@ import (
 	"os"
 	"testing"
 
-	xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
 	"golang.org/x/oauth2"
 )
@@ -19,11 +19,13 @@ func TestOAuthConfigWellKnownIncludesIntrospectionAndRevocation(t *testing.T) {
 		userinfoURI      = "https://sso.example.com/oauth2/userinfo"
 		introspectionURI = "https://sso.example.com/oauth2/introspect"
 		revocationURI    = "https://sso.example.com/oauth2/revoke"
+		jwksURI          = "https://sso.example.com/oauth2/jwks"
+		issuer           = "https://sso.example.com"
 	)
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"authorization_endpoint":"` + authURI + `","token_endpoint":"` + tokenURI + `","userinfo_endpoint":"` + userinfoURI + `","introspection_endpoint":"` + introspectionURI + `","revocation_endpoint":"` + revocationURI + `"}`))
+		_, _ = w.Write([]byte(`{"authorization_endpoint":"` + authURI + `","token_endpoint":"` + tokenURI + `","userinfo_endpoint":"` + userinfoURI + `","introspection_endpoint":"` + introspectionURI + `","revocation_endpoint":"` + revocationURI + `","jwks_uri":"` + jwksURI + `","issuer":"` + issuer + `"}`))
 	}))
 	defer srv.Close()
 
@@ -36,9 +38,19 @@ func TestOAuthConfigWellKnownIncludesIntrospectionAndRevocation(t *testing.T) {
 	unsetEnv(t, bSdkConst.EnvSsoEndpointUserinfoURI.String())
 	unsetEnv(t, bSdkConst.EnvSsoEndpointIntrospectionURI.String())
 	unsetEnv(t, bSdkConst.EnvSsoEndpointRevocationURI.String())
+	unsetEnv(t, bSdkConst.EnvSsoEndpointJwksURI.String())
+	unsetEnv(t, bSdkConst.EnvSsoIssuer.String())
 
-	node := oAuthConfig()
-	value, err := node.Node(context.Background())
+	value, err := sdkConfig(nil).Node(context.Background())
+	if err != nil {
+		t.Fatalf("加载 SDK 配置失败: %v", err)
+	}
+	sdkCfg, ok := value.(*bSdkConfig.Config)
+	if !ok {
+		t.Fatalf("返回值类型错误，期望 *bSdkConfig.Config")
+	}
+
+	value, err = oAuthConfig().Node(context.Background())
 	if err != nil {
 		t.Fatalf("初始化 OAuth 配置失败: %v", err)
 	}
@@ -53,14 +65,23 @@ func TestOAuthConfigWellKnownIncludesIntrospectionAndRevocation(t *testing.T) {
 	if cfg.Endpoint.TokenURL != tokenURI {
 		t.Fatalf("token url 不匹配，期望 %s，实际 %s", tokenURI, cfg.Endpoint.TokenURL)
 	}
-	if xEnv.GetEnvString(bSdkConst.EnvSsoEndpointUserinfoURI, "") != userinfoURI {
-		t.Fatalf("userinfo endpoint 未正确写入环境变量")
+	if sdkCfg.Endpoints.Userinfo != userinfoURI {
+		t.Fatalf("userinfo endpoint 未正确发现")
+	}
+	if sdkCfg.Endpoints.Introspection != introspectionURI {
+		t.Fatalf("introspection endpoint 未正确发现")
+	}
+	if sdkCfg.Endpoints.Revocation != revocationURI {
+		t.Fatalf("revocation endpoint 未正确发现")
+	}
+	if sdkCfg.Endpoints.JWKS != jwksURI {
+		t.Fatalf("jwks uri 未正确发现")
 	}
-	if xEnv.GetEnvString(bSdkConst.EnvSsoEndpointIntrospectionURI, "") != introspectionURI {
-		t.Fatalf("introspection endpoint 未正确写入环境变量")
+	if sdkCfg.Endpoints.Issuer != issuer {
+		t.Fatalf("issuer 未正确发现")
 	}
-	if xEnv.GetEnvString(bSdkConst.EnvSsoEndpointRevocationURI, "") != revocationURI {
-		t.Fatalf("revocation endpoint 未正确写入环境变量")
+	if _, exist := os.LookupEnv(bSdkConst.EnvSsoEndpointUserinfoURI.String()); exist {
+		t.Fatalf("发现的端点不应回写环境变量")
 	}
 }
 
@@ -0,0 +1,28 @@
+package bSdkStartup
+
+import (
+	"context"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
+)
+
+// revocationBus 启动令牌吊销广播订阅并注册依赖项。
+//
+// 该节点依赖上下文中已注入的 Redis 实例（未注入时跳过订阅，吊销仅在本实例生效），订阅成功后，
+// 其他副本发布的吊销消息会触发本进程通过 `bSdkLogic.RegisterRevocationHandler` 注册的处理函数。
+//
+// 注册的上下文键为 `CtxRevocationBus`，值为 `*bSdkLogic.RevocationSubscriber`。
+func revocationBus() xRegNode.RegNodeList {
+	return xRegNode.RegNodeList{
+		Key: bSdkConst.CtxRevocationBus,
+		Node: func(ctx context.Context) (any, error) {
+			log := xLog.WithName(xLog.NamedINIT)
+			log.Info(ctx, "初始化令牌吊销广播订阅")
+
+			return bSdkLogic.NewRevocation(ctx).Subscribe(ctx), nil
+		},
+	}
+}
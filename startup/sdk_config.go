@@ -0,0 +1,72 @@
+package bSdkStartup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// sdkConfig 加载、补全并校验 SDK 配置并注册依赖项。
+//
+// 传入 preset 时使用其副本；否则按 `SSO_CONFIG_FILE` 读取配置文件，再以 `SSO_*` 环境变量覆盖。
+// 配置了元数据端点时会自动发现未显式配置的端点（含各命名身份提供方），任一配置非法时启动失败并列出全部错误。
+// 注册的配置是热更新句柄，由 `configReload` 节点在后台刷新，读取时应使用 `Current` 获取当前快照。
+//
+// 注册的上下文键为 `CtxSdkConfig`，值为 `*bSdkConfig.Config`。
+func sdkConfig(preset *bSdkConfig.Config) xRegNode.RegNodeList {
+	return xRegNode.RegNodeList{
+		Key: bSdkConst.CtxSdkConfig,
+		Node: func(ctx context.Context) (any, error) {
+			log := xLog.WithName(xLog.NamedINIT)
+			log.Info(ctx, "加载 SDK 配置")
+
+			cfg, err := loadConfig(ctx, preset)
+			if err != nil {
+				return nil, err
+			}
+
+			log.Info(ctx, "SDK 配置加载成功",
+				slog.String("client_id", cfg.Client.ID),
+				slog.String("issuer", cfg.Endpoints.Issuer),
+				slog.Any("scopes", cfg.Scopes),
+				slog.Any("providers", cfg.ProviderNames()),
+				slog.Any("tenants", cfg.TenantNames()),
+			)
+			return cfg, nil
+		},
+	}
+}
+
+// loadConfig 加载并校验 SDK 配置，返回可热更新的配置句柄。
+func loadConfig(ctx context.Context, preset *bSdkConfig.Config) (*bSdkConfig.Config, error) {
+	source := func() (*bSdkConfig.Config, error) {
+		if preset != nil {
+			return preset.Clone(), nil
+		}
+		loaded, err := bSdkConfig.Load()
+		if err != nil {
+			return nil, fmt.Errorf("读取 SDK 配置失败: %w", err)
+		}
+		return loaded, nil
+	}
+
+	reloader, err := bSdkConfig.NewReloader(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("SDK 配置非法: %w", err)
+	}
+	return reloader.Config(), nil
+}
+
+// currentConfig 返回已注册的 SDK 配置，未注册 `sdkConfig` 节点时按环境变量加载并校验。
+func currentConfig(ctx context.Context) (*bSdkConfig.Config, error) {
+	if cfg := bSdkUtil.GetConfig(ctx); cfg != nil {
+		return cfg, nil
+	}
+	return loadConfig(ctx, nil)
+}
@@ -2,25 +2,29 @@ package bSdkStartup
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
-	xEnv "github.com/bamboo-services/bamboo-base-go/defined/env"
 	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
 	bSdkClient "github.com/phalanx-labs/beacon-sso-sdk/client"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
 )
 
 // ssoClient 初始化 SsoClient 并注册依赖项。
 //
-// 该函数从环境变量读取 gRPC 连接配置，创建 SsoClient 实例。
-// 如果必要的配置缺失，会触发 Panic 终止程序。
+// 该函数从 SDK 配置读取 gRPC 连接配置，创建 SsoClient 实例；未注册 `sdkConfig` 节点时使用进程级配置（`bSdkConfig.Process`）。
+// 必要的配置缺失时返回汇总后的错误，启动失败；不需要 gRPC 功能的应用可排除该节点，
+// 此时依赖 SsoClient 的逻辑返回 `bSdkLogic.ErrSsoClientUnavailable`。
 //
-// 环境变量：
-//   - SSO_GRPC_HOST: gRPC 主机地址
-//   - SSO_GRPC_PORT: gRPC 端口
-//   - SSO_APP_ACCESS_ID: App Access ID
-//   - SSO_APP_SECRET_KEY: App Secret Key
+// 配置项（括号内为对应环境变量）：
+//   - grpc.host（SSO_GRPC_HOST）: gRPC 主机地址
+//   - grpc.port（SSO_GRPC_PORT）: gRPC 端口
+//   - client.id（SSO_CLIENT_ID）: 客户端 ID
+//   - client.secret（SSO_CLIENT_SECRET 或 SSO_CLIENT_SECRET_FILE）: 客户端 Secret，日志中仅输出占位符
 //
 // 注册的上下文键为 `CtxSsoClient`。
 func ssoClient() xRegNode.RegNodeList {
@@ -30,20 +34,29 @@ func ssoClient() xRegNode.RegNodeList {
 			log := xLog.WithName(xLog.NamedINIT)
 			log.Info(ctx, "初始化 SsoClient")
 
-			// 获取环境变量
-			host := xEnv.GetEnvString(bSdkConst.EnvSsoGrpcHost, "")
-			port := xEnv.GetEnvString(bSdkConst.EnvSsoGrpcPort, "")
-			appClientID := xEnv.GetEnvString(bSdkConst.EnvSsoClientID, "")
-			appClientSecret := xEnv.GetEnvString(bSdkConst.EnvSsoClientSecret, "")
+			// 获取配置
+			cfg := bSdkUtil.GetConfig(ctx)
+			if cfg == nil {
+				loaded, err := bSdkConfig.Process()
+				if err != nil {
+					return nil, fmt.Errorf("读取 SSO gRPC 客户端配置失败: %w", err)
+				}
+				cfg = loaded
+			}
+			host := cfg.Grpc.Host
+			port := cfg.Grpc.Port
+			appClientID := cfg.Client.ID
+			appClientSecret := cfg.Client.Secret
 
 			// 校验配置
-			if host == "" || port == "" || appClientID == "" || appClientSecret == "" {
-				xLog.Panic(ctx, "SSO gRPC 客户端配置缺失",
+			if err := checkSsoClient(cfg); err != nil {
+				log.Warn(ctx, "SSO gRPC 客户端配置缺失",
 					slog.String("host", host),
 					slog.String("port", port),
 					slog.String("app_client_id", appClientID),
-					slog.String("app_client_secret", appClientSecret),
+					slog.String("app_client_secret", bSdkConfig.RedactSecret(appClientSecret)),
 				)
+				return nil, err
 			}
 
 			// 创建 SsoClient
@@ -61,3 +74,21 @@ func ssoClient() xRegNode.RegNodeList {
 		},
 	}
 }
+
+// checkSsoClient 校验创建 SsoClient 所需的配置，所有缺失项会一并返回。
+func checkSsoClient(cfg *bSdkConfig.Config) error {
+	var errs []error
+	if cfg.Grpc.Host == "" {
+		errs = append(errs, errors.New("grpc.host 不能为空"))
+	}
+	if cfg.Grpc.Port == "" {
+		errs = append(errs, errors.New("grpc.port 不能为空"))
+	}
+	if cfg.Client.ID == "" {
+		errs = append(errs, errors.New("client.id 不能为空"))
+	}
+	if cfg.Client.Secret == "" {
+		errs = append(errs, errors.New("client.secret 不能为空"))
+	}
+	return errors.Join(errs...)
+}
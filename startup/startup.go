@@ -2,6 +2,7 @@ package bSdkStartup
 
 import (
 	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 )
 
 // startupNode 启动注册节点定义
@@ -13,16 +14,43 @@ type startupNode struct {
 // NewStartupConfig 初始化并返回 SSO SDK 所有依赖注入节点列表。
 //
 // 该函数聚合了以下注册节点，用于在应用启动时批量注册 SSO 相关的上下文键值：
+//   - `sdkConfig`: SDK 配置（按 `SSO_CONFIG_FILE` 与 `SSO_*` 环境变量加载，自动发现端点并校验）
+//   - `configReload`: SDK 配置热更新（元数据后台刷新与 SIGHUP 重载）
 //   - `oAuthConfig`: OAuth2 核心配置（ClientID、Endpoint 等）
 //   - `oAuthRedirectURI`: OAuth2 重定向地址
 //   - `ssoClient`: SsoClient gRPC 客户端
+//   - `cacheConfig`: 各缓存的 TTL、上限、启用状态与键前缀配置（`SSO_CACHE_<NAME>_*`），非法时启动失败
+//   - `storage`: SDK 状态存储（按 `SSO_STORAGE` 选择 Redis、内存或数据库实现）
+//   - `revocationBus`: 令牌吊销广播订阅（依赖 Redis 注入节点，未注入时跳过订阅）
+//   - `tokenPersistence`: 令牌持久化表结构迁移与过期记录清理（依赖数据库注入节点，仅启用 `token.persistence` 时执行）
+//   - `startupProbe`: 依赖自检（令牌端点、gRPC、Redis 与 JWKS，仅 `probe.mode`（`SSO_STARTUP_PROBE`）为 `warn`/`fail` 时执行）
 //
 // 参数:
-//   - exclude: 要排除的注册节点名称列表（可选），支持: "oAuthConfig", "oAuthRedirectURI", "ssoClient"
+//   - exclude: 要排除的注册节点名称列表（可选），支持: "sdkConfig", "configReload", "oAuthConfig", "oAuthRedirectURI", "ssoClient", "cacheConfig", "storage", "revocationBus", "tokenPersistence", "startupProbe"
 //
 // 返回值:
 //   - 包含所有未被排除的注册节点的切片。
 func NewStartupConfig(exclude ...string) []xRegNode.RegNodeList {
+	return newStartupNodes(nil, exclude)
+}
+
+// NewStartupConfigWith 使用以代码构建的 SDK 配置初始化注册节点列表。
+//
+// 与 `NewStartupConfig` 相同，但 `sdkConfig` 节点使用传入配置的副本而不读取配置文件与环境变量，
+// 适用于同一进程内以不同配置运行多个 SDK 实例的场景。
+//
+// 参数:
+//   - cfg: SDK 配置，通常由 `bSdkConfig.New` 或 `bSdkConfig.LoadFile` 构建，启动时补全并校验。
+//   - exclude: 要排除的注册节点名称列表（可选），同 `NewStartupConfig`。
+//
+// 返回值:
+//   - 包含所有未被排除的注册节点的切片。
+func NewStartupConfigWith(cfg *bSdkConfig.Config, exclude ...string) []xRegNode.RegNodeList {
+	return newStartupNodes(cfg, exclude)
+}
+
+// newStartupNodes 构建注册节点列表，preset 为空时从配置文件与环境变量加载 SDK 配置。
+func newStartupNodes(preset *bSdkConfig.Config, exclude []string) []xRegNode.RegNodeList {
 	// 构建排除集合
 	excludeSet := make(map[string]struct{}, len(exclude))
 	for _, name := range exclude {
@@ -31,9 +59,16 @@ func NewStartupConfig(exclude ...string) []xRegNode.RegNodeList {
 
 	// 定义所有注册节点
 	nodes := []startupNode{
+		{name: "sdkConfig", node: sdkConfig(preset)},
+		{name: "configReload", node: configReload()},
 		{name: "oAuthConfig", node: oAuthConfig()},
 		{name: "oAuthRedirectURI", node: oAuthRedirectURI()},
 		{name: "ssoClient", node: ssoClient()},
+		{name: "cacheConfig", node: cacheConfig()},
+		{name: "storage", node: storage()},
+		{name: "revocationBus", node: revocationBus()},
+		{name: "tokenPersistence", node: tokenPersistence()},
+		{name: "startupProbe", node: startupProbe()},
 	}
 
 	// 过滤并收集注册节点
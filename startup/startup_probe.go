@@ -0,0 +1,85 @@
+package bSdkStartup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkLogic "github.com/phalanx-labs/beacon-sso-sdk/logic"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// 启动自检模式。
+const (
+	probeModeOff  = "off"  // 不执行自检
+	probeModeWarn = "warn" // 自检失败时仅告警
+	probeModeFail = "fail" // 自检失败时启动失败
+)
+
+// startupProbe 按需执行依赖自检并注册依赖项。
+//
+// 由 `probe.mode`（`SSO_STARTUP_PROBE`）控制（`off`/`warn`/`fail`，默认 `off`）：启用后并发检查令牌端点是否接受客户端凭证、
+// gRPC 服务是否接受 App 认证凭证、Redis 是否可连通以及 JWKS 是否可加载，单项超时由 `probe.timeout`（`SSO_STARTUP_PROBE_TIMEOUT`）设置。
+// 未配置的依赖会被跳过；`fail` 模式下任一检查失败时启动失败并列出全部失败项，`warn` 模式下仅记录告警。
+//
+// 注册的上下文键为 `CtxStartupProbe`，值为 `*bSdkLogic.ProbeReport`，未启用时为 nil。
+func startupProbe() xRegNode.RegNodeList {
+	return xRegNode.RegNodeList{
+		Key: bSdkConst.CtxStartupProbe,
+		Node: func(ctx context.Context) (any, error) {
+			cfg := bSdkUtil.GetConfig(ctx).Current()
+			if cfg == nil {
+				cfg, _ = bSdkConfig.Process()
+			}
+			mode, timeout, err := probeSettings(cfg)
+			if err != nil {
+				return nil, err
+			}
+			if mode == probeModeOff {
+				return (*bSdkLogic.ProbeReport)(nil), nil
+			}
+
+			log := xLog.WithName(xLog.NamedINIT)
+			log.Info(ctx, "执行 SSO 依赖自检", slog.String("mode", mode))
+
+			report := bSdkLogic.NewProbe(ctx).Run(ctx, timeout)
+			for _, check := range report.Checks {
+				attrs := []slog.Attr{
+					slog.String("check", check.Name),
+					slog.String("status", string(check.Status)),
+					slog.Int64("latency_ms", check.LatencyMS),
+				}
+				if check.Status == bSdkLogic.ProbeFailed {
+					log.Warn(ctx, "SSO 依赖自检失败", append(attrs, slog.String("error", check.Error))...)
+					continue
+				}
+				log.Info(ctx, "SSO 依赖自检", attrs...)
+			}
+
+			if err := report.Err(); err != nil && mode == probeModeFail {
+				return nil, fmt.Errorf("SSO 依赖自检失败: %w", err)
+			}
+			return report, nil
+		},
+	}
+}
+
+// probeSettings 读取并校验 `probe.mode` 与 `probe.timeout`。
+func probeSettings(cfg *bSdkConfig.Config) (string, time.Duration, error) {
+	mode := strings.ToLower(cfg.Probe.Mode)
+	switch mode {
+	case probeModeOff, probeModeWarn, probeModeFail:
+	default:
+		return "", 0, fmt.Errorf("probe.mode 非法: %q（可选 off/warn/fail）", cfg.Probe.Mode)
+	}
+	if cfg.Probe.Timeout <= 0 {
+		return "", 0, fmt.Errorf("probe.timeout 必须大于 0")
+	}
+	return mode, cfg.Probe.Timeout.Duration(), nil
+}
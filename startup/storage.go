@@ -0,0 +1,111 @@
+package bSdkStartup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	xCtxUtil "github.com/bamboo-services/bamboo-base-go/common/utility/context"
+	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
+	bSdkUtil "github.com/phalanx-labs/beacon-sso-sdk/utility"
+)
+
+// storage 按 SDK 配置的 `storage.driver`（`SSO_STORAGE`）初始化 SDK 状态存储并注册依赖项。
+//
+// 未注册 `sdkConfig` 节点时使用进程级配置（`bSdkConfig.Process`）。支持的驱动：
+//   - `redis`（默认）：依赖上下文中已注入的 Redis 客户端；
+//   - `memory`：进程内存储，带 TTL 淘汰，仅适用于单实例应用与测试；
+//   - `gorm`：依赖上下文中已注入的数据库实例，启动时自动迁移 `sso_store` 表，
+//     并按 `storage.purge_interval`（`SSO_STORAGE_PURGE_INTERVAL`）在后台物理删除过期行。
+//
+// 开启 `cache.local.enabled` 时，存储会按 `cache.local` 包装进程内一级缓存（参见 `bSdkCache.NewLocalStore`）；
+// 一级缓存依赖吊销广播跨副本失效，`gorm` 驱动且未注入 Redis 客户端时拒绝启用并输出错误日志。
+// 注册的上下文键为 `CtxStore`，值为 `bSdkStore.Store`；`gorm` 驱动的存储实现 `Close()`，调用后停止后台清理。
+func storage() xRegNode.RegNodeList {
+	return xRegNode.RegNodeList{
+		Key: bSdkConst.CtxStore,
+		Node: func(ctx context.Context) (any, error) {
+			log := xLog.WithName(xLog.NamedINIT)
+
+			cfg := bSdkUtil.GetConfig(ctx).Current()
+			if cfg == nil {
+				cfg, _ = bSdkConfig.Process()
+			}
+			driver, err := storageDriver(cfg)
+			if err != nil {
+				return nil, err
+			}
+			log.Info(ctx, "初始化状态存储", slog.String("driver", string(driver)))
+
+			store, err := newStore(ctx, driver)
+			if err != nil {
+				return nil, err
+			}
+			if gormStore, ok := store.(*bSdkStore.GormStore); ok {
+				purgeLog := xLog.WithName(xLog.NamedLOGC, "StoreJanitor")
+				gormStore.StartJanitor(cfg.Storage.PurgeInterval.Duration(), func(count int64, err error) {
+					switch {
+					case err != nil:
+						purgeLog.Warn(ctx, "清理过期状态失败", slog.String("error", err.Error()))
+					case count > 0:
+						purgeLog.Info(ctx, "已清理过期状态", slog.Int64("count", count))
+					}
+				})
+			}
+
+			// gorm 驱动面向多副本部署，没有 Redis 时吊销广播不可用，其他副本的吊销无法失效本实例的一级缓存
+			local := cfg.Cache.Local
+			if local.Enabled && driver == bSdkStore.DriverGorm {
+				if _, xErr := xCtxUtil.GetRDB(ctx); xErr != nil {
+					log.Error(ctx, "状态存储驱动为 gorm 且未注入 Redis 客户端，吊销广播不可用，已禁用进程内一级缓存")
+					local.Enabled = false
+				}
+			}
+			return bSdkCache.NewLocalStore(store, local), nil
+		},
+	}
+}
+
+// newStore 按驱动创建根状态存储。
+func newStore(ctx context.Context, driver bSdkStore.Driver) (bSdkStore.Store, error) {
+	switch driver {
+	case bSdkStore.DriverRedis:
+		rdb, xErr := xCtxUtil.GetRDB(ctx)
+		if xErr != nil {
+			return nil, fmt.Errorf("状态存储驱动为 redis，但上下文中未注入 Redis 客户端")
+		}
+		return bSdkStore.NewRedisStore(rdb), nil
+	case bSdkStore.DriverMemory:
+		return bSdkStore.NewMemoryStore(time.Minute), nil
+	case bSdkStore.DriverGorm:
+		db, xErr := xCtxUtil.GetDB(ctx)
+		if xErr != nil {
+			return nil, fmt.Errorf("状态存储驱动为 gorm，但上下文中未注入数据库实例")
+		}
+		store := bSdkStore.NewGormStore(db)
+		if err := store.AutoMigrate(ctx); err != nil {
+			return nil, fmt.Errorf("状态存储表结构迁移失败: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("不支持的状态存储驱动: %s（可选 redis/memory/gorm）", driver)
+	}
+}
+
+// storageDriver 读取 `storage.driver` 并校验驱动名称。
+func storageDriver(cfg *bSdkConfig.Config) (bSdkStore.Driver, error) {
+	driver := bSdkStore.Driver(strings.ToLower(cfg.Storage.Driver))
+	switch driver {
+	case bSdkStore.DriverRedis, bSdkStore.DriverMemory, bSdkStore.DriverGorm:
+		return driver, nil
+	default:
+		return "", fmt.Errorf("不支持的状态存储驱动: %s（可选 redis/memory/gorm）", driver)
+	}
+}
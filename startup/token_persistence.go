@@ -0,0 +1,56 @@
+package bSdkStartup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	xCtxUtil "github.com/bamboo-services/bamboo-base-go/common/utility/context"
+	xRegNode "github.com/bamboo-services/bamboo-base-go/major/register/node"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkCache "github.com/phalanx-labs/beacon-sso-sdk/repository/cache"
+	bSdkDatabase "github.com/phalanx-labs/beacon-sso-sdk/repository/database"
+)
+
+// tokenPersistence 按需迁移令牌持久化表结构、启动过期记录清理并注册依赖项。
+//
+// 仅当 SDK 配置启用 `token.persistence`（`SSO_TOKEN_PERSISTENCE=true`）时执行，依赖上下文中已注入的数据库实例；
+// 未启用时不访问数据库，令牌仅保存在 Redis 中。启用后按 `storage.purge_interval`（`SSO_STORAGE_PURGE_INTERVAL`）
+// 在后台物理删除已到清理时间的令牌记录。
+//
+// 注册的上下文键为 `CtxTokenPersistence`，值为 `*bSdkDatabase.OAuthTokenStore`（未启用时为 nil），需调用其 `Close` 停止后台清理。
+func tokenPersistence() xRegNode.RegNodeList {
+	return xRegNode.RegNodeList{
+		Key: bSdkConst.CtxTokenPersistence,
+		Node: func(ctx context.Context) (any, error) {
+			cfg, err := currentConfig(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if !cfg.Token.Persistence {
+				return (*bSdkDatabase.OAuthTokenStore)(nil), nil
+			}
+
+			log := xLog.WithName(xLog.NamedINIT)
+			log.Info(ctx, "初始化令牌持久化存储")
+
+			db := xCtxUtil.MustGetDB(ctx)
+			if err := bSdkDatabase.AutoMigrate(ctx, db); err != nil {
+				return nil, fmt.Errorf("令牌持久化表结构迁移失败: %w", err)
+			}
+
+			store := bSdkDatabase.NewOAuthTokenStore(db, bSdkCache.GetCacheConfig(cfg.Cache, bSdkCache.CacheToken).TTL)
+			purgeLog := xLog.WithName(xLog.NamedLOGC, "TokenJanitor")
+			store.StartJanitor(cfg.Storage.PurgeInterval.Duration(), func(count int64, err error) {
+				switch {
+				case err != nil:
+					purgeLog.Warn(ctx, "清理过期令牌记录失败", slog.String("error", err.Error()))
+				case count > 0:
+					purgeLog.Info(ctx, "已清理过期令牌记录", slog.Int64("count", count))
+				}
+			})
+			return store, nil
+		},
+	}
+}
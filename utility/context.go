@@ -2,18 +2,53 @@ package bSdkUtil
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
+	xError "github.com/bamboo-services/bamboo-base-go/common/error"
 	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
 	xCtxUtil "github.com/bamboo-services/bamboo-base-go/common/utility/context"
+	xCtx "github.com/bamboo-services/bamboo-base-go/defined/context"
 	bSdkClient "github.com/phalanx-labs/beacon-sso-sdk/client"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
 	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	bSdkStore "github.com/phalanx-labs/beacon-sso-sdk/repository/store"
 	"golang.org/x/oauth2"
 )
 
+// fallbackStore 上下文中既没有注册状态存储也没有 Redis 客户端时使用的进程内存储。
+var (
+	fallbackStore     *bSdkStore.MemoryStore
+	fallbackStoreOnce sync.Once
+)
+
+// GetConfig 从上下文中检索 SDK 配置
+//
+// 参数说明:
+//   - ctx: 请求上下文对象。
+//
+// 返回值:
+//   - *bSdkConfig.Config: 启动节点注册的 SDK 配置；未注册时返回 nil，由调用方决定是否退回环境变量。
+func GetConfig(ctx context.Context) *bSdkConfig.Config {
+	if cfg, err := xCtxUtil.Get[*bSdkConfig.Config](ctx, bSdkConst.CtxSdkConfig); err == nil && cfg != nil {
+		return cfg
+	}
+	return nil
+}
+
+// GetConfigReloader 返回 SDK 配置的热更新器，配置不是由启动节点加载时返回 nil
+//
+// 可在管理接口中调用其 `Reload` 以重新读取配置源（例如轮换客户端密钥后）。
+func GetConfigReloader(ctx context.Context) *bSdkConfig.Reloader {
+	return GetConfig(ctx).Reloader()
+}
+
 // GetOAuthConfig 从上下文中检索 OAuth 配置
 //
 // 该函数尝试从传入的上下文（context）中获取已注入的 `oauth2.Config` 对象。
 // 它常用于处理 OAuth 回调或生成授权 URL 的业务逻辑中。
+// SDK 配置为热更新句柄时返回其当前快照对应的 OAuth 配置，元数据刷新或重载后无需重启即可生效。
 //
 // 参数说明:
 //   - ctx: 请求上下文对象，必须包含 `bSdkConst.CtxOAuthConfig` 键值。
@@ -21,14 +56,22 @@ import (
 // 返回值:
 //   - *oauth2.Config: 从上下文中提取的 OAuth 配置实例。如果配置不存在则引发 panic。
 //
-// 注意: 如果在上下文中找不到对应的配置（即注入失败），该函数会记录错误日志并 panic。
+// 注意: 如果在上下文中找不到对应的配置（即注入失败），该函数会记录错误日志并 panic；
+// 需要在配置缺失时降级运行的调用方应使用 `TryGetOAuthConfig`。
 func GetOAuthConfig(ctx context.Context) *oauth2.Config {
-	get, err := xCtxUtil.Get[*oauth2.Config](ctx, bSdkConst.CtxOAuthConfig)
-	if err != nil {
-		xLog.WithName(xLog.NamedUTIL).Error(ctx, err.ErrorMessage.String())
-		panic(err.ErrorMessage.String())
+	return mustGet(ctx, TryGetOAuthConfig)
+}
+
+// TryGetOAuthConfig 从上下文中检索 OAuth 配置，与 `GetOAuthConfig` 相同但在配置缺失时返回错误而不是 panic
+//
+// 返回值:
+//   - *oauth2.Config: OAuth 配置实例，缺失时为 nil。
+//   - *xError.Error: 上下文中未注册 OAuth 配置时返回错误。
+func TryGetOAuthConfig(ctx context.Context) (*oauth2.Config, *xError.Error) {
+	if current := GetConfig(ctx).CurrentOAuth2(); current != nil {
+		return current, nil
 	}
-	return get
+	return tryGet[*oauth2.Config](ctx, bSdkConst.CtxOAuthConfig)
 }
 
 // GetOAuthUserinfoURI 从上下文中获取 OAuth 用户信息 URI
@@ -43,14 +86,14 @@ func GetOAuthConfig(ctx context.Context) *oauth2.Config {
 //   - string: 从上下文中检索到的 OAuth 用户信息 URI 字符串。
 //
 // 注意: 此函数依赖于中间件或前置逻辑将 `bSdkConst.CtxOAuthUserinfoURI` 键注入到上下文中。
-// 如果获取失败，程序将 panic，通常意味着中间件配置缺失或执行顺序错误。
+// 如果获取失败，程序将 panic，通常意味着中间件配置缺失或执行顺序错误；不希望 panic 时使用 `TryGetOAuthUserinfoURI`。
 func GetOAuthUserinfoURI(ctx context.Context) string {
-	get, err := xCtxUtil.Get[string](ctx, bSdkConst.CtxOAuthUserinfoURI)
-	if err != nil {
-		xLog.WithName(xLog.NamedUTIL).Error(ctx, err.ErrorMessage.String())
-		panic(err.ErrorMessage.String())
-	}
-	return get
+	return mustGet(ctx, TryGetOAuthUserinfoURI)
+}
+
+// TryGetOAuthUserinfoURI 从上下文中获取 OAuth 用户信息 URI，缺失时返回错误而不是 panic
+func TryGetOAuthUserinfoURI(ctx context.Context) (string, *xError.Error) {
+	return xCtxUtil.Get[string](ctx, bSdkConst.CtxOAuthUserinfoURI)
 }
 
 // GetSsoClient 从上下文中检索 SsoClient 实例
@@ -64,12 +107,74 @@ func GetOAuthUserinfoURI(ctx context.Context) string {
 // 返回值:
 //   - *bSdkClient.SsoClient: 从上下文中提取的 SsoClient 实例。如果实例不存在则引发 panic。
 //
-// 注意: 如果在上下文中找不到对应的客户端（即注入失败），该函数会记录错误日志并 panic。
+// 注意: 如果在上下文中找不到对应的客户端（即注入失败），该函数会记录错误日志并 panic；
+// 未启用 gRPC 客户端（如排除了 `ssoClient` 节点）时应使用 `TryGetSsoClient`。
 func GetSsoClient(ctx context.Context) *bSdkClient.SsoClient {
-	get, err := xCtxUtil.Get[*bSdkClient.SsoClient](ctx, bSdkConst.CtxSsoClient)
+	return mustGet(ctx, TryGetSsoClient)
+}
+
+// TryGetSsoClient 从上下文中检索 SsoClient 实例，未注册时返回错误而不是 panic
+//
+// 返回值:
+//   - *bSdkClient.SsoClient: SsoClient 实例，未注册时为 nil。
+//   - *xError.Error: 上下文中未注册 SsoClient 时返回错误。
+func TryGetSsoClient(ctx context.Context) (*bSdkClient.SsoClient, *xError.Error) {
+	return tryGet[*bSdkClient.SsoClient](ctx, bSdkConst.CtxSsoClient)
+}
+
+// tryGet 从上下文中检索组件，组件缺失或为 nil（如节点返回了类型化的 nil）时返回错误。
+func tryGet[T comparable](ctx context.Context, key xCtx.ContextKey) (T, *xError.Error) {
+	get, err := xCtxUtil.Get[T](ctx, key)
+	if err != nil {
+		return get, err
+	}
+	var zero T
+	if get == zero {
+		return zero, &xError.Error{
+			ErrorCode:    xError.ServerInternalError,
+			ErrorMessage: xError.ErrMessage(fmt.Sprintf("SDK 组件未初始化: 上下文中 Key 为 [%v] 的组件为空", key)),
+		}
+	}
+	return get, nil
+}
+
+// mustGet 调用 try 检索组件，失败时记录错误日志并 panic。
+func mustGet[T any](ctx context.Context, try func(ctx context.Context) (T, *xError.Error)) T {
+	get, err := try(ctx)
 	if err != nil {
 		xLog.WithName(xLog.NamedUTIL).Error(ctx, err.ErrorMessage.String())
 		panic(err.ErrorMessage.String())
 	}
 	return get
 }
+
+// GetStore 从上下文中检索 SDK 状态存储
+//
+// 优先返回启动节点按 `SSO_STORAGE` 注册的存储；未注册时退回上下文中的 Redis 客户端，
+// 两者均不存在时使用进程内存储并记录告警，保证未注入 Redis 的应用不会因此 panic。
+// 返回的存储会按请求所属租户（见 `WithTenant`）为键附加命名空间，未绑定租户时键保持不变。
+//
+// 参数说明:
+//   - ctx: 请求上下文对象。
+//
+// 返回值:
+//   - bSdkStore.Store: 可用的状态存储实例。
+func GetStore(ctx context.Context) bSdkStore.Store {
+	return bSdkStore.NewNamespaceStore(getRawStore(ctx), TenantNamespace)
+}
+
+// getRawStore 返回未附加租户命名空间的状态存储。
+func getRawStore(ctx context.Context) bSdkStore.Store {
+	if store, err := xCtxUtil.Get[bSdkStore.Store](ctx, bSdkConst.CtxStore); err == nil && store != nil {
+		return store
+	}
+	if rdb, err := xCtxUtil.GetRDB(ctx); err == nil {
+		return bSdkStore.NewRedisStore(rdb)
+	}
+
+	fallbackStoreOnce.Do(func() {
+		xLog.WithName(xLog.NamedUTIL).Warn(ctx, "未注册状态存储且未注入 Redis 客户端，使用进程内存储（仅适用于单实例）")
+		fallbackStore = bSdkStore.NewMemoryStore(time.Minute)
+	})
+	return fallbackStore
+}
@@ -0,0 +1,39 @@
+package bSdkUtil
+
+import (
+	"context"
+	"testing"
+
+	bSdkClient "github.com/phalanx-labs/beacon-sso-sdk/client"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+	"golang.org/x/oauth2"
+)
+
+func TestTryGet(t *testing.T) {
+	ctx := context.Background()
+	if client, err := TryGetSsoClient(ctx); err == nil || client != nil {
+		t.Fatalf("未注册 SsoClient 时应返回错误")
+	}
+	if _, err := TryGetSsoClient(context.WithValue(ctx, bSdkConst.CtxSsoClient, (*bSdkClient.SsoClient)(nil))); err == nil {
+		t.Fatalf("SsoClient 为空时应返回错误")
+	}
+	if _, err := TryGetOAuthConfig(ctx); err == nil {
+		t.Fatalf("未注册 OAuth 配置时应返回错误")
+	}
+	if _, err := TryGetOAuthUserinfoURI(ctx); err == nil {
+		t.Fatalf("未注册用户信息地址时应返回错误")
+	}
+
+	cfg := &oauth2.Config{ClientID: "client-id"}
+	got, err := TryGetOAuthConfig(context.WithValue(ctx, bSdkConst.CtxOAuthConfig, cfg))
+	if err != nil || got != cfg {
+		t.Fatalf("应返回已注册的 OAuth 配置: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("GetSsoClient 在未注册时应 panic")
+		}
+	}()
+	GetSsoClient(ctx)
+}
@@ -0,0 +1,118 @@
+package bSdkUtil
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+)
+
+// GetSessionCookie 从请求中读取本地会话 Cookie 的值。
+//
+// 参数:
+//   - ctx: Gin 的上下文对象。
+//   - session: 本地会话配置，Cookie 名称取自 `CookieName`。
+//
+// 返回值:
+//   - string: 会话 Cookie 的值；未开启 Cookie 会话模式或 Cookie 不存在时返回空字符串。
+func GetSessionCookie(ctx *gin.Context, session bSdkConfig.SessionConfig) string {
+	if !session.Cookie {
+		return ""
+	}
+	value, err := ctx.Cookie(session.CookieName)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// SetSessionCookie 写入本地会话 Cookie，值为访问令牌。
+//
+// SameSite 取自 `session.same_site`，默认 `Lax`；未设置 MaxAge，浏览器关闭后失效，
+// 会话的实际有效期由服务端令牌缓存控制。
+//
+// 参数:
+//   - ctx: Gin 的上下文对象。
+//   - session: 本地会话配置，决定 Cookie 的名称、作用域名与 SameSite 属性。
+//   - accessToken: 访问令牌。
+func SetSessionCookie(ctx *gin.Context, session bSdkConfig.SessionConfig, accessToken string) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     session.CookieName,
+		Value:    accessToken,
+		Path:     "/",
+		Domain:   session.CookieDomain,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: sessionSameSite(session.SameSite),
+	})
+}
+
+// ClearSessionCookie 清除本地会话 Cookie。
+//
+// 前端通道登出通过 SSO 页面中的 iframe 跨站发起，因此清除时固定使用 `SameSite=None; Secure`，
+// 确保跨站响应中的 Set-Cookie 不被浏览器丢弃；写入时的 SameSite 属性不影响覆盖。
+//
+// 参数:
+//   - ctx: Gin 的上下文对象。
+//   - session: 本地会话配置，决定 Cookie 的名称与作用域名。
+func ClearSessionCookie(ctx *gin.Context, session bSdkConfig.SessionConfig) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     session.CookieName,
+		Value:    "",
+		Path:     "/",
+		Domain:   session.CookieDomain,
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteNoneMode,
+	})
+}
+
+// CheckSessionOrigin 校验以会话 Cookie 认证的请求来源，防御跨站请求伪造。
+//
+// 安全方法（GET/HEAD/OPTIONS/TRACE）直接放行；其余请求的 `Origin`（缺失时取 `Referer`）
+// 须与当前请求主机一致，或为 `session.trusted_origins` 之一，两者均缺失时拒绝。
+//
+// 参数:
+//   - ctx: Gin 的上下文对象。
+//   - session: 本地会话配置，可信来源取自 `TrustedOrigins`。
+//
+// 返回值:
+//   - bool: 来源可信时返回 true。
+func CheckSessionOrigin(ctx *gin.Context, session bSdkConfig.SessionConfig) bool {
+	switch ctx.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	source := ctx.GetHeader("Origin")
+	if source == "" || source == "null" {
+		source = ctx.GetHeader("Referer")
+	}
+	parsed, err := url.Parse(source)
+	if source == "" || err != nil || parsed.Host == "" {
+		return false
+	}
+	if strings.EqualFold(parsed.Host, ctx.Request.Host) {
+		return true
+	}
+	origin := strings.ToLower(parsed.Scheme + "://" + parsed.Host)
+	return slices.ContainsFunc(session.TrustedOrigins, func(trusted string) bool {
+		return strings.EqualFold(strings.TrimRight(trusted, "/"), origin)
+	})
+}
+
+// sessionSameSite 将配置的 SameSite 取值转换为 Cookie 属性，未识别的取值按 Lax 处理。
+func sessionSameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
@@ -0,0 +1,113 @@
+package bSdkUtil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+)
+
+func TestSetSessionCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("默认配置", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+
+		SetSessionCookie(ctx, bSdkConfig.Default().Session, "new-access-token")
+
+		cookies := recorder.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("期望写入一个 Cookie，实际 %d 个", len(cookies))
+		}
+		cookie := cookies[0]
+		if cookie.Name != bSdkConst.DefaultSessionCookieName || cookie.Value != "new-access-token" || cookie.Domain != "" {
+			t.Fatalf("Cookie 内容不正确: %s=%s; Domain=%s", cookie.Name, cookie.Value, cookie.Domain)
+		}
+		if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+			t.Fatalf("Cookie 默认应为 HttpOnly、Secure 与 SameSite=Lax")
+		}
+	})
+
+	t.Run("自定义名称与作用域名", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+		session := bSdkConfig.SessionConfig{CookieName: "app_session", CookieDomain: "example.com", SameSite: "strict"}
+
+		SetSessionCookie(ctx, session, "new-access-token")
+		ClearSessionCookie(ctx, session)
+
+		cookies := recorder.Result().Cookies()
+		if len(cookies) != 2 {
+			t.Fatalf("期望写入两个 Cookie，实际 %d 个", len(cookies))
+		}
+		for _, cookie := range cookies {
+			if cookie.Name != "app_session" || cookie.Domain != "example.com" {
+				t.Fatalf("Cookie 应使用配置的名称与作用域名: %s; Domain=%s", cookie.Name, cookie.Domain)
+			}
+		}
+		if cookies[0].SameSite != http.SameSiteStrictMode {
+			t.Fatalf("写入时应使用配置的 SameSite 属性")
+		}
+		if cookies[1].MaxAge >= 0 || cookies[1].Value != "" {
+			t.Fatalf("清除时应使 Cookie 立即过期")
+		}
+		if cookies[1].SameSite != http.SameSiteNoneMode || !cookies[1].Secure {
+			t.Fatalf("清除时应使用 SameSite=None; Secure 以支持前端通道登出 iframe")
+		}
+	})
+}
+
+func TestGetSessionCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx.Request.AddCookie(&http.Cookie{Name: "app_session", Value: "cookie-token"})
+
+	if value := GetSessionCookie(ctx, bSdkConfig.SessionConfig{Cookie: true, CookieName: "app_session"}); value != "cookie-token" {
+		t.Fatalf("期望读取到会话 Cookie，实际 %q", value)
+	}
+	if value := GetSessionCookie(ctx, bSdkConfig.SessionConfig{CookieName: "app_session"}); value != "" {
+		t.Fatalf("未开启 Cookie 会话模式时不应读取 Cookie，实际 %q", value)
+	}
+	session := bSdkConfig.Default().Session
+	session.Cookie = true
+	if value := GetSessionCookie(ctx, session); value != "" {
+		t.Fatalf("名称不匹配时不应读取到 Cookie，实际 %q", value)
+	}
+}
+
+func TestCheckSessionOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	session := bSdkConfig.SessionConfig{TrustedOrigins: []string{"https://app.example.com"}}
+
+	cases := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "安全方法", method: http.MethodGet, want: true},
+		{name: "同源", method: http.MethodPost, headers: map[string]string{"Origin": "https://api.example.com"}, want: true},
+		{name: "可信来源", method: http.MethodPost, headers: map[string]string{"Origin": "https://app.example.com"}, want: true},
+		{name: "Referer 回退", method: http.MethodDelete, headers: map[string]string{"Referer": "https://app.example.com/page"}, want: true},
+		{name: "跨站来源", method: http.MethodPost, headers: map[string]string{"Origin": "https://evil.example.net"}, want: false},
+		{name: "协议不符", method: http.MethodPost, headers: map[string]string{"Origin": "http://app.example.com"}, want: false},
+		{name: "缺少来源", method: http.MethodPut, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(tc.method, "https://api.example.com/", nil)
+			for key, value := range tc.headers {
+				ctx.Request.Header.Set(key, value)
+			}
+			if got := CheckSessionOrigin(ctx, session); got != tc.want {
+				t.Fatalf("期望 %v，实际 %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package bSdkUtil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// TokenFingerprint 计算令牌指纹。
+//
+// 指纹为令牌的 HMAC-SHA256，用作 Redis 缓存键、会话索引成员与吊销广播内容，
+// 避免令牌明文出现在键名或进程间消息中。HMAC 密钥取自 `token.hash_key`（`SSO_TOKEN_HASH_KEY`），
+// 未配置时由客户端密钥派生（参见 `ConfigureTokenKeys`），同一部署内所有实例需保持一致。
+//
+// 参数:
+//   - token: 令牌明文。
+//
+// 返回值:
+//   - string: 十六进制编码的指纹；令牌为空时返回空字符串。
+//   - error: 令牌密钥不可用时返回 `ErrTokenKeyUnavailable`。
+func TokenFingerprint(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	state, err := currentTokenKeys()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, state.hashKey)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// RedactToken 返回用于日志输出的令牌标识
+//
+// 取令牌指纹的前 12 位，便于在日志中关联同一令牌而不暴露令牌明文；令牌为空或令牌密钥不可用时返回空字符串。
+func RedactToken(token string) string {
+	fingerprint, _ := TokenFingerprint(token)
+	if len(fingerprint) > 12 {
+		return fingerprint[:12]
+	}
+	return fingerprint
+}
+
+// deriveTokenKey 由客户端密钥派生指定用途的 32 字节密钥。
+func deriveTokenKey(secret string, label string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("beacon-sso-sdk/" + label))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,100 @@
+package bSdkUtil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+)
+
+func TestTokenFingerprint(t *testing.T) {
+	resetKeyRing(t)
+	configureTestKeys(t, bSdkConfig.TokenConfig{})
+
+	if fingerprint, err := TokenFingerprint(""); fingerprint != "" || err != nil {
+		t.Fatalf("空令牌应返回空指纹")
+	}
+
+	first := mustFingerprint(t, "access-token")
+	if len(first) != 64 {
+		t.Fatalf("指纹长度不正确: %d", len(first))
+	}
+	if first != mustFingerprint(t, "access-token") {
+		t.Fatalf("相同令牌的指纹应一致")
+	}
+	if first == mustFingerprint(t, "access-token-2") {
+		t.Fatalf("不同令牌的指纹不应相同")
+	}
+
+	resetKeyRing(t)
+	configureTestKeys(t, bSdkConfig.TokenConfig{HashKey: "another-hash-key"})
+	second := mustFingerprint(t, "access-token")
+	if first == second {
+		t.Fatalf("更换 HMAC 密钥后指纹应变化")
+	}
+	if RedactToken("access-token") != second[:12] {
+		t.Fatalf("日志中的令牌标识应为指纹前缀")
+	}
+
+	t.Run("按环境变量惰性加载", func(t *testing.T) {
+		resetKeyRing(t)
+		path := filepath.Join(t.TempDir(), "hash-key")
+		if err := os.WriteFile(path, []byte("another-hash-key\n"), 0o600); err != nil {
+			t.Fatalf("写入密钥文件失败: %v", err)
+		}
+		t.Setenv(bSdkConst.EnvSsoTokenHashKeyFile.String(), path)
+		t.Setenv(bSdkConst.EnvSsoClientSecret.String(), "client-secret")
+		if mustFingerprint(t, "access-token") != second {
+			t.Fatalf("从文件读取的 HMAC 密钥应与配置一致")
+		}
+
+		// 文件轮换后需重新加载才会生效
+		if err := os.WriteFile(path, []byte("rotated-hash-key"), 0o600); err != nil {
+			t.Fatalf("写入密钥文件失败: %v", err)
+		}
+		if mustFingerprint(t, "access-token") != second {
+			t.Fatalf("未重新加载时指纹不应变化")
+		}
+		if _, err := ReloadTokenKeyRing(context.Background()); err != nil {
+			t.Fatalf("重新加载失败: %v", err)
+		}
+		if mustFingerprint(t, "access-token") == second {
+			t.Fatalf("重新加载后应读取新的 HMAC 密钥")
+		}
+	})
+
+	t.Run("无可用密钥时返回错误", func(t *testing.T) {
+		resetKeyRing(t)
+		t.Setenv(bSdkConst.EnvSsoClientSecret.String(), "")
+		fingerprint, err := TokenFingerprint("access-token")
+		if !errors.Is(err, ErrTokenKeyUnavailable) || fingerprint != "" {
+			t.Fatalf("无可用密钥时应返回错误而非使用临时密钥，实际 %q, %v", fingerprint, err)
+		}
+		if _, err = EncryptToken("access-token", "fp-1"); !errors.Is(err, ErrTokenKeyUnavailable) {
+			t.Fatalf("无可用密钥时加密应返回错误，实际 %v", err)
+		}
+		if RedactToken("access-token") != "" {
+			t.Fatalf("无可用密钥时日志标识应为空")
+		}
+
+		// 加载失败不会被缓存，补齐密钥后可恢复
+		t.Setenv(bSdkConst.EnvSsoClientSecret.String(), "client-secret")
+		if _, err = TokenFingerprint("access-token"); err != nil {
+			t.Fatalf("补齐密钥后应可计算指纹: %v", err)
+		}
+	})
+}
+
+// mustFingerprint 计算令牌指纹，失败时终止测试。
+func mustFingerprint(t *testing.T, token string) string {
+	t.Helper()
+	fingerprint, err := TokenFingerprint(token)
+	if err != nil {
+		t.Fatalf("计算令牌指纹失败: %v", err)
+	}
+	return fingerprint
+}
@@ -0,0 +1,41 @@
+package bSdkUtil
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvalidTokenScopes 返回无效令牌限流维度
+//
+// 上下文为（或派生自）Gin 请求时按客户端 IP 计数；prefixLen 大于 0 时额外按令牌前缀计数，
+// 前缀以指纹形式出现在键中，令牌密钥不可用时不按前缀计数。
+// JWT 的 JOSE 头部对同一签发者的令牌基本相同，令牌包含 `.` 时前缀从第一个 `.` 之后开始截取。
+//
+// 参数:
+//   - ctx: 请求上下文，无法取得 Gin 请求时不按 IP 限流。
+//   - token: 待校验的令牌。
+//   - prefixLen: 令牌前缀长度，通常来自 SDK 配置的 `business.invalid_token_prefix_len`。
+//
+// 返回值:
+//   - []string: 限流维度标识列表，可能为空。
+func InvalidTokenScopes(ctx context.Context, token string, prefixLen int) []string {
+	scopes := make([]string, 0, 2)
+	// 经 `context.WithoutCancel` 等派生后仍可通过 `gin.ContextKey` 取回 Gin 上下文
+	if ginCtx, ok := ctx.Value(gin.ContextKey).(*gin.Context); ok && ginCtx.Request != nil {
+		if ip := ginCtx.ClientIP(); ip != "" {
+			scopes = append(scopes, "ip:"+ip)
+		}
+	}
+
+	if _, rest, found := strings.Cut(token, "."); found {
+		token = rest
+	}
+	if prefixLen > 0 && len(token) > prefixLen {
+		if fingerprint, fpErr := TokenFingerprint(token[:prefixLen]); fpErr == nil {
+			scopes = append(scopes, "prefix:"+fingerprint)
+		}
+	}
+	return scopes
+}
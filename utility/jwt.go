@@ -0,0 +1,262 @@
+package bSdkUtil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+)
+
+// JWTHeader 表示 JWS 头部中与签名校验相关的字段。
+type JWTHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// JWT 表示一个已解码但尚未校验签名的 JWS Compact 令牌。
+//
+// 该结构体仅负责结构解析，签名需调用 `Verify` 使用 JWKS 中的公钥进行校验，
+// 声明（claims）的业务校验由调用方自行完成。
+type JWT struct {
+	Header       JWTHeader      // JWS 头部
+	Claims       map[string]any // 声明集合
+	signingInput string         // 签名原文（header.payload）
+	signature    []byte         // 签名值
+}
+
+// ParseJWT 解析 JWS Compact 格式的令牌，不校验签名。
+//
+// 参数说明:
+//   - raw: 原始令牌字符串（header.payload.signature）。
+//
+// 返回值:
+//   - *JWT: 解析后的令牌结构。
+//   - error: 令牌格式非法、Base64 或 JSON 解码失败时返回错误。
+func ParseJWT(raw string) (*JWT, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("令牌格式错误")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("解析令牌头部失败: %w", err)
+	}
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("解析令牌载荷失败: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("解析令牌签名失败: %w", err)
+	}
+
+	token := &JWT{
+		signingInput: parts[0] + "." + parts[1],
+		signature:    signature,
+	}
+	if err = json.Unmarshal(headerBytes, &token.Header); err != nil {
+		return nil, fmt.Errorf("解析令牌头部失败: %w", err)
+	}
+	if err = json.Unmarshal(payloadBytes, &token.Claims); err != nil {
+		return nil, fmt.Errorf("解析令牌载荷失败: %w", err)
+	}
+
+	return token, nil
+}
+
+// ClaimString 读取字符串类型的声明，不存在或类型不符时返回空字符串。
+func (t *JWT) ClaimString(name string) string {
+	if value, ok := t.Claims[name].(string); ok {
+		return value
+	}
+	return ""
+}
+
+// ClaimInt64 读取数值类型的声明（JSON number），不存在或类型不符时返回 false。
+func (t *JWT) ClaimInt64(name string) (int64, bool) {
+	value, ok := t.Claims[name].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int64(value), true
+}
+
+// ClaimAudience 读取 `aud` 声明，兼容字符串与字符串数组两种格式。
+func (t *JWT) ClaimAudience() []string {
+	switch value := t.Claims["aud"].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		audience := make([]string, 0, len(value))
+		for _, item := range value {
+			if aud, ok := item.(string); ok {
+				audience = append(audience, aud)
+			}
+		}
+		return audience
+	default:
+		return nil
+	}
+}
+
+// Verify 使用给定的 JWK 公钥校验令牌签名。
+//
+// 支持 RS256/384/512、PS256/384/512、ES256/384/512 与 EdDSA，`none` 算法始终被拒绝。
+// 如果 JWK 声明了 `alg`，则必须与令牌头部的 `alg` 一致，防止算法混淆攻击；
+// ES 系列算法还要求公钥曲线与算法匹配（ES256 对应 P-256，ES384 对应 P-384，ES512 对应 P-521）。
+//
+// 参数说明:
+//   - key: JWKS 中与令牌 `kid` 匹配的公钥。
+//
+// 返回值:
+//   - error: 算法不受支持、公钥不匹配或签名无效时返回错误。
+func (t *JWT) Verify(key *bSdkModels.JSONWebKey) error {
+	if key == nil {
+		return errors.New("公钥为空")
+	}
+	if key.Alg != "" && key.Alg != t.Header.Alg {
+		return fmt.Errorf("公钥算法 %s 与令牌算法 %s 不一致", key.Alg, t.Header.Alg)
+	}
+
+	switch t.Header.Alg {
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		pub, err := rsaPublicKey(key)
+		if err != nil {
+			return err
+		}
+		hash := jwtHash(t.Header.Alg[2:])
+		digest := hashSum(hash, t.signingInput)
+		if strings.HasPrefix(t.Header.Alg, "PS") {
+			return rsa.VerifyPSS(pub, hash, digest, t.signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, digest, t.signature)
+	case "ES256", "ES384", "ES512":
+		// RFC 7518 §3.4 将算法与曲线一一绑定，防止以其他曲线的公钥校验签名
+		if key.Crv != ecdsaCurves[t.Header.Alg] {
+			return fmt.Errorf("公钥曲线 %s 与令牌算法 %s 不一致", key.Crv, t.Header.Alg)
+		}
+		pub, err := ecdsaPublicKey(key)
+		if err != nil {
+			return err
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(t.signature) != size*2 {
+			return errors.New("签名长度无效")
+		}
+		r := new(big.Int).SetBytes(t.signature[:size])
+		s := new(big.Int).SetBytes(t.signature[size:])
+		if !ecdsa.Verify(pub, hashSum(jwtHash(t.Header.Alg[2:]), t.signingInput), r, s) {
+			return errors.New("签名无效")
+		}
+		return nil
+	case "EdDSA":
+		if key.Kty != "OKP" || key.Crv != "Ed25519" {
+			return errors.New("公钥类型与算法不匹配")
+		}
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return errors.New("公钥格式错误")
+		}
+		if !ed25519.Verify(x, []byte(t.signingInput), t.signature) {
+			return errors.New("签名无效")
+		}
+		return nil
+	default:
+		return fmt.Errorf("不支持的签名算法: %s", t.Header.Alg)
+	}
+}
+
+// DecodeJWTClaims 仅解码 JWT 载荷而不校验签名。
+//
+// 该函数用于读取直接从令牌端点（TLS 信道）获取的 ID Token 中的 `sub`/`sid`，
+// 以建立本地会话索引；切勿用于校验来自不可信来源的令牌。
+func DecodeJWTClaims(raw string) (map[string]any, error) {
+	token, err := ParseJWT(raw)
+	if err != nil {
+		return nil, err
+	}
+	return token.Claims, nil
+}
+
+func jwtHash(bits string) crypto.Hash {
+	switch bits {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+func hashSum(hash crypto.Hash, input string) []byte {
+	h := hash.New()
+	h.Write([]byte(input))
+	return h.Sum(nil)
+}
+
+func rsaPublicKey(key *bSdkModels.JSONWebKey) (*rsa.PublicKey, error) {
+	if key.Kty != "RSA" {
+		return nil, errors.New("公钥类型与算法不匹配")
+	}
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, errors.New("公钥格式错误")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, errors.New("公钥格式错误")
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// ecdsaCurves ES 系列签名算法对应的椭圆曲线。
+var ecdsaCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+func ecdsaPublicKey(key *bSdkModels.JSONWebKey) (*ecdsa.PublicKey, error) {
+	if key.Kty != "EC" {
+		return nil, errors.New("公钥类型与算法不匹配")
+	}
+	var curve elliptic.Curve
+	switch key.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("不支持的椭圆曲线: %s", key.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil {
+		return nil, errors.New("公钥格式错误")
+	}
+	y, err := base64.RawURLEncoding.DecodeString(key.Y)
+	if err != nil {
+		return nil, errors.New("公钥格式错误")
+	}
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
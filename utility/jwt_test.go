@@ -0,0 +1,183 @@
+package bSdkUtil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	bSdkModels "github.com/phalanx-labs/beacon-sso-sdk/models"
+)
+
+func signTestJWT(t *testing.T, key *rsa.PrivateKey, header map[string]any, claims map[string]any) string {
+	t.Helper()
+
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("序列化头部失败: %v", err)
+	}
+	claimsBytes, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("序列化载荷失败: %v", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerBytes) + "." + base64.RawURLEncoding.EncodeToString(claimsBytes)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func rsaTestJWK(key *rsa.PrivateKey, kid string, alg string) *bSdkModels.JSONWebKey {
+	return &bSdkModels.JSONWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: alg,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestJWTVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	raw := signTestJWT(t, key,
+		map[string]any{"alg": "RS256", "kid": "k1", "typ": "logout+jwt"},
+		map[string]any{"iss": "https://sso.example.com", "aud": []string{"cid"}, "iat": 1700000000, "sid": "s1"},
+	)
+
+	t.Run("校验成功", func(t *testing.T) {
+		token, err := ParseJWT(raw)
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		if token.Header.Kid != "k1" || token.ClaimString("sid") != "s1" {
+			t.Fatalf("解析结果不正确: %+v", token)
+		}
+		if iat, ok := token.ClaimInt64("iat"); !ok || iat != 1700000000 {
+			t.Fatalf("iat 解析不正确: %d", iat)
+		}
+		if aud := token.ClaimAudience(); len(aud) != 1 || aud[0] != "cid" {
+			t.Fatalf("aud 解析不正确: %v", aud)
+		}
+		if err = token.Verify(rsaTestJWK(key, "k1", "RS256")); err != nil {
+			t.Fatalf("期望签名校验通过: %v", err)
+		}
+	})
+
+	t.Run("签名被篡改", func(t *testing.T) {
+		tampered := signTestJWT(t, key,
+			map[string]any{"alg": "RS256", "kid": "k1"},
+			map[string]any{"sid": "s2"},
+		)
+		token, err := ParseJWT(tampered[:len(tampered)-4] + "AAAA")
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		if err = token.Verify(rsaTestJWK(key, "k1", "RS256")); err == nil {
+			t.Fatalf("期望签名校验失败")
+		}
+	})
+
+	t.Run("公钥算法不一致", func(t *testing.T) {
+		token, _ := ParseJWT(raw)
+		if err := token.Verify(rsaTestJWK(key, "k1", "RS512")); err == nil {
+			t.Fatalf("期望算法不一致时校验失败")
+		}
+	})
+
+	t.Run("拒绝 none 算法", func(t *testing.T) {
+		token, _ := ParseJWT(raw)
+		token.Header.Alg = "none"
+		if err := token.Verify(rsaTestJWK(key, "k1", "")); err == nil {
+			t.Fatalf("期望 none 算法被拒绝")
+		}
+	})
+
+	t.Run("格式错误", func(t *testing.T) {
+		if _, err := ParseJWT("a.b"); err == nil {
+			t.Fatalf("期望格式错误")
+		}
+	})
+}
+
+func signTestECJWT(t *testing.T, key *ecdsa.PrivateKey, alg string, claims map[string]any) string {
+	t.Helper()
+
+	headerBytes, _ := json.Marshal(map[string]any{"alg": alg, "kid": "ec"})
+	claimsBytes, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("序列化载荷失败: %v", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerBytes) + "." + base64.RawURLEncoding.EncodeToString(claimsBytes)
+	r, s, err := ecdsa.Sign(rand.Reader, key, hashSum(jwtHash(alg[2:]), signingInput))
+	if err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	size := (key.Curve.Params().BitSize + 7) / 8
+	signature := make([]byte, size*2)
+	r.FillBytes(signature[:size])
+	s.FillBytes(signature[size:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func ecTestJWK(key *ecdsa.PrivateKey, crv string) *bSdkModels.JSONWebKey {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	return &bSdkModels.JSONWebKey{
+		Kty: "EC",
+		Kid: "ec",
+		Crv: crv,
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size))),
+	}
+}
+
+func TestJWTVerifyECDSA(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	claims := map[string]any{"sid": "s1"}
+
+	tests := []struct {
+		name    string
+		raw     string
+		key     *bSdkModels.JSONWebKey
+		wantErr bool
+	}{
+		{name: "ES256 使用 P-256", raw: signTestECJWT(t, p256, "ES256", claims), key: ecTestJWK(p256, "P-256")},
+		{name: "ES384 使用 P-384", raw: signTestECJWT(t, p384, "ES384", claims), key: ecTestJWK(p384, "P-384")},
+		{name: "ES256 使用 P-384 公钥", raw: signTestECJWT(t, p384, "ES256", claims), key: ecTestJWK(p384, "P-384"), wantErr: true},
+		{name: "ES384 使用 P-256 公钥", raw: signTestECJWT(t, p256, "ES384", claims), key: ecTestJWK(p256, "P-256"), wantErr: true},
+		{name: "公钥声明的曲线与实际不符", raw: signTestECJWT(t, p384, "ES256", claims), key: ecTestJWK(p384, "P-256"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := ParseJWT(tt.raw)
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			err = token.Verify(tt.key)
+			if tt.wantErr && err == nil {
+				t.Fatalf("期望校验失败")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("期望校验通过: %v", err)
+			}
+		})
+	}
+}
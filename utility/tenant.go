@@ -0,0 +1,69 @@
+package bSdkUtil
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tenantContextKey 租户上下文键。
+type tenantContextKey struct{}
+
+// tenantContext 请求所属的租户及其缓存命名空间。
+type tenantContext struct {
+	name      string
+	namespace string
+}
+
+// WithTenant 返回携带租户信息的上下文
+//
+// 参数说明:
+//   - ctx: 父上下文。
+//   - name: 租户名称，为空表示使用根配置。
+//   - namespace: 租户的缓存命名空间，状态存储会将其附加到全部键之前。
+//
+// 返回值:
+//   - context.Context: 携带租户信息的上下文。
+func WithTenant(ctx context.Context, name string, namespace string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantContext{name: name, namespace: namespace})
+}
+
+// InheritTenant 将 from 携带的租户信息复制到 ctx，用于脱离请求生命周期的后台任务。
+func InheritTenant(ctx context.Context, from context.Context) context.Context {
+	tenant, ok := lookupTenant(from)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// GetTenant 返回上下文所属的租户名称，未绑定租户时返回空字符串
+//
+// 对 `*gin.Context` 及其派生上下文，从请求上下文中读取由租户中间件写入的信息。
+func GetTenant(ctx context.Context) string {
+	tenant, _ := lookupTenant(ctx)
+	return tenant.name
+}
+
+// TenantNamespace 返回上下文所属租户的缓存命名空间，未绑定租户时返回空字符串。
+func TenantNamespace(ctx context.Context) string {
+	tenant, _ := lookupTenant(ctx)
+	return tenant.namespace
+}
+
+// lookupTenant 读取上下文中的租户信息。
+func lookupTenant(ctx context.Context) (tenantContext, bool) {
+	if ctx == nil {
+		return tenantContext{}, false
+	}
+	if tenant, ok := ctx.Value(tenantContextKey{}).(tenantContext); ok {
+		return tenant, true
+	}
+	// gin 未开启 ContextWithFallback 时不会把非字符串键转发给请求上下文，需要显式读取
+	if req, ok := ctx.Value(gin.ContextRequestKey).(*http.Request); ok && req != nil {
+		tenant, ok := req.Context().Value(tenantContextKey{}).(tenantContext)
+		return tenant, ok
+	}
+	return tenantContext{}, false
+}
@@ -0,0 +1,452 @@
+package bSdkUtil
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	xLog "github.com/bamboo-services/bamboo-base-go/common/log"
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+)
+
+const (
+	tokenCipherV2 = "v2." // 信封格式：`v2.<kid>.<base64url(包裹的数据密钥)>.<base64url(nonce||ciphertext)>`
+
+	DerivedTokenKeyID = "derived" // 未配置密钥时由客户端密钥派生的密钥标识
+	DefaultTokenKeyID = "default" // 通过 `token.encryption_key`（`SSO_TOKEN_ENCRYPTION_KEY`）配置单个密钥时的密钥标识
+
+	tokenDataKeySize = 32 // 每条记录独立生成的数据密钥长度（AES-256）
+)
+
+var (
+	keyProviderMu sync.Mutex
+	keyProvider   KeyProvider // 自定义密钥提供者，为 nil 时使用 `ConfigKeyProvider`
+	tokenKeys     atomic.Pointer[tokenKeyState]
+)
+
+// tokenKeyState 进程级令牌密钥。
+//
+// 令牌指纹密钥与派生密钥在首次通过 `ConfigureTokenKeys` 配置后固定，客户端密钥后续轮换不会影响已写入的缓存；
+// 加密密钥环在配置热更新后按新快照重新加载。
+type tokenKeyState struct {
+	handle  *bSdkConfig.Config // 通过 `ConfigureTokenKeys` 注册的配置句柄，按环境变量惰性加载时为 nil
+	source  *bSdkConfig.Config // 构建密钥所基于的配置快照
+	hashKey []byte             // 令牌指纹的 HMAC 密钥
+	derived []byte             // 由客户端密钥派生的加密密钥，客户端密钥为空时为 nil
+	ring    *TokenKeyRing      // 加密密钥环，为 nil 时在下次使用时重新加载
+}
+
+// SetKeyProvider 替换令牌加密密钥提供者，并在下次使用时重新加载密钥环。
+//
+// 需在 SDK 初始化（`NewStartupConfig`）之前调用，以便启动阶段即可校验密钥；传入 nil 时恢复按 SDK 配置加载。
+// 派生密钥（kid 为 `derived`）会作为仅解密密钥追加到自定义提供者的密钥环中，便于从派生密钥迁移。
+func SetKeyProvider(provider KeyProvider) {
+	keyProviderMu.Lock()
+	defer keyProviderMu.Unlock()
+	keyProvider = provider
+	if state := tokenKeys.Load(); state != nil {
+		next := *state
+		next.ring = nil
+		tokenKeys.Store(&next)
+	}
+}
+
+// ConfigureTokenKeys 按 SDK 配置设置进程级令牌密钥，由 `oAuthConfig` 启动节点与 `bSdkLogic.NewOAuthWith`、`bSdkLogic.NewBusinessWith` 调用
+//
+// 令牌指纹密钥取 `token.hash_key`，未配置时由客户端密钥派生；加密密钥环由 `SetKeyProvider` 注册的提供者
+// 或 `ConfigKeyProvider` 加载，未配置加密密钥时使用由客户端密钥派生的密钥（kid 为 `derived`），
+// 配置了加密密钥时派生密钥保留为仅解密密钥，便于迁移。两者都需要派生而客户端密钥为空时返回错误。
+//
+// 派生所用的客户端密钥与令牌指纹密钥在首次配置后固定，轮换客户端密钥不会改变令牌密钥；
+// 加密密钥环随 cfg 的热更新快照自动重新加载，用于密钥轮换。令牌密钥是进程级的，
+// 同一进程内的多个 SDK 实例需使用相同的 `token` 密钥配置。
+//
+// 参数说明:
+//   - ctx: 上下文对象，传递给密钥提供者。
+//   - cfg: SDK 配置，通常由 `bSdkConfig.NewReloader` 构建，密钥引用需已读取。
+//
+// 返回值:
+//   - *TokenKeyRing: 生效的加密密钥环。
+//   - error: 缺少密钥、密钥非法或与进程内已配置的密钥冲突时返回错误，此时沿用原有密钥。
+func ConfigureTokenKeys(ctx context.Context, cfg *bSdkConfig.Config) (*TokenKeyRing, error) {
+	if cfg == nil {
+		return nil, errors.New("SDK 配置为空，无法配置令牌密钥")
+	}
+
+	keyProviderMu.Lock()
+	defer keyProviderMu.Unlock()
+
+	pinned := pinnedTokenKeys()
+	if pinned != nil && pinned.handle != cfg {
+		if !sameTokenKeyConfig(pinned.handle.Current().Token, cfg.Current().Token) {
+			return nil, errors.New("同一进程内的 SDK 实例需使用相同的令牌密钥配置（token）")
+		}
+		return pinned.ring, nil
+	}
+
+	state, err := buildTokenKeys(ctx, cfg.Current(), pinned)
+	if err != nil {
+		return nil, err
+	}
+	state.handle = cfg
+	tokenKeys.Store(state)
+	return state.ring, nil
+}
+
+// CheckTokenKeys 按 SDK 配置校验令牌密钥，不替换当前生效的密钥，供启动前检查使用
+//
+// 参数说明:
+//   - ctx: 上下文对象，传递给密钥提供者。
+//   - cfg: SDK 配置，密钥引用需已读取。
+//
+// 返回值:
+//   - bool: 是否显式配置了加密密钥（未配置时由客户端密钥派生）。
+//   - error: 缺少密钥、密钥非法或与进程内已配置的密钥冲突时返回错误。
+func CheckTokenKeys(ctx context.Context, cfg *bSdkConfig.Config) (bool, error) {
+	if cfg == nil {
+		return false, errors.New("SDK 配置为空，无法配置令牌密钥")
+	}
+
+	keyProviderMu.Lock()
+	defer keyProviderMu.Unlock()
+
+	state, err := buildTokenKeys(ctx, cfg.Current(), pinnedTokenKeys())
+	if err != nil {
+		return false, err
+	}
+	return state.ring.Active.ID != DerivedTokenKeyID, nil
+}
+
+// ReloadTokenKeyRing 立即通过当前密钥提供者重新加载密钥环。
+//
+// 密钥轮换时，先将新密钥设为活动密钥、旧密钥保留为仅解密密钥，再调用该方法；
+// 旧密钥加密的缓存会在读取时被惰性地使用新密钥重新加密。
+// 通过 `ConfigureTokenKeys` 注册的配置热更新后会自动重新加载，密钥来自文件或自定义提供者时可调用该方法。
+//
+// 参数:
+//   - ctx: 上下文对象。
+//
+// 返回值:
+//   - *TokenKeyRing: 加载成功的密钥环。
+//   - error: 加载或校验失败时返回错误，此时仍沿用旧密钥环。
+func ReloadTokenKeyRing(ctx context.Context) (*TokenKeyRing, error) {
+	keyProviderMu.Lock()
+	defer keyProviderMu.Unlock()
+
+	current := pinnedTokenKeys()
+	if current == nil {
+		state, err := loadEnvTokenKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tokenKeys.Store(state)
+		return state.ring, nil
+	}
+
+	state, err := buildTokenKeys(ctx, current.handle.Current(), current)
+	if err != nil {
+		return nil, err
+	}
+	state.handle = current.handle
+	tokenKeys.Store(state)
+	return state.ring, nil
+}
+
+// currentKeyRing 获取当前密钥环，未加载或已失效时重新加载。
+func currentKeyRing() (*TokenKeyRing, error) {
+	state, err := currentTokenKeys()
+	if err != nil {
+		return nil, err
+	}
+	if state.ring != nil {
+		return state.ring, nil
+	}
+	return ReloadTokenKeyRing(context.Background())
+}
+
+// currentTokenKeys 获取当前令牌密钥。
+//
+// 注册的配置句柄热更新后按新快照重新构建密钥环，失败时沿用原有密钥并记录告警；
+// 未调用 `ConfigureTokenKeys` 时按环境变量（及 `SSO_CONFIG_FILE`）惰性加载，无可用密钥时返回错误，
+// 不会生成临时密钥，避免各实例计算出不同的令牌指纹、无法读取彼此的密文。加载失败不会被缓存，下次使用时重试。
+func currentTokenKeys() (*tokenKeyState, error) {
+	state := tokenKeys.Load()
+	if state != nil && (state.handle == nil || state.handle.Current() == state.source) {
+		return state, nil
+	}
+
+	keyProviderMu.Lock()
+	defer keyProviderMu.Unlock()
+
+	ctx := context.Background()
+	state = tokenKeys.Load()
+	switch {
+	case state == nil:
+		next, err := loadEnvTokenKeys(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrTokenKeyUnavailable, err)
+		}
+		state = next
+	case state.handle != nil && state.handle.Current() != state.source:
+		snapshot := state.handle.Current()
+		next, err := buildTokenKeys(ctx, snapshot, state)
+		if err != nil {
+			xLog.WithName(xLog.NamedUTIL).Warn(ctx, "配置热更新后重新加载令牌加密密钥失败，继续使用原有密钥",
+				slog.String("error", err.Error()),
+			)
+			unchanged := *state
+			unchanged.source = snapshot
+			next = &unchanged
+		}
+		next.handle = state.handle
+		state = next
+	default:
+		return state, nil
+	}
+	tokenKeys.Store(state)
+	return state, nil
+}
+
+// pinnedTokenKeys 返回已通过 `ConfigureTokenKeys` 固定的令牌密钥，未配置时返回 nil。
+func pinnedTokenKeys() *tokenKeyState {
+	if state := tokenKeys.Load(); state != nil && state.handle != nil {
+		return state
+	}
+	return nil
+}
+
+// loadEnvTokenKeys 按进程级配置（`SSO_CONFIG_FILE` 与环境变量，参见 `bSdkConfig.Process`）加载令牌密钥。
+func loadEnvTokenKeys(ctx context.Context) (*tokenKeyState, error) {
+	cfg, err := bSdkConfig.Process()
+	if err != nil {
+		return nil, err
+	}
+	return buildTokenKeys(ctx, cfg, nil)
+}
+
+// buildTokenKeys 按配置快照构建令牌密钥，pinned 不为空时沿用其令牌指纹密钥与派生密钥。
+func buildTokenKeys(ctx context.Context, cfg *bSdkConfig.Config, pinned *tokenKeyState) (*tokenKeyState, error) {
+	token := cfg.Token
+	state := &tokenKeyState{source: cfg}
+	if pinned != nil {
+		state.derived = pinned.derived
+	} else if cfg.Client.Secret != "" {
+		state.derived = deriveTokenKey(cfg.Client.Secret, "token-encryption")
+	}
+
+	switch {
+	case token.HashKey != "":
+		state.hashKey = []byte(token.HashKey)
+	case pinned != nil:
+		state.hashKey = pinned.hashKey
+	case cfg.Client.Secret != "":
+		state.hashKey = deriveTokenKey(cfg.Client.Secret, "token-hash")
+	default:
+		return nil, errors.New("未配置令牌指纹密钥（token.hash_key / SSO_TOKEN_HASH_KEY），且客户端密钥为空，无法派生")
+	}
+	if pinned != nil && !hmac.Equal(state.hashKey, pinned.hashKey) {
+		return nil, errors.New("令牌指纹密钥（token.hash_key）不支持热更新，修改后需重启服务")
+	}
+
+	provider := keyProvider
+	if provider == nil {
+		provider = ConfigKeyProvider{Token: token}
+	}
+	ring, err := provider.LoadKeyRing(ctx)
+	switch {
+	case errors.Is(err, ErrTokenKeyNotConfigured) && state.derived != nil:
+		ring = &TokenKeyRing{Active: TokenKey{ID: DerivedTokenKeyID, Key: state.derived}}
+	case errors.Is(err, ErrTokenKeyNotConfigured):
+		return nil, errors.New("未配置令牌加密密钥（token.encryption_keys / SSO_TOKEN_ENCRYPTION_KEYS），且客户端密钥为空，无法派生")
+	case err != nil:
+		return nil, err
+	}
+	if err = ring.validate(); err != nil {
+		return nil, err
+	}
+	if _, exist := ring.find(DerivedTokenKeyID); !exist && state.derived != nil {
+		ring = &TokenKeyRing{
+			Active:  ring.Active,
+			Decrypt: append(slices.Clone(ring.Decrypt), TokenKey{ID: DerivedTokenKeyID, Key: state.derived}),
+		}
+	}
+	state.ring = ring
+	return state, nil
+}
+
+// sameTokenKeyConfig 判断两份令牌配置的密钥是否一致。
+func sameTokenKeyConfig(a bSdkConfig.TokenConfig, b bSdkConfig.TokenConfig) bool {
+	return a.HashKey == b.HashKey && a.EncryptionKey == b.EncryptionKey && a.ActiveKeyID == b.ActiveKeyID &&
+		a.KeysFile == b.KeysFile && slices.Equal(a.EncryptionKeys, b.EncryptionKeys)
+}
+
+// EncryptToken 使用信封加密保护令牌，用于令牌在 Redis 中的静态存储。
+//
+// 每条记录生成独立的数据密钥加密令牌，数据密钥再由密钥环中的活动密钥包裹，
+// 密文中记录活动密钥的 kid，便于密钥轮换后定位解密密钥。
+// 令牌所属记录的指纹作为 AES-GCM 附加认证数据，密文被复制到其他记录下时无法解密。
+//
+// 参数:
+//   - plain: 令牌明文，为空时直接返回空字符串。
+//   - fingerprint: 令牌所属记录的指纹（令牌缓存为访问令牌指纹），解密时需传入相同的值。
+//
+// 返回值:
+//   - string: 带版本与 kid 前缀的密文。
+//   - error: 密钥加载失败或加密失败时返回错误。
+func EncryptToken(plain string, fingerprint string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+
+	ring, err := currentKeyRing()
+	if err != nil {
+		return "", err
+	}
+
+	dataKey := make([]byte, tokenDataKeySize)
+	if _, err = rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("生成数据密钥失败: %w", err)
+	}
+	payload, err := gcmSeal(dataKey, []byte(plain), []byte(fingerprint))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := gcmSeal(ring.Active.Key, dataKey, []byte(ring.Active.ID))
+	if err != nil {
+		return "", err
+	}
+
+	return tokenCipherV2 + ring.Active.ID + "." +
+		base64.RawURLEncoding.EncodeToString(wrapped) + "." +
+		base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// OpenToken 解密 `EncryptToken` 生成的密文，并报告该值是否需要使用活动密钥重新加密。
+//
+// 使用 kid 对应的密钥解密，kid 非活动密钥时标记为过期；不带版本前缀的值视为写入存储的明文，
+// 返回 `ErrTokenNotEncrypted`，避免其被当作有效令牌使用。
+//
+// 参数:
+//   - value: 缓存中的令牌值。
+//   - fingerprint: 令牌所属记录的指纹，需与加密时一致。
+//
+// 返回值:
+//   - string: 令牌明文。
+//   - bool: 是否需要重新加密。
+//   - error: 密钥缺失、密文损坏、认证失败（含记录指纹不一致）或读到明文时返回错误。
+func OpenToken(value string, fingerprint string) (string, bool, error) {
+	switch {
+	case value == "":
+		return "", false, nil
+	case strings.HasPrefix(value, tokenCipherV2):
+		return openTokenV2(strings.TrimPrefix(value, tokenCipherV2), fingerprint)
+	default:
+		return "", false, ErrTokenNotEncrypted
+	}
+}
+
+// DecryptToken 解密缓存中的令牌，等价于忽略过期标记的 `OpenToken`。
+func DecryptToken(value string, fingerprint string) (string, error) {
+	plain, _, err := OpenToken(value, fingerprint)
+	return plain, err
+}
+
+func openTokenV2(rest string, fingerprint string) (string, bool, error) {
+	parts := strings.SplitN(rest, ".", 3)
+	if len(parts) != 3 {
+		return "", false, errors.New("令牌密文格式错误")
+	}
+
+	ring, err := currentKeyRing()
+	if err != nil {
+		return "", false, err
+	}
+	key, ok := ring.find(parts[0])
+	if !ok {
+		return "", false, fmt.Errorf("未找到令牌加密密钥: %s", parts[0])
+	}
+
+	wrapped, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", false, fmt.Errorf("令牌密文格式错误: %w", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", false, fmt.Errorf("令牌密文格式错误: %w", err)
+	}
+
+	dataKey, err := gcmOpen(key.Key, wrapped, []byte(key.ID))
+	if err != nil {
+		return "", false, fmt.Errorf("数据密钥解密失败: %w", err)
+	}
+	plain, err := gcmOpen(dataKey, payload, []byte(fingerprint))
+	if err != nil {
+		return "", false, fmt.Errorf("令牌解密失败: %w", err)
+	}
+	return string(plain), key.ID != ring.Active.ID, nil
+}
+
+// gcmSeal 使用 AES-GCM 加密，输出格式为 nonce||ciphertext。
+func gcmSeal(key []byte, plain []byte, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成随机数失败: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plain, aad), nil
+}
+
+// gcmOpen 解密 `gcmSeal` 的输出。
+func gcmOpen(key []byte, sealed []byte, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("密文长度无效")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("初始化令牌加密失败: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// decodeAESKey 解析 Base64（标准或 URL 安全，带或不带填充）编码的 AES 密钥。
+func decodeAESKey(raw string) ([]byte, error) {
+	for _, encoding := range []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	} {
+		key, err := encoding.DecodeString(raw)
+		if err != nil {
+			continue
+		}
+		switch len(key) {
+		case 16, 24, 32:
+			return key, nil
+		default:
+			return nil, fmt.Errorf("令牌加密密钥长度需为 16/24/32 字节，实际 %d 字节", len(key))
+		}
+	}
+	return nil, errors.New("令牌加密密钥需为 Base64 编码")
+}
@@ -0,0 +1,299 @@
+package bSdkUtil
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+	bSdkConst "github.com/phalanx-labs/beacon-sso-sdk/constant"
+)
+
+type staticKeyProvider struct {
+	ring *TokenKeyRing
+}
+
+func (p staticKeyProvider) LoadKeyRing(_ context.Context) (*TokenKeyRing, error) {
+	return p.ring, nil
+}
+
+func testAESKey(seed string) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(seed, 32)[:32]))
+}
+
+// resetKeyRing 清除进程级令牌密钥，并在测试结束后再次清除。
+func resetKeyRing(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		keyProviderMu.Lock()
+		defer keyProviderMu.Unlock()
+		keyProvider = nil
+		tokenKeys.Store(nil)
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+// configureTestKeys 以指定的令牌配置设置进程级令牌密钥。
+func configureTestKeys(t *testing.T, token bSdkConfig.TokenConfig) *TokenKeyRing {
+	t.Helper()
+	cfg := bSdkConfig.New(bSdkConfig.WithClient("app", "client-secret"))
+	cfg.Token = token
+	ring, err := ConfigureTokenKeys(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("配置令牌密钥失败: %v", err)
+	}
+	return ring
+}
+
+func TestEncryptToken(t *testing.T) {
+	resetKeyRing(t)
+	configureTestKeys(t, bSdkConfig.TokenConfig{EncryptionKeys: []bSdkConfig.TokenKeyConfig{{ID: "k1", Key: testAESKey("a")}}})
+
+	t.Run("加解密往返", func(t *testing.T) {
+		sealed, err := EncryptToken("refresh-token", "fp-1")
+		if err != nil {
+			t.Fatalf("加密失败: %v", err)
+		}
+		if !strings.HasPrefix(sealed, tokenCipherV2+"k1.") || strings.Contains(sealed, "refresh-token") {
+			t.Fatalf("密文格式不正确: %s", sealed)
+		}
+		plain, stale, err := OpenToken(sealed, "fp-1")
+		if err != nil || plain != "refresh-token" || stale {
+			t.Fatalf("解密结果不正确: %s, %v, %v", plain, stale, err)
+		}
+	})
+
+	t.Run("每次加密使用独立数据密钥", func(t *testing.T) {
+		first, _ := EncryptToken("refresh-token", "fp-1")
+		second, _ := EncryptToken("refresh-token", "fp-1")
+		if first == second {
+			t.Fatalf("期望相同明文产生不同密文")
+		}
+	})
+
+	t.Run("拒绝明文", func(t *testing.T) {
+		if _, _, err := OpenToken("legacy-refresh-token", "fp-1"); !errors.Is(err, ErrTokenNotEncrypted) {
+			t.Fatalf("期望拒绝未加密的令牌，实际 %v", err)
+		}
+	})
+
+	t.Run("密文被篡改", func(t *testing.T) {
+		sealed, _ := EncryptToken("refresh-token", "fp-1")
+		tampered := sealed[:len(sealed)-2] + "AA"
+		if _, err := DecryptToken(tampered, "fp-1"); err == nil {
+			t.Fatalf("期望密文被篡改时解密失败")
+		}
+	})
+
+	t.Run("密文绑定记录指纹", func(t *testing.T) {
+		sealed, _ := EncryptToken("refresh-token", "fp-1")
+		if _, err := DecryptToken(sealed, "fp-2"); err == nil {
+			t.Fatalf("期望复制到其他记录的密文解密失败")
+		}
+	})
+}
+
+func TestTokenKeyRotation(t *testing.T) {
+	resetKeyRing(t)
+	source := bSdkConfig.New(bSdkConfig.WithClient("app", "client-secret"))
+	source.Token.EncryptionKeys = []bSdkConfig.TokenKeyConfig{{ID: "k1", Key: testAESKey("a")}}
+	reloader, err := bSdkConfig.NewReloader(context.Background(), func() (*bSdkConfig.Config, error) {
+		next := source.Clone()
+		next.Endpoints = bSdkConfig.EndpointConfig{
+			Auth:          "https://sso.example.com/authorize",
+			Token:         "https://sso.example.com/token",
+			Userinfo:      "https://sso.example.com/userinfo",
+			Introspection: "https://sso.example.com/introspect",
+			Revocation:    "https://sso.example.com/revoke",
+		}
+		next.Client.RedirectURI = "https://app.example.com/callback"
+		return next, nil
+	})
+	if err != nil {
+		t.Fatalf("创建配置失败: %v", err)
+	}
+	defer reloader.Close()
+	if _, err = ConfigureTokenKeys(context.Background(), reloader.Config()); err != nil {
+		t.Fatalf("配置令牌密钥失败: %v", err)
+	}
+	fingerprint := mustFingerprint(t, "access-token")
+
+	sealed, err := EncryptToken("access-token", fingerprint)
+	if err != nil {
+		t.Fatalf("加密失败: %v", err)
+	}
+
+	t.Run("热更新后旧密钥仍可解密并标记过期", func(t *testing.T) {
+		source.Token.EncryptionKeys = []bSdkConfig.TokenKeyConfig{{ID: "k2", Key: testAESKey("b")}, {ID: "k1", Key: testAESKey("a")}}
+		if err := reloader.Reload(context.Background()); err != nil {
+			t.Fatalf("重新加载配置失败: %v", err)
+		}
+
+		plain, stale, err := OpenToken(sealed, fingerprint)
+		if err != nil || plain != "access-token" || !stale {
+			t.Fatalf("旧密钥解密结果不正确: %s, %v, %v", plain, stale, err)
+		}
+
+		resealed, _ := EncryptToken(plain, fingerprint)
+		if !strings.HasPrefix(resealed, tokenCipherV2+"k2.") {
+			t.Fatalf("期望使用活动密钥加密: %s", resealed)
+		}
+	})
+
+	t.Run("轮换客户端密钥不影响令牌密钥", func(t *testing.T) {
+		source.Client.Secret = "rotated-secret"
+		if err := reloader.Reload(context.Background()); err != nil {
+			t.Fatalf("重新加载配置失败: %v", err)
+		}
+		if mustFingerprint(t, "access-token") != fingerprint {
+			t.Fatalf("轮换客户端密钥后令牌指纹不应变化")
+		}
+		if _, err := DecryptToken(sealed, fingerprint); err != nil {
+			t.Fatalf("轮换客户端密钥后仍应可解密: %v", err)
+		}
+	})
+
+	t.Run("移除旧密钥后无法解密", func(t *testing.T) {
+		source.Token.EncryptionKeys = []bSdkConfig.TokenKeyConfig{{ID: "k2", Key: testAESKey("b")}}
+		if err := reloader.Reload(context.Background()); err != nil {
+			t.Fatalf("重新加载配置失败: %v", err)
+		}
+		if _, err := DecryptToken(sealed, fingerprint); err == nil {
+			t.Fatalf("期望缺少密钥时解密失败")
+		}
+	})
+
+	t.Run("指纹密钥不支持热更新", func(t *testing.T) {
+		source.Token.HashKey = "another-hash-key"
+		if err := reloader.Reload(context.Background()); err != nil {
+			t.Fatalf("重新加载配置失败: %v", err)
+		}
+		if mustFingerprint(t, "access-token") != fingerprint {
+			t.Fatalf("热更新不应改变令牌指纹")
+		}
+		if _, err := ConfigureTokenKeys(context.Background(), reloader.Config()); err == nil {
+			t.Fatalf("期望修改指纹密钥时报错")
+		}
+	})
+}
+
+func TestTokenKeyProvider(t *testing.T) {
+	resetKeyRing(t)
+
+	t.Run("单个密钥", func(t *testing.T) {
+		ring := configureTestKeys(t, bSdkConfig.TokenConfig{EncryptionKey: testAESKey("a")})
+		if ring.Active.ID != DefaultTokenKeyID {
+			t.Fatalf("单个密钥的 kid 应为 %s: %+v", DefaultTokenKeyID, ring)
+		}
+		sealed, err := EncryptToken("single-token", "fp-1")
+		if err != nil || !strings.HasPrefix(sealed, tokenCipherV2+DefaultTokenKeyID+".") {
+			t.Fatalf("单个密钥加密失败: %s, %v", sealed, err)
+		}
+	})
+
+	t.Run("文件加载", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys.json")
+		content := `{"active":"k2","keys":[{"id":"k1","key":"` + testAESKey("a") + `"},{"id":"k2","key":"` + testAESKey("b") + `"}]}`
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("写入密钥文件失败: %v", err)
+		}
+		t.Setenv(bSdkConst.EnvSsoTokenEncryptionKeysFile.String(), path)
+
+		ring, err := EnvKeyProvider{}.LoadKeyRing(context.Background())
+		if err != nil || ring.Active.ID != "k2" || len(ring.Decrypt) != 1 {
+			t.Fatalf("文件加载失败: %+v, %v", ring, err)
+		}
+	})
+
+	t.Run("派生密钥保留为仅解密密钥", func(t *testing.T) {
+		resetKeyRing(t)
+		if ring := configureTestKeys(t, bSdkConfig.TokenConfig{}); ring.Active.ID != DerivedTokenKeyID {
+			t.Fatalf("未配置加密密钥时应使用派生密钥: %+v", ring)
+		}
+		sealed, _ := EncryptToken("derived-token", "fp-1")
+
+		resetKeyRing(t)
+		ring := configureTestKeys(t, bSdkConfig.TokenConfig{EncryptionKeys: []bSdkConfig.TokenKeyConfig{{ID: "k1", Key: testAESKey("a")}}})
+		if _, ok := ring.find(DerivedTokenKeyID); !ok || ring.Active.ID != "k1" {
+			t.Fatalf("派生密钥应保留为仅解密密钥: %+v", ring)
+		}
+		plain, stale, err := OpenToken(sealed, "fp-1")
+		if err != nil || plain != "derived-token" || !stale {
+			t.Fatalf("派生密钥解密结果不正确: %s, %v, %v", plain, stale, err)
+		}
+	})
+
+	t.Run("自定义提供者", func(t *testing.T) {
+		key := make([]byte, 32)
+		_, _ = rand.Read(key)
+		configureTestKeys(t, bSdkConfig.TokenConfig{})
+		SetKeyProvider(staticKeyProvider{ring: &TokenKeyRing{Active: TokenKey{ID: "kms-1", Key: key}}})
+
+		sealed, err := EncryptToken("id-token", "fp-1")
+		if err != nil || !strings.HasPrefix(sealed, tokenCipherV2+"kms-1.") {
+			t.Fatalf("自定义提供者加密失败: %s, %v", sealed, err)
+		}
+	})
+
+	t.Run("密钥环校验", func(t *testing.T) {
+		check := func(token bSdkConfig.TokenConfig, secret string) error {
+			cfg := bSdkConfig.New(bSdkConfig.WithClient("app", secret))
+			cfg.Token = token
+			_, err := CheckTokenKeys(context.Background(), cfg)
+			return err
+		}
+
+		SetKeyProvider(staticKeyProvider{ring: &TokenKeyRing{Active: TokenKey{ID: "bad.kid", Key: make([]byte, 32)}}})
+		if err := check(bSdkConfig.TokenConfig{}, "client-secret"); err == nil {
+			t.Fatalf("期望非法 kid 校验失败")
+		}
+
+		SetKeyProvider(nil)
+		short := base64.StdEncoding.EncodeToString([]byte("short"))
+		if err := check(bSdkConfig.TokenConfig{EncryptionKeys: []bSdkConfig.TokenKeyConfig{{ID: "k1", Key: short}}}, "client-secret"); err == nil {
+			t.Fatalf("期望密钥长度校验失败")
+		}
+		if err := check(bSdkConfig.TokenConfig{EncryptionKeys: []bSdkConfig.TokenKeyConfig{{ID: "k1", Key: testAESKey("a")}}, ActiveKeyID: "k9"}, "client-secret"); err == nil {
+			t.Fatalf("期望活动密钥缺失时校验失败")
+		}
+	})
+
+	t.Run("缺少密钥时拒绝配置", func(t *testing.T) {
+		resetKeyRing(t)
+		cfg := bSdkConfig.New(bSdkConfig.WithClient("app", ""))
+		if _, err := ConfigureTokenKeys(context.Background(), cfg); err == nil {
+			t.Fatalf("客户端密钥与令牌密钥均为空时应报错")
+		}
+
+		cfg.Token.HashKey = "hash-key"
+		if _, err := ConfigureTokenKeys(context.Background(), cfg); err == nil {
+			t.Fatalf("缺少加密密钥且无法派生时应报错")
+		}
+
+		cfg.Token.EncryptionKey = testAESKey("a")
+		if _, err := ConfigureTokenKeys(context.Background(), cfg); err != nil {
+			t.Fatalf("显式配置密钥后不应依赖客户端密钥: %v", err)
+		}
+	})
+
+	t.Run("多个实例需使用相同的密钥配置", func(t *testing.T) {
+		resetKeyRing(t)
+		configureTestKeys(t, bSdkConfig.TokenConfig{HashKey: "hash-key", EncryptionKey: testAESKey("a")})
+
+		other := bSdkConfig.New(bSdkConfig.WithClient("other", "other-secret"))
+		other.Token = bSdkConfig.TokenConfig{HashKey: "hash-key", EncryptionKey: testAESKey("a")}
+		if _, err := ConfigureTokenKeys(context.Background(), other); err != nil {
+			t.Fatalf("相同的密钥配置应可共存: %v", err)
+		}
+		other.Token.HashKey = "other-hash-key"
+		if _, err := ConfigureTokenKeys(context.Background(), other); err == nil {
+			t.Fatalf("不同的密钥配置应报错")
+		}
+	})
+}
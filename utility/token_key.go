@@ -0,0 +1,200 @@
+package bSdkUtil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	bSdkConfig "github.com/phalanx-labs/beacon-sso-sdk/config"
+)
+
+// TokenKey 令牌加密密钥。
+type TokenKey struct {
+	ID  string // 密钥标识（kid），写入密文头部用于解密时定位密钥，不可包含 "."
+	Key []byte // AES 密钥（16/24/32 字节）
+}
+
+// TokenKeyRing 令牌加密密钥环
+//
+// 活动密钥用于加密新数据；仅解密密钥用于读取轮换前写入的数据，
+// 读取到旧密钥加密的数据时会惰性地使用活动密钥重新加密。
+type TokenKeyRing struct {
+	Active  TokenKey   // 活动密钥
+	Decrypt []TokenKey // 仅解密密钥
+}
+
+// find 根据 kid 查找密钥。
+func (r *TokenKeyRing) find(id string) (TokenKey, bool) {
+	if r.Active.ID == id {
+		return r.Active, true
+	}
+	for _, key := range r.Decrypt {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return TokenKey{}, false
+}
+
+// validate 校验密钥环的完整性。
+func (r *TokenKeyRing) validate() error {
+	if r == nil || r.Active.ID == "" {
+		return errors.New("令牌加密密钥环缺少活动密钥")
+	}
+
+	seen := make(map[string]struct{}, len(r.Decrypt)+1)
+	for _, key := range append([]TokenKey{r.Active}, r.Decrypt...) {
+		if key.ID == "" || strings.Contains(key.ID, ".") {
+			return fmt.Errorf("令牌加密密钥标识非法: %q", key.ID)
+		}
+		if _, exist := seen[key.ID]; exist {
+			return fmt.Errorf("令牌加密密钥标识重复: %s", key.ID)
+		}
+		seen[key.ID] = struct{}{}
+		switch len(key.Key) {
+		case 16, 24, 32:
+		default:
+			return fmt.Errorf("令牌加密密钥 %s 长度需为 16/24/32 字节", key.ID)
+		}
+	}
+	return nil
+}
+
+// KeyProvider 令牌加密密钥提供者
+//
+// 业务方可实现该接口从 KMS、Vault 等外部系统加载密钥，并通过 `SetKeyProvider` 注册；未注册时使用 `ConfigKeyProvider`。
+type KeyProvider interface {
+	// LoadKeyRing 加载密钥环，在 `ConfigureTokenKeys`、配置热更新后首次使用以及调用 `ReloadTokenKeyRing` 时执行。
+	LoadKeyRing(ctx context.Context) (*TokenKeyRing, error)
+}
+
+// ErrTokenKeyNotConfigured 未配置令牌加密密钥，此时 `ConfigureTokenKeys` 使用由客户端密钥派生的密钥。
+var ErrTokenKeyNotConfigured = errors.New("未配置令牌加密密钥")
+
+// ErrTokenKeyUnavailable 未调用 `ConfigureTokenKeys` 且按环境变量惰性加载令牌密钥失败，此时无法计算令牌指纹或加解密令牌。
+var ErrTokenKeyUnavailable = errors.New("令牌密钥不可用")
+
+// ErrTokenNotEncrypted 存储中的令牌字段为未加密的明文，拒绝读取。
+var ErrTokenNotEncrypted = errors.New("令牌未加密，拒绝使用")
+
+// ConfigKeyProvider 按 SDK 配置的 `token` 加载密钥环
+//
+// 读取顺序：
+//  1. `KeysFile`：JSON 密钥环文件，格式同 `FileKeyProvider`；
+//  2. `EncryptionKeys`：密钥列表，活动密钥由 `ActiveKeyID` 指定，未指定时为列表中的第一个；
+//  3. `EncryptionKey`：单个密钥，kid 为 `default`。
+//
+// 均未配置时返回 `ErrTokenKeyNotConfigured`。`*_ref` 引用需事先通过 `bSdkConfig.Config.ResolveSecrets` 读取。
+type ConfigKeyProvider struct {
+	Token bSdkConfig.TokenConfig // 令牌配置
+}
+
+// LoadKeyRing 实现 KeyProvider 接口。
+func (p ConfigKeyProvider) LoadKeyRing(ctx context.Context) (*TokenKeyRing, error) {
+	switch {
+	case p.Token.KeysFile != "":
+		return FileKeyProvider{Path: p.Token.KeysFile}.LoadKeyRing(ctx)
+	case len(p.Token.EncryptionKeys) > 0:
+		keys := make([]TokenKey, 0, len(p.Token.EncryptionKeys))
+		for _, item := range p.Token.EncryptionKeys {
+			key, err := decodeAESKey(item.Key)
+			if err != nil {
+				return nil, fmt.Errorf("令牌加密密钥 %s 无效: %w", item.ID, err)
+			}
+			keys = append(keys, TokenKey{ID: item.ID, Key: key})
+		}
+		return buildKeyRing(keys, p.Token.ActiveKeyID)
+	case p.Token.EncryptionKey != "":
+		key, err := decodeAESKey(p.Token.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		return &TokenKeyRing{Active: TokenKey{ID: DefaultTokenKeyID, Key: key}}, nil
+	}
+	return nil, ErrTokenKeyNotConfigured
+}
+
+// EnvKeyProvider 从环境变量加载密钥环
+//
+// 每次加载时通过 `bSdkConfig.LoadEnv` 重新读取 `SSO_TOKEN_ENCRYPTION_KEYS_FILE`、`SSO_TOKEN_ENCRYPTION_KEYS`
+// （活动密钥由 `SSO_TOKEN_ENCRYPTION_KEY_ID` 指定）与 `SSO_TOKEN_ENCRYPTION_KEY`（或 `SSO_TOKEN_ENCRYPTION_KEY_FILE`），
+// 再交由 `ConfigKeyProvider` 加载。
+type EnvKeyProvider struct{}
+
+// LoadKeyRing 实现 KeyProvider 接口。
+func (EnvKeyProvider) LoadKeyRing(ctx context.Context) (*TokenKeyRing, error) {
+	cfg, err := bSdkConfig.LoadEnv()
+	if err != nil {
+		return nil, err
+	}
+	if err = cfg.ResolveSecrets(ctx); err != nil {
+		return nil, err
+	}
+	return ConfigKeyProvider{Token: cfg.Token}.LoadKeyRing(ctx)
+}
+
+// FileKeyProvider 从 JSON 文件加载密钥环，适用于挂载 Kubernetes Secret 等场景
+//
+// 文件格式：
+//
+//	{"active": "k2", "keys": [{"id": "k1", "key": "<base64>"}, {"id": "k2", "key": "<base64>"}]}
+//
+// 未指定 `active` 时以第一个密钥为活动密钥。
+type FileKeyProvider struct {
+	Path string // 密钥文件路径
+}
+
+// LoadKeyRing 实现 KeyProvider 接口。
+func (p FileKeyProvider) LoadKeyRing(_ context.Context) (*TokenKeyRing, error) {
+	content, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("读取令牌加密密钥文件失败: %w", err)
+	}
+
+	var file struct {
+		Active string `json:"active"`
+		Keys   []struct {
+			ID  string `json:"id"`
+			Key string `json:"key"`
+		} `json:"keys"`
+	}
+	if err = json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("解析令牌加密密钥文件失败: %w", err)
+	}
+
+	keys := make([]TokenKey, 0, len(file.Keys))
+	for _, item := range file.Keys {
+		key, decodeErr := decodeAESKey(item.Key)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("令牌加密密钥 %s 无效: %w", item.ID, decodeErr)
+		}
+		keys = append(keys, TokenKey{ID: item.ID, Key: key})
+	}
+	return buildKeyRing(keys, file.Active)
+}
+
+// buildKeyRing 根据密钥列表与活动密钥标识构建密钥环。
+func buildKeyRing(keys []TokenKey, activeID string) (*TokenKeyRing, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("令牌加密密钥列表为空")
+	}
+	if activeID == "" {
+		activeID = keys[0].ID
+	}
+
+	ring := &TokenKeyRing{}
+	for _, key := range keys {
+		if key.ID == activeID && ring.Active.ID == "" {
+			ring.Active = key
+			continue
+		}
+		ring.Decrypt = append(ring.Decrypt, key)
+	}
+	if ring.Active.ID == "" {
+		return nil, fmt.Errorf("未找到活动令牌加密密钥: %s", activeID)
+	}
+	return ring, nil
+}